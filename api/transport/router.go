@@ -22,6 +22,7 @@ package transport
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -47,6 +48,45 @@ type Procedure struct {
 	// Signature of the handler, for introspection. This should be a snippet of
 	// Go code representing the function definition.
 	Signature string
+
+	// Options declared for the procedure in its IDL, for introspection and
+	// inbound middleware, or nil if none were declared. This is a pointer so
+	// that Procedure remains comparable.
+	Options *ProcedureOptions
+}
+
+// ProcedureOptions describe the expected behavior of a procedure, as declared
+// in its IDL.
+//
+// YARPC enforces RequiredHeaders on inbound requests, and clients generated
+// for Protobuf apply Timeout to calls without a deadline. Idempotent and
+// Retry are informational: they are reported by introspection, but YARPC does
+// not retry calls, and outbound middleware does not see any of these options.
+type ProcedureOptions struct {
+	// Timeout is the default timeout of calls to the procedure, used when the
+	// caller does not specify one. Zero if unspecified.
+	Timeout time.Duration
+
+	// Idempotent procedures may safely be called more than once for the same
+	// request.
+	Idempotent bool
+
+	// Retry declares how calls to the procedure may be retried.
+	Retry RetryPolicy
+
+	// RequiredHeaders are the headers that requests to the procedure must
+	// have.
+	RequiredHeaders []string
+}
+
+// RetryPolicy declares how calls to a procedure may be retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Zero if unspecified.
+	MaxAttempts int
+
+	// Backoff is the time to wait between attempts.
+	Backoff time.Duration
 }
 
 // MarshalLogObject implements zap.ObjectMarshaler.
@@ -48,3 +48,12 @@ func TestProcedureLogMarshaling(t *testing.T) {
 		"signature": "signature",
 	}, enc.Fields, "Unexpected output from marshaling procedure.")
 }
+
+func TestProcedureComparable(t *testing.T) {
+	p := Procedure{
+		Name:    "name",
+		Options: &ProcedureOptions{RequiredHeaders: []string{"x-tenant"}},
+	}
+	q := p
+	assert.True(t, p == q, "procedures must be comparable")
+}
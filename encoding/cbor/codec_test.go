@@ -0,0 +1,92 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cbor
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/bufferpool"
+	"go.uber.org/yarpc/internal/clientconfig"
+)
+
+type getValueRequest struct {
+	Key   string            `cbor:"key"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+type getValueResponse struct {
+	Value string `codec:"value"`
+}
+
+func TestCodec(t *testing.T) {
+	buf, err := _codec.Marshal(&getValueRequest{Key: "foo", Attrs: map[string]string{"bar": "baz"}})
+	require.NoError(t, err)
+	body := append([]byte(nil), buf.Bytes()...)
+	bufferpool.Put(buf)
+
+	var req getValueRequest
+	require.NoError(t, _codec.Unmarshal(bytes.NewReader(body), &req))
+	assert.Equal(t, getValueRequest{Key: "foo", Attrs: map[string]string{"bar": "baz"}}, req)
+
+	var generic interface{}
+	require.NoError(t, _codec.Unmarshal(bytes.NewReader(body), &generic))
+	assert.Equal(t, map[string]interface{}{
+		"key":   "foo",
+		"attrs": map[string]interface{}{"bar": "baz"},
+	}, generic, "maps must decode into map[string]interface{} with string values")
+}
+
+func TestRoundTrip(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	procedures := Procedure("getValue", func(ctx context.Context, req *getValueRequest) (*getValueResponse, error) {
+		return &getValueResponse{Value: req.Key + req.Attrs["bar"]}, nil
+	})
+	require.Len(t, procedures, 1)
+	assert.Equal(t, Encoding, procedures[0].Encoding)
+	handler := procedures[0].HandlerSpec.Unary()
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *transport.Request) (*transport.Response, error) {
+			assert.Equal(t, Encoding, req.Encoding)
+			resw := new(transporttest.FakeResponseWriter)
+			if err := handler.Handle(ctx, req, resw); err != nil {
+				return nil, err
+			}
+			return &transport.Response{Body: ioutil.NopCloser(&resw.Body)}, nil
+		})
+
+	client := New(clientconfig.MultiOutbound("caller", "service", transport.Outbounds{Unary: outbound}))
+	var res getValueResponse
+	require.NoError(t, client.Call(context.Background(), "getValue",
+		&getValueRequest{Key: "foo", Attrs: map[string]string{"bar": "baz"}}, &res))
+	assert.Equal(t, "foobaz", res.Value)
+}
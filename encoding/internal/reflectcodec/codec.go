@@ -0,0 +1,70 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package reflectcodec implements encodings whose request and response bodies
+// are Go values serialized by a handle of github.com/ugorji/go/codec, like
+// the MessagePack and CBOR encodings.
+//
+// Handlers and clients of these encodings mirror those of the JSON encoding.
+package reflectcodec
+
+import (
+	"io"
+
+	"github.com/ugorji/go/codec"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/bufferpool"
+)
+
+// Codec serializes the bodies of an encoding with a codec handle.
+type Codec struct {
+	encoding transport.Encoding
+	handle   codec.Handle
+}
+
+// New builds a Codec for the given encoding. The handle must be safe for
+// concurrent use and must not be modified afterwards.
+func New(encoding transport.Encoding, handle codec.Handle) *Codec {
+	return &Codec{encoding: encoding, handle: handle}
+}
+
+// Marshal encodes the given value into a buffer from the buffer pool.
+//
+// The returned buffer must be released with bufferpool.Put once the caller is
+// done with it.
+func (c *Codec) Marshal(v interface{}) (*bufferpool.Buffer, error) {
+	buf := bufferpool.Get()
+	if err := codec.NewEncoder(buf, c.handle).Encode(v); err != nil {
+		bufferpool.Put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Unmarshal decodes the contents of the given reader into v.
+func (c *Codec) Unmarshal(r io.Reader, v interface{}) error {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+	return codec.NewDecoderBytes(buf.Bytes(), c.handle).Decode(v)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflectcodec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/bufferpool"
+)
+
+const _testEncoding transport.Encoding = "msgpack"
+
+// _testCodec is configured like the MessagePack encoding.
+var _testCodec = New(_testEncoding, newTestHandle())
+
+func newTestHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.TypeInfos = codec.NewTypeInfos([]string{"msgpack", "codec", "json"})
+	return h
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	buf, err := _testCodec.Marshal(&simpleRequest{Name: "foo"})
+	require.NoError(t, err)
+	defer bufferpool.Put(buf)
+
+	var got simpleRequest
+	require.NoError(t, _testCodec.Unmarshal(buf, &got))
+	assert.Equal(t, simpleRequest{Name: "foo"}, got)
+
+	_, err = _testCodec.Marshal(complex(1, 2))
+	assert.Error(t, err, "complex numbers cannot be encoded")
+}
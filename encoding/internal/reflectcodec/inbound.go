@@ -0,0 +1,151 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflectcodec
+
+import (
+	"context"
+	"io"
+	"reflect"
+
+	encodingapi "go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/bufferpool"
+	"go.uber.org/yarpc/pkg/errors"
+)
+
+// codecHandler adapts a user-provided high-level handler into a
+// transport-level Handler.
+//
+// The wrapped function must already be in the correct format:
+//
+// 	f(ctx context.Context, body $reqBody) ($resBody, error)
+type codecHandler struct {
+	codec   *Codec
+	reader  requestReader
+	handler reflect.Value
+}
+
+func (h codecHandler) Handle(ctx context.Context, treq *transport.Request, rw transport.ResponseWriter) error {
+	if err := errors.ExpectEncodings(treq, h.codec.encoding); err != nil {
+		return err
+	}
+
+	ctx, call := encodingapi.NewInboundCall(ctx)
+	if err := call.ReadFromRequest(treq); err != nil {
+		return err
+	}
+
+	reqBody, err := h.reader.Read(h.codec, treq.Body)
+	if err != nil {
+		return errors.RequestBodyDecodeError(treq, err)
+	}
+
+	results := h.handler.Call([]reflect.Value{reflect.ValueOf(ctx), reqBody})
+
+	if err := call.WriteToResponse(rw); err != nil {
+		return err
+	}
+
+	// we want to return the appErr if it exists as this is what
+	// the previous behavior was so we deprioritize this error
+	var encodeErr error
+	if result := results[0].Interface(); result != nil {
+		encodeErr = h.writeResponse(treq, rw, result)
+	}
+
+	if appErr, _ := results[1].Interface().(error); appErr != nil {
+		rw.SetApplicationError()
+		return appErr
+	}
+
+	return encodeErr
+}
+
+func (h codecHandler) HandleOneway(ctx context.Context, treq *transport.Request) error {
+	if err := errors.ExpectEncodings(treq, h.codec.encoding); err != nil {
+		return err
+	}
+
+	ctx, call := encodingapi.NewInboundCall(ctx)
+	if err := call.ReadFromRequest(treq); err != nil {
+		return err
+	}
+
+	reqBody, err := h.reader.Read(h.codec, treq.Body)
+	if err != nil {
+		return errors.RequestBodyDecodeError(treq, err)
+	}
+
+	results := h.handler.Call([]reflect.Value{reflect.ValueOf(ctx), reqBody})
+
+	if err := results[0].Interface(); err != nil {
+		return err.(error)
+	}
+
+	return nil
+}
+
+// writeResponse encodes the result into a pooled buffer before writing it so
+// that encoding failures do not leave a partial response behind.
+func (h codecHandler) writeResponse(treq *transport.Request, rw transport.ResponseWriter, result interface{}) error {
+	buf, err := h.codec.Marshal(result)
+	if err != nil {
+		return errors.ResponseBodyEncodeError(treq, err)
+	}
+	defer bufferpool.Put(buf)
+
+	_, err = buf.WriteTo(rw)
+	return err
+}
+
+// requestReader is used to parse a request argument from a request body.
+type requestReader interface {
+	Read(*Codec, io.Reader) (reflect.Value, error)
+}
+
+type structReader struct {
+	// Type of the struct (not a pointer to the struct)
+	Type reflect.Type
+}
+
+func (r structReader) Read(c *Codec, body io.Reader) (reflect.Value, error) {
+	value := reflect.New(r.Type)
+	err := c.Unmarshal(body, value.Interface())
+	return value, err
+}
+
+type mapReader struct {
+	Type reflect.Type // Type of the map
+}
+
+func (r mapReader) Read(c *Codec, body io.Reader) (reflect.Value, error) {
+	value := reflect.New(r.Type)
+	err := c.Unmarshal(body, value.Interface())
+	return value.Elem(), err
+}
+
+type ifaceEmptyReader struct{}
+
+func (ifaceEmptyReader) Read(c *Codec, body io.Reader) (reflect.Value, error) {
+	value := reflect.New(_interfaceEmptyType)
+	err := c.Unmarshal(body, value.Interface())
+	return value.Elem(), err
+}
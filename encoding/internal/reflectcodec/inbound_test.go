@@ -0,0 +1,215 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflectcodec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/bufferpool"
+)
+
+type simpleRequest struct {
+	Name       string           `msgpack:"name"`
+	Attributes map[string]int32 `json:"attributes"`
+}
+
+type simpleResponse struct {
+	Success bool
+}
+
+func TestHandleStructSuccess(t *testing.T) {
+	h := func(ctx context.Context, body *simpleRequest) (*simpleResponse, error) {
+		assert.Equal(t, "simpleCall", yarpc.CallFromContext(ctx).Procedure())
+		assert.Equal(t, "foo", body.Name)
+		assert.Equal(t, map[string]int32{"bar": 42}, body.Attributes)
+
+		return &simpleResponse{Success: true}, nil
+	}
+
+	handler := codecHandler{
+		codec:   _testCodec,
+		reader:  structReader{reflect.TypeOf(simpleRequest{})},
+		handler: reflect.ValueOf(h),
+	}
+
+	resw := new(transporttest.FakeResponseWriter)
+	err := handler.Handle(context.Background(), &transport.Request{
+		Procedure: "simpleCall",
+		Encoding:  _testEncoding,
+		Body: encodedBody(t, map[string]interface{}{
+			"name":       "foo",
+			"attributes": map[string]interface{}{"bar": 42},
+		}),
+	}, resw)
+	require.NoError(t, err)
+
+	var response simpleResponse
+	require.NoError(t, _testCodec.Unmarshal(&resw.Body, &response))
+	assert.Equal(t, simpleResponse{Success: true}, response)
+}
+
+func TestHandleMapSuccess(t *testing.T) {
+	h := func(ctx context.Context, body map[string]interface{}) (map[string]string, error) {
+		assert.EqualValues(t, 42, body["foo"])
+		assert.Equal(t, []interface{}{"a", "b", "c"}, body["bar"])
+
+		return map[string]string{"success": "true"}, nil
+	}
+
+	handler := codecHandler{
+		codec:   _testCodec,
+		reader:  mapReader{reflect.TypeOf(make(map[string]interface{}))},
+		handler: reflect.ValueOf(h),
+	}
+
+	resw := new(transporttest.FakeResponseWriter)
+	err := handler.Handle(context.Background(), &transport.Request{
+		Procedure: "foo",
+		Encoding:  _testEncoding,
+		Body: encodedBody(t, map[string]interface{}{
+			"foo": 42,
+			"bar": []string{"a", "b", "c"},
+		}),
+	}, resw)
+	require.NoError(t, err)
+
+	var response struct {
+		Success string `json:"success"`
+	}
+	require.NoError(t, _testCodec.Unmarshal(&resw.Body, &response))
+	assert.Equal(t, "true", response.Success)
+}
+
+func TestHandleInterfaceEmptySuccess(t *testing.T) {
+	h := func(ctx context.Context, body interface{}) (interface{}, error) {
+		return body, nil
+	}
+
+	handler := codecHandler{codec: _testCodec, reader: ifaceEmptyReader{}, handler: reflect.ValueOf(h)}
+
+	resw := new(transporttest.FakeResponseWriter)
+	err := handler.Handle(context.Background(), &transport.Request{
+		Procedure: "foo",
+		Encoding:  _testEncoding,
+		Body:      encodedBody(t, []string{"a", "b", "c"}),
+	}, resw)
+	require.NoError(t, err)
+
+	var response []string
+	require.NoError(t, _testCodec.Unmarshal(&resw.Body, &response))
+	assert.Equal(t, []string{"a", "b", "c"}, response)
+}
+
+func TestHandleDecodeError(t *testing.T) {
+	h := func(ctx context.Context, body *simpleRequest) (*simpleResponse, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	}
+
+	handler := codecHandler{
+		codec:   _testCodec,
+		reader:  structReader{reflect.TypeOf(simpleRequest{})},
+		handler: reflect.ValueOf(h),
+	}
+
+	err := handler.Handle(context.Background(), &transport.Request{
+		Service:   "service",
+		Procedure: "foo",
+		Encoding:  _testEncoding,
+		Body:      encodedBody(t, "not a struct"),
+	}, new(transporttest.FakeResponseWriter))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decode "msgpack" request body for procedure "foo" of service "service"`)
+}
+
+func TestHandleWrongEncoding(t *testing.T) {
+	handler := _testCodec.newHandler(reflect.TypeOf(&simpleRequest{}), func(context.Context, *simpleRequest) error {
+		t.Fatal("handler must not be called")
+		return nil
+	})
+
+	err := handler.HandleOneway(context.Background(), &transport.Request{
+		Procedure: "foo",
+		Encoding:  "json",
+		Body:      strings.NewReader("{}"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected encoding")
+}
+
+func TestHandleOneway(t *testing.T) {
+	var got *simpleRequest
+	handler := _testCodec.newHandler(reflect.TypeOf(&simpleRequest{}), func(ctx context.Context, body *simpleRequest) error {
+		got = body
+		return errors.New("great sadness")
+	})
+
+	err := handler.HandleOneway(context.Background(), &transport.Request{
+		Procedure: "foo",
+		Encoding:  _testEncoding,
+		Body:      encodedBody(t, &simpleRequest{Name: "foo"}),
+	})
+	assert.EqualError(t, err, "great sadness")
+	assert.Equal(t, &simpleRequest{Name: "foo"}, got)
+}
+
+func TestHandleBothResponseError(t *testing.T) {
+	h := func(ctx context.Context, body *simpleRequest) (*simpleResponse, error) {
+		return &simpleResponse{Success: true}, errors.New("bar")
+	}
+
+	handler := codecHandler{
+		codec:   _testCodec,
+		reader:  structReader{reflect.TypeOf(simpleRequest{})},
+		handler: reflect.ValueOf(h),
+	}
+
+	resw := new(transporttest.FakeResponseWriter)
+	err := handler.Handle(context.Background(), &transport.Request{
+		Procedure: "simpleCall",
+		Encoding:  _testEncoding,
+		Body:      encodedBody(t, &simpleRequest{Name: "foo"}),
+	}, resw)
+	require.Equal(t, errors.New("bar"), err)
+	assert.True(t, resw.IsApplicationError)
+
+	var response simpleResponse
+	require.NoError(t, _testCodec.Unmarshal(&resw.Body, &response))
+	assert.Equal(t, simpleResponse{Success: true}, response)
+}
+
+func encodedBody(t *testing.T, v interface{}) io.Reader {
+	buf, err := _testCodec.Marshal(v)
+	require.NoError(t, err)
+	defer bufferpool.Put(buf)
+	return bytes.NewReader(append([]byte(nil), buf.Bytes()...))
+}
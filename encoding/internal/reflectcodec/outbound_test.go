@@ -0,0 +1,163 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflectcodec
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/clientconfig"
+)
+
+func TestCall(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		desc        string
+		body        interface{}
+		response    interface{}
+		responseErr error
+
+		// whether the outbound receives the request
+		noCall bool
+
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			desc:     "success",
+			body:     &simpleRequest{Name: "foo"},
+			response: map[string]interface{}{"success": true},
+			want:     map[string]interface{}{"success": true},
+		},
+		{
+			desc:        "application error",
+			body:        &simpleRequest{Name: "foo"},
+			response:    map[string]interface{}{"success": false},
+			responseErr: errors.New("bar"),
+			want:        map[string]interface{}{"success": false},
+			wantErr:     "bar",
+		},
+		{
+			desc:     "invalid response",
+			body:     &simpleRequest{Name: "foo"},
+			response: []string{"not", "a", "map"},
+			wantErr:  `failed to decode "msgpack" response body for procedure "procedure" of service "service"`,
+		},
+		{
+			desc:    "invalid request",
+			body:    complex(1, 2), // complex numbers cannot be encoded
+			noCall:  true,
+			wantErr: `failed to encode "msgpack" request body for procedure "procedure" of service "service"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+			client := _testCodec.NewClient(clientconfig.MultiOutbound("caller", "service",
+				transport.Outbounds{
+					Unary: outbound,
+				}))
+
+			if !tt.noCall {
+				outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *transport.Request) (*transport.Response, error) {
+						assert.Equal(t, "caller", req.Caller)
+						assert.Equal(t, "service", req.Service)
+						assert.Equal(t, "procedure", req.Procedure)
+						assert.Equal(t, _testEncoding, req.Encoding)
+						assert.Equal(t, "bar", req.Headers.Items()["foo"])
+
+						var reqBody simpleRequest
+						require.NoError(t, _testCodec.Unmarshal(req.Body, &reqBody))
+						assert.Equal(t, tt.body, &reqBody)
+
+						return &transport.Response{
+							Body:    ioutil.NopCloser(encodedBody(t, tt.response)),
+							Headers: transport.NewHeaders().With("baz", "qux"),
+						}, tt.responseErr
+					})
+			}
+
+			var (
+				resBody    map[string]interface{}
+				resHeaders map[string]string
+			)
+			err := client.Call(context.Background(), "procedure", tt.body, &resBody,
+				yarpc.WithHeader("foo", "bar"), yarpc.ResponseHeaders(&resHeaders))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, map[string]string{"baz": "qux"}, resHeaders)
+			}
+			if tt.want != nil {
+				assert.Equal(t, tt.want, resBody)
+			}
+		})
+	}
+}
+
+type successAck struct{}
+
+func (a successAck) String() string {
+	return "success"
+}
+
+func TestCallOneway(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockOnewayOutbound(mockCtrl)
+	client := _testCodec.NewClient(clientconfig.MultiOutbound("caller", "service",
+		transport.Outbounds{
+			Oneway: outbound,
+		}))
+
+	outbound.EXPECT().CallOneway(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *transport.Request) (transport.Ack, error) {
+			assert.Equal(t, _testEncoding, req.Encoding)
+
+			var reqBody simpleRequest
+			require.NoError(t, _testCodec.Unmarshal(req.Body, &reqBody))
+			assert.Equal(t, simpleRequest{Name: "foo"}, reqBody)
+			return successAck{}, nil
+		})
+
+	ack, err := client.CallOneway(context.Background(), "procedure", &simpleRequest{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "success", ack.String())
+
+	_, err = client.CallOneway(context.Background(), "procedure", complex(1, 2))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to encode "msgpack" request body`)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflectcodec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapUnaryHandlerInvalid(t *testing.T) {
+	tests := []struct {
+		Name string
+		Func interface{}
+	}{
+		{"empty", func() {}},
+		{"not-a-function", 0},
+		{
+			"wrong-args-in",
+			func(context.Context) (*struct{}, error) {
+				return nil, nil
+			},
+		},
+		{
+			"wrong-ctx",
+			func(string, *struct{}) (*struct{}, error) {
+				return nil, nil
+			},
+		},
+		{
+			"wrong-req-body",
+			func(context.Context, string) (*struct{}, error) {
+				return nil, nil
+			},
+		},
+		{
+			"wrong-response",
+			func(context.Context, map[string]interface{}) error {
+				return nil
+			},
+		},
+		{
+			"non-pointer-res",
+			func(context.Context, *struct{}) (struct{}, error) {
+				return struct{}{}, nil
+			},
+		},
+		{
+			"second-return-value-not-error",
+			func(context.Context, *struct{}) (*struct{}, *struct{}) {
+				return nil, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Panics(t, assert.PanicTestFunc(func() {
+			_testCodec.wrapUnaryHandler(tt.Name, tt.Func)
+		}), tt.Name)
+	}
+}
+
+func TestWrapUnaryHandlerValid(t *testing.T) {
+	handlers := []interface{}{
+		func(context.Context, map[string]interface{}) (*struct{}, error) {
+			return nil, nil
+		},
+		func(context.Context, *struct{}) (map[string]interface{}, error) {
+			return nil, nil
+		},
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	for _, h := range handlers {
+		_testCodec.wrapUnaryHandler("test", h)
+	}
+}
+
+func TestWrapOnewayHandlerInvalid(t *testing.T) {
+	tests := []struct {
+		Name string
+		Func interface{}
+	}{
+		{"empty", func() {}},
+		{
+			"return-values",
+			func(context.Context, *struct{}) (*struct{}, error) {
+				return nil, nil
+			},
+		},
+		{
+			"non-error-return",
+			func(context.Context, *struct{}) *struct{} {
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Panics(t, assert.PanicTestFunc(func() {
+			_testCodec.wrapOnewayHandler(tt.Name, tt.Func)
+		}), tt.Name)
+	}
+}
+
+func TestWrapOnewayHandlerValid(t *testing.T) {
+	_testCodec.wrapOnewayHandler("test", func(context.Context, map[string]interface{}) error {
+		return nil
+	})
+}
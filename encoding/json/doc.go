@@ -52,4 +52,22 @@
 //  dispatcher.Register(json.OnewayProcedure("setValue", SetValue))
 //  dispatcher.Register(json.OnewayProcedure("runTask", RunTask))
 //
+// Streaming JSON procedures exchange a sequence of JSON values over a stream.
+// Use StreamProcedure for bidirectional streams, ServerStreamProcedure for
+// handlers that receive one request and send any number of responses, and
+// ClientStreamProcedure for handlers that receive any number of requests and
+// send one response.
+//
+// 	f(stream *json.ServerStream) error
+// 	f(body $reqBody, stream *json.ServerStream) error
+// 	f(stream *json.ServerStream) ($resBody, error)
+//
+// To open streams, build a client with NewStreamClient,
+//
+// 	client := json.NewStreamClient(clientConfig)
+// 	stream, err := client.CallStream(ctx, "watchValues")
+// 	err = stream.Send(&WatchRequest{...})
+// 	var resBody WatchResponse
+// 	err = stream.Receive(&resBody)
+//
 package json
@@ -103,6 +103,29 @@ func (h jsonHandler) HandleOneway(ctx context.Context, treq *transport.Request)
 	return nil
 }
 
+// jsonStreamHandler adapts a JSON stream handler into a transport-level
+// StreamHandler.
+type jsonStreamHandler struct {
+	handle func(*ServerStream) error
+}
+
+func newJSONStreamHandler(handle func(*ServerStream) error) jsonStreamHandler {
+	return jsonStreamHandler{handle: handle}
+}
+
+func (h jsonStreamHandler) HandleStream(stream *transport.ServerStream) error {
+	if err := errors.ExpectEncodings(stream.Request().Meta.ToRequest(), Encoding); err != nil {
+		return err
+	}
+
+	ctx, call := encodingapi.NewInboundCallWithOptions(stream.Context(), encodingapi.DisableResponseHeaders())
+	if err := call.ReadFromRequestMeta(stream.Request().Meta); err != nil {
+		return err
+	}
+
+	return h.handle(newServerStream(ctx, stream))
+}
+
 // requestReader is used to parse a JSON request argument from a JSON decoder.
 type requestReader interface {
 	Read(*json.Decoder) (reflect.Value, error)
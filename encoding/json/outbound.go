@@ -30,6 +30,7 @@ import (
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/pkg/encoding"
 	"go.uber.org/yarpc/pkg/errors"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 // Client makes JSON requests to a single service.
@@ -44,11 +45,28 @@ type Client interface {
 	CallOneway(ctx context.Context, procedure string, reqBody interface{}, opts ...yarpc.CallOption) (transport.Ack, error)
 }
 
+// StreamClient makes JSON requests, including streaming requests, to a single
+// service.
+type StreamClient interface {
+	Client
+
+	// CallStream opens a stream with the given procedure.
+	//
+	// Messages are JSON values sent and received on the returned stream.
+	CallStream(ctx context.Context, procedure string, opts ...yarpc.CallOption) (*ClientStream, error)
+}
+
 // New builds a new JSON client.
 func New(c transport.ClientConfig) Client {
 	return jsonClient{cc: c}
 }
 
+// NewStreamClient builds a new JSON client with streaming. The client
+// configuration must have a stream outbound to open streams.
+func NewStreamClient(c transport.ClientConfig) StreamClient {
+	return jsonClient{cc: c}
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(New)
 }
@@ -125,3 +143,33 @@ func (c jsonClient) CallOneway(ctx context.Context, procedure string, reqBody in
 
 	return c.cc.GetOnewayOutbound().CallOneway(ctx, &treq)
 }
+
+func (c jsonClient) CallStream(ctx context.Context, procedure string, opts ...yarpc.CallOption) (*ClientStream, error) {
+	streamRequest := &transport.StreamRequest{
+		Meta: &transport.RequestMeta{
+			Caller:    c.cc.Caller(),
+			Service:   c.cc.Service(),
+			Procedure: procedure,
+			Encoding:  Encoding,
+		},
+	}
+
+	call, err := encodingapi.NewStreamOutboundCall(encoding.FromOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = call.WriteToRequestMeta(ctx, streamRequest.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundConfig, ok := c.cc.(*transport.OutboundConfig)
+	if !ok || outboundConfig.Outbounds.Stream == nil {
+		return nil, yarpcerrors.InternalErrorf("no stream outbounds for client of service %q", c.cc.Service())
+	}
+	stream, err := outboundConfig.Outbounds.Stream.CallStream(ctx, streamRequest)
+	if err != nil {
+		return nil, err
+	}
+	return newClientStream(stream), nil
+}
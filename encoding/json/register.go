@@ -32,6 +32,7 @@ var (
 	_ctxType            = reflect.TypeOf((*context.Context)(nil)).Elem()
 	_errorType          = reflect.TypeOf((*error)(nil)).Elem()
 	_interfaceEmptyType = reflect.TypeOf((*interface{})(nil)).Elem()
+	_serverStreamType   = reflect.TypeOf((*ServerStream)(nil))
 )
 
 // Register calls the RouteTable's Register method.
@@ -80,6 +81,93 @@ func OnewayProcedure(name string, handler interface{}) []transport.Procedure {
 	}
 }
 
+// StreamProcedure builds a Procedure from the given bidirectional JSON stream
+// handler. handler must be a function with the signature,
+//
+// 	f(stream *json.ServerStream) error
+//
+// The handler receives and sends any number of messages on the stream.
+func StreamProcedure(name string, handler func(*ServerStream) error) []transport.Procedure {
+	return []transport.Procedure{
+		{
+			Name:        name,
+			HandlerSpec: transport.NewStreamHandlerSpec(newJSONStreamHandler(handler)),
+			Encoding:    Encoding,
+		},
+	}
+}
+
+// ServerStreamProcedure builds a Procedure from the given server-streaming
+// JSON handler. handler must be a function with a signature similar to,
+//
+// 	f(body $reqBody, stream *json.ServerStream) error
+//
+// Where $reqBody is a map[string]interface{} or pointer to a struct. The
+// handler receives the first message of the stream as the request body and
+// sends any number of messages on the stream.
+func ServerStreamProcedure(name string, handler interface{}) []transport.Procedure {
+	return []transport.Procedure{
+		{
+			Name: name,
+			HandlerSpec: transport.NewStreamHandlerSpec(
+				wrapServerStreamHandler(name, handler),
+			),
+			Encoding: Encoding,
+		},
+	}
+}
+
+// ClientStreamProcedure builds a Procedure from the given client-streaming
+// JSON handler. handler must be a function with a signature similar to,
+//
+// 	f(stream *json.ServerStream) ($resBody, error)
+//
+// Where $resBody is a map[string]interface{} or pointer to a struct. The
+// handler receives any number of messages on the stream until Receive returns
+// io.EOF, and its result is sent as the only message to the client.
+func ClientStreamProcedure(name string, handler interface{}) []transport.Procedure {
+	return []transport.Procedure{
+		{
+			Name: name,
+			HandlerSpec: transport.NewStreamHandlerSpec(
+				wrapClientStreamHandler(name, handler),
+			),
+			Encoding: Encoding,
+		},
+	}
+}
+
+// wrapServerStreamHandler takes a valid server-streaming JSON handler
+// function and converts it into a transport.StreamHandler.
+func wrapServerStreamHandler(name string, handler interface{}) transport.StreamHandler {
+	reqBodyType := verifyServerStreamSignature(name, reflect.TypeOf(handler))
+	h := reflect.ValueOf(handler)
+	return newJSONStreamHandler(func(stream *ServerStream) error {
+		reqBody := reflect.New(reqBodyType)
+		if err := stream.Receive(reqBody.Interface()); err != nil {
+			return err
+		}
+
+		results := h.Call([]reflect.Value{reqBody.Elem(), reflect.ValueOf(stream)})
+		err, _ := results[0].Interface().(error)
+		return err
+	})
+}
+
+// wrapClientStreamHandler takes a valid client-streaming JSON handler
+// function and converts it into a transport.StreamHandler.
+func wrapClientStreamHandler(name string, handler interface{}) transport.StreamHandler {
+	verifyClientStreamSignature(name, reflect.TypeOf(handler))
+	h := reflect.ValueOf(handler)
+	return newJSONStreamHandler(func(stream *ServerStream) error {
+		results := h.Call([]reflect.Value{reflect.ValueOf(stream)})
+		if appErr, _ := results[1].Interface().(error); appErr != nil {
+			return appErr
+		}
+		return stream.Send(results[0].Interface())
+	})
+}
+
 // wrapUnaryHandler takes a valid JSON handler function and converts it into a
 // transport.UnaryHandler.
 func wrapUnaryHandler(name string, handler interface{}) transport.UnaryHandler {
@@ -167,6 +255,86 @@ func verifyOnewaySignature(n string, t reflect.Type) reflect.Type {
 	return reqBodyType
 }
 
+// verifyServerStreamSignature verifies that the given type matches what we
+// expect from server-streaming JSON handlers and returns the request type.
+func verifyServerStreamSignature(n string, t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Func {
+		panic(fmt.Sprintf(
+			"handler for %q is not a function but a %v", n, t.Kind(),
+		))
+	}
+
+	if t.NumIn() != 2 {
+		panic(fmt.Sprintf(
+			"expected handler for %q to have 2 arguments but it had %v",
+			n, t.NumIn(),
+		))
+	}
+
+	reqBodyType := t.In(0)
+
+	if !isValidReqResType(reqBodyType) {
+		panic(fmt.Sprintf(
+			"the first argument of the handler for %q must be "+
+				"a struct pointer, a map[string]interface{}, or interface{}, and not: %v",
+			n, reqBodyType,
+		))
+	}
+
+	if t.In(1) != _serverStreamType {
+		panic(fmt.Sprintf(
+			"the second argument of the handler for %q must be of type "+
+				"*json.ServerStream, and not: %v", n, t.In(1),
+		))
+	}
+
+	if t.NumOut() != 1 || t.Out(0) != _errorType {
+		panic(fmt.Sprintf(
+			"handler for %q must return only an error", n,
+		))
+	}
+
+	return reqBodyType
+}
+
+// verifyClientStreamSignature verifies that the given type matches what we
+// expect from client-streaming JSON handlers.
+func verifyClientStreamSignature(n string, t reflect.Type) {
+	if t.Kind() != reflect.Func {
+		panic(fmt.Sprintf(
+			"handler for %q is not a function but a %v", n, t.Kind(),
+		))
+	}
+
+	if t.NumIn() != 1 || t.In(0) != _serverStreamType {
+		panic(fmt.Sprintf(
+			"handler for %q must accept only a *json.ServerStream", n,
+		))
+	}
+
+	if t.NumOut() != 2 {
+		panic(fmt.Sprintf(
+			"expected handler for %q to have 2 results but it had %v",
+			n, t.NumOut(),
+		))
+	}
+
+	if t.Out(1) != _errorType {
+		panic(fmt.Sprintf(
+			"handler for %q must return error as its second result, not %v",
+			n, t.Out(1),
+		))
+	}
+
+	if !isValidReqResType(t.Out(0)) {
+		panic(fmt.Sprintf(
+			"the first result of the handler for %q must be "+
+				"a struct pointer, a map[string]interface{}, or interface{}, and not: %v",
+			n, t.Out(0),
+		))
+	}
+}
+
 // verifyInputSignature verifies that the given input argument types match
 // what we expect from JSON handlers and returns the request body type.
 func verifyInputSignature(n string, t reflect.Type) reflect.Type {
@@ -0,0 +1,163 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/pkg/errors"
+)
+
+// ClientStream is a JSON-specific client stream.
+type ClientStream struct {
+	stream *transport.ClientStream
+	reader streamReader
+}
+
+func newClientStream(stream *transport.ClientStream) *ClientStream {
+	return &ClientStream{stream: stream, reader: streamReader{stream: stream}}
+}
+
+// Context returns the context of the stream.
+func (c *ClientStream) Context() context.Context {
+	return c.stream.Context()
+}
+
+// Receive decodes the next JSON message from the stream into bodyOut, which
+// must be a pointer to a value that can be filled with json.Unmarshal.
+//
+// Returns io.EOF once the server closes the stream, and errors from the
+// transport unchanged.
+func (c *ClientStream) Receive(bodyOut interface{}, options ...yarpc.StreamOption) error {
+	return c.reader.read(context.Background(), bodyOut, errors.ResponseBodyDecodeError)
+}
+
+// Send encodes the given body as a JSON message and sends it on the stream.
+func (c *ClientStream) Send(body interface{}, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), c.stream, body, errors.RequestBodyEncodeError)
+}
+
+// Close closes the stream, signaling that the client has no more messages to
+// send.
+func (c *ClientStream) Close(options ...yarpc.StreamOption) error {
+	return c.stream.Close(context.Background())
+}
+
+// ServerStream is a JSON-specific server stream.
+type ServerStream struct {
+	ctx    context.Context
+	stream *transport.ServerStream
+	reader streamReader
+}
+
+func newServerStream(ctx context.Context, stream *transport.ServerStream) *ServerStream {
+	return &ServerStream{ctx: ctx, stream: stream, reader: streamReader{stream: stream}}
+}
+
+// Context returns the context of the stream.
+func (s *ServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// Receive decodes the next JSON message from the stream into bodyOut, which
+// must be a pointer to a value that can be filled with json.Unmarshal.
+//
+// Returns io.EOF once the client closes the stream, and errors from the
+// transport unchanged.
+func (s *ServerStream) Receive(bodyOut interface{}, options ...yarpc.StreamOption) error {
+	return s.reader.read(context.Background(), bodyOut, errors.RequestBodyDecodeError)
+}
+
+// Send encodes the given body as a JSON message and sends it on the stream.
+func (s *ServerStream) Send(body interface{}, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), s.stream, body, errors.ResponseBodyEncodeError)
+}
+
+// streamReader decodes JSON values from the messages of a stream.
+//
+// Each message holds one or more JSON values separated by newlines, so
+// transports that split or coalesce messages still yield whole values.
+type streamReader struct {
+	stream  transport.Stream
+	body    io.ReadCloser
+	decoder *json.Decoder
+}
+
+// read decodes the next JSON value into bodyOut, wrapping failures to decode
+// it with decodeError.
+func (r *streamReader) read(
+	ctx context.Context,
+	bodyOut interface{},
+	decodeError func(*transport.Request, error) error,
+) error {
+	for {
+		if r.decoder == nil {
+			msg, err := r.stream.ReceiveMessage(ctx)
+			if err != nil {
+				return err
+			}
+			if msg.Body == nil {
+				continue
+			}
+			r.body = msg.Body
+			r.decoder = json.NewDecoder(msg.Body)
+		}
+
+		err := r.decoder.Decode(bodyOut)
+		if err == io.EOF {
+			// The current message has no more values.
+			r.closeMessage()
+			continue
+		}
+		if err != nil {
+			r.closeMessage()
+			return decodeError(r.stream.Request().Meta.ToRequest(), err)
+		}
+		return nil
+	}
+}
+
+func (r *streamReader) closeMessage() {
+	r.body.Close()
+	r.body = nil
+	r.decoder = nil
+}
+
+// writeToStream encodes the body as a newline-terminated JSON value and sends
+// it as a single message.
+func writeToStream(
+	ctx context.Context,
+	stream transport.Stream,
+	body interface{},
+	encodeError func(*transport.Request, error) error,
+) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return encodeError(stream.Request().Meta.ToRequest(), err)
+	}
+	return stream.SendMessage(ctx, &transport.StreamMessage{Body: ioutil.NopCloser(&buf)})
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/streamtest"
+	"go.uber.org/yarpc/pkg/errors"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func newPipeClient(t *testing.T, procedures []transport.Procedure) (StreamClient, *streamtest.Outbound) {
+	require.Len(t, procedures, 1)
+	o := streamtest.NewOutbound(procedures[0].HandlerSpec.Stream())
+	return NewStreamClient(&transport.OutboundConfig{
+		CallerName: "caller",
+		Outbounds: transport.Outbounds{
+			ServiceName: "service",
+			Stream:      o,
+		},
+	}), o
+}
+
+type streamItem struct {
+	Value int `json:"value"`
+}
+
+func TestBidirectionalStream(t *testing.T) {
+	client, outbound := newPipeClient(t, StreamProcedure("echo", func(stream *ServerStream) error {
+		for {
+			var item streamItem
+			if err := stream.Receive(&item); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			item.Value *= 2
+			if err := stream.Send(&item); err != nil {
+				return err
+			}
+		}
+	}))
+
+	stream, err := client.CallStream(context.Background(), "echo")
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, stream.Send(&streamItem{Value: i}))
+
+		var item streamItem
+		require.NoError(t, stream.Receive(&item))
+		assert.Equal(t, 2*i, item.Value)
+	}
+
+	require.NoError(t, stream.Close())
+	var item streamItem
+	assert.Equal(t, io.EOF, stream.Receive(&item))
+	assert.NoError(t, outbound.HandlerError())
+}
+
+func TestServerStream(t *testing.T) {
+	client, outbound := newPipeClient(t, ServerStreamProcedure("count",
+		func(req *streamItem, stream *ServerStream) error {
+			for i := 0; i < req.Value; i++ {
+				if err := stream.Send(&streamItem{Value: i}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+
+	stream, err := client.CallStream(context.Background(), "count")
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&streamItem{Value: 3}))
+
+	var values []int
+	for {
+		var item streamItem
+		err := stream.Receive(&item)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		values = append(values, item.Value)
+	}
+	assert.Equal(t, []int{0, 1, 2}, values)
+	assert.NoError(t, outbound.HandlerError())
+}
+
+func TestClientStream(t *testing.T) {
+	client, outbound := newPipeClient(t, ClientStreamProcedure("sum",
+		func(stream *ServerStream) (*streamItem, error) {
+			var sum streamItem
+			for {
+				var item streamItem
+				if err := stream.Receive(&item); err != nil {
+					if err == io.EOF {
+						return &sum, nil
+					}
+					return nil, err
+				}
+				sum.Value += item.Value
+			}
+		}))
+
+	stream, err := client.CallStream(context.Background(), "sum")
+	require.NoError(t, err)
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, stream.Send(&streamItem{Value: i}))
+	}
+	require.NoError(t, stream.Close())
+
+	var sum streamItem
+	require.NoError(t, stream.Receive(&sum))
+	assert.Equal(t, 10, sum.Value)
+	assert.Equal(t, io.EOF, stream.Receive(&sum))
+	assert.NoError(t, outbound.HandlerError())
+}
+
+func TestClientStreamHandlerError(t *testing.T) {
+	client, outbound := newPipeClient(t, ClientStreamProcedure("fail",
+		func(stream *ServerStream) (map[string]interface{}, error) {
+			return nil, yarpcerrors.FailedPreconditionErrorf("not ready")
+		}))
+
+	stream, err := client.CallStream(context.Background(), "fail")
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	err = stream.Receive(&body)
+	require.Error(t, err)
+	assert.Equal(t, yarpcerrors.CodeFailedPrecondition, yarpcerrors.FromError(err).Code(),
+		"must return the error of the handler unchanged")
+	assert.Equal(t, "not ready", yarpcerrors.FromError(err).Message())
+	assert.True(t, yarpcerrors.IsFailedPrecondition(outbound.HandlerError()))
+}
+
+func TestStreamReaderMultipleValuesPerMessage(t *testing.T) {
+	reader := streamReader{stream: streamtest.NewStream(context.Background(), nil,
+		&transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewBufferString(`{"value": 1}` + "\n" + `{"value": 2}`))},
+		&transport.StreamMessage{},
+		&transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewBufferString(`{"value": 3}`))},
+	)}
+	for i := 1; i <= 3; i++ {
+		var item streamItem
+		require.NoError(t, reader.read(context.Background(), &item, errors.RequestBodyDecodeError))
+		assert.Equal(t, i, item.Value)
+	}
+
+	var item streamItem
+	assert.Equal(t, io.EOF, reader.read(context.Background(), &item, errors.RequestBodyDecodeError))
+}
+
+func TestStreamReceiveDecodeError(t *testing.T) {
+	serverStream, err := transport.NewServerStream(streamtest.NewStream(context.Background(),
+		&transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "procedure",
+			Encoding:  Encoding,
+		}},
+		&transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewBufferString(`{"value": "foo"}`))},
+	))
+	require.NoError(t, err)
+
+	var item streamItem
+	err = newServerStream(context.Background(), serverStream).Receive(&item)
+	require.Error(t, err)
+	assert.True(t, yarpcerrors.IsInvalidArgument(err))
+}
+
+func TestStreamHandlerInvalidEncoding(t *testing.T) {
+	serverStream, err := transport.NewServerStream(streamtest.NewStream(context.Background(),
+		&transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "procedure",
+			Encoding:  "raw",
+		}},
+	))
+	require.NoError(t, err)
+
+	handler := newJSONStreamHandler(func(*ServerStream) error {
+		t.Fatal("handler must not be called")
+		return nil
+	})
+	assert.Error(t, handler.HandleStream(serverStream))
+}
+
+func TestNoStreamOutbound(t *testing.T) {
+	client := NewStreamClient(&transport.OutboundConfig{
+		CallerName: "caller",
+		Outbounds:  transport.Outbounds{ServiceName: "service"},
+	})
+
+	_, err := client.CallStream(context.Background(), "procedure")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no stream outbounds")
+}
+
+func TestWrapStreamHandlerInvalid(t *testing.T) {
+	serverStreamTests := []struct {
+		Name string
+		Func interface{}
+	}{
+		{"not-a-function", 0},
+		{"no-stream", func(*struct{}) error { return nil }},
+		{"wrong-req-body", func(string, *ServerStream) error { return nil }},
+		{"wrong-stream", func(*struct{}, *transport.ServerStream) error { return nil }},
+		{"wrong-result", func(*struct{}, *ServerStream) (*struct{}, error) { return nil, nil }},
+	}
+
+	for _, tt := range serverStreamTests {
+		assert.Panics(t, func() { ServerStreamProcedure(tt.Name, tt.Func) }, tt.Name)
+	}
+
+	clientStreamTests := []struct {
+		Name string
+		Func interface{}
+	}{
+		{"not-a-function", 0},
+		{"wrong-args", func(context.Context, *ServerStream) (*struct{}, error) { return nil, nil }},
+		{"only-error", func(*ServerStream) error { return nil }},
+		{"wrong-res-body", func(*ServerStream) (string, error) { return "", nil }},
+		{"second-result-not-error", func(*ServerStream) (*struct{}, string) { return nil, "" }},
+	}
+
+	for _, tt := range clientStreamTests {
+		assert.Panics(t, func() { ClientStreamProcedure(tt.Name, tt.Func) }, tt.Name)
+	}
+}
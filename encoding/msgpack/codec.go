@@ -0,0 +1,47 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package msgpack
+
+import (
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+	"go.uber.org/yarpc/encoding/internal/reflectcodec"
+)
+
+// _codec serializes MessagePack bodies. It is safe for concurrent use.
+var _codec = reflectcodec.New(Encoding, newHandle())
+
+func newHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	// Use the str8 and bin types of the current MessagePack spec.
+	h.WriteExt = true
+	// Decode raw strings into string rather than []byte.
+	h.RawToString = true
+	// Decode maps into map[string]interface{} rather than
+	// map[interface{}]interface{} so that map and interface{} request bodies
+	// match what handlers of other encodings receive.
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	// Struct fields are named by "msgpack" tags, falling back to "codec" and
+	// "json" tags so existing JSON types can be reused.
+	h.TypeInfos = codec.NewTypeInfos([]string{"msgpack", "codec", "json"})
+	return h
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package msgpack provides the MessagePack encoding for YARPC.
+//
+// MessagePack (https://msgpack.org) is a compact binary serialization format
+// with the same data model as JSON.
+//
+// The API of this package mirrors that of the JSON encoding. To make outbound
+// requests using this encoding,
+//
+// 	client := msgpack.New(clientConfig)
+// 	var resBody GetValueResponse
+// 	err := client.Call(ctx, "getValue", &GetValueRequest{...}, &resBody)
+//
+// To register a MessagePack procedure, define functions in the format,
+//
+// 	f(ctx context.Context, body $reqBody) ($resBody, error)
+//
+// Where '$reqBody' and '$resBody' are either pointers to structs representing
+// your request and response objects, or map[string]interface{}.
+//
+// Use the Procedure function to build procedures to register against a
+// Router.
+//
+// 	dispatcher.Register(msgpack.Procedure("getValue", GetValue))
+// 	dispatcher.Register(msgpack.Procedure("setValue", SetValue))
+//
+// Similarly, to register a oneway MessagePack procedure, define functions in
+// the format,
+//
+// 	f(ctx context.Context, body $reqBody) error
+//
+// Where $reqBody is a map[string]interface{} or pointer to a struct.
+//
+// Use the OnewayProcedure function to build procedures to register against a
+// Router.
+//
+// 	dispatcher.Register(msgpack.OnewayProcedure("setValue", SetValue))
+//
+// Struct fields are named by their "msgpack" tags, falling back to "codec"
+// and "json" tags, so types written for the JSON encoding can be reused.
+package msgpack
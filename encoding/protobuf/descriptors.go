@@ -0,0 +1,49 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import "sync"
+
+// _fileDescriptors holds the file descriptors of the services that
+// procedures were built for, by service name.
+var _fileDescriptors = struct {
+	sync.RWMutex
+	services map[string][][]byte
+}{services: make(map[string][][]byte)}
+
+func registerFileDescriptors(serviceName string, fileDescriptors [][]byte) {
+	_fileDescriptors.Lock()
+	defer _fileDescriptors.Unlock()
+	_fileDescriptors.services[serviceName] = fileDescriptors
+}
+
+// FileDescriptors returns the gzipped FileDescriptorProtos of the file
+// declaring the given service and of all files it imports, with the
+// declaring file first.
+//
+// File descriptors are embedded in code generated by protoc-gen-yarpc-go and
+// become available once procedures are built for the service.
+func FileDescriptors(serviceName string) ([][]byte, bool) {
+	_fileDescriptors.RLock()
+	defer _fileDescriptors.RUnlock()
+	fileDescriptors, ok := _fileDescriptors.services[serviceName]
+	return fileDescriptors, ok
+}
@@ -130,7 +130,128 @@
 //     Fire(context.Context, *FireRequest) error
 //   }
 //
-// Except for any ClientOptions (such as UseJSON), the types and functions
-// defined in this package should not be directly used in applications,
-// instead use the code generated from protoc-gen-yarpc-go.
+// The behavior of a method can be declared with the uber.yarpc.method option
+// defined in go.uber.org/yarpc/yarpcproto/options.proto.
+//
+//   import "go.uber.org/yarpc/yarpcproto/options.proto";
+//
+//   service Baz {
+//     rpc Echo(EchoRequest) returns (EchoResponse) {
+//       option (uber.yarpc.method) = {
+//         timeout_ms: 500
+//         idempotent: true
+//         retry: { max_attempts: 3 backoff_ms: 50 }
+//         required_headers: "x-tenant"
+//       };
+//     }
+//   }
+//
+// These options are available to inbound middleware and introspection as the
+// Options of the generated transport.Procedures. Procedures reject requests
+// that are missing a required header, and generated clients use the timeout
+// for calls whose context has no deadline. The idempotent and retry options
+// are informational: calls are not retried, and outbound middleware does not
+// see these options. Methods with the oneway option set are generated as
+// oneway methods.
+//
+// The JSON representation of messages can be configured with JSONOptions,
+// both for clients and for the procedures of a server.
+//
+//   barClient := foo.NewBarYARPCClient(
+//     dispatcher.ClientConfig("myservice"),
+//     protobuf.UseJSON,
+//     protobuf.JSONOptions{OrigName: true, EmitDefaults: true},
+//   )
+//   dispatcher.Register(foo.BuildBarYARPCProcedures(
+//     barServer,
+//     protobuf.JSONOptions{DisallowUnknownFields: true},
+//   ))
+//
+// Generated code embeds the descriptors of the Protobuf file and of the files
+// it imports. Registering the procedures of go.uber.org/yarpc/encoding/protobuf/reflection
+// on a dispatcher lets tools discover its Protobuf services, including gRPC
+// tools that use the gRPC server reflection protocol.
+//
+//   reflection.Register(dispatcher)
+//
+// Procedures can also be called without generated code with a
+// DynamicClient, which converts requests and responses between their JSON
+// representation and the protobuf wire representation using the descriptors
+// of a FileDescriptorSet, like one written by
+// "protoc --include_imports --descriptor_set_out=foo.pb foo.proto".
+//
+//   set, err := protobuf.LoadFileDescriptorSet("foo.pb")
+//   ...
+//   client, err := protobuf.NewDynamicClient(protobuf.DynamicClientParams{
+//     ClientConfig:      dispatcher.ClientConfig("hello"),
+//     FileDescriptorSet: set,
+//   })
+//   ...
+//   response, err := client.Call(ctx, "foo.bar.Baz::Echo", []byte(`{"value":"sample"}`))
+//
+// For a file in the package foo, test helpers are generated in the package
+// footest, next to foo. A gomock-compatible mock client is generated with the
+// name MockBarYARPCClient, and a fake server serving an implementation of
+// BarYARPCServer over an in-process outbound with the name FakeBarYARPCServer.
+//
+//   server, err := footest.NewFakeBarYARPCServer(barServer)
+//   ...
+//   defer server.Stop()
+//   response, err := server.Client().Echo(ctx, &foo.EchoRequest{Value: "sample"})
+//
+// Constraints on the fields of a message can be declared with the
+// uber.yarpc.field option. A Validate method is generated for each message
+// with constraints, or with fields holding such messages, which returns a
+// *ValidationError listing the fields that violate them.
+//
+//   message EchoRequest {
+//     string value = 1 [(uber.yarpc.field) = {
+//       required: true
+//       max_len: 64
+//       pattern: "^[a-z]+$"
+//     }];
+//     int32 count = 2 [(uber.yarpc.field).max = 10];
+//   }
+//
+// Clients and procedures given the ValidateRequests option validate
+// requests, and reject invalid ones with an error with code
+// yarpcerrors.CodeInvalidArgument.
+//
+//   dispatcher.Register(foo.BuildBarYARPCProcedures(
+//     barServer,
+//     protobuf.ValidateRequests,
+//   ))
+//
+// Methods annotated with the google.api.http option can also be served on
+// RESTful URLs. For a file foo.proto, a file foo.pb.http.go is generated with
+// a function BuildBarYARPCHTTPRoutes, which returns the routes to give to an
+// HTTP inbound. Path variables and query parameters are copied into the
+// fields of the JSON request, and the body is mapped as declared by the body
+// and response_body fields of the rule.
+//
+//   service Bar {
+//     rpc Echo(EchoRequest) returns (EchoResponse) {
+//       option (google.api.http) = {
+//         get: "/v1/echo/{value}"
+//       };
+//     }
+//   }
+//
+//   inbound := httpTransport.NewInbound(":8080", http.Routes(foo.BuildBarYARPCHTTPRoutes()...))
+//
+// Clients given the Intercept option run interceptors around each unary
+// call. Interceptors see the request message before it is serialized and the
+// response message after it is deserialized, so they can log, redact or cache
+// calls without handling bytes. Unlike outbound middleware, they are
+// installed on a single client.
+//
+//   barClient := foo.NewBarYARPCClient(
+//     dispatcher.ClientConfig("myservice"),
+//     protobuf.Intercept(logCalls),
+//   )
+//
+// Except for any ClientOptions (such as UseJSON and Intercept), JSONOptions,
+// ValidateRequests, ValidationError and the DynamicClient, the types and
+// functions defined in this package should not be directly used in
+// applications, instead use the code generated from protoc-gen-yarpc-go.
 package protobuf
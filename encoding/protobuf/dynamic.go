@@ -0,0 +1,305 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc"
+	apiencoding "go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf/internal/dynamic"
+	"go.uber.org/yarpc/pkg/encoding"
+	"go.uber.org/yarpc/pkg/errors"
+	"go.uber.org/yarpc/pkg/procedure"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// DynamicClientParams contains the parameters for creating a new
+// DynamicClient.
+type DynamicClientParams struct {
+	ClientConfig transport.ClientConfig
+
+	// FileDescriptorSet holds the files declaring the services to call and
+	// all of their dependencies. Use LoadFileDescriptorSet or
+	// RegisteredFileDescriptorSet to obtain one.
+	FileDescriptorSet *descriptor.FileDescriptorSet
+
+	Options []ClientOption
+}
+
+// DynamicClient calls protobuf procedures without generated code.
+//
+// Requests and responses are given and returned in their JSON
+// representation, and converted to and from the protobuf wire representation
+// with the descriptors of the procedures' messages. Calls are made with the
+// Encoding, or the JSONEncoding if the UseJSON option is given.
+type DynamicClient struct {
+	client   *client
+	registry *dynamic.Registry
+}
+
+// NewDynamicClient creates a new DynamicClient.
+func NewDynamicClient(params DynamicClientParams) (*DynamicClient, error) {
+	if params.FileDescriptorSet == nil {
+		return nil, fmt.Errorf("a FileDescriptorSet is required")
+	}
+	registry, err := dynamic.NewRegistry(params.FileDescriptorSet.File)
+	if err != nil {
+		return nil, err
+	}
+	return &DynamicClient{
+		client:   newClient("", params.ClientConfig, params.Options...),
+		registry: registry,
+	}, nil
+}
+
+// Procedures returns the names of all procedures declared in the
+// FileDescriptorSet of the client, in lexical order.
+func (c *DynamicClient) Procedures() []string {
+	var names []string
+	for _, serviceName := range c.registry.Services() {
+		service, _ := c.registry.Service(serviceName)
+		for _, method := range service.Methods {
+			names = append(names, procedure.ToName(serviceName, method.Name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Call calls the unary procedure with the given name, like
+// "uber.foo.Bar::Baz", with a request in its JSON representation, and
+// returns the JSON representation of the response.
+//
+// As with generated clients, a response is returned along with an
+// application error if the server provided one.
+func (c *DynamicClient) Call(
+	ctx context.Context,
+	procedureName string,
+	request []byte,
+	options ...yarpc.CallOption,
+) ([]byte, error) {
+	method, err := c.method(procedureName, false)
+	if err != nil {
+		return nil, err
+	}
+	ctx, call, transportRequest, err := c.buildTransportRequest(ctx, procedureName, method, request, options)
+	if err != nil {
+		return nil, err
+	}
+	unaryOutbound := c.client.outboundConfig.Outbounds.Unary
+	if unaryOutbound == nil {
+		return nil, yarpcerrors.InternalErrorf("no unary outbounds for OutboundConfig %s", c.client.outboundConfig.CallerName)
+	}
+	transportResponse, appErr := unaryOutbound.Call(ctx, transportRequest)
+	if transportResponse == nil {
+		return nil, appErr
+	}
+	if transportResponse.Body != nil {
+		defer transportResponse.Body.Close()
+	}
+	if _, err := call.ReadFromResponse(ctx, transportResponse); err != nil {
+		return nil, err
+	}
+	if transportResponse.Body == nil {
+		return nil, appErr
+	}
+
+	responseData, err := ioutil.ReadAll(transportResponse.Body)
+	if err != nil {
+		return nil, errors.ResponseBodyDecodeError(transportRequest, err)
+	}
+	if transportRequest.Encoding == JSONEncoding {
+		// Normalize the response to the same JSON representation as with the
+		// protobuf encoding.
+		if responseData, err = c.registry.JSONToWire(method.OutputType, responseData); err != nil {
+			return nil, errors.ResponseBodyDecodeError(transportRequest, err)
+		}
+	}
+	response, err := c.registry.WireToJSON(method.OutputType, responseData)
+	if err != nil {
+		return nil, errors.ResponseBodyDecodeError(transportRequest, err)
+	}
+	return response, appErr
+}
+
+// CallOneway calls the oneway procedure with the given name, like
+// "uber.foo.Bar::Baz", with a request in its JSON representation.
+func (c *DynamicClient) CallOneway(
+	ctx context.Context,
+	procedureName string,
+	request []byte,
+	options ...yarpc.CallOption,
+) (transport.Ack, error) {
+	method, err := c.method(procedureName, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, transportRequest, err := c.buildTransportRequest(ctx, procedureName, method, request, options)
+	if err != nil {
+		return nil, err
+	}
+	onewayOutbound := c.client.outboundConfig.Outbounds.Oneway
+	if onewayOutbound == nil {
+		return nil, yarpcerrors.InternalErrorf("no oneway outbounds for OutboundConfig %s", c.client.outboundConfig.CallerName)
+	}
+	return onewayOutbound.CallOneway(ctx, transportRequest)
+}
+
+func (c *DynamicClient) method(procedureName string, oneway bool) (*dynamic.Method, error) {
+	serviceName, methodName := procedure.FromName(procedureName)
+	service, ok := c.registry.Service(serviceName)
+	if !ok {
+		return nil, yarpcerrors.InvalidArgumentErrorf("unknown service %q", serviceName)
+	}
+	method, ok := service.Method(methodName)
+	if !ok {
+		return nil, yarpcerrors.InvalidArgumentErrorf("unknown method %q of service %q", methodName, serviceName)
+	}
+	if method.ClientStreaming || method.ServerStreaming {
+		return nil, yarpcerrors.InvalidArgumentErrorf("procedure %q is a streaming procedure", procedureName)
+	}
+	if method.Oneway != oneway {
+		if method.Oneway {
+			return nil, yarpcerrors.InvalidArgumentErrorf("procedure %q is a oneway procedure", procedureName)
+		}
+		return nil, yarpcerrors.InvalidArgumentErrorf("procedure %q is not a oneway procedure", procedureName)
+	}
+	return method, nil
+}
+
+func (c *DynamicClient) buildTransportRequest(
+	ctx context.Context,
+	procedureName string,
+	method *dynamic.Method,
+	request []byte,
+	options []yarpc.CallOption,
+) (context.Context, *apiencoding.OutboundCall, *transport.Request, error) {
+	transportRequest := &transport.Request{
+		Caller:    c.client.outboundConfig.CallerName,
+		Service:   c.client.outboundConfig.Outbounds.ServiceName,
+		Procedure: procedureName,
+		Encoding:  c.client.encoding,
+	}
+	call := apiencoding.NewOutboundCall(encoding.FromOptions(options)...)
+	ctx, err := call.WriteToRequest(ctx, transportRequest)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if transportRequest.Encoding != Encoding && transportRequest.Encoding != JSONEncoding {
+		return nil, nil, nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "can only use encodings %q or %q, but %q was specified", Encoding, JSONEncoding, transportRequest.Encoding)
+	}
+
+	// The request is converted even with the JSON encoding so that it is
+	// validated against the descriptors before it is sent.
+	requestData, err := c.registry.JSONToWire(method.InputType, request)
+	if err != nil {
+		return nil, nil, nil, errors.RequestBodyEncodeError(transportRequest, err)
+	}
+	if transportRequest.Encoding == JSONEncoding {
+		if requestData, err = c.registry.WireToJSON(method.InputType, requestData); err != nil {
+			return nil, nil, nil, errors.RequestBodyEncodeError(transportRequest, err)
+		}
+	}
+	transportRequest.Body = bytes.NewReader(requestData)
+	return ctx, call, transportRequest, nil
+}
+
+// LoadFileDescriptorSet reads a serialized FileDescriptorSet from a file, as
+// written by "protoc --include_imports --descriptor_set_out".
+func LoadFileDescriptorSet(path string) (*descriptor.FileDescriptorSet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptor.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to read FileDescriptorSet from %q: %v", path, err)
+	}
+	return &set, nil
+}
+
+// RegisteredFileDescriptorSet builds a FileDescriptorSet from the
+// descriptors that generated code registered with the protobuf runtime
+// for the given files, like "uber/foo/bar.proto", and all files they import.
+func RegisteredFileDescriptorSet(filenames ...string) (*descriptor.FileDescriptorSet, error) {
+	var (
+		set  descriptor.FileDescriptorSet
+		seen = make(map[string]struct{})
+		add  func(string) error
+	)
+	add = func(filename string) error {
+		if _, ok := seen[filename]; ok {
+			return nil
+		}
+		seen[filename] = struct{}{}
+
+		file, err := registeredFileDescriptor(filename)
+		if err != nil {
+			return err
+		}
+		// Dependencies precede the files that import them, as in sets
+		// written by protoc.
+		for _, dependency := range file.Dependency {
+			if err := add(dependency); err != nil {
+				return err
+			}
+		}
+		set.File = append(set.File, file)
+		return nil
+	}
+
+	for _, filename := range filenames {
+		if err := add(filename); err != nil {
+			return nil, err
+		}
+	}
+	return &set, nil
+}
+
+func registeredFileDescriptor(filename string) (*descriptor.FileDescriptorProto, error) {
+	compressed := proto.FileDescriptor(filename)
+	if compressed == nil {
+		return nil, fmt.Errorf("no descriptor registered for file %q", filename)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor of file %q: %v", filename, err)
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor of file %q: %v", filename, err)
+	}
+	var file descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to read descriptor of file %q: %v", filename, err)
+	}
+	return &file, nil
+}
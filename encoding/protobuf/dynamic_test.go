@@ -0,0 +1,312 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+func dynamicTestFileDescriptorSet(t *testing.T) *descriptor.FileDescriptorSet {
+	set, err := RegisteredFileDescriptorSet("google/protobuf/struct.proto")
+	require.NoError(t, err)
+
+	stringField := func(name string, number int32) *descriptor.FieldDescriptorProto {
+		return &descriptor.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   descriptor.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+	}
+	onewayOptions := &descriptor.MethodOptions{}
+	require.NoError(t, proto.SetExtension(onewayOptions, yarpcproto.E_Method, &yarpcproto.MethodOptions{Oneway: true}))
+
+	set.File = append(set.File, &descriptor.FileDescriptorProto{
+		Name:       proto.String("test/kv.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		MessageType: []*descriptor.DescriptorProto{
+			{Name: proto.String("GetRequest"), Field: []*descriptor.FieldDescriptorProto{stringField("key", 1)}},
+			{Name: proto.String("GetResponse"), Field: []*descriptor.FieldDescriptorProto{stringField("value", 1)}},
+			{Name: proto.String("Oneway")},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{
+			{
+				Name: proto.String("KeyValue"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{Name: proto.String("Get"), InputType: proto.String(".test.GetRequest"), OutputType: proto.String(".test.GetResponse")},
+					{Name: proto.String("Describe"), InputType: proto.String(".google.protobuf.Struct"), OutputType: proto.String(".google.protobuf.Struct")},
+					{Name: proto.String("Watch"), InputType: proto.String(".test.GetRequest"), OutputType: proto.String(".test.GetResponse"), ServerStreaming: proto.Bool(true)},
+				},
+			},
+			{
+				Name: proto.String("Sink"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{Name: proto.String("Fire"), InputType: proto.String(".test.GetRequest"), OutputType: proto.String(".uber.yarpc.Oneway")},
+					{Name: proto.String("Notify"), InputType: proto.String(".test.GetRequest"), OutputType: proto.String(".test.GetResponse"), Options: onewayOptions},
+				},
+			},
+		},
+	})
+	return set
+}
+
+func newDynamicTestClient(t *testing.T, outbounds transport.Outbounds, opts ...ClientOption) *DynamicClient {
+	outbounds.ServiceName = "kv"
+	client, err := NewDynamicClient(DynamicClientParams{
+		ClientConfig:      &transport.OutboundConfig{CallerName: "caller", Outbounds: outbounds},
+		FileDescriptorSet: dynamicTestFileDescriptorSet(t),
+		Options:           opts,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestDynamicClientProcedures(t *testing.T) {
+	client := newDynamicTestClient(t, transport.Outbounds{})
+	assert.Equal(t, []string{
+		"test.KeyValue::Describe",
+		"test.KeyValue::Get",
+		"test.KeyValue::Watch",
+		"test.Sink::Fire",
+		"test.Sink::Notify",
+	}, client.Procedures())
+}
+
+func TestDynamicClientCall(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	requestData, err := proto.Marshal(&types.StringValue{Value: "foo"})
+	require.NoError(t, err)
+	responseData, err := proto.Marshal(&types.StringValue{Value: "bar"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		msg          string
+		opts         []ClientOption
+		wantEncoding transport.Encoding
+		wantRequest  []byte
+		giveResponse []byte
+	}{
+		{
+			msg:          "proto",
+			wantEncoding: Encoding,
+			wantRequest:  requestData,
+			giveResponse: responseData,
+		},
+		{
+			msg:          "json",
+			opts:         []ClientOption{UseJSON},
+			wantEncoding: JSONEncoding,
+			wantRequest:  []byte(`{"key":"foo"}`),
+			giveResponse: []byte(`{"value":"bar"}`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+			client := newDynamicTestClient(t, transport.Outbounds{Unary: outbound}, tt.opts...)
+
+			outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, req *transport.Request) (*transport.Response, error) {
+					assert.Equal(t, "caller", req.Caller)
+					assert.Equal(t, "kv", req.Service)
+					assert.Equal(t, "test.KeyValue::Get", req.Procedure)
+					assert.Equal(t, tt.wantEncoding, req.Encoding)
+					body, err := ioutil.ReadAll(req.Body)
+					require.NoError(t, err)
+					assert.Equal(t, tt.wantRequest, body)
+					return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader(tt.giveResponse))}, nil
+				})
+
+			response, err := client.Call(context.Background(), "test.KeyValue::Get", []byte(`{"key": "foo"}`))
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"value":"bar"}`, string(response))
+		})
+	}
+}
+
+func TestDynamicClientCallWellKnownTypes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newDynamicTestClient(t, transport.Outbounds{Unary: outbound})
+
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *transport.Request) (*transport.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			var s types.Struct
+			require.NoError(t, proto.Unmarshal(body, &s))
+			assert.Equal(t, "b", s.Fields["a"].GetStringValue())
+
+			s.Fields["c"] = &types.Value{Kind: &types.Value_BoolValue{BoolValue: true}}
+			body, err = proto.Marshal(&s)
+			require.NoError(t, err)
+			return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+		})
+
+	response, err := client.Call(context.Background(), "test.KeyValue::Describe", []byte(`{"a":"b"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":"b","c":true}`, string(response))
+}
+
+func TestDynamicClientCallApplicationError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newDynamicTestClient(t, transport.Outbounds{Unary: outbound})
+
+	appErr := yarpcerrors.NotFoundErrorf("no value for key")
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).Return(&transport.Response{ApplicationError: true}, appErr)
+
+	response, err := client.Call(context.Background(), "test.KeyValue::Get", []byte(`{}`))
+	assert.Nil(t, response)
+	assert.Equal(t, appErr, err)
+}
+
+func TestDynamicClientCallOneway(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockOnewayOutbound(mockCtrl)
+	client := newDynamicTestClient(t, transport.Outbounds{Oneway: outbound})
+
+	// Methods are oneway if they return uber.yarpc.Oneway or set the oneway
+	// method option.
+	for _, procedure := range []string{"test.Sink::Fire", "test.Sink::Notify"} {
+		outbound.EXPECT().CallOneway(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *transport.Request) (transport.Ack, error) {
+				assert.Equal(t, procedure, req.Procedure)
+				return nil, nil
+			})
+
+		_, err := client.CallOneway(context.Background(), procedure, []byte(`{"key":"foo"}`))
+		assert.NoError(t, err, procedure)
+	}
+}
+
+func TestDynamicClientErrors(t *testing.T) {
+	client := newDynamicTestClient(t, transport.Outbounds{})
+
+	tests := []struct {
+		msg       string
+		procedure string
+		request   string
+		oneway    bool
+		wantCode  yarpcerrors.Code
+	}{
+		{msg: "unknown service", procedure: "test.Unknown::Get", wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "unknown method", procedure: "test.KeyValue::Put", wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "streaming", procedure: "test.KeyValue::Watch", wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "oneway with Call", procedure: "test.Sink::Fire", wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "oneway option with Call", procedure: "test.Sink::Notify", wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "unary with CallOneway", procedure: "test.KeyValue::Get", oneway: true, wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "invalid request", procedure: "test.KeyValue::Get", request: `{"unknown":1}`, wantCode: yarpcerrors.CodeInvalidArgument},
+		{msg: "no unary outbound", procedure: "test.KeyValue::Get", wantCode: yarpcerrors.CodeInternal},
+		{msg: "no oneway outbound", procedure: "test.Sink::Fire", oneway: true, wantCode: yarpcerrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			request := tt.request
+			if request == "" {
+				request = "{}"
+			}
+
+			var err error
+			if tt.oneway {
+				_, err = client.CallOneway(context.Background(), tt.procedure, []byte(request))
+			} else {
+				_, err = client.Call(context.Background(), tt.procedure, []byte(request))
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, yarpcerrors.FromError(err).Code())
+		})
+	}
+}
+
+func TestNewDynamicClientErrors(t *testing.T) {
+	_, err := NewDynamicClient(DynamicClientParams{ClientConfig: &transport.OutboundConfig{}})
+	assert.Error(t, err)
+
+	set := dynamicTestFileDescriptorSet(t)
+	duplicate := *set.File[len(set.File)-1]
+	duplicate.Name = proto.String("test/other.proto")
+	set.File = append(set.File, &duplicate)
+	_, err = NewDynamicClient(DynamicClientParams{ClientConfig: &transport.OutboundConfig{}, FileDescriptorSet: set})
+	assert.Error(t, err)
+}
+
+func TestRegisteredFileDescriptorSet(t *testing.T) {
+	set, err := RegisteredFileDescriptorSet("google/protobuf/struct.proto", "google/protobuf/struct.proto")
+	require.NoError(t, err)
+	require.Len(t, set.File, 1)
+	assert.Equal(t, "google.protobuf", set.File[0].GetPackage())
+
+	_, err = RegisteredFileDescriptorSet("does/not/exist.proto")
+	assert.Error(t, err)
+}
+
+func TestLoadFileDescriptorSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yarpc-protobuf")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	want := dynamicTestFileDescriptorSet(t)
+	data, err := proto.Marshal(want)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "set.pb")
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+	got, err := LoadFileDescriptorSet(path)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(want, got))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a descriptor set"), 0644))
+	_, err = LoadFileDescriptorSet(path)
+	assert.Error(t, err)
+
+	_, err = LoadFileDescriptorSet(filepath.Join(dir, "missing.pb"))
+	assert.Error(t, err)
+}
@@ -32,17 +32,20 @@ import (
 type unaryHandler struct {
 	handle     func(context.Context, proto.Message) (proto.Message, error)
 	newRequest func() proto.Message
+	codec      *codec
+
+	validateRequests bool
 }
 
 func newUnaryHandler(
 	handle func(context.Context, proto.Message) (proto.Message, error),
 	newRequest func() proto.Message,
 ) *unaryHandler {
-	return &unaryHandler{handle, newRequest}
+	return &unaryHandler{handle: handle, newRequest: newRequest, codec: _defaultCodec}
 }
 
 func (u *unaryHandler) Handle(ctx context.Context, transportRequest *transport.Request, responseWriter transport.ResponseWriter) error {
-	ctx, call, request, err := getProtoRequest(ctx, transportRequest, u.newRequest)
+	ctx, call, request, err := getProtoRequest(ctx, transportRequest, u.codec, u.newRequest, u.validateRequests)
 	if err != nil {
 		return err
 	}
@@ -55,7 +58,7 @@ func (u *unaryHandler) Handle(ctx context.Context, transportRequest *transport.R
 	var responseData []byte
 	var responseCleanup func()
 	if response != nil {
-		responseData, responseCleanup, err = marshal(transportRequest.Encoding, response)
+		responseData, responseCleanup, err = u.codec.marshal(transportRequest.Encoding, response)
 		if responseCleanup != nil {
 			defer responseCleanup()
 		}
@@ -76,17 +79,20 @@ func (u *unaryHandler) Handle(ctx context.Context, transportRequest *transport.R
 type onewayHandler struct {
 	handleOneway func(context.Context, proto.Message) error
 	newRequest   func() proto.Message
+	codec        *codec
+
+	validateRequests bool
 }
 
 func newOnewayHandler(
 	handleOneway func(context.Context, proto.Message) error,
 	newRequest func() proto.Message,
 ) *onewayHandler {
-	return &onewayHandler{handleOneway, newRequest}
+	return &onewayHandler{handleOneway: handleOneway, newRequest: newRequest, codec: _defaultCodec}
 }
 
 func (o *onewayHandler) HandleOneway(ctx context.Context, transportRequest *transport.Request) error {
-	ctx, _, request, err := getProtoRequest(ctx, transportRequest, o.newRequest)
+	ctx, _, request, err := getProtoRequest(ctx, transportRequest, o.codec, o.newRequest, o.validateRequests)
 	if err != nil {
 		return err
 	}
@@ -95,10 +101,13 @@ func (o *onewayHandler) HandleOneway(ctx context.Context, transportRequest *tran
 
 type streamHandler struct {
 	handle func(*ServerStream) error
+	codec  *codec
+
+	validateRequests bool
 }
 
 func newStreamHandler(handle func(*ServerStream) error) *streamHandler {
-	return &streamHandler{handle}
+	return &streamHandler{handle: handle, codec: _defaultCodec}
 }
 
 func (s *streamHandler) HandleStream(stream *transport.ServerStream) error {
@@ -109,11 +118,14 @@ func (s *streamHandler) HandleStream(stream *transport.ServerStream) error {
 	protoStream := &ServerStream{
 		ctx:    ctx,
 		stream: stream,
+		codec:  s.codec,
+
+		validateRequests: s.validateRequests,
 	}
 	return s.handle(protoStream)
 }
 
-func getProtoRequest(ctx context.Context, transportRequest *transport.Request, newRequest func() proto.Message) (context.Context, *apiencoding.InboundCall, proto.Message, error) {
+func getProtoRequest(ctx context.Context, transportRequest *transport.Request, codec *codec, newRequest func() proto.Message, validate bool) (context.Context, *apiencoding.InboundCall, proto.Message, error) {
 	if err := errors.ExpectEncodings(transportRequest, Encoding, JSONEncoding); err != nil {
 		return nil, nil, nil, err
 	}
@@ -122,8 +134,49 @@ func getProtoRequest(ctx context.Context, transportRequest *transport.Request, n
 		return nil, nil, nil, err
 	}
 	request := newRequest()
-	if err := unmarshal(transportRequest.Encoding, transportRequest.Body, request); err != nil {
+	if err := codec.unmarshal(transportRequest.Encoding, transportRequest.Body, request); err != nil {
 		return nil, nil, nil, errors.RequestBodyDecodeError(transportRequest, err)
 	}
+	if validate {
+		if err := validateMessage(request); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 	return ctx, call, request, nil
 }
+
+// withUnaryCodec returns a copy of a handler built by NewUnaryHandler using
+// the given codec. Other handlers are returned as-is.
+func withUnaryCodec(handler transport.UnaryHandler, codec *codec) transport.UnaryHandler {
+	u, ok := handler.(*unaryHandler)
+	if !ok {
+		return handler
+	}
+	withCodec := *u
+	withCodec.codec = codec
+	return &withCodec
+}
+
+// withOnewayCodec returns a copy of a handler built by NewOnewayHandler using
+// the given codec. Other handlers are returned as-is.
+func withOnewayCodec(handler transport.OnewayHandler, codec *codec) transport.OnewayHandler {
+	o, ok := handler.(*onewayHandler)
+	if !ok {
+		return handler
+	}
+	withCodec := *o
+	withCodec.codec = codec
+	return &withCodec
+}
+
+// withStreamCodec returns a copy of a handler built by NewStreamHandler using
+// the given codec. Other handlers are returned as-is.
+func withStreamCodec(handler transport.StreamHandler, codec *codec) transport.StreamHandler {
+	s, ok := handler.(*streamHandler)
+	if !ok {
+		return handler
+	}
+	withCodec := *s
+	withCodec.codec = codec
+	return &withCodec
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/yarpc"
+)
+
+// UnaryInvoker sends a request to a unary method of the service and returns
+// its response.
+type UnaryInvoker func(
+	ctx context.Context,
+	requestMethodName string,
+	request proto.Message,
+	newResponse func() proto.Message,
+	options ...yarpc.CallOption,
+) (proto.Message, error)
+
+// UnaryInterceptor intercepts the unary calls made by a client. It sees the
+// request message before it is serialized and the response message after it
+// is deserialized, and calls invoke to send the request.
+//
+// Interceptors may modify or replace the request and the response, or return
+// a response without calling invoke at all, for example from a cache. Use
+// newResponse to build a response of the type expected by the generated
+// client.
+type UnaryInterceptor func(
+	ctx context.Context,
+	requestMethodName string,
+	request proto.Message,
+	newResponse func() proto.Message,
+	invoke UnaryInvoker,
+	options ...yarpc.CallOption,
+) (proto.Message, error)
+
+// Intercept installs interceptors on the unary calls of a client. The first
+// interceptor is the outermost one: it sees the request first and the
+// response last. Interceptors given with multiple Intercept options run in
+// the order of the options.
+//
+// Unlike outbound middleware, which is installed on the outbounds of a
+// dispatcher and sees serialized requests, interceptors are installed on a
+// single client and see the typed messages of the generated code.
+//
+// 	client := examplepb.NewKeyValueYARPCClient(
+// 		dispatcher.ClientConfig("keyvalue"),
+// 		protobuf.Intercept(logRequests),
+// 	)
+func Intercept(interceptors ...UnaryInterceptor) ClientOption {
+	return intercept(interceptors)
+}
+
+type intercept []UnaryInterceptor
+
+func (i intercept) apply(client *client) {
+	client.interceptors = append(client.interceptors, i...)
+}
+
+// chainUnaryInterceptors returns an UnaryInvoker that runs the given
+// interceptors before invoke.
+func chainUnaryInterceptors(interceptors []UnaryInterceptor, invoke UnaryInvoker) UnaryInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(
+			ctx context.Context,
+			requestMethodName string,
+			request proto.Message,
+			newResponse func() proto.Message,
+			options ...yarpc.CallOption,
+		) (proto.Message, error) {
+			return interceptor(ctx, requestMethodName, request, newResponse, next, options...)
+		}
+	}
+	return invoke
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+)
+
+func newStringValue() proto.Message { return &types.StringValue{} }
+
+func TestClientIntercept(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var calls []string
+	record := func(name string) UnaryInterceptor {
+		return func(
+			ctx context.Context,
+			requestMethodName string,
+			request proto.Message,
+			newResponse func() proto.Message,
+			invoke UnaryInvoker,
+			options ...yarpc.CallOption,
+		) (proto.Message, error) {
+			calls = append(calls, name+" "+requestMethodName+" "+request.(*types.StringValue).Value)
+			response, err := invoke(ctx, requestMethodName, request, newResponse, options...)
+			if err == nil {
+				calls = append(calls, name+" got "+response.(*types.StringValue).Value)
+			}
+			return response, err
+		}
+	}
+	redact := func(
+		ctx context.Context,
+		requestMethodName string,
+		request proto.Message,
+		newResponse func() proto.Message,
+		invoke UnaryInvoker,
+		options ...yarpc.CallOption,
+	) (proto.Message, error) {
+		return invoke(ctx, requestMethodName, &types.StringValue{Value: "redacted"}, newResponse, options...)
+	}
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newClientFromParams(ClientParams{
+		ServiceName: "foo",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds:  transport.Outbounds{ServiceName: "service", Unary: outbound},
+		},
+		Options: []ClientOption{
+			Intercept(record("first"), record("second")),
+			Intercept(redact),
+		},
+	})
+
+	responseBody, err := proto.Marshal(&types.StringValue{Value: "world"})
+	require.NoError(t, err)
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *transport.Request) (*transport.Response, error) {
+			assert.Equal(t, "foo::bar", request.Procedure)
+			var value types.StringValue
+			body, err := ioutil.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(body, &value))
+			assert.Equal(t, "redacted", value.Value)
+			return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader(responseBody))}, nil
+		})
+
+	response, err := client.Call(context.Background(), "bar", &types.StringValue{Value: "hello"}, newStringValue)
+	require.NoError(t, err)
+	assert.Equal(t, &types.StringValue{Value: "world"}, response)
+	assert.Equal(t, []string{
+		"first bar hello",
+		"second bar hello",
+		"second got world",
+		"first got world",
+	}, calls)
+}
+
+func TestClientInterceptWithoutInvoke(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// The outbound expects no calls.
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newClientFromParams(ClientParams{
+		ServiceName: "foo",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds:  transport.Outbounds{ServiceName: "service", Unary: outbound},
+		},
+		Options: []ClientOption{
+			Intercept(func(
+				ctx context.Context,
+				requestMethodName string,
+				request proto.Message,
+				newResponse func() proto.Message,
+				invoke UnaryInvoker,
+				options ...yarpc.CallOption,
+			) (proto.Message, error) {
+				response := newResponse().(*types.StringValue)
+				response.Value = "cached"
+				return response, nil
+			}),
+		},
+	})
+
+	response, err := client.Call(context.Background(), "bar", &types.StringValue{Value: "hello"}, newStringValue)
+	require.NoError(t, err)
+	assert.Equal(t, &types.StringValue{Value: "cached"}, response)
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// WireToJSON converts the protobuf wire representation of a message of the
+// named type into its JSON representation.
+//
+// The JSON representation is the one defined by the protobuf JSON mapping:
+// fields are named by their JSON names, fields that are not present are
+// omitted, enums are named, 64-bit integers are quoted and bytes are base64
+// encoded. Unknown fields are ignored.
+func (r *Registry) WireToJSON(messageName string, data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if err := r.writeMessage(&out, messageName, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (r *Registry) writeMessage(out *bytes.Buffer, name string, data []byte) error {
+	if wkt, ok := _wellKnownTypes[name]; ok {
+		return wkt.decode(r, out, data)
+	}
+
+	m, err := r.message(name)
+	if err != nil {
+		return err
+	}
+	fields, err := parseWire(data)
+	if err != nil {
+		return err
+	}
+
+	out.WriteByte('{')
+	first := true
+	for _, f := range m.fields {
+		values, ok := fields[f.number]
+		if !ok {
+			continue
+		}
+		if !first {
+			out.WriteByte(',')
+		}
+		first = false
+
+		writeString(out, f.jsonName)
+		out.WriteByte(':')
+		if err := r.writeField(out, f, values); err != nil {
+			return fmt.Errorf("field %q: %v", f.jsonName, err)
+		}
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+func (r *Registry) writeField(out *bytes.Buffer, f *field, values []wireValue) error {
+	if r.isMapField(f) {
+		return r.writeMap(out, f, values)
+	}
+
+	if f.repeated {
+		values, err := unpack(f.typ, values)
+		if err != nil {
+			return err
+		}
+		out.WriteByte('[')
+		for i, v := range values {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := r.writeValue(out, f.typ, f.typeName, v); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+	}
+
+	if f.typ == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		// Repeated occurrences of a message field are merged, which is
+		// equivalent to decoding their concatenation.
+		var merged []byte
+		for _, v := range values {
+			if v.wireType != proto.WireBytes {
+				return fmt.Errorf("unexpected wire type %d", v.wireType)
+			}
+			merged = append(merged, v.b...)
+		}
+		return r.writeMessage(out, f.typeName, merged)
+	}
+
+	// The last occurrence of a scalar field wins.
+	return r.writeValue(out, f.typ, f.typeName, values[len(values)-1])
+}
+
+// writeMap writes the entries of a map field as a JSON object, ordered by
+// key.
+func (r *Registry) writeMap(out *bytes.Buffer, f *field, values []wireValue) error {
+	entry := r.messages[f.typeName]
+	keyField, valueField := entry.byNumber[1], entry.byNumber[2]
+	if keyField == nil || valueField == nil {
+		return fmt.Errorf("invalid map entry message %q", f.typeName)
+	}
+
+	entries := make(map[string][]byte, len(values))
+	for _, v := range values {
+		if v.wireType != proto.WireBytes {
+			return fmt.Errorf("unexpected wire type %d", v.wireType)
+		}
+		fields, err := parseWire(v.b)
+		if err != nil {
+			return err
+		}
+
+		var key bytes.Buffer
+		if keys := fields[1]; len(keys) > 0 {
+			if err := r.writeValue(&key, keyField.typ, keyField.typeName, keys[len(keys)-1]); err != nil {
+				return err
+			}
+		} else {
+			writeDefault(&key, keyField.typ)
+		}
+
+		var value bytes.Buffer
+		if values := fields[2]; len(values) > 0 {
+			if err := r.writeField(&value, valueField, values); err != nil {
+				return err
+			}
+		} else if valueField.typ == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			if err := r.writeMessage(&value, valueField.typeName, nil); err != nil {
+				return err
+			}
+		} else if valueField.typ == descriptor.FieldDescriptorProto_TYPE_ENUM {
+			if err := r.writeValue(&value, valueField.typ, valueField.typeName, wireValue{}); err != nil {
+				return err
+			}
+		} else {
+			writeDefault(&value, valueField.typ)
+		}
+
+		// Keys are JSON strings, so keys that are written as JSON strings
+		// are unquoted first.
+		k := key.String()
+		if unquoted, err := strconv.Unquote(k); err == nil {
+			k = unquoted
+		}
+		entries[k] = value.Bytes()
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		writeString(out, k)
+		out.WriteByte(':')
+		out.Write(entries[k])
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+// writeValue writes a single value of the given type.
+func (r *Registry) writeValue(out *bytes.Buffer, t descriptor.FieldDescriptorProto_Type, typeName string, v wireValue) error {
+	if want := wireType(t); v.wireType != want {
+		return fmt.Errorf("unexpected wire type %d for a field of type %v", v.wireType, t)
+	}
+
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		writeFloat(out, math.Float64frombits(v.x), 64)
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		writeFloat(out, float64(math.Float32frombits(uint32(v.x))), 32)
+	case descriptor.FieldDescriptorProto_TYPE_INT32:
+		out.WriteString(strconv.FormatInt(int64(int32(v.x)), 10))
+	case descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		out.WriteString(strconv.FormatInt(int64(int32(uint32(v.x))), 10))
+	case descriptor.FieldDescriptorProto_TYPE_SINT32:
+		x := uint32(v.x)
+		out.WriteString(strconv.FormatInt(int64(int32(x>>1)^-int32(x&1)), 10))
+	case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		out.WriteString(strconv.FormatUint(uint64(uint32(v.x)), 10))
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		writeQuoted(out, strconv.FormatInt(int64(v.x), 10))
+	case descriptor.FieldDescriptorProto_TYPE_SINT64:
+		writeQuoted(out, strconv.FormatInt(int64(v.x>>1)^-int64(v.x&1), 10))
+	case descriptor.FieldDescriptorProto_TYPE_UINT64, descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		writeQuoted(out, strconv.FormatUint(v.x, 10))
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		out.WriteString(strconv.FormatBool(v.x != 0))
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		writeString(out, string(v.b))
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		writeQuoted(out, base64.StdEncoding.EncodeToString(v.b))
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		e, err := r.enum(typeName)
+		if err != nil {
+			return err
+		}
+		if name, ok := e.byNumber[int32(v.x)]; ok {
+			writeString(out, name)
+		} else {
+			out.WriteString(strconv.FormatInt(int64(int32(v.x)), 10))
+		}
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		return r.writeMessage(out, typeName, v.b)
+	default:
+		return fmt.Errorf("unsupported field type %v", t)
+	}
+	return nil
+}
+
+// writeDefault writes the default value of a scalar type.
+func writeDefault(out *bytes.Buffer, t descriptor.FieldDescriptorProto_Type) {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		out.WriteString(`"0"`)
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		out.WriteString("false")
+	case descriptor.FieldDescriptorProto_TYPE_STRING,
+		descriptor.FieldDescriptorProto_TYPE_BYTES:
+		out.WriteString(`""`)
+	default:
+		out.WriteString("0")
+	}
+}
+
+func writeFloat(out *bytes.Buffer, f float64, bits int) {
+	switch {
+	case math.IsNaN(f):
+		out.WriteString(`"NaN"`)
+	case math.IsInf(f, 1):
+		out.WriteString(`"Infinity"`)
+	case math.IsInf(f, -1):
+		out.WriteString(`"-Infinity"`)
+	default:
+		out.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	}
+}
+
+func writeQuoted(out *bytes.Buffer, s string) {
+	out.WriteByte('"')
+	out.WriteString(s)
+	out.WriteByte('"')
+}
+
+func writeString(out *bytes.Buffer, s string) {
+	// Marshaling a string cannot fail.
+	b, _ := json.Marshal(s)
+	out.Write(b)
+}
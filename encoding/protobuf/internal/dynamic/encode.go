@@ -0,0 +1,407 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// JSONToWire converts the JSON representation of a message of the named type
+// into its protobuf wire representation.
+//
+// The JSON representation is the one defined by the protobuf JSON mapping.
+// Fields may be named by their JSON names or by their names in the protobuf
+// file.
+func (r *Registry) JSONToWire(messageName string, data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value of message %q", messageName)
+	}
+	return r.appendMessage(nil, messageName, v)
+}
+
+func (r *Registry) appendMessage(b []byte, name string, v interface{}) ([]byte, error) {
+	if wkt, ok := _wellKnownTypes[name]; ok {
+		return wkt.encode(r, b, v)
+	}
+
+	m, err := r.message(name)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return b, nil
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object for message %q, got %v", name, describe(v))
+	}
+
+	fields := make([]*field, 0, len(obj))
+	for k := range obj {
+		f, ok := m.byJSON[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q in message %q", k, name)
+		}
+		for _, other := range fields {
+			if other == f {
+				return nil, fmt.Errorf("field %q of message %q is set more than once", f.name, name)
+			}
+		}
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].number < fields[j].number })
+
+	for _, f := range fields {
+		v, ok := obj[f.jsonName]
+		if !ok {
+			v = obj[f.name]
+		}
+		if b, err = r.appendField(b, m, f, v); err != nil {
+			return nil, fmt.Errorf("field %q: %v", f.jsonName, err)
+		}
+	}
+	return b, nil
+}
+
+func (r *Registry) appendField(b []byte, m *message, f *field, v interface{}) ([]byte, error) {
+	if v == nil {
+		// null stands for the default value of any field except for
+		// google.protobuf.Value, which represents null explicitly.
+		if f.typeName == "google.protobuf.Value" && !f.repeated {
+			return r.appendSingle(b, f, v)
+		}
+		return b, nil
+	}
+
+	if r.isMapField(f) {
+		return r.appendMap(b, f, v)
+	}
+
+	if f.repeated {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON array, got %v", describe(v))
+		}
+		if !f.packed {
+			var err error
+			for _, e := range list {
+				if b, err = r.appendSingle(b, f, e); err != nil {
+					return nil, err
+				}
+			}
+			return b, nil
+		}
+
+		if len(list) == 0 {
+			return b, nil
+		}
+		var (
+			packed []byte
+			err    error
+		)
+		for _, e := range list {
+			if packed, err = r.appendScalar(packed, f.typ, f.typeName, e); err != nil {
+				return nil, err
+			}
+		}
+		return appendBytes(appendTag(b, f.number, proto.WireBytes), packed), nil
+	}
+
+	if m.proto3 && !f.oneof && f.typ != descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		// Scalar fields of proto3 messages are not encoded when they hold
+		// their default value. The encoding of every default value, and of
+		// no other value, consists only of zero bytes.
+		encoded, err := r.appendScalar(nil, f.typ, f.typeName, v)
+		if err != nil {
+			return nil, err
+		}
+		if isZeroBytes(encoded) {
+			return b, nil
+		}
+		return append(appendTag(b, f.number, wireType(f.typ)), encoded...), nil
+	}
+
+	return r.appendSingle(b, f, v)
+}
+
+// appendSingle appends a single value of the field with its key.
+func (r *Registry) appendSingle(b []byte, f *field, v interface{}) ([]byte, error) {
+	switch f.typ {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		nested, err := r.appendMessage(nil, f.typeName, v)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(appendTag(b, f.number, proto.WireBytes), nested), nil
+	case descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return nil, fmt.Errorf("groups are not supported")
+	default:
+		return r.appendScalar(appendTag(b, f.number, wireType(f.typ)), f.typ, f.typeName, v)
+	}
+}
+
+func (r *Registry) isMapField(f *field) bool {
+	if !f.repeated || f.typ != descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		return false
+	}
+	m, ok := r.messages[f.typeName]
+	return ok && m.mapEntry
+}
+
+// appendMap appends the entries of a map field, in the order of their keys.
+func (r *Registry) appendMap(b []byte, f *field, v interface{}) ([]byte, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object, got %v", describe(v))
+	}
+
+	entry := r.messages[f.typeName]
+	keyField, valueField := entry.byNumber[1], entry.byNumber[2]
+	if keyField == nil || valueField == nil {
+		return nil, fmt.Errorf("invalid map entry message %q", f.typeName)
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var key interface{} = k
+		switch keyField.typ {
+		case descriptor.FieldDescriptorProto_TYPE_STRING:
+		case descriptor.FieldDescriptorProto_TYPE_BOOL:
+			parsed, err := strconv.ParseBool(k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid map key %q: %v", k, err)
+			}
+			key = parsed
+		default:
+			key = json.Number(k)
+		}
+
+		encoded, err := r.appendSingle(nil, keyField, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid map key %q: %v", k, err)
+		}
+		if encoded, err = r.appendSingle(encoded, valueField, obj[k]); err != nil {
+			return nil, fmt.Errorf("map value for key %q: %v", k, err)
+		}
+		b = appendBytes(appendTag(b, f.number, proto.WireBytes), encoded)
+	}
+	return b, nil
+}
+
+// appendScalar appends the encoding of a single scalar value without a key.
+func (r *Registry) appendScalar(b []byte, t descriptor.FieldDescriptorProto_Type, typeName string, v interface{}) ([]byte, error) {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		f, err := parseFloat(v, 64)
+		return appendFixed64(b, math.Float64bits(f)), err
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		f, err := parseFloat(v, 32)
+		return appendFixed32(b, math.Float32bits(float32(f))), err
+	case descriptor.FieldDescriptorProto_TYPE_INT32:
+		i, err := parseInt(v, 32)
+		return appendVarint(b, uint64(i)), err
+	case descriptor.FieldDescriptorProto_TYPE_INT64:
+		i, err := parseInt(v, 64)
+		return appendVarint(b, uint64(i)), err
+	case descriptor.FieldDescriptorProto_TYPE_SINT32:
+		i, err := parseInt(v, 32)
+		return appendVarint(b, uint64(uint32(i<<1)^uint32(int32(i)>>31))), err
+	case descriptor.FieldDescriptorProto_TYPE_SINT64:
+		i, err := parseInt(v, 64)
+		return appendVarint(b, uint64(i<<1)^uint64(i>>63)), err
+	case descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		i, err := parseInt(v, 32)
+		return appendFixed32(b, uint32(i)), err
+	case descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		i, err := parseInt(v, 64)
+		return appendFixed64(b, uint64(i)), err
+	case descriptor.FieldDescriptorProto_TYPE_UINT32:
+		u, err := parseUint(v, 32)
+		return appendVarint(b, u), err
+	case descriptor.FieldDescriptorProto_TYPE_UINT64:
+		u, err := parseUint(v, 64)
+		return appendVarint(b, u), err
+	case descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		u, err := parseUint(v, 32)
+		return appendFixed32(b, uint32(u)), err
+	case descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		u, err := parseUint(v, 64)
+		return appendFixed64(b, u), err
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		bv, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON boolean, got %v", describe(v))
+		}
+		if bv {
+			return appendVarint(b, 1), nil
+		}
+		return appendVarint(b, 0), nil
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a JSON string, got %v", describe(v))
+		}
+		return appendBytes(b, []byte(s)), nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		bs, err := parseBytes(v)
+		return appendBytes(b, bs), err
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		n, err := r.parseEnum(typeName, v)
+		return appendVarint(b, uint64(int64(n))), err
+	default:
+		return nil, fmt.Errorf("unsupported field type %v", t)
+	}
+}
+
+// numberString returns the string form of a JSON number, or of a number
+// quoted in a JSON string, as the protobuf JSON mapping allows for all
+// numeric types.
+func numberString(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return string(n), true
+	case string:
+		return n, true
+	default:
+		return "", false
+	}
+}
+
+func parseFloat(v interface{}, bits int) (float64, error) {
+	s, ok := numberString(v)
+	if !ok {
+		return 0, fmt.Errorf("expected a JSON number, got %v", describe(v))
+	}
+	switch s {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "-Infinity":
+		return math.Inf(-1), nil
+	}
+	return strconv.ParseFloat(s, bits)
+}
+
+func parseInt(v interface{}, bits int) (int64, error) {
+	s, ok := numberString(v)
+	if !ok {
+		return 0, fmt.Errorf("expected a JSON number, got %v", describe(v))
+	}
+	return strconv.ParseInt(s, 10, bits)
+}
+
+func parseUint(v interface{}, bits int) (uint64, error) {
+	s, ok := numberString(v)
+	if !ok {
+		return 0, fmt.Errorf("expected a JSON number, got %v", describe(v))
+	}
+	return strconv.ParseUint(s, 10, bits)
+}
+
+// parseBytes decodes a base64 string in either the standard or URL-safe
+// alphabet, with or without padding.
+func parseBytes(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a base64 encoded JSON string, got %v", describe(v))
+	}
+
+	encoding := base64.StdEncoding
+	if bytes.ContainsAny([]byte(s), "-_") {
+		encoding = base64.URLEncoding
+	}
+	if len(s)%4 != 0 {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	return encoding.DecodeString(s)
+}
+
+// parseEnum accepts the name or the number of an enum value.
+func (r *Registry) parseEnum(typeName string, v interface{}) (int32, error) {
+	e, err := r.enum(typeName)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n := v.(type) {
+	case string:
+		if number, ok := e.byName[n]; ok {
+			return number, nil
+		}
+		return 0, fmt.Errorf("unknown value %q for enum %q", n, typeName)
+	case json.Number:
+		number, err := strconv.ParseInt(string(n), 10, 32)
+		return int32(number), err
+	default:
+		return 0, fmt.Errorf("expected a JSON string or number for enum %q, got %v", typeName, describe(v))
+	}
+}
+
+func isZeroBytes(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// describe names the JSON type of a decoded JSON value for error messages.
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+// _onewayType is the fully-qualified name of the response type of oneway
+// methods.
+const _onewayType = "uber.yarpc.Oneway"
+
+// IsOneway returns whether a method is oneway, either because it returns the
+// uber.yarpc.Oneway type or because its uber.yarpc.method option sets
+// oneway.
+//
+// This is the rule protoc-gen-yarpc-go uses to generate oneway methods, so
+// code that works from descriptors must use it too.
+func IsOneway(method *descriptor.MethodDescriptorProto) (bool, error) {
+	if strings.TrimPrefix(method.GetOutputType(), ".") == _onewayType {
+		return true, nil
+	}
+
+	options := method.GetOptions()
+	if options == nil || !proto.HasExtension(options, yarpcproto.E_Method) {
+		return false, nil
+	}
+	extension, err := proto.GetExtension(options, yarpcproto.E_Method)
+	if err != nil {
+		return false, fmt.Errorf("could not read the uber.yarpc.method option of method %s: %v", method.GetName(), err)
+	}
+	methodOptions, ok := extension.(*yarpcproto.MethodOptions)
+	if !ok {
+		return false, fmt.Errorf("expected the uber.yarpc.method option of method %s to be a %T but was a %T", method.GetName(), methodOptions, extension)
+	}
+	return methodOptions.GetOneway(), nil
+}
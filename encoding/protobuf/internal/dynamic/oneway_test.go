@@ -0,0 +1,98 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+func TestIsOneway(t *testing.T) {
+	methodOptions := func(o *yarpcproto.MethodOptions) *descriptor.MethodOptions {
+		options := &descriptor.MethodOptions{}
+		require.NoError(t, proto.SetExtension(options, yarpcproto.E_Method, o))
+		return options
+	}
+
+	tests := []struct {
+		msg     string
+		method  *descriptor.MethodDescriptorProto
+		want    bool
+		wantErr string
+	}{
+		{
+			msg:    "unary",
+			method: &descriptor.MethodDescriptorProto{OutputType: proto.String(".test.Response")},
+		},
+		{
+			msg:    "oneway type",
+			method: &descriptor.MethodDescriptorProto{OutputType: proto.String(".uber.yarpc.Oneway")},
+			want:   true,
+		},
+		{
+			msg: "oneway option",
+			method: &descriptor.MethodDescriptorProto{
+				OutputType: proto.String(".test.Response"),
+				Options:    methodOptions(&yarpcproto.MethodOptions{Oneway: true}),
+			},
+			want: true,
+		},
+		{
+			msg: "other options",
+			method: &descriptor.MethodDescriptorProto{
+				OutputType: proto.String(".test.Response"),
+				Options:    methodOptions(&yarpcproto.MethodOptions{Idempotent: true}),
+			},
+		},
+		{
+			msg: "invalid option",
+			method: &descriptor.MethodDescriptorProto{
+				Name:       proto.String("Fire"),
+				OutputType: proto.String(".test.Response"),
+				Options: func() *descriptor.MethodOptions {
+					options := &descriptor.MethodOptions{}
+					field := yarpcproto.E_Method.Field
+					proto.SetRawExtension(options, field, []byte{byte(field<<3 | 2), 10, 1})
+					return options
+				}(),
+			},
+			wantErr: "could not read the uber.yarpc.method option of method Fire",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			oneway, err := IsOneway(tt.method)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, oneway)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// Registry indexes the messages, enums and services declared in a set of
+// protobuf files by their fully-qualified names.
+type Registry struct {
+	messages map[string]*message
+	enums    map[string]*enum
+	services map[string]*Service
+}
+
+// Service is a service declared in a protobuf file.
+type Service struct {
+	// Name is the fully-qualified name of the service.
+	Name    string
+	Methods []*Method
+}
+
+// Method is a method of a service declared in a protobuf file.
+type Method struct {
+	Name string
+
+	// InputType and OutputType are the fully-qualified names of the request
+	// and response messages of the method.
+	InputType  string
+	OutputType string
+
+	ClientStreaming bool
+	ServerStreaming bool
+
+	// Oneway is whether the method is oneway, as decided by IsOneway.
+	Oneway bool
+}
+
+type message struct {
+	name     string
+	proto3   bool
+	mapEntry bool
+	fields   []*field // in declaration order
+	byNumber map[int32]*field
+	byJSON   map[string]*field
+}
+
+type field struct {
+	name     string
+	jsonName string
+	number   int32
+	typ      descriptor.FieldDescriptorProto_Type
+	typeName string // fully-qualified name of message and enum types
+	repeated bool
+	packed   bool
+	oneof    bool
+}
+
+type enum struct {
+	byName   map[string]int32
+	byNumber map[int32]string
+}
+
+// NewRegistry builds a Registry from the given files. Files that appear more
+// than once in the set are indexed once.
+func NewRegistry(files []*descriptor.FileDescriptorProto) (*Registry, error) {
+	r := &Registry{
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+		services: make(map[string]*Service),
+	}
+
+	seen := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		if _, ok := seen[file.GetName()]; ok {
+			continue
+		}
+		seen[file.GetName()] = struct{}{}
+
+		if err := r.addFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) addFile(file *descriptor.FileDescriptorProto) error {
+	prefix := file.GetPackage()
+	proto3 := file.GetSyntax() == "proto3"
+
+	for _, m := range file.MessageType {
+		if err := r.addMessage(prefix, proto3, m); err != nil {
+			return err
+		}
+	}
+	for _, e := range file.EnumType {
+		if err := r.addEnum(prefix, e); err != nil {
+			return err
+		}
+	}
+	for _, s := range file.Service {
+		name := qualify(prefix, s.GetName())
+		if _, ok := r.services[name]; ok {
+			return fmt.Errorf("service %q is declared more than once", name)
+		}
+
+		service := &Service{Name: name}
+		for _, m := range s.Method {
+			oneway, err := IsOneway(m)
+			if err != nil {
+				return fmt.Errorf("invalid method of service %q: %v", name, err)
+			}
+			service.Methods = append(service.Methods, &Method{
+				Name:            m.GetName(),
+				InputType:       strings.TrimPrefix(m.GetInputType(), "."),
+				OutputType:      strings.TrimPrefix(m.GetOutputType(), "."),
+				ClientStreaming: m.GetClientStreaming(),
+				ServerStreaming: m.GetServerStreaming(),
+				Oneway:          oneway,
+			})
+		}
+		r.services[name] = service
+	}
+	return nil
+}
+
+func (r *Registry) addMessage(prefix string, proto3 bool, d *descriptor.DescriptorProto) error {
+	name := qualify(prefix, d.GetName())
+	if _, ok := r.messages[name]; ok {
+		return fmt.Errorf("message %q is declared more than once", name)
+	}
+
+	m := &message{
+		name:     name,
+		proto3:   proto3,
+		mapEntry: d.GetOptions().GetMapEntry(),
+		byNumber: make(map[int32]*field, len(d.Field)),
+		byJSON:   make(map[string]*field, 2*len(d.Field)),
+	}
+	for _, fd := range d.Field {
+		f := &field{
+			name:     fd.GetName(),
+			jsonName: fd.GetJsonName(),
+			number:   fd.GetNumber(),
+			typ:      fd.GetType(),
+			typeName: strings.TrimPrefix(fd.GetTypeName(), "."),
+			repeated: fd.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED,
+			oneof:    fd.OneofIndex != nil,
+		}
+		if f.jsonName == "" {
+			f.jsonName = lowerCamelCase(f.name)
+		}
+		if f.repeated && isPackable(f.typ) {
+			if fd.GetOptions() != nil && fd.GetOptions().Packed != nil {
+				f.packed = fd.GetOptions().GetPacked()
+			} else {
+				f.packed = proto3
+			}
+		}
+
+		m.fields = append(m.fields, f)
+		m.byNumber[f.number] = f
+		m.byJSON[f.name] = f
+		m.byJSON[f.jsonName] = f
+	}
+	r.messages[name] = m
+
+	for _, nested := range d.NestedType {
+		if err := r.addMessage(name, proto3, nested); err != nil {
+			return err
+		}
+	}
+	for _, e := range d.EnumType {
+		if err := r.addEnum(name, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registry) addEnum(prefix string, d *descriptor.EnumDescriptorProto) error {
+	name := qualify(prefix, d.GetName())
+	if _, ok := r.enums[name]; ok {
+		return fmt.Errorf("enum %q is declared more than once", name)
+	}
+
+	e := &enum{
+		byName:   make(map[string]int32, len(d.Value)),
+		byNumber: make(map[int32]string, len(d.Value)),
+	}
+	for _, v := range d.Value {
+		e.byName[v.GetName()] = v.GetNumber()
+		// With allow_alias, the first name of a number is its canonical name.
+		if _, ok := e.byNumber[v.GetNumber()]; !ok {
+			e.byNumber[v.GetNumber()] = v.GetName()
+		}
+	}
+	r.enums[name] = e
+	return nil
+}
+
+// Services returns the fully-qualified names of all services in the
+// registry, in lexical order.
+func (r *Registry) Services() []string {
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Service returns the service with the given fully-qualified name.
+func (r *Registry) Service(name string) (*Service, bool) {
+	s, ok := r.services[name]
+	return s, ok
+}
+
+// Method returns the method with the given name.
+func (s *Service) Method(name string) (*Method, bool) {
+	for _, m := range s.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) message(name string) (*message, error) {
+	m, ok := r.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %q", name)
+	}
+	return m, nil
+}
+
+func (r *Registry) enum(name string) (*enum, error) {
+	e, ok := r.enums[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown enum type %q", name)
+	}
+	return e, nil
+}
+
+func qualify(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// lowerCamelCase converts a field name to its default JSON name the way
+// protoc does, by dropping underscores and capitalizing the letter after
+// each.
+func lowerCamelCase(name string) string {
+	var (
+		b     = make([]byte, 0, len(name))
+		upper bool
+	)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			b = append(b, c-'a'+'A')
+			upper = false
+		default:
+			b = append(b, c)
+			upper = false
+		}
+	}
+	return string(b)
+}
+
+// isPackable returns whether repeated fields of the given type may use the
+// packed encoding.
+func isPackable(t descriptor.FieldDescriptorProto_Type) bool {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_STRING,
+		descriptor.FieldDescriptorProto_TYPE_BYTES,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testField(name string, number int32, t descriptor.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptor.FieldDescriptorProto {
+	label := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptor.FieldDescriptorProto_LABEL_REPEATED
+	}
+	f := &descriptor.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   t.Enum(),
+		Label:  label.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func testFile() *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("test/test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("Scalars"),
+				Field: []*descriptor.FieldDescriptorProto{
+					testField("double_value", 1, descriptor.FieldDescriptorProto_TYPE_DOUBLE, "", false),
+					testField("float_value", 2, descriptor.FieldDescriptorProto_TYPE_FLOAT, "", false),
+					testField("int64_value", 3, descriptor.FieldDescriptorProto_TYPE_INT64, "", false),
+					testField("uint64_value", 4, descriptor.FieldDescriptorProto_TYPE_UINT64, "", false),
+					testField("int32_value", 5, descriptor.FieldDescriptorProto_TYPE_INT32, "", false),
+					testField("fixed64_value", 6, descriptor.FieldDescriptorProto_TYPE_FIXED64, "", false),
+					testField("fixed32_value", 7, descriptor.FieldDescriptorProto_TYPE_FIXED32, "", false),
+					testField("bool_value", 8, descriptor.FieldDescriptorProto_TYPE_BOOL, "", false),
+					testField("string_value", 9, descriptor.FieldDescriptorProto_TYPE_STRING, "", false),
+					testField("bytes_value", 12, descriptor.FieldDescriptorProto_TYPE_BYTES, "", false),
+					testField("uint32_value", 13, descriptor.FieldDescriptorProto_TYPE_UINT32, "", false),
+					testField("sfixed32_value", 15, descriptor.FieldDescriptorProto_TYPE_SFIXED32, "", false),
+					testField("sfixed64_value", 16, descriptor.FieldDescriptorProto_TYPE_SFIXED64, "", false),
+					testField("sint32_value", 17, descriptor.FieldDescriptorProto_TYPE_SINT32, "", false),
+					testField("sint64_value", 18, descriptor.FieldDescriptorProto_TYPE_SINT64, "", false),
+				},
+			},
+			{
+				Name: proto.String("Composite"),
+				Field: []*descriptor.FieldDescriptorProto{
+					testField("color", 1, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Color", false),
+					testField("numbers", 2, descriptor.FieldDescriptorProto_TYPE_INT32, "", true),
+					testField("names", 3, descriptor.FieldDescriptorProto_TYPE_STRING, "", true),
+					testField("child", 4, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Composite.Child", false),
+					testField("children", 5, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Composite.Child", true),
+					testField("labels", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Composite.LabelsEntry", true),
+					testField("created_at", 7, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp", false),
+					testField("timeout", 8, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Duration", false),
+					testField("maybe", 9, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Int64Value", false),
+					testField("extra", 10, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct", false),
+					testField("mask", 11, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.FieldMask", false),
+				},
+				NestedType: []*descriptor.DescriptorProto{
+					{
+						Name: proto.String("Child"),
+						Field: []*descriptor.FieldDescriptorProto{
+							testField("name", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "", false),
+						},
+					},
+					{
+						Name: proto.String("LabelsEntry"),
+						Field: []*descriptor.FieldDescriptorProto{
+							testField("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "", false),
+							testField("value", 2, descriptor.FieldDescriptorProto_TYPE_INT64, "", false),
+						},
+						Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name: proto.String("Color"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					{Name: proto.String("RED"), Number: proto.Int32(0)},
+					{Name: proto.String("GREEN"), Number: proto.Int32(1)},
+				},
+			},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{
+			{
+				Name: proto.String("Store"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{
+						Name:       proto.String("Get"),
+						InputType:  proto.String(".test.Scalars"),
+						OutputType: proto.String(".test.Composite"),
+					},
+					{
+						Name:            proto.String("Watch"),
+						InputType:       proto.String(".test.Scalars"),
+						OutputType:      proto.String(".test.Composite"),
+						ServerStreaming: proto.Bool(true),
+					},
+				},
+			},
+		},
+	}
+}
+
+func testRegistry(t *testing.T) *Registry {
+	r, err := NewRegistry([]*descriptor.FileDescriptorProto{testFile(), testFile()})
+	require.NoError(t, err)
+	return r
+}
+
+func TestRegistryServices(t *testing.T) {
+	r := testRegistry(t)
+	assert.Equal(t, []string{"test.Store"}, r.Services())
+
+	s, ok := r.Service("test.Store")
+	require.True(t, ok)
+
+	m, ok := s.Method("Watch")
+	require.True(t, ok)
+	assert.Equal(t, &Method{
+		Name:            "Watch",
+		InputType:       "test.Scalars",
+		OutputType:      "test.Composite",
+		ServerStreaming: true,
+	}, m)
+
+	_, ok = s.Method("Put")
+	assert.False(t, ok)
+	_, ok = r.Service("test.Unknown")
+	assert.False(t, ok)
+}
+
+func TestRegistryDuplicateDeclarations(t *testing.T) {
+	other := testFile()
+	other.Name = proto.String("test/other.proto")
+	_, err := NewRegistry([]*descriptor.FileDescriptorProto{testFile(), other})
+	assert.Error(t, err)
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		msg  string
+		give string
+		want string // defaults to give
+	}{
+		{msg: "test.Scalars", give: `{}`},
+		{
+			msg: "test.Scalars",
+			give: `{"doubleValue":1.5,"floatValue":-2.25,"int64Value":"-9007199254740993",` +
+				`"uint64Value":"18446744073709551615","int32Value":-42,"fixed64Value":"7",` +
+				`"fixed32Value":8,"boolValue":true,"stringValue":"héllo \"world\"",` +
+				`"bytesValue":"AAEC","uint32Value":4294967295,"sfixed32Value":-3,` +
+				`"sfixed64Value":"-4","sint32Value":-5,"sint64Value":"-6"}`,
+		},
+		{
+			msg:  "test.Scalars",
+			give: `{"double_value":"NaN","float_value":"-Infinity","int64_value":12,"int32_value":"13"}`,
+			want: `{"doubleValue":"NaN","floatValue":"-Infinity","int64Value":"12","int32Value":13}`,
+		},
+		{
+			msg:  "test.Scalars",
+			give: `{"int32Value":0,"stringValue":"","boolValue":false}`,
+			want: `{}`,
+		},
+		{
+			msg: "test.Composite",
+			give: `{"color":"GREEN","numbers":[1,-2,3],"names":["a","b"],"child":{"name":"c"},` +
+				`"children":[{"name":"d"},{}],"labels":{"x":"1","y":"-2"}}`,
+		},
+		{
+			msg:  "test.Composite",
+			give: `{"color":1,"numbers":[],"labels":{}}`,
+			want: `{"color":"GREEN"}`,
+		},
+		{
+			msg: "test.Composite",
+			give: `{"createdAt":"2018-06-01T12:30:45.123Z","timeout":"-1.500s","maybe":"0",` +
+				`"extra":{"a":null,"b":[1,"two",false,{"c":{}}]},"mask":"fooBar,baz"}`,
+			want: `{"createdAt":"2018-06-01T12:30:45.123Z","timeout":"-1.500s","maybe":"0",` +
+				`"extra":{"a":null,"b":[1,"two",false,{"c":{}}]},"mask":"fooBar,baz"}`,
+		},
+		{
+			msg:  "test.Composite",
+			give: `{"createdAt":"2018-06-01T14:30:45+02:00","timeout":"3s"}`,
+			want: `{"createdAt":"2018-06-01T12:30:45Z","timeout":"3s"}`,
+		},
+	}
+
+	r := testRegistry(t)
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			want := tt.want
+			if want == "" {
+				want = tt.give
+			}
+
+			wire, err := r.JSONToWire(tt.msg, []byte(tt.give))
+			require.NoError(t, err)
+
+			got, err := r.WireToJSON(tt.msg, wire)
+			require.NoError(t, err)
+			assert.JSONEq(t, want, string(got))
+		})
+	}
+}
+
+func TestJSONToWireErrors(t *testing.T) {
+	tests := []struct {
+		msg  string
+		give string
+	}{
+		{msg: "test.Unknown", give: `{}`},
+		{msg: "test.Scalars", give: `[]`},
+		{msg: "test.Scalars", give: `{} {}`},
+		{msg: "test.Scalars", give: `{"unknown":1}`},
+		{msg: "test.Scalars", give: `{"int32Value":1,"int32_value":2}`},
+		{msg: "test.Scalars", give: `{"int32Value":4294967296}`},
+		{msg: "test.Scalars", give: `{"uint32Value":-1}`},
+		{msg: "test.Scalars", give: `{"boolValue":"true"}`},
+		{msg: "test.Scalars", give: `{"bytesValue":"not base64!"}`},
+		{msg: "test.Composite", give: `{"color":"BLUE"}`},
+		{msg: "test.Composite", give: `{"numbers":1}`},
+		{msg: "test.Composite", give: `{"labels":[]}`},
+		{msg: "test.Composite", give: `{"createdAt":"yesterday"}`},
+		{msg: "test.Composite", give: `{"timeout":"1m"}`},
+		{msg: "test.Composite", give: `{"timeout":"1.0000000001s"}`},
+	}
+
+	r := testRegistry(t)
+	for _, tt := range tests {
+		t.Run(tt.give, func(t *testing.T) {
+			_, err := r.JSONToWire(tt.msg, []byte(tt.give))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWireToJSONErrors(t *testing.T) {
+	r := testRegistry(t)
+
+	_, err := r.WireToJSON("test.Unknown", nil)
+	assert.Error(t, err)
+
+	// A length-delimited field whose length runs past the end of the data.
+	_, err = r.WireToJSON("test.Scalars", []byte{0x4a, 0x05, 'a'})
+	assert.Error(t, err)
+
+	// A varint for a string field.
+	_, err = r.WireToJSON("test.Scalars", []byte{0x48, 0x01})
+	assert.Error(t, err)
+}
+
+func TestWireCompatibility(t *testing.T) {
+	r := testRegistry(t)
+
+	// Unpacked repeated scalars are accepted on the wire.
+	got, err := r.WireToJSON("test.Composite", []byte{0x10, 0x01, 0x10, 0x02})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"numbers":[1,2]}`, string(got))
+
+	// Unknown fields are skipped.
+	got, err = r.WireToJSON("test.Scalars", []byte{0xf8, 0x01, 0x01, 0x28, 0x07})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"int32Value":7}`, string(got))
+}
+
+func TestWellKnownTypesMatchGeneratedCode(t *testing.T) {
+	r := testRegistry(t)
+	createdAt := time.Date(2018, 6, 1, 12, 30, 45, 123000000, time.UTC)
+
+	ts, err := types.TimestampProto(createdAt)
+	require.NoError(t, err)
+	want, err := proto.Marshal(ts)
+	require.NoError(t, err)
+
+	// createdAt is field 7 of Composite: tag 0x3a followed by the length.
+	wire, err := r.JSONToWire("test.Composite", []byte(`{"createdAt":"2018-06-01T12:30:45.123Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0x3a, byte(len(want))}, want...), wire)
+
+	want, err = proto.Marshal(types.DurationProto(-1500 * time.Millisecond))
+	require.NoError(t, err)
+	wire, err = r.JSONToWire("test.Composite", []byte(`{"timeout":"-1.5s"}`))
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0x42, byte(len(want))}, want...), wire)
+
+	want, err = proto.Marshal(&types.Struct{Fields: map[string]*types.Value{
+		"a": {Kind: &types.Value_NumberValue{NumberValue: 1}},
+	}})
+	require.NoError(t, err)
+	wire, err = r.JSONToWire("test.Composite", []byte(`{"extra":{"a":1}}`))
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{0x52, byte(len(want))}, want...), wire)
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"foo":         "foo",
+		"foo_bar":     "fooBar",
+		"foo_bar_baz": "fooBarBaz",
+		"foo__bar":    "fooBar",
+		"foo_1":       "foo1",
+		"Foo":         "Foo",
+	}
+	for give, want := range tests {
+		assert.Equal(t, want, lowerCamelCase(give), give)
+	}
+}
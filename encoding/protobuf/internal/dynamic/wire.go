@@ -0,0 +1,169 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// wireValue is a single encoded value of a field.
+type wireValue struct {
+	wireType int
+	x        uint64 // varint, fixed32 and fixed64 values
+	b        []byte // length-delimited values
+}
+
+// wireFields is a decoded message, with the values of each field number in
+// the order in which they appeared.
+type wireFields map[int32][]wireValue
+
+// parseWire splits an encoded message into the values of its fields.
+func parseWire(data []byte) (wireFields, error) {
+	fields := make(wireFields)
+	for len(data) > 0 {
+		tag, n := proto.DecodeVarint(data)
+		if n == 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+
+		number := int32(tag >> 3)
+		v := wireValue{wireType: int(tag & 7)}
+		switch v.wireType {
+		case proto.WireVarint:
+			v.x, n = proto.DecodeVarint(data)
+			if n == 0 {
+				return nil, errTruncated
+			}
+		case proto.WireFixed64:
+			if len(data) < 8 {
+				return nil, errTruncated
+			}
+			v.x, n = binary.LittleEndian.Uint64(data), 8
+		case proto.WireFixed32:
+			if len(data) < 4 {
+				return nil, errTruncated
+			}
+			v.x, n = uint64(binary.LittleEndian.Uint32(data)), 4
+		case proto.WireBytes:
+			length, m := proto.DecodeVarint(data)
+			if m == 0 || uint64(len(data)-m) < length {
+				return nil, errTruncated
+			}
+			v.b, n = data[m:m+int(length)], m+int(length)
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d for field %d", v.wireType, number)
+		}
+		data = data[n:]
+		fields[number] = append(fields[number], v)
+	}
+	return fields, nil
+}
+
+// wireType returns the wire type of a single value of the given field type.
+func wireType(t descriptor.FieldDescriptorProto_Type) int {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return proto.WireFixed64
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return proto.WireFixed32
+	case descriptor.FieldDescriptorProto_TYPE_STRING,
+		descriptor.FieldDescriptorProto_TYPE_BYTES,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		return proto.WireBytes
+	case descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return proto.WireStartGroup
+	default:
+		return proto.WireVarint
+	}
+}
+
+// unpack expands packed values of a repeated scalar field.
+func unpack(t descriptor.FieldDescriptorProto_Type, values []wireValue) ([]wireValue, error) {
+	wt := wireType(t)
+	unpacked := make([]wireValue, 0, len(values))
+	for _, v := range values {
+		if v.wireType != proto.WireBytes || wt == proto.WireBytes {
+			unpacked = append(unpacked, v)
+			continue
+		}
+
+		data := v.b
+		for len(data) > 0 {
+			u := wireValue{wireType: wt}
+			var n int
+			switch wt {
+			case proto.WireVarint:
+				u.x, n = proto.DecodeVarint(data)
+			case proto.WireFixed64:
+				if len(data) >= 8 {
+					u.x, n = binary.LittleEndian.Uint64(data), 8
+				}
+			case proto.WireFixed32:
+				if len(data) >= 4 {
+					u.x, n = uint64(binary.LittleEndian.Uint32(data)), 4
+				}
+			}
+			if n == 0 {
+				return nil, errTruncated
+			}
+			data = data[n:]
+			unpacked = append(unpacked, u)
+		}
+	}
+	return unpacked, nil
+}
+
+// appendTag appends the key of a field with the given number and wire type.
+func appendTag(b []byte, number int32, wireType int) []byte {
+	return append(b, proto.EncodeVarint(uint64(number)<<3|uint64(wireType))...)
+}
+
+func appendVarint(b []byte, x uint64) []byte {
+	return append(b, proto.EncodeVarint(x)...)
+}
+
+func appendBytes(b []byte, v []byte) []byte {
+	return append(appendVarint(b, uint64(len(v))), v...)
+}
+
+func appendFixed32(b []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendFixed64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
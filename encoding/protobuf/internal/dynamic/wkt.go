@@ -0,0 +1,460 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// wellKnownType converts a well-known type that has a special JSON
+// representation. Well-known types are converted without their descriptors,
+// so they need not be part of the registry.
+type wellKnownType struct {
+	encode func(r *Registry, b []byte, v interface{}) ([]byte, error)
+	decode func(r *Registry, out *bytes.Buffer, data []byte) error
+}
+
+// _wellKnownTypes is populated in init because its converters refer back to
+// the registry, which consults it.
+var _wellKnownTypes map[string]wellKnownType
+
+func init() {
+	_wellKnownTypes = map[string]wellKnownType{
+		"google.protobuf.Any": {
+			encode: func(*Registry, []byte, interface{}) ([]byte, error) {
+				return nil, fmt.Errorf("google.protobuf.Any is not supported")
+			},
+			decode: func(*Registry, *bytes.Buffer, []byte) error {
+				return fmt.Errorf("google.protobuf.Any is not supported")
+			},
+		},
+		"google.protobuf.Timestamp": {encode: encodeTimestamp, decode: decodeTimestamp},
+		"google.protobuf.Duration":  {encode: encodeDuration, decode: decodeDuration},
+		"google.protobuf.FieldMask": {encode: encodeFieldMask, decode: decodeFieldMask},
+		"google.protobuf.Struct":    {encode: encodeStruct, decode: decodeStruct},
+		"google.protobuf.Value":     {encode: encodeValue, decode: decodeValue},
+		"google.protobuf.ListValue": {encode: encodeListValue, decode: decodeListValue},
+
+		"google.protobuf.DoubleValue": wrapper(descriptor.FieldDescriptorProto_TYPE_DOUBLE),
+		"google.protobuf.FloatValue":  wrapper(descriptor.FieldDescriptorProto_TYPE_FLOAT),
+		"google.protobuf.Int64Value":  wrapper(descriptor.FieldDescriptorProto_TYPE_INT64),
+		"google.protobuf.UInt64Value": wrapper(descriptor.FieldDescriptorProto_TYPE_UINT64),
+		"google.protobuf.Int32Value":  wrapper(descriptor.FieldDescriptorProto_TYPE_INT32),
+		"google.protobuf.UInt32Value": wrapper(descriptor.FieldDescriptorProto_TYPE_UINT32),
+		"google.protobuf.BoolValue":   wrapper(descriptor.FieldDescriptorProto_TYPE_BOOL),
+		"google.protobuf.StringValue": wrapper(descriptor.FieldDescriptorProto_TYPE_STRING),
+		"google.protobuf.BytesValue":  wrapper(descriptor.FieldDescriptorProto_TYPE_BYTES),
+	}
+}
+
+// wrapper converts wrapper types, which are represented by the JSON value of
+// their only field.
+func wrapper(t descriptor.FieldDescriptorProto_Type) wellKnownType {
+	m := &message{proto3: true}
+	f := &field{name: "value", jsonName: "value", number: 1, typ: t}
+	return wellKnownType{
+		encode: func(r *Registry, b []byte, v interface{}) ([]byte, error) {
+			return r.appendField(b, m, f, v)
+		},
+		decode: func(r *Registry, out *bytes.Buffer, data []byte) error {
+			fields, err := parseWire(data)
+			if err != nil {
+				return err
+			}
+			values, ok := fields[1]
+			if !ok {
+				writeDefault(out, t)
+				return nil
+			}
+			return r.writeField(out, f, values)
+		},
+	}
+}
+
+// secondsAndNanos reads the seconds (1) and nanos (2) fields shared by
+// Timestamp and Duration.
+func secondsAndNanos(data []byte) (int64, int32, error) {
+	fields, err := parseWire(data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		seconds int64
+		nanos   int32
+	)
+	if values := fields[1]; len(values) > 0 {
+		seconds = int64(values[len(values)-1].x)
+	}
+	if values := fields[2]; len(values) > 0 {
+		nanos = int32(values[len(values)-1].x)
+	}
+	return seconds, nanos, nil
+}
+
+func appendSecondsAndNanos(b []byte, seconds int64, nanos int32) []byte {
+	if seconds != 0 {
+		b = appendVarint(appendTag(b, 1, proto.WireVarint), uint64(seconds))
+	}
+	if nanos != 0 {
+		b = appendVarint(appendTag(b, 2, proto.WireVarint), uint64(int64(nanos)))
+	}
+	return b
+}
+
+// formatNanos formats a fractional second with 0, 3, 6 or 9 digits.
+func formatNanos(nanos int32) string {
+	if nanos == 0 {
+		return ""
+	}
+	s := fmt.Sprintf(".%09d", nanos)
+	for strings.HasSuffix(s, "000") {
+		s = s[:len(s)-3]
+	}
+	return s
+}
+
+func encodeTimestamp(_ *Registry, b []byte, v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected an RFC 3339 JSON string for google.protobuf.Timestamp, got %v", describe(v))
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, err
+	}
+	return appendSecondsAndNanos(b, t.Unix(), int32(t.Nanosecond())), nil
+}
+
+func decodeTimestamp(_ *Registry, out *bytes.Buffer, data []byte) error {
+	seconds, nanos, err := secondsAndNanos(data)
+	if err != nil {
+		return err
+	}
+	t := time.Unix(seconds, 0).UTC()
+	writeQuoted(out, t.Format("2006-01-02T15:04:05")+formatNanos(nanos)+"Z")
+	return nil
+}
+
+func encodeDuration(_ *Registry, b []byte, v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok || !strings.HasSuffix(s, "s") {
+		return nil, fmt.Errorf(`expected a JSON string like "1.5s" for google.protobuf.Duration, got %v`, describe(v))
+	}
+	s = strings.TrimSuffix(s, "s")
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid google.protobuf.Duration %q: %v", v, err)
+	}
+	var nanos int64
+	if frac != "" {
+		if len(frac) > 9 {
+			return nil, fmt.Errorf("invalid google.protobuf.Duration %q: more than nanosecond precision", v)
+		}
+		if nanos, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid google.protobuf.Duration %q: %v", v, err)
+		}
+	}
+	if negative {
+		seconds, nanos = -seconds, -nanos
+	}
+	return appendSecondsAndNanos(b, seconds, int32(nanos)), nil
+}
+
+func decodeDuration(_ *Registry, out *bytes.Buffer, data []byte) error {
+	seconds, nanos, err := secondsAndNanos(data)
+	if err != nil {
+		return err
+	}
+
+	sign := ""
+	if seconds < 0 || nanos < 0 {
+		sign = "-"
+		seconds, nanos = -seconds, -nanos
+	}
+	writeQuoted(out, sign+strconv.FormatInt(seconds, 10)+formatNanos(nanos)+"s")
+	return nil
+}
+
+func encodeFieldMask(_ *Registry, b []byte, v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON string for google.protobuf.FieldMask, got %v", describe(v))
+	}
+	if s == "" {
+		return b, nil
+	}
+	for _, path := range strings.Split(s, ",") {
+		b = appendBytes(appendTag(b, 1, proto.WireBytes), []byte(snakeCase(path)))
+	}
+	return b, nil
+}
+
+func decodeFieldMask(_ *Registry, out *bytes.Buffer, data []byte) error {
+	fields, err := parseWire(data)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(fields[1]))
+	for _, v := range fields[1] {
+		paths = append(paths, lowerCamelCase(string(v.b)))
+	}
+	writeString(out, strings.Join(paths, ","))
+	return nil
+}
+
+// snakeCase reverses lowerCamelCase for the paths of field masks.
+func snakeCase(name string) string {
+	var b []byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if 'A' <= c && c <= 'Z' {
+			b = append(b, '_', c-'A'+'a')
+		} else {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// Struct is a map<string, Value> in its field 1.
+func encodeStruct(r *Registry, b []byte, v interface{}) ([]byte, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object for google.protobuf.Struct, got %v", describe(v))
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		value, err := encodeValue(r, nil, obj[k])
+		if err != nil {
+			return nil, err
+		}
+		entry := appendBytes(appendTag(nil, 1, proto.WireBytes), []byte(k))
+		entry = appendBytes(appendTag(entry, 2, proto.WireBytes), value)
+		b = appendBytes(appendTag(b, 1, proto.WireBytes), entry)
+	}
+	return b, nil
+}
+
+func decodeStruct(r *Registry, out *bytes.Buffer, data []byte) error {
+	fields, err := parseWire(data)
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string][]byte, len(fields[1]))
+	for _, v := range fields[1] {
+		entry, err := parseWire(v.b)
+		if err != nil {
+			return err
+		}
+		var key string
+		if keys := entry[1]; len(keys) > 0 {
+			key = string(keys[len(keys)-1].b)
+		}
+		var value []byte
+		for _, v := range entry[2] {
+			value = append(value, v.b...)
+		}
+		entries[key] = value
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		writeString(out, k)
+		out.WriteByte(':')
+		if err := decodeValue(r, out, entries[k]); err != nil {
+			return err
+		}
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+// Value is a oneof of null_value (1), number_value (2), string_value (3),
+// bool_value (4), struct_value (5) and list_value (6).
+func encodeValue(r *Registry, b []byte, v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case nil:
+		return appendVarint(appendTag(b, 1, proto.WireVarint), 0), nil
+	case json.Number:
+		f, err := strconv.ParseFloat(string(value), 64)
+		if err != nil {
+			return nil, err
+		}
+		return appendFixed64(appendTag(b, 2, proto.WireFixed64), math.Float64bits(f)), nil
+	case string:
+		return appendBytes(appendTag(b, 3, proto.WireBytes), []byte(value)), nil
+	case bool:
+		x := uint64(0)
+		if value {
+			x = 1
+		}
+		return appendVarint(appendTag(b, 4, proto.WireVarint), x), nil
+	case map[string]interface{}:
+		s, err := encodeStruct(r, nil, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(appendTag(b, 5, proto.WireBytes), s), nil
+	case []interface{}:
+		l, err := encodeListValue(r, nil, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(appendTag(b, 6, proto.WireBytes), l), nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON value %v", describe(v))
+	}
+}
+
+func decodeValue(r *Registry, out *bytes.Buffer, data []byte) error {
+	fields, err := parseWire(data)
+	if err != nil {
+		return err
+	}
+
+	// The last member of the oneof to appear wins.
+	var (
+		kind  int32
+		value wireValue
+		pos   = -1
+	)
+	for number := int32(1); number <= 6; number++ {
+		values := fields[number]
+		if len(values) == 0 {
+			continue
+		}
+		if p := lastPosition(data, number); p > pos {
+			kind, value, pos = number, values[len(values)-1], p
+		}
+	}
+
+	switch kind {
+	case 2:
+		writeFloat(out, math.Float64frombits(value.x), 64)
+	case 3:
+		writeString(out, string(value.b))
+	case 4:
+		out.WriteString(strconv.FormatBool(value.x != 0))
+	case 5:
+		return decodeStruct(r, out, value.b)
+	case 6:
+		return decodeListValue(r, out, value.b)
+	default:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+// lastPosition returns the offset of the last occurrence of the given field
+// number in an encoded message that is known to be valid.
+func lastPosition(data []byte, number int32) int {
+	pos := -1
+	for offset := 0; offset < len(data); {
+		tag, n := proto.DecodeVarint(data[offset:])
+		if int32(tag>>3) == number {
+			pos = offset
+		}
+		offset += n
+		switch tag & 7 {
+		case proto.WireVarint:
+			_, m := proto.DecodeVarint(data[offset:])
+			offset += m
+		case proto.WireFixed64:
+			offset += 8
+		case proto.WireFixed32:
+			offset += 4
+		case proto.WireBytes:
+			length, m := proto.DecodeVarint(data[offset:])
+			offset += m + int(length)
+		}
+	}
+	return pos
+}
+
+// ListValue is a repeated Value in its field 1.
+func encodeListValue(r *Registry, b []byte, v interface{}) ([]byte, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON array for google.protobuf.ListValue, got %v", describe(v))
+	}
+	for _, e := range list {
+		value, err := encodeValue(r, nil, e)
+		if err != nil {
+			return nil, err
+		}
+		b = appendBytes(appendTag(b, 1, proto.WireBytes), value)
+	}
+	return b, nil
+}
+
+func decodeListValue(r *Registry, out *bytes.Buffer, data []byte) error {
+	fields, err := parseWire(data)
+	if err != nil {
+		return err
+	}
+
+	out.WriteByte('[')
+	for i, v := range fields[1] {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if err := decodeValue(r, out, v.b); err != nil {
+			return err
+		}
+	}
+	out.WriteByte(']')
+	return nil
+}
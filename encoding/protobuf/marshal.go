@@ -33,16 +33,76 @@ import (
 )
 
 var (
-	_jsonMarshaler   = &jsonpb.Marshaler{}
-	_jsonUnmarshaler = &jsonpb.Unmarshaler{AllowUnknownFields: true}
-	_bufferPool      = sync.Pool{
+	_defaultCodec = newCodec(JSONOptions{})
+	_bufferPool   = sync.Pool{
 		New: func() interface{} {
 			return proto.NewBuffer(make([]byte, 1024))
 		},
 	}
 )
 
-func unmarshal(encoding transport.Encoding, reader io.Reader, message proto.Message) error {
+// JSONOptions configures the JSON representation of messages sent and
+// received with JSONEncoding.
+//
+// JSONOptions may be given as a ClientOption to clients, and as a
+// ProceduresOption to BuildProcedures.
+//
+// 	client := examplepb.NewKeyValueYARPCClient(
+// 		dispatcher.ClientConfig("keyvalue"),
+// 		protobuf.UseJSON,
+// 		protobuf.JSONOptions{OrigName: true, EmitDefaults: true},
+// 	)
+type JSONOptions struct {
+	// OrigName uses the field names from the proto file instead of their
+	// lowerCamelCase names when marshaling.
+	OrigName bool
+
+	// EmitDefaults marshals fields holding their zero value.
+	EmitDefaults bool
+
+	// EnumsAsInts marshals enum values as numbers instead of names.
+	EnumsAsInts bool
+
+	// AnyResolver resolves the type URLs of google.protobuf.Any messages.
+	// Types registered with the proto package are used if nil.
+	AnyResolver jsonpb.AnyResolver
+
+	// DisallowUnknownFields fails unmarshaling of objects holding fields
+	// unknown to their message instead of ignoring them.
+	DisallowUnknownFields bool
+}
+
+func (o JSONOptions) apply(client *client) {
+	client.codec = newCodec(o)
+}
+
+func (o JSONOptions) applyProcedures(params *BuildProceduresParams) {
+	params.JSONOptions = o
+}
+
+// codec marshals and unmarshals messages with the Encoding and JSONEncoding
+// encodings.
+type codec struct {
+	jsonMarshaler   *jsonpb.Marshaler
+	jsonUnmarshaler *jsonpb.Unmarshaler
+}
+
+func newCodec(options JSONOptions) *codec {
+	return &codec{
+		jsonMarshaler: &jsonpb.Marshaler{
+			OrigName:     options.OrigName,
+			EmitDefaults: options.EmitDefaults,
+			EnumsAsInts:  options.EnumsAsInts,
+			AnyResolver:  options.AnyResolver,
+		},
+		jsonUnmarshaler: &jsonpb.Unmarshaler{
+			AllowUnknownFields: !options.DisallowUnknownFields,
+			AnyResolver:        options.AnyResolver,
+		},
+	}
+}
+
+func (c *codec) unmarshal(encoding transport.Encoding, reader io.Reader, message proto.Message) error {
 	buf := bufferpool.Get()
 	defer bufferpool.Put(buf)
 	if _, err := buf.ReadFrom(reader); err != nil {
@@ -56,7 +116,7 @@ func unmarshal(encoding transport.Encoding, reader io.Reader, message proto.Mess
 	case Encoding:
 		return unmarshalProto(body, message)
 	case JSONEncoding:
-		return unmarshalJSON(body, message)
+		return c.unmarshalJSON(body, message)
 	default:
 		return yarpcerrors.Newf(yarpcerrors.CodeInternal, "encoding.Expect should have handled encoding %q but did not", encoding)
 	}
@@ -66,16 +126,16 @@ func unmarshalProto(body []byte, message proto.Message) error {
 	return proto.Unmarshal(body, message)
 }
 
-func unmarshalJSON(body []byte, message proto.Message) error {
-	return _jsonUnmarshaler.Unmarshal(bytes.NewReader(body), message)
+func (c *codec) unmarshalJSON(body []byte, message proto.Message) error {
+	return c.jsonUnmarshaler.Unmarshal(bytes.NewReader(body), message)
 }
 
-func marshal(encoding transport.Encoding, message proto.Message) ([]byte, func(), error) {
+func (c *codec) marshal(encoding transport.Encoding, message proto.Message) ([]byte, func(), error) {
 	switch encoding {
 	case Encoding:
 		return marshalProto(message)
 	case JSONEncoding:
-		return marshalJSON(message)
+		return c.marshalJSON(message)
 	default:
 		return nil, nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "encoding.Expect should have handled encoding %q but did not", encoding)
 	}
@@ -91,10 +151,10 @@ func marshalProto(message proto.Message) ([]byte, func(), error) {
 	return protoBuffer.Bytes(), cleanup, nil
 }
 
-func marshalJSON(message proto.Message) ([]byte, func(), error) {
+func (c *codec) marshalJSON(message proto.Message) ([]byte, func(), error) {
 	buf := bufferpool.Get()
 	cleanup := func() { bufferpool.Put(buf) }
-	if err := _jsonMarshaler.Marshal(buf, message); err != nil {
+	if err := c.jsonMarshaler.Marshal(buf, message); err != nil {
 		cleanup()
 		return nil, nil, err
 	}
@@ -22,15 +22,99 @@ package protobuf
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
 func TestUnhandledEncoding(t *testing.T) {
-	assert.Equal(t, yarpcerrors.CodeInternal, yarpcerrors.FromError(unmarshal(transport.Encoding("foo"), bytes.NewReader([]byte("foo")), nil)).Code())
-	_, _, err := marshal(transport.Encoding("foo"), nil)
+	assert.Equal(t, yarpcerrors.CodeInternal, yarpcerrors.FromError(_defaultCodec.unmarshal(transport.Encoding("foo"), bytes.NewReader([]byte("foo")), nil)).Code())
+	_, _, err := _defaultCodec.marshal(transport.Encoding("foo"), nil)
 	assert.Equal(t, yarpcerrors.CodeInternal, yarpcerrors.FromError(err).Code())
 }
+
+type fakeAnyResolver map[string]func() proto.Message
+
+func (r fakeAnyResolver) Resolve(typeURL string) (proto.Message, error) {
+	newMessage, ok := r[typeURL]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", typeURL)
+	}
+	return newMessage(), nil
+}
+
+func TestJSONOptions(t *testing.T) {
+	field := &types.Field{Kind: types.Field_TYPE_STRING, TypeUrl: "foo"}
+	stringValue, err := proto.Marshal(&types.StringValue{Value: "bar"})
+	require.NoError(t, err)
+	resolver := fakeAnyResolver{"custom/foo": func() proto.Message { return &types.StringValue{} }}
+
+	tests := []struct {
+		desc     string
+		options  JSONOptions
+		give     proto.Message
+		wantJSON string
+	}{
+		{
+			desc:     "defaults",
+			give:     field,
+			wantJSON: `{"kind":"TYPE_STRING","typeUrl":"foo"}`,
+		},
+		{
+			desc:     "original names",
+			options:  JSONOptions{OrigName: true},
+			give:     field,
+			wantJSON: `{"kind":"TYPE_STRING","type_url":"foo"}`,
+		},
+		{
+			desc:     "enums as ints",
+			options:  JSONOptions{EnumsAsInts: true},
+			give:     field,
+			wantJSON: `{"kind":9,"typeUrl":"foo"}`,
+		},
+		{
+			desc:     "emit defaults",
+			options:  JSONOptions{EmitDefaults: true},
+			give:     &types.SourceContext{},
+			wantJSON: `{"fileName":""}`,
+		},
+		{
+			desc:     "any resolver",
+			options:  JSONOptions{AnyResolver: resolver},
+			give:     &types.Any{TypeUrl: "custom/foo", Value: stringValue},
+			wantJSON: `{"@type":"custom/foo","value":"bar"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := newCodec(tt.options)
+			data, cleanup, err := c.marshal(JSONEncoding, tt.give)
+			require.NoError(t, err)
+			defer cleanup()
+			assert.Equal(t, tt.wantJSON, string(data))
+
+			got := proto.Clone(tt.give)
+			got.Reset()
+			require.NoError(t, c.unmarshal(JSONEncoding, bytes.NewReader(data), got))
+			assert.True(t, proto.Equal(tt.give, got), "round trip mismatch: %v", got)
+		})
+	}
+}
+
+func TestJSONOptionsUnknownFields(t *testing.T) {
+	data := []byte(`{"fileName":"foo","unknown":true}`)
+
+	var message types.SourceContext
+	require.NoError(t, _defaultCodec.unmarshal(JSONEncoding, bytes.NewReader(data), &message))
+	assert.Equal(t, "foo", message.FileName)
+
+	c := newCodec(JSONOptions{DisallowUnknownFields: true})
+	assert.Error(t, c.unmarshal(JSONEncoding, bytes.NewReader(data), &message))
+}
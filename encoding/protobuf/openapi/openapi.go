@@ -0,0 +1,540 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package openapi describes the protobuf services registered on a
+// dispatcher with an OpenAPI 3 document, so that API portals can document
+// them without access to their protobuf files.
+//
+// Code generated by protoc-gen-yarpc-go embeds the descriptors of the file
+// declaring each service and of all files it imports, and
+// protobuf.BuildProcedures makes them available once the procedures of a
+// service are built.
+//
+//   dispatcher.Register(examplepb.BuildKeyValueYARPCProcedures(server))
+//   openapi.Register(dispatcher, openapi.Info{Title: "keyvalue", Version: "1.0.0"})
+//
+// The Procedure procedure answers any request with the document. Document
+// returns it directly, for instance to write it to a file at build time.
+//
+// Each unary and oneway method is described as a POST request with a JSON
+// body on the path named after its procedure, with the headers of the YARPC
+// HTTP protocol: HTTP inbounds serve YARPC requests on any path. Methods
+// annotated with the google.api.http option are also described on the
+// RESTful paths served by the routes built by protobuf.BuildHTTPRoutes.
+// Streaming methods are not described.
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gogo/googleapis/google/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	yarpcjson "go.uber.org/yarpc/encoding/json"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/encoding/protobuf/internal/dynamic"
+	"go.uber.org/yarpc/pkg/procedure"
+	yarpchttp "go.uber.org/yarpc/transport/http"
+)
+
+const (
+	// Procedure is the name of the procedure answering with the OpenAPI
+	// document, encoded in JSON.
+	Procedure = "yarpc::openapi"
+
+	_openAPIVersion = "3.0.1"
+	_jsonMediaType  = "application/json"
+)
+
+// Info is the metadata of the API, as required by OpenAPI.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Register registers the OpenAPI procedure on a dispatcher, describing the
+// protobuf services registered on it.
+func Register(d *yarpc.Dispatcher, info Info) {
+	d.Register(Procedures(d.Router(), info))
+}
+
+// Procedures returns the OpenAPI procedure, describing the protobuf services
+// registered on the given router at the time of each request.
+func Procedures(router transport.Router, info Info) []transport.Procedure {
+	return yarpcjson.Procedure(Procedure, func(context.Context, interface{}) (*document, error) {
+		return newDocument(router, info)
+	})
+}
+
+// Document returns the OpenAPI document, encoded in JSON, describing the
+// protobuf services registered on the given router.
+func Document(router transport.Router, info Info) ([]byte, error) {
+	doc, err := newDocument(router, info)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]pathItem `json:"paths"`
+	Components components          `json:"components"`
+	Tags       []tag               `json:"tags,omitempty"`
+}
+
+// pathItem holds the operations on a path, by lowercase HTTP method.
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Content  map[string]mediaType `json:"content"`
+	Required bool                 `json:"required,omitempty"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas,omitempty"`
+}
+
+type tag struct {
+	Name string `json:"name"`
+}
+
+// registration describes how a protobuf procedure is registered on the
+// router.
+type registration struct {
+	Service string // name of the service that the procedure is registered for
+	Type    transport.Type
+}
+
+// builder builds a document from the descriptors of protobuf services.
+type builder struct {
+	doc           *document
+	schemas       *schemaSet
+	registrations map[string]registration // by procedure name
+}
+
+func newDocument(router transport.Router, info Info) (*document, error) {
+	b := &builder{
+		doc: &document{
+			OpenAPI: _openAPIVersion,
+			Info:    info,
+			Paths:   make(map[string]pathItem),
+		},
+		registrations: make(map[string]registration),
+	}
+
+	// Protobuf procedures are registered for both the protobuf and the JSON
+	// encodings; the former tells them apart from other procedures.
+	var serviceNames []string
+	for _, p := range router.Procedures() {
+		if p.Encoding != protobuf.Encoding {
+			continue
+		}
+		serviceName, _ := procedure.FromName(p.Name)
+		if _, ok := protobuf.FileDescriptors(serviceName); !ok {
+			continue
+		}
+		if _, ok := b.registrations[p.Name]; !ok {
+			b.registrations[p.Name] = registration{Service: p.Service, Type: p.HandlerSpec.Type()}
+		}
+		serviceNames = append(serviceNames, serviceName)
+	}
+	sort.Strings(serviceNames)
+
+	files := newFileSet()
+	b.schemas = newSchemaSet(files)
+	for i, serviceName := range serviceNames {
+		if i > 0 && serviceNames[i-1] == serviceName {
+			continue
+		}
+		fileDescriptors, _ := protobuf.FileDescriptors(serviceName)
+		if err := files.add(fileDescriptors); err != nil {
+			return nil, fmt.Errorf("could not read the descriptors of service %q: %v", serviceName, err)
+		}
+		service, ok := files.services[serviceName]
+		if !ok {
+			return nil, fmt.Errorf("service %q is not declared in its descriptors", serviceName)
+		}
+		b.doc.Tags = append(b.doc.Tags, tag{Name: serviceName})
+		if err := b.addService(serviceName, service); err != nil {
+			return nil, err
+		}
+	}
+	b.doc.Components.Schemas = b.schemas.schemas
+	return b.doc, nil
+}
+
+func (b *builder) addService(serviceName string, service *descriptor.ServiceDescriptorProto) error {
+	for _, method := range service.Method {
+		name := procedure.ToName(serviceName, method.GetName())
+		reg, ok := b.registrations[name]
+		if !ok || reg.Type == transport.Streaming {
+			continue
+		}
+		input, ok := b.schemas.files.messages[method.GetInputType()]
+		if !ok {
+			return fmt.Errorf("unknown request type %q of procedure %q", method.GetInputType(), name)
+		}
+		output, ok := b.schemas.files.messages[method.GetOutputType()]
+		if !ok {
+			return fmt.Errorf("unknown response type %q of procedure %q", method.GetOutputType(), name)
+		}
+
+		op := &operation{
+			OperationID: name,
+			Tags:        []string{serviceName},
+			Parameters:  yarpcParameters(reg.Service, name),
+			RequestBody: &requestBody{
+				Content:  jsonContent(b.schemas.ref(method.GetInputType())),
+				Required: true,
+			},
+			Responses: map[string]*response{"default": errorResponse()},
+		}
+		oneway, err := dynamic.IsOneway(method)
+		if err != nil {
+			return fmt.Errorf("invalid procedure %q: %v", name, err)
+		}
+		if oneway {
+			op.Responses["202"] = &response{Description: "The request was accepted."}
+		} else {
+			op.Responses["200"] = &response{
+				Description: "A successful response.",
+				Content:     jsonContent(b.schemas.ref(method.GetOutputType())),
+			}
+		}
+		b.addOperation("/"+name, "post", op)
+
+		if reg.Type != transport.Unary {
+			// Routes only serve unary procedures.
+			continue
+		}
+		rules, err := httpRules(method)
+		if err != nil {
+			return fmt.Errorf("invalid google.api.http option of procedure %q: %v", name, err)
+		}
+		for _, rule := range rules {
+			if err := b.addRoute(serviceName, rule, input, output); err != nil {
+				return fmt.Errorf("invalid google.api.http option of procedure %q: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builder) addOperation(path, method string, op *operation) {
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = make(pathItem)
+		b.doc.Paths[path] = item
+	}
+	item[method] = op
+}
+
+// _routeVariableRegexp matches the variables of a path template, such as
+// {id} or {name=shelves/*}.
+var _routeVariableRegexp = regexp.MustCompile(`\{([^=}]*)(=[^}]*)?\}`)
+
+func (b *builder) addRoute(serviceName string, rule *api.HttpRule, input, output *message) error {
+	var method, pattern string
+	switch p := rule.GetPattern().(type) {
+	case *api.HttpRule_Get:
+		method, pattern = "get", p.Get
+	case *api.HttpRule_Put:
+		method, pattern = "put", p.Put
+	case *api.HttpRule_Post:
+		method, pattern = "post", p.Post
+	case *api.HttpRule_Delete:
+		method, pattern = "delete", p.Delete
+	case *api.HttpRule_Patch:
+		method, pattern = "patch", p.Patch
+	case *api.HttpRule_Custom:
+		method, pattern = strings.ToLower(p.Custom.GetKind()), p.Custom.GetPath()
+		switch method {
+		case "head", "options", "trace":
+		default:
+			// OpenAPI has no way to describe other methods.
+			return nil
+		}
+	default:
+		return fmt.Errorf("no HTTP method and path")
+	}
+
+	op := &operation{
+		Tags:      []string{serviceName},
+		Responses: map[string]*response{"default": errorResponse()},
+	}
+
+	// Path variables are named after the field they set, and only their
+	// name appears in the path of the operation.
+	variables := make(map[string]struct{})
+	for _, match := range _routeVariableRegexp.FindAllStringSubmatch(pattern, -1) {
+		fieldSchema, err := b.schemas.fieldPathSchema(input, match[1])
+		if err != nil {
+			return err
+		}
+		variables[match[1]] = struct{}{}
+		op.Parameters = append(op.Parameters, &parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   fieldSchema,
+		})
+	}
+	path := _routeVariableRegexp.ReplaceAllString(pattern, "{$1}")
+
+	body := rule.GetBody()
+	switch body {
+	case "":
+	case "*":
+		op.RequestBody = &requestBody{Content: jsonContent(b.schemas.ref(input.name))}
+	default:
+		field, ok := input.field(body)
+		if !ok {
+			return fmt.Errorf("unknown body field %q", body)
+		}
+		op.RequestBody = &requestBody{Content: jsonContent(b.schemas.fieldSchema(field))}
+	}
+
+	// Other top-level scalar fields may be set with query parameters, unless
+	// the whole request is in the body.
+	if body != "*" {
+		for _, field := range input.descriptor.Field {
+			if _, ok := variables[field.GetName()]; ok || field.GetName() == body {
+				continue
+			}
+			fieldSchema := b.schemas.fieldSchema(field)
+			if fieldSchema.Ref != "" || fieldSchema.Type == "object" || (fieldSchema.Items != nil && fieldSchema.Items.Ref != "") {
+				continue
+			}
+			op.Parameters = append(op.Parameters, &parameter{
+				Name:   jsonName(field),
+				In:     "query",
+				Schema: fieldSchema,
+			})
+		}
+	}
+
+	responseSchema := b.schemas.ref(output.name)
+	if responseBody := rule.GetResponseBody(); responseBody != "" {
+		field, ok := output.field(responseBody)
+		if !ok {
+			return fmt.Errorf("unknown response body field %q", responseBody)
+		}
+		responseSchema = b.schemas.fieldSchema(field)
+	}
+	op.Responses["200"] = &response{
+		Description: "A successful response.",
+		Content:     jsonContent(responseSchema),
+	}
+
+	b.addOperation(path, method, op)
+	return nil
+}
+
+// httpRules returns the bindings declared with the google.api.http option of
+// a method, including its additional bindings.
+func httpRules(method *descriptor.MethodDescriptorProto) ([]*api.HttpRule, error) {
+	if method.GetOptions() == nil || !proto.HasExtension(method.GetOptions(), api.E_Http) {
+		return nil, nil
+	}
+	extension, err := proto.GetExtension(method.GetOptions(), api.E_Http)
+	if err != nil {
+		return nil, err
+	}
+	rule, ok := extension.(*api.HttpRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a %T but got a %T", rule, extension)
+	}
+	return append([]*api.HttpRule{rule}, rule.GetAdditionalBindings()...), nil
+}
+
+// yarpcParameters returns the headers of a request for a procedure with the
+// YARPC HTTP protocol.
+func yarpcParameters(service, name string) []*parameter {
+	return []*parameter{
+		{
+			Name:        yarpchttp.CallerHeader,
+			In:          "header",
+			Description: "Name of the calling service.",
+			Required:    true,
+			Schema:      &schema{Type: "string"},
+		},
+		{
+			Name:     yarpchttp.ServiceHeader,
+			In:       "header",
+			Required: true,
+			Schema:   &schema{Type: "string", Enum: []string{service}},
+		},
+		{
+			Name:     yarpchttp.ProcedureHeader,
+			In:       "header",
+			Required: true,
+			Schema:   &schema{Type: "string", Enum: []string{name}},
+		},
+		{
+			Name:     yarpchttp.EncodingHeader,
+			In:       "header",
+			Required: true,
+			Schema:   &schema{Type: "string", Enum: []string{string(protobuf.JSONEncoding)}},
+		},
+		{
+			Name:        yarpchttp.TTLMSHeader,
+			In:          "header",
+			Description: "Time in milliseconds that the caller waits for a response.",
+			Required:    true,
+			Schema:      &schema{Type: "integer"},
+		},
+	}
+}
+
+func errorResponse() *response {
+	return &response{
+		Description: "An error, whose code is in the " + yarpchttp.ErrorCodeHeader + " header and whose message is the body.",
+	}
+}
+
+func jsonContent(s *schema) map[string]mediaType {
+	return map[string]mediaType{_jsonMediaType: {Schema: s}}
+}
+
+// fileSet indexes the messages, enums and services of protobuf files by
+// their fully qualified name.
+type fileSet struct {
+	files    map[string]struct{}
+	messages map[string]*message // by name with a leading period
+	enums    map[string]*descriptor.EnumDescriptorProto
+	services map[string]*descriptor.ServiceDescriptorProto // without a leading period
+}
+
+type message struct {
+	name       string
+	descriptor *descriptor.DescriptorProto
+}
+
+func (m *message) field(name string) (*descriptor.FieldDescriptorProto, bool) {
+	for _, field := range m.descriptor.Field {
+		if field.GetName() == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+func newFileSet() *fileSet {
+	return &fileSet{
+		files:    make(map[string]struct{}),
+		messages: make(map[string]*message),
+		enums:    make(map[string]*descriptor.EnumDescriptorProto),
+		services: make(map[string]*descriptor.ServiceDescriptorProto),
+	}
+}
+
+func (fs *fileSet) add(fileDescriptors [][]byte) error {
+	for _, compressed := range fileDescriptors {
+		fd, err := decodeFileDescriptor(compressed)
+		if err != nil {
+			return err
+		}
+		if _, ok := fs.files[fd.GetName()]; ok {
+			continue
+		}
+		fs.files[fd.GetName()] = struct{}{}
+
+		prefix := "."
+		if fd.GetPackage() != "" {
+			prefix += fd.GetPackage() + "."
+		}
+		fs.addMessages(prefix, fd.MessageType)
+		fs.addEnums(prefix, fd.EnumType)
+		for _, s := range fd.Service {
+			fs.services[prefix[1:]+s.GetName()] = s
+		}
+	}
+	return nil
+}
+
+func (fs *fileSet) addMessages(prefix string, messages []*descriptor.DescriptorProto) {
+	for _, m := range messages {
+		name := prefix + m.GetName()
+		fs.messages[name] = &message{name: name, descriptor: m}
+		fs.addMessages(name+".", m.NestedType)
+		fs.addEnums(name+".", m.EnumType)
+	}
+}
+
+func (fs *fileSet) addEnums(prefix string, enums []*descriptor.EnumDescriptorProto) {
+	for _, e := range enums {
+		fs.enums[prefix+e.GetName()] = e
+	}
+}
+
+func decodeFileDescriptor(compressed []byte) (*descriptor.FileDescriptorProto, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(data, &fd); err != nil {
+		return nil, err
+	}
+	return &fd, nil
+}
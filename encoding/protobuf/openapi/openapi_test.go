@@ -0,0 +1,321 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+
+	"github.com/gogo/googleapis/google/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+func field(name, jsonName string, number int32, fieldType descriptor.FieldDescriptorProto_Type, typeName string) *descriptor.FieldDescriptorProto {
+	f := &descriptor.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(jsonName),
+		Number:   proto.Int32(number),
+		Type:     fieldType.Enum(),
+		Label:    descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func repeated(f *descriptor.FieldDescriptorProto) *descriptor.FieldDescriptorProto {
+	f.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return f
+}
+
+func testFileDescriptor(t *testing.T) []byte {
+	getOptions := &descriptor.MethodOptions{}
+	require.NoError(t, proto.SetExtension(getOptions, api.E_Http, &api.HttpRule{
+		Pattern: &api.HttpRule_Get{Get: "/v1/values/{key=keys/*}"},
+		AdditionalBindings: []*api.HttpRule{
+			{Pattern: &api.HttpRule_Post{Post: "/v1/values:get"}, Body: "*"},
+		},
+	}))
+	fd := &descriptor.FileDescriptorProto{
+		Name:       proto.String("test/kv.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"yarpcproto/yarpc.proto"},
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("GetRequest"),
+				Field: []*descriptor.FieldDescriptorProto{
+					field("key", "key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+					field("kind", "kind", 2, descriptor.FieldDescriptorProto_TYPE_ENUM, ".test.Kind"),
+					repeated(field("labels", "labels", 3, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.GetRequest.LabelsEntry")),
+					field("max_size", "maxSize", 4, descriptor.FieldDescriptorProto_TYPE_INT64, ""),
+				},
+				NestedType: []*descriptor.DescriptorProto{
+					{
+						Name: proto.String("LabelsEntry"),
+						Field: []*descriptor.FieldDescriptorProto{
+							field("key", "key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+							field("value", "value", 2, descriptor.FieldDescriptorProto_TYPE_STRING, ""),
+						},
+						Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+			{
+				Name: proto.String("Value"),
+				Field: []*descriptor.FieldDescriptorProto{
+					field("data", "data", 1, descriptor.FieldDescriptorProto_TYPE_BYTES, ""),
+					repeated(field("children", "children", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".test.Value")),
+				},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name: proto.String("Kind"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					{Name: proto.String("DEFAULT"), Number: proto.Int32(0)},
+					{Name: proto.String("LATEST"), Number: proto.Int32(1)},
+				},
+			},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{
+			{
+				Name: proto.String("KeyValue"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{
+						Name:       proto.String("Get"),
+						InputType:  proto.String(".test.GetRequest"),
+						OutputType: proto.String(".test.Value"),
+						Options:    getOptions,
+					},
+					{
+						Name:       proto.String("Fire"),
+						InputType:  proto.String(".test.Value"),
+						OutputType: proto.String(".uber.yarpc.Oneway"),
+					},
+					{
+						Name:            proto.String("Watch"),
+						InputType:       proto.String(".test.GetRequest"),
+						OutputType:      proto.String(".test.Value"),
+						ServerStreaming: proto.Bool(true),
+					},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(fd)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// newTestRouter returns a router with the procedures of the test.KeyValue
+// service, whose file descriptors are registered, and of the
+// test.Undescribed service, whose are not.
+func newTestRouter(t *testing.T) transport.Router {
+	unary := protobuf.NewUnaryHandler(protobuf.UnaryHandlerParams{
+		Handle: func(context.Context, proto.Message) (proto.Message, error) {
+			return nil, nil
+		},
+		NewRequest: func() proto.Message { return &yarpcproto.Oneway{} },
+	})
+	oneway := protobuf.NewOnewayHandler(protobuf.OnewayHandlerParams{
+		Handle: func(context.Context, proto.Message) error {
+			return nil
+		},
+		NewRequest: func() proto.Message { return &yarpcproto.Oneway{} },
+	})
+	stream := protobuf.NewStreamHandler(protobuf.StreamHandlerParams{
+		Handle: func(*protobuf.ServerStream) error {
+			return nil
+		},
+	})
+
+	router := yarpc.NewMapRouter("keyvalue")
+	router.Register(protobuf.BuildProcedures(protobuf.BuildProceduresParams{
+		ServiceName:         "test.KeyValue",
+		UnaryHandlerParams:  []protobuf.BuildProceduresUnaryHandlerParams{{MethodName: "Get", Handler: unary}},
+		OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{{MethodName: "Fire", Handler: oneway}},
+		StreamHandlerParams: []protobuf.BuildProceduresStreamHandlerParams{{MethodName: "Watch", Handler: stream}},
+		FileDescriptors: [][]byte{
+			testFileDescriptor(t),
+			proto.FileDescriptor("yarpcproto/yarpc.proto"),
+		},
+	}))
+	router.Register(protobuf.BuildProcedures(protobuf.BuildProceduresParams{
+		ServiceName:        "test.Undescribed",
+		UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{{MethodName: "Get", Handler: unary}},
+	}))
+	router.Register(Procedures(router, Info{Title: "keyvalue", Version: "1.0.0"}))
+	return router
+}
+
+const _wantDocument = `{
+  "openapi": "3.0.1",
+  "info": {"title": "keyvalue", "version": "1.0.0"},
+  "tags": [{"name": "test.KeyValue"}],
+  "paths": {
+    "/test.KeyValue::Get": {
+      "post": {
+        "operationId": "test.KeyValue::Get",
+        "tags": ["test.KeyValue"],
+        "parameters": [
+          {"name": "Rpc-Caller", "in": "header", "description": "Name of the calling service.", "required": true, "schema": {"type": "string"}},
+          {"name": "Rpc-Service", "in": "header", "required": true, "schema": {"type": "string", "enum": ["keyvalue"]}},
+          {"name": "Rpc-Procedure", "in": "header", "required": true, "schema": {"type": "string", "enum": ["test.KeyValue::Get"]}},
+          {"name": "Rpc-Encoding", "in": "header", "required": true, "schema": {"type": "string", "enum": ["json"]}},
+          {"name": "Context-TTL-MS", "in": "header", "description": "Time in milliseconds that the caller waits for a response.", "required": true, "schema": {"type": "integer"}}
+        ],
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.GetRequest"}}},
+          "required": true
+        },
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.Value"}}}
+          },
+          "default": {"description": "An error, whose code is in the Rpc-Error-Code header and whose message is the body."}
+        }
+      }
+    },
+    "/test.KeyValue::Fire": {
+      "post": {
+        "operationId": "test.KeyValue::Fire",
+        "tags": ["test.KeyValue"],
+        "parameters": [
+          {"name": "Rpc-Caller", "in": "header", "description": "Name of the calling service.", "required": true, "schema": {"type": "string"}},
+          {"name": "Rpc-Service", "in": "header", "required": true, "schema": {"type": "string", "enum": ["keyvalue"]}},
+          {"name": "Rpc-Procedure", "in": "header", "required": true, "schema": {"type": "string", "enum": ["test.KeyValue::Fire"]}},
+          {"name": "Rpc-Encoding", "in": "header", "required": true, "schema": {"type": "string", "enum": ["json"]}},
+          {"name": "Context-TTL-MS", "in": "header", "description": "Time in milliseconds that the caller waits for a response.", "required": true, "schema": {"type": "integer"}}
+        ],
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.Value"}}},
+          "required": true
+        },
+        "responses": {
+          "202": {"description": "The request was accepted."},
+          "default": {"description": "An error, whose code is in the Rpc-Error-Code header and whose message is the body."}
+        }
+      }
+    },
+    "/v1/values/{key}": {
+      "get": {
+        "tags": ["test.KeyValue"],
+        "parameters": [
+          {"name": "key", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "kind", "in": "query", "schema": {"type": "string", "enum": ["DEFAULT", "LATEST"]}},
+          {"name": "maxSize", "in": "query", "schema": {"type": "string", "format": "int64"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.Value"}}}
+          },
+          "default": {"description": "An error, whose code is in the Rpc-Error-Code header and whose message is the body."}
+        }
+      }
+    },
+    "/v1/values:get": {
+      "post": {
+        "tags": ["test.KeyValue"],
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.GetRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/test.Value"}}}
+          },
+          "default": {"description": "An error, whose code is in the Rpc-Error-Code header and whose message is the body."}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "test.GetRequest": {
+        "type": "object",
+        "properties": {
+          "key": {"type": "string"},
+          "kind": {"type": "string", "enum": ["DEFAULT", "LATEST"]},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "maxSize": {"type": "string", "format": "int64"}
+        }
+      },
+      "test.Value": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "string", "format": "byte"},
+          "children": {"type": "array", "items": {"$ref": "#/components/schemas/test.Value"}}
+        }
+      }
+    }
+  }
+}`
+
+func TestDocument(t *testing.T) {
+	router := newTestRouter(t)
+	doc, err := Document(router, Info{Title: "keyvalue", Version: "1.0.0"})
+	require.NoError(t, err)
+	assert.JSONEq(t, _wantDocument, string(doc))
+}
+
+func TestProcedures(t *testing.T) {
+	procedures := Procedures(yarpc.NewMapRouter("keyvalue"), Info{Title: "keyvalue", Version: "1.0.0"})
+	require.Len(t, procedures, 1)
+	assert.Equal(t, Procedure, procedures[0].Name)
+	assert.Equal(t, transport.Encoding("json"), procedures[0].Encoding)
+}
+
+func TestDocumentUndeclaredService(t *testing.T) {
+	router := yarpc.NewMapRouter("keyvalue")
+	router.Register(protobuf.BuildProcedures(protobuf.BuildProceduresParams{
+		ServiceName: "test.Missing",
+		UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{{
+			MethodName: "Get",
+			Handler: protobuf.NewUnaryHandler(protobuf.UnaryHandlerParams{
+				Handle: func(context.Context, proto.Message) (proto.Message, error) {
+					return nil, nil
+				},
+				NewRequest: func() proto.Message { return &yarpcproto.Oneway{} },
+			}),
+		}},
+		FileDescriptors: [][]byte{testFileDescriptor(t)},
+	}))
+	_, err := Document(router, Info{Title: "keyvalue", Version: "1.0.0"})
+	assert.EqualError(t, err, `service "test.Missing" is not declared in its descriptors`)
+}
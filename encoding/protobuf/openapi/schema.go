@@ -0,0 +1,190 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// schema is an OpenAPI schema object, describing the JSON representation
+// of protobuf messages produced by jsonpb.
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+// _wellKnownSchemas are the schemas of the well-known types that jsonpb
+// represents specially.
+var _wellKnownSchemas = map[string]*schema{
+	".google.protobuf.Any":         {Type: "object", Properties: map[string]*schema{"@type": {Type: "string"}}, AdditionalProperties: &schema{}},
+	".google.protobuf.Duration":    {Type: "string"},
+	".google.protobuf.Empty":       {Type: "object"},
+	".google.protobuf.FieldMask":   {Type: "string"},
+	".google.protobuf.ListValue":   {Type: "array", Items: &schema{}},
+	".google.protobuf.Struct":      {Type: "object", AdditionalProperties: &schema{}},
+	".google.protobuf.Timestamp":   {Type: "string", Format: "date-time"},
+	".google.protobuf.Value":       {},
+	".google.protobuf.BoolValue":   {Type: "boolean"},
+	".google.protobuf.BytesValue":  {Type: "string", Format: "byte"},
+	".google.protobuf.DoubleValue": {Type: "number", Format: "double"},
+	".google.protobuf.FloatValue":  {Type: "number", Format: "float"},
+	".google.protobuf.Int32Value":  {Type: "integer", Format: "int32"},
+	".google.protobuf.Int64Value":  {Type: "string", Format: "int64"},
+	".google.protobuf.StringValue": {Type: "string"},
+	".google.protobuf.UInt32Value": {Type: "integer", Format: "int64"},
+	".google.protobuf.UInt64Value": {Type: "string", Format: "uint64"},
+}
+
+// schemaSet builds the schemas of messages, adding the schemas of the
+// messages they reference to the components of the document.
+type schemaSet struct {
+	files   *fileSet
+	schemas map[string]*schema // by name without a leading period
+}
+
+func newSchemaSet(files *fileSet) *schemaSet {
+	return &schemaSet{files: files, schemas: make(map[string]*schema)}
+}
+
+// ref returns the schema of the message with the given fully qualified
+// name, referencing its component unless it is a well-known type.
+func (s *schemaSet) ref(name string) *schema {
+	if wellKnown, ok := _wellKnownSchemas[name]; ok {
+		return wellKnown
+	}
+	key := strings.TrimPrefix(name, ".")
+	ref := &schema{Ref: "#/components/schemas/" + key}
+	if _, ok := s.schemas[key]; ok {
+		return ref
+	}
+	m, ok := s.files.messages[name]
+	if !ok {
+		// The descriptors of a service include all the files it imports, so
+		// this only happens if they were tampered with.
+		return &schema{Type: "object"}
+	}
+
+	// Register the component before building it, for recursive messages.
+	component := &schema{Type: "object", Properties: make(map[string]*schema)}
+	s.schemas[key] = component
+	for _, field := range m.descriptor.Field {
+		component.Properties[jsonName(field)] = s.fieldSchema(field)
+	}
+	return ref
+}
+
+// fieldSchema returns the schema of the value of a field.
+func (s *schemaSet) fieldSchema(field *descriptor.FieldDescriptorProto) *schema {
+	if field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		if entry, ok := s.files.messages[field.GetTypeName()]; ok && entry.descriptor.GetOptions().GetMapEntry() {
+			value, _ := entry.field("value")
+			return &schema{Type: "object", AdditionalProperties: s.singularSchema(value)}
+		}
+	}
+	valueSchema := s.singularSchema(field)
+	if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return &schema{Type: "array", Items: valueSchema}
+	}
+	return valueSchema
+}
+
+// fieldPathSchema returns the schema of the field named by a dot-separated
+// path of field names from a message.
+func (s *schemaSet) fieldPathSchema(m *message, path string) (*schema, error) {
+	var field *descriptor.FieldDescriptorProto
+	for _, name := range strings.Split(path, ".") {
+		if m == nil {
+			return nil, fmt.Errorf("%s is not a message field", field.GetName())
+		}
+		var ok bool
+		if field, ok = m.field(name); !ok {
+			return nil, fmt.Errorf("message %s has no field %s", strings.TrimPrefix(m.name, "."), name)
+		}
+		m = nil
+		if field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			m = s.files.messages[field.GetTypeName()]
+		}
+	}
+	return s.fieldSchema(field), nil
+}
+
+func (s *schemaSet) singularSchema(field *descriptor.FieldDescriptorProto) *schema {
+	if field == nil {
+		return &schema{}
+	}
+	switch field.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return &schema{Type: "number", Format: "double"}
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return &schema{Type: "number", Format: "float"}
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return &schema{Type: "integer", Format: "int32"}
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return &schema{Type: "integer", Format: "int64"}
+	// jsonpb represents 64-bit integers as strings, since they do not fit in
+	// JavaScript numbers.
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return &schema{Type: "string", Format: "int64"}
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return &schema{Type: "string", Format: "uint64"}
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return &schema{Type: "boolean"}
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return &schema{Type: "string"}
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return &schema{Type: "string", Format: "byte"}
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		enumSchema := &schema{Type: "string"}
+		if enum, ok := s.files.enums[field.GetTypeName()]; ok {
+			for _, value := range enum.Value {
+				enumSchema.Enum = append(enumSchema.Enum, value.GetName())
+			}
+		}
+		return enumSchema
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return s.ref(field.GetTypeName())
+	default:
+		return &schema{}
+	}
+}
+
+// jsonName returns the name of a field in the JSON representation of its
+// message. protoc sets it in the descriptors given to plugins.
+func jsonName(field *descriptor.FieldDescriptorProto) string {
+	if name := field.GetJsonName(); name != "" {
+		return name
+	}
+	return field.GetName()
+}
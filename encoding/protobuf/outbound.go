@@ -38,6 +38,13 @@ type client struct {
 	serviceName    string
 	outboundConfig *transport.OutboundConfig
 	encoding       transport.Encoding
+	codec          *codec
+
+	procedureOptions map[string]transport.ProcedureOptions
+	validateRequests bool
+
+	interceptors []UnaryInterceptor
+	invoke       UnaryInvoker
 }
 
 func newClient(serviceName string, clientConfig transport.ClientConfig, options ...ClientOption) *client {
@@ -46,10 +53,12 @@ func newClient(serviceName string, clientConfig transport.ClientConfig, options
 		serviceName:    serviceName,
 		outboundConfig: outboundConfig,
 		encoding:       Encoding,
+		codec:          _defaultCodec,
 	}
 	for _, option := range options {
 		option.apply(client)
 	}
+	client.invoke = chainUnaryInterceptors(client.interceptors, client.call)
 	return client
 }
 
@@ -78,6 +87,18 @@ func (c *client) Call(
 	newResponse func() proto.Message,
 	options ...yarpc.CallOption,
 ) (proto.Message, error) {
+	return c.invoke(ctx, requestMethodName, request, newResponse, options...)
+}
+
+func (c *client) call(
+	ctx context.Context,
+	requestMethodName string,
+	request proto.Message,
+	newResponse func() proto.Message,
+	options ...yarpc.CallOption,
+) (proto.Message, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.procedureOptions[requestMethodName].Timeout)
+	defer cancel()
 	ctx, call, transportRequest, cleanup, err := c.buildTransportRequest(ctx, requestMethodName, request, options)
 	if cleanup != nil {
 		defer cleanup()
@@ -103,7 +124,7 @@ func (c *client) Call(
 	var response proto.Message
 	if transportResponse.Body != nil {
 		response = newResponse()
-		if err := unmarshal(transportRequest.Encoding, transportResponse.Body, response); err != nil {
+		if err := c.codec.unmarshal(transportRequest.Encoding, transportResponse.Body, response); err != nil {
 			return nil, errors.ResponseBodyDecodeError(transportRequest, err)
 		}
 	}
@@ -116,6 +137,8 @@ func (c *client) CallOneway(
 	request proto.Message,
 	options ...yarpc.CallOption,
 ) (transport.Ack, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.procedureOptions[requestMethodName].Timeout)
+	defer cancel()
 	ctx, _, transportRequest, cleanup, err := c.buildTransportRequest(ctx, requestMethodName, request, options)
 	if cleanup != nil {
 		defer cleanup()
@@ -146,7 +169,12 @@ func (c *client) buildTransportRequest(ctx context.Context, requestMethodName st
 		return nil, nil, nil, nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "can only use encodings %q or %q, but %q was specified", Encoding, JSONEncoding, transportRequest.Encoding)
 	}
 	if request != nil {
-		requestData, cleanup, err := marshal(transportRequest.Encoding, request)
+		if c.validateRequests {
+			if err := validateMessage(request); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		}
+		requestData, cleanup, err := c.codec.marshal(transportRequest.Encoding, request)
 		if err != nil {
 			return nil, nil, nil, cleanup, errors.RequestBodyEncodeError(transportRequest, err)
 		}
@@ -190,5 +218,5 @@ func (c *client) CallStream(
 	if err != nil {
 		return nil, err
 	}
-	return &ClientStream{stream: stream}, nil
+	return &ClientStream{stream: stream, codec: c.codec, validateRequests: c.validateRequests}, nil
 }
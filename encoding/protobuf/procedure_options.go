@@ -0,0 +1,126 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// procedureOptions returns the given options for a transport.Procedure, or
+// nil if none were declared.
+func procedureOptions(options transport.ProcedureOptions) *transport.ProcedureOptions {
+	if options.Timeout == 0 && !options.Idempotent && options.Retry == (transport.RetryPolicy{}) && len(options.RequiredHeaders) == 0 {
+		return nil
+	}
+	return &options
+}
+
+// withUnaryProcedureOptions wraps a handler to enforce the given
+// ProcedureOptions. The handler is returned as-is if there is nothing to
+// enforce.
+func withUnaryProcedureOptions(handler transport.UnaryHandler, options transport.ProcedureOptions) transport.UnaryHandler {
+	if len(options.RequiredHeaders) == 0 {
+		return handler
+	}
+	return &requiredHeadersUnaryHandler{handler, options.RequiredHeaders}
+}
+
+// withOnewayProcedureOptions wraps a handler to enforce the given
+// ProcedureOptions. The handler is returned as-is if there is nothing to
+// enforce.
+func withOnewayProcedureOptions(handler transport.OnewayHandler, options transport.ProcedureOptions) transport.OnewayHandler {
+	if len(options.RequiredHeaders) == 0 {
+		return handler
+	}
+	return &requiredHeadersOnewayHandler{handler, options.RequiredHeaders}
+}
+
+// withStreamProcedureOptions wraps a handler to enforce the given
+// ProcedureOptions. The handler is returned as-is if there is nothing to
+// enforce.
+func withStreamProcedureOptions(handler transport.StreamHandler, options transport.ProcedureOptions) transport.StreamHandler {
+	if len(options.RequiredHeaders) == 0 {
+		return handler
+	}
+	return &requiredHeadersStreamHandler{handler, options.RequiredHeaders}
+}
+
+type requiredHeadersUnaryHandler struct {
+	handler         transport.UnaryHandler
+	requiredHeaders []string
+}
+
+func (h *requiredHeadersUnaryHandler) Handle(ctx context.Context, request *transport.Request, responseWriter transport.ResponseWriter) error {
+	if err := checkRequiredHeaders(request.Procedure, request.Headers, h.requiredHeaders); err != nil {
+		return err
+	}
+	return h.handler.Handle(ctx, request, responseWriter)
+}
+
+type requiredHeadersOnewayHandler struct {
+	handler         transport.OnewayHandler
+	requiredHeaders []string
+}
+
+func (h *requiredHeadersOnewayHandler) HandleOneway(ctx context.Context, request *transport.Request) error {
+	if err := checkRequiredHeaders(request.Procedure, request.Headers, h.requiredHeaders); err != nil {
+		return err
+	}
+	return h.handler.HandleOneway(ctx, request)
+}
+
+type requiredHeadersStreamHandler struct {
+	handler         transport.StreamHandler
+	requiredHeaders []string
+}
+
+func (h *requiredHeadersStreamHandler) HandleStream(stream *transport.ServerStream) error {
+	meta := stream.Request().Meta
+	if err := checkRequiredHeaders(meta.Procedure, meta.Headers, h.requiredHeaders); err != nil {
+		return err
+	}
+	return h.handler.HandleStream(stream)
+}
+
+func checkRequiredHeaders(procedure string, headers transport.Headers, requiredHeaders []string) error {
+	for _, key := range requiredHeaders {
+		if _, ok := headers.Get(key); !ok {
+			return yarpcerrors.InvalidArgumentErrorf("missing required header %q for procedure %q", key, procedure)
+		}
+	}
+	return nil
+}
+
+// withDefaultTimeout applies the default timeout of a procedure to a context
+// without a deadline.
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestBuildProceduresProcedureOptions(t *testing.T) {
+	options := transport.ProcedureOptions{
+		Timeout:         time.Second,
+		Idempotent:      true,
+		Retry:           transport.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond},
+		RequiredHeaders: []string{"x-tenant"},
+	}
+	var called int
+	procedures := BuildProcedures(BuildProceduresParams{
+		ServiceName: "foo",
+		UnaryHandlerParams: []BuildProceduresUnaryHandlerParams{
+			{
+				MethodName: "bar",
+				Handler: NewUnaryHandler(UnaryHandlerParams{
+					Handle: func(context.Context, proto.Message) (proto.Message, error) {
+						called++
+						return &types.Empty{}, nil
+					},
+					NewRequest: func() proto.Message { return &types.Empty{} },
+				}),
+				ProcedureOptions: options,
+			},
+		},
+		OnewayHandlerParams: []BuildProceduresOnewayHandlerParams{
+			{
+				MethodName: "baz",
+				Handler: NewOnewayHandler(OnewayHandlerParams{
+					Handle:     func(context.Context, proto.Message) error { return nil },
+					NewRequest: func() proto.Message { return &types.Empty{} },
+				}),
+			},
+		},
+	})
+	require.Len(t, procedures, 4)
+	for _, p := range procedures {
+		if p.Name == "foo::bar" {
+			require.NotNil(t, p.Options)
+			assert.Equal(t, options, *p.Options)
+		} else {
+			assert.Nil(t, p.Options)
+		}
+	}
+
+	unary := procedures[0].HandlerSpec.Unary()
+	err := unary.Handle(context.Background(), &transport.Request{
+		Caller:    "caller",
+		Service:   "service",
+		Procedure: "foo::bar",
+		Encoding:  Encoding,
+	}, new(transporttest.FakeResponseWriter))
+	require.Error(t, err)
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+	assert.Contains(t, err.Error(), `missing required header "x-tenant"`)
+	assert.Equal(t, 0, called)
+
+	err = unary.Handle(context.Background(), &transport.Request{
+		Caller:    "caller",
+		Service:   "service",
+		Procedure: "foo::bar",
+		Encoding:  Encoding,
+		Headers:   transport.NewHeaders().With("X-Tenant", "qux"),
+		Body:      bytes.NewReader(nil),
+	}, new(transporttest.FakeResponseWriter))
+	require.NoError(t, err)
+	assert.Equal(t, 1, called)
+}
+
+func TestRequiredHeadersOnewayHandler(t *testing.T) {
+	var called bool
+	handler := withOnewayProcedureOptions(
+		NewOnewayHandler(OnewayHandlerParams{
+			Handle: func(context.Context, proto.Message) error {
+				called = true
+				return nil
+			},
+			NewRequest: func() proto.Message { return &types.Empty{} },
+		}),
+		transport.ProcedureOptions{RequiredHeaders: []string{"x-tenant"}},
+	)
+
+	err := handler.HandleOneway(context.Background(), &transport.Request{Procedure: "foo::baz", Encoding: Encoding})
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+	assert.False(t, called)
+
+	err = handler.HandleOneway(context.Background(), &transport.Request{
+		Procedure: "foo::baz",
+		Encoding:  Encoding,
+		Headers:   transport.NewHeaders().With("x-tenant", "qux"),
+		Body:      bytes.NewReader(nil),
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestClientProcedureOptionsTimeout(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newClientFromParams(ClientParams{
+		ServiceName: "foo",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds:  transport.Outbounds{ServiceName: "service", Unary: outbound},
+		},
+		ProcedureOptions: map[string]transport.ProcedureOptions{
+			"bar": {Timeout: time.Minute},
+		},
+	})
+
+	var deadline time.Time
+	var hasDeadline bool
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *transport.Request) (*transport.Response, error) {
+			deadline, hasDeadline = ctx.Deadline()
+			return &transport.Response{}, nil
+		},
+	).Times(3)
+
+	_, err := client.Call(context.Background(), "bar", &types.Empty{}, newEmpty)
+	require.NoError(t, err)
+	assert.True(t, hasDeadline, "procedure timeout must be applied")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = client.Call(ctx, "bar", &types.Empty{}, newEmpty)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, 10*time.Second, "caller deadline must take precedence")
+
+	_, err = client.Call(context.Background(), "qux", &types.Empty{}, newEmpty)
+	require.NoError(t, err)
+	assert.False(t, hasDeadline, "methods without a timeout must not get a deadline")
+}
+
+func newEmpty() proto.Message {
+	return &types.Empty{}
+}
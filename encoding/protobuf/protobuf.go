@@ -53,71 +53,132 @@ type BuildProceduresParams struct {
 	UnaryHandlerParams  []BuildProceduresUnaryHandlerParams
 	OnewayHandlerParams []BuildProceduresOnewayHandlerParams
 	StreamHandlerParams []BuildProceduresStreamHandlerParams
+
+	// FileDescriptors are the gzipped FileDescriptorProtos of the file
+	// declaring the service and of all files it imports, for reflection.
+	FileDescriptors [][]byte
+
+	// JSONOptions configures the JSON representation of messages handled
+	// with JSONEncoding by handlers built with this package.
+	JSONOptions JSONOptions
+
+	// ValidateRequests validates request messages with their Validate
+	// method before handling them.
+	ValidateRequests bool
+
+	// Options are applied to these parameters before the procedures are
+	// built.
+	Options []ProceduresOption
+}
+
+// ProceduresOption is an option for BuildProcedures.
+type ProceduresOption interface {
+	applyProcedures(*BuildProceduresParams)
 }
 
 // BuildProceduresUnaryHandlerParams contains the parameters for a UnaryHandler for BuildProcedures.
 type BuildProceduresUnaryHandlerParams struct {
 	MethodName string
 	Handler    transport.UnaryHandler
+
+	// ProcedureOptions are the options declared for the method in its
+	// Protobuf definition.
+	ProcedureOptions transport.ProcedureOptions
 }
 
 // BuildProceduresOnewayHandlerParams contains the parameters for a OnewayHandler for BuildProcedures.
 type BuildProceduresOnewayHandlerParams struct {
 	MethodName string
 	Handler    transport.OnewayHandler
+
+	// ProcedureOptions are the options declared for the method in its
+	// Protobuf definition.
+	ProcedureOptions transport.ProcedureOptions
 }
 
 // BuildProceduresStreamHandlerParams contains the parameters for a StreamHandler for BuildProcedures.
 type BuildProceduresStreamHandlerParams struct {
 	MethodName string
 	Handler    transport.StreamHandler
+
+	// ProcedureOptions are the options declared for the method in its
+	// Protobuf definition.
+	ProcedureOptions transport.ProcedureOptions
 }
 
 // BuildProcedures builds the transport.Procedures.
 func BuildProcedures(params BuildProceduresParams) []transport.Procedure {
+	for _, option := range params.Options {
+		option.applyProcedures(&params)
+	}
+	if len(params.FileDescriptors) > 0 {
+		registerFileDescriptors(params.ServiceName, params.FileDescriptors)
+	}
+	codec := newCodec(params.JSONOptions)
 	procedures := make([]transport.Procedure, 0, 2*(len(params.UnaryHandlerParams)+len(params.OnewayHandlerParams)))
 	for _, unaryHandlerParams := range params.UnaryHandlerParams {
+		unaryHandlerParams.Handler = withUnaryCodec(unaryHandlerParams.Handler, codec)
+		if params.ValidateRequests {
+			unaryHandlerParams.Handler = withUnaryValidation(unaryHandlerParams.Handler)
+		}
+		unaryHandlerParams.Handler = withUnaryProcedureOptions(unaryHandlerParams.Handler, unaryHandlerParams.ProcedureOptions)
 		procedures = append(
 			procedures,
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, unaryHandlerParams.MethodName),
 				HandlerSpec: transport.NewUnaryHandlerSpec(unaryHandlerParams.Handler),
 				Encoding:    Encoding,
+				Options:     procedureOptions(unaryHandlerParams.ProcedureOptions),
 			},
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, unaryHandlerParams.MethodName),
 				HandlerSpec: transport.NewUnaryHandlerSpec(unaryHandlerParams.Handler),
 				Encoding:    JSONEncoding,
+				Options:     procedureOptions(unaryHandlerParams.ProcedureOptions),
 			},
 		)
 	}
 	for _, onewayHandlerParams := range params.OnewayHandlerParams {
+		onewayHandlerParams.Handler = withOnewayCodec(onewayHandlerParams.Handler, codec)
+		if params.ValidateRequests {
+			onewayHandlerParams.Handler = withOnewayValidation(onewayHandlerParams.Handler)
+		}
+		onewayHandlerParams.Handler = withOnewayProcedureOptions(onewayHandlerParams.Handler, onewayHandlerParams.ProcedureOptions)
 		procedures = append(
 			procedures,
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, onewayHandlerParams.MethodName),
 				HandlerSpec: transport.NewOnewayHandlerSpec(onewayHandlerParams.Handler),
 				Encoding:    Encoding,
+				Options:     procedureOptions(onewayHandlerParams.ProcedureOptions),
 			},
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, onewayHandlerParams.MethodName),
 				HandlerSpec: transport.NewOnewayHandlerSpec(onewayHandlerParams.Handler),
 				Encoding:    JSONEncoding,
+				Options:     procedureOptions(onewayHandlerParams.ProcedureOptions),
 			},
 		)
 	}
 	for _, streamHandlerParams := range params.StreamHandlerParams {
+		streamHandlerParams.Handler = withStreamCodec(streamHandlerParams.Handler, codec)
+		if params.ValidateRequests {
+			streamHandlerParams.Handler = withStreamValidation(streamHandlerParams.Handler)
+		}
+		streamHandlerParams.Handler = withStreamProcedureOptions(streamHandlerParams.Handler, streamHandlerParams.ProcedureOptions)
 		procedures = append(
 			procedures,
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, streamHandlerParams.MethodName),
 				HandlerSpec: transport.NewStreamHandlerSpec(streamHandlerParams.Handler),
 				Encoding:    Encoding,
+				Options:     procedureOptions(streamHandlerParams.ProcedureOptions),
 			},
 			transport.Procedure{
 				Name:        procedure.ToName(params.ServiceName, streamHandlerParams.MethodName),
 				HandlerSpec: transport.NewStreamHandlerSpec(streamHandlerParams.Handler),
 				Encoding:    JSONEncoding,
+				Options:     procedureOptions(streamHandlerParams.ProcedureOptions),
 			},
 		)
 	}
@@ -162,16 +223,41 @@ type ClientParams struct {
 	ServiceName  string
 	ClientConfig transport.ClientConfig
 	Options      []ClientOption
+
+	// JSONOptions configures the JSON representation of messages sent with
+	// JSONEncoding. JSONOptions given in Options take precedence.
+	JSONOptions JSONOptions
+
+	// ProcedureOptions are the options declared for the methods of the
+	// service in its Protobuf definition, keyed by method name. The Timeout
+	// of a method is used for calls whose context has no deadline.
+	ProcedureOptions map[string]transport.ProcedureOptions
+
+	// ValidateRequests validates request messages with their Validate
+	// method before sending them.
+	ValidateRequests bool
 }
 
 // NewClient creates a new client.
 func NewClient(params ClientParams) Client {
-	return newClient(params.ServiceName, params.ClientConfig, params.Options...)
+	return newClientFromParams(params)
 }
 
 // NewStreamClient creates a new stream client.
 func NewStreamClient(params ClientParams) StreamClient {
-	return newClient(params.ServiceName, params.ClientConfig, params.Options...)
+	return newClientFromParams(params)
+}
+
+func newClientFromParams(params ClientParams) *client {
+	options := make([]ClientOption, 0, len(params.Options)+1)
+	options = append(options, params.JSONOptions)
+	options = append(options, params.Options...)
+	client := newClient(params.ServiceName, params.ClientConfig, options...)
+	client.procedureOptions = params.ProcedureOptions
+	if params.ValidateRequests {
+		client.validateRequests = true
+	}
+	return client
 }
 
 // UnaryHandlerParams contains the parameters for creating a new UnaryHandler.
@@ -247,6 +333,9 @@ func uniqueLowercaseStrings(s []string) []string {
 // ClientStream is a protobuf-specific client stream.
 type ClientStream struct {
 	stream *transport.ClientStream
+	codec  *codec
+
+	validateRequests bool
 }
 
 // Context returns the context of the stream.
@@ -256,12 +345,17 @@ func (c *ClientStream) Context() context.Context {
 
 // Receive will receive a protobuf message from the client stream.
 func (c *ClientStream) Receive(newMessage func() proto.Message, options ...yarpc.StreamOption) (proto.Message, error) {
-	return readFromStream(context.Background(), c.stream, newMessage)
+	return readFromStream(context.Background(), c.stream, c.codec, newMessage)
 }
 
 // Send will send a protobuf message to the client stream.
 func (c *ClientStream) Send(message proto.Message, options ...yarpc.StreamOption) error {
-	return writeToStream(context.Background(), c.stream, message)
+	if c.validateRequests {
+		if err := validateMessage(message); err != nil {
+			return err
+		}
+	}
+	return writeToStream(context.Background(), c.stream, c.codec, message)
 }
 
 // Close will close the protobuf stream.
@@ -273,6 +367,9 @@ func (c *ClientStream) Close(options ...yarpc.StreamOption) error {
 type ServerStream struct {
 	ctx    context.Context
 	stream *transport.ServerStream
+	codec  *codec
+
+	validateRequests bool
 }
 
 // Context returns the context of the stream.
@@ -282,10 +379,19 @@ func (s *ServerStream) Context() context.Context {
 
 // Receive will receive a protobuf message from the server stream.
 func (s *ServerStream) Receive(newMessage func() proto.Message, options ...yarpc.StreamOption) (proto.Message, error) {
-	return readFromStream(context.Background(), s.stream, newMessage)
+	message, err := readFromStream(context.Background(), s.stream, s.codec, newMessage)
+	if err != nil {
+		return nil, err
+	}
+	if s.validateRequests {
+		if err := validateMessage(message); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
 }
 
 // Send will send a protobuf message to the server stream.
 func (s *ServerStream) Send(message proto.Message, options ...yarpc.StreamOption) error {
-	return writeToStream(context.Background(), s.stream, message)
+	return writeToStream(context.Background(), s.stream, s.codec, message)
 }
@@ -21,11 +21,19 @@
 package protobuf
 
 import (
+	"bytes"
+	"context"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
@@ -63,3 +71,112 @@ func TestUniqueLowercaseStrings(t *testing.T) {
 		assert.Equal(t, tt.want, got)
 	}
 }
+
+func TestBuildProceduresJSONOptions(t *testing.T) {
+	handler := NewUnaryHandler(UnaryHandlerParams{
+		Handle: func(_ context.Context, request proto.Message) (proto.Message, error) {
+			return &types.Field{
+				Kind:    types.Field_TYPE_STRING,
+				TypeUrl: request.(*types.SourceContext).FileName,
+			}, nil
+		},
+		NewRequest: func() proto.Message { return &types.SourceContext{} },
+	})
+
+	tests := []struct {
+		desc     string
+		params   BuildProceduresParams
+		give     string
+		wantBody string
+		wantErr  string
+	}{
+		{
+			desc:     "defaults",
+			give:     `{"fileName":"foo","unknown":1}`,
+			wantBody: `{"kind":"TYPE_STRING","typeUrl":"foo"}`,
+		},
+		{
+			desc: "params",
+			params: BuildProceduresParams{
+				JSONOptions: JSONOptions{OrigName: true},
+			},
+			give:     `{"file_name":"foo"}`,
+			wantBody: `{"kind":"TYPE_STRING","type_url":"foo"}`,
+		},
+		{
+			desc: "options take precedence",
+			params: BuildProceduresParams{
+				JSONOptions: JSONOptions{OrigName: true},
+				Options:     []ProceduresOption{JSONOptions{EnumsAsInts: true, DisallowUnknownFields: true}},
+			},
+			give:    `{"fileName":"foo","unknown":1}`,
+			wantErr: "failed to decode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			params := tt.params
+			params.ServiceName = "foo"
+			params.UnaryHandlerParams = []BuildProceduresUnaryHandlerParams{
+				{MethodName: "bar", Handler: handler},
+			}
+
+			var unary transport.UnaryHandler
+			for _, p := range BuildProcedures(params) {
+				if p.Encoding == JSONEncoding {
+					unary = p.HandlerSpec.Unary()
+				}
+			}
+			require.NotNil(t, unary)
+
+			resw := new(transporttest.FakeResponseWriter)
+			err := unary.Handle(context.Background(), &transport.Request{
+				Caller:    "caller",
+				Service:   "service",
+				Procedure: "foo::bar",
+				Encoding:  JSONEncoding,
+				Body:      strings.NewReader(tt.give),
+			}, resw)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, resw.Body.String())
+		})
+	}
+}
+
+func TestClientJSONOptions(t *testing.T) {
+	c := newClientFromParams(ClientParams{
+		ServiceName:  "foo",
+		ClientConfig: &transport.OutboundConfig{CallerName: "caller", Outbounds: transport.Outbounds{ServiceName: "service"}},
+		JSONOptions:  JSONOptions{OrigName: true},
+		Options:      []ClientOption{UseJSON},
+	})
+	_, _, req, cleanup, err := c.buildTransportRequest(context.Background(), "bar", &types.SourceContext{FileName: "baz"}, nil)
+	require.NoError(t, err)
+	defer cleanup()
+
+	var body bytes.Buffer
+	_, err = body.ReadFrom(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"file_name":"baz"}`, body.String())
+
+	c = newClientFromParams(ClientParams{
+		ServiceName:  "foo",
+		ClientConfig: &transport.OutboundConfig{CallerName: "caller", Outbounds: transport.Outbounds{ServiceName: "service"}},
+		JSONOptions:  JSONOptions{OrigName: true},
+		Options:      []ClientOption{UseJSON, JSONOptions{}},
+	})
+	_, _, req, cleanup, err = c.buildTransportRequest(context.Background(), "bar", &types.SourceContext{FileName: "baz"}, nil)
+	require.NoError(t, err)
+	defer cleanup()
+
+	body.Reset()
+	_, err = body.ReadFrom(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"fileName":"baz"}`, body.String())
+}
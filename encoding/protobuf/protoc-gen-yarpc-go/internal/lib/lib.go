@@ -24,12 +24,20 @@
 package lib
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc/encoding/protobuf/internal/dynamic"
 	"go.uber.org/yarpc/internal/protoplugin"
+	"go.uber.org/yarpc/yarpcproto"
 )
 
 const tmpl = `{{$packagePath := .GoPackage.Path}}{{$packageName := .GoPackage.Name}}
@@ -92,7 +100,8 @@ func New{{$service.GetName}}YARPCClient(clientConfig transport.ClientConfig, opt
 		protobuf.ClientParams{
 			ServiceName: "{{trimPrefixPeriod $service.FQSN}}",
 			ClientConfig: clientConfig,
-			Options: options,
+			{{if hasProcedureOptions $service}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions,
+			{{end}}Options: options,
 		},
 	)}
 }
@@ -133,15 +142,18 @@ type {{$service.GetName}}Service{{$method.GetName}}YARPCServer interface {
 {{end}}
 
 // Build{{$service.GetName}}YARPCProcedures prepares an implementation of the {{$service.GetName}} service for YARPC registration.
-func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCServer) []transport.Procedure {
+func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_{{$service.GetName}}YARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
 			ServiceName: "{{trimPrefixPeriod $service.FQSN}}",
+			FileDescriptors: {{fileDescriptorClosureVarName $}},
+			Options: options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 			{{range $method := unaryMethods $service}}{
 					MethodName: "{{$method.GetName}}",
-					Handler: protobuf.NewUnaryHandler(
+					{{if procedureOptions $method}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions["{{$method.GetName}}"],
+					{{end}}					Handler: protobuf.NewUnaryHandler(
 						protobuf.UnaryHandlerParams{
 							Handle: handler.{{$method.GetName}},
 							NewRequest: new{{$service.GetName}}Service{{$method.GetName}}YARPCRequest,
@@ -153,7 +165,8 @@ func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCSe
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{
 			{{range $method := onewayMethods $service}}{
 					MethodName: "{{$method.GetName}}",
-					Handler: protobuf.NewOnewayHandler(
+					{{if procedureOptions $method}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions["{{$method.GetName}}"],
+					{{end}}					Handler: protobuf.NewOnewayHandler(
 						protobuf.OnewayHandlerParams{
 							Handle: handler.{{$method.GetName}},
 							NewRequest: new{{$service.GetName}}Service{{$method.GetName}}YARPCRequest,
@@ -165,7 +178,8 @@ func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCSe
 			StreamHandlerParams: []protobuf.BuildProceduresStreamHandlerParams{
 			{{range $method := clientServerStreamingMethods $service}}{
 					MethodName: "{{$method.GetName}}",
-					Handler: protobuf.NewStreamHandler(
+					{{if procedureOptions $method}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions["{{$method.GetName}}"],
+					{{end}}					Handler: protobuf.NewStreamHandler(
 						protobuf.StreamHandlerParams{
 							Handle: handler.{{$method.GetName}},
 						},
@@ -174,7 +188,8 @@ func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCSe
 			{{end}}
 			{{range $method := serverStreamingMethods $service}}{
 					MethodName: "{{$method.GetName}}",
-					Handler: protobuf.NewStreamHandler(
+					{{if procedureOptions $method}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions["{{$method.GetName}}"],
+					{{end}}					Handler: protobuf.NewStreamHandler(
 						protobuf.StreamHandlerParams{
 							Handle: handler.{{$method.GetName}},
 						},
@@ -183,7 +198,8 @@ func Build{{$service.GetName}}YARPCProcedures(server {{$service.GetName}}YARPCSe
 			{{end}}
 			{{range $method := clientStreamingMethods $service}}{
 					MethodName: "{{$method.GetName}}",
-					Handler: protobuf.NewStreamHandler(
+					{{if procedureOptions $method}}ProcedureOptions: _{{$service.GetName}}YARPCProcedureOptions["{{$method.GetName}}"],
+					{{end}}					Handler: protobuf.NewStreamHandler(
 						protobuf.StreamHandlerParams{
 							Handle: handler.{{$method.GetName}},
 						},
@@ -262,14 +278,21 @@ type Fx{{$service.GetName}}YARPCProceduresResult struct {
 //    {{$packageName}}.NewFx{{$service.GetName}}YARPCProcedures(),
 //    ...
 //  )
-func NewFx{{$service.GetName}}YARPCProcedures() interface{} {
+func NewFx{{$service.GetName}}YARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params Fx{{$service.GetName}}YARPCProceduresParams) Fx{{$service.GetName}}YARPCProceduresResult {
 		return Fx{{$service.GetName}}YARPCProceduresResult{
-			Procedures: Build{{$service.GetName}}YARPCProcedures(params.Server),
+			Procedures: Build{{$service.GetName}}YARPCProcedures(params.Server, options...),
 		}
 	}
 }
 
+{{if hasProcedureOptions $service}}
+var _{{$service.GetName}}YARPCProcedureOptions = map[string]transport.ProcedureOptions{
+	{{range $method := $service.Methods}}{{with procedureOptions $method}}"{{$method.GetName}}": {{.}},
+	{{end}}{{end}}
+}
+{{end}}
+
 type _{{$service.GetName}}YARPCCaller struct {
 	streamClient protobuf.StreamClient
 }
@@ -548,6 +571,14 @@ var (
 	empty{{$service.GetName}}Service{{$method.GetName}}YARPCResponse = &{{$method.ResponseType.GoType $packagePath}}{}{{end}}
 )
 {{end}}
+{{if .Services}}var {{fileDescriptorClosureVarName .}} = [][]byte{
+	{{range $fileDescriptor := fileDescriptorClosure .}}// {{$fileDescriptor.Name}}
+	[]byte{
+		{{$fileDescriptor.Bytes}}
+	},
+	{{end}}
+}
+{{end}}
 {{if .Services}}func init() { {{range $service := .Services}}
 	yarpc.RegisterClientBuilder(
 		func(clientConfig transport.ClientConfig, structField reflect.StructField) {{$service.GetName}}YARPCClient {
@@ -558,7 +589,15 @@ var (
 `
 
 // Runner is the Runner used for protoc-gen-yarpc-go.
-var Runner = protoplugin.NewRunner(
+//
+// It generates the YARPC types of every Protobuf file in foo.pb.yarpc.go,
+// gomock clients and fake servers for its services in a separate test
+// package, Validate methods for its messages in foo.pb.validate.go if their
+// fields declare constraints, and HTTP routes for its services in
+// foo.pb.http.go if their methods declare google.api.http options.
+var Runner = protoplugin.NewMultiRunner(runner, testRunner, validateRunner, routesRunner)
+
+var runner = protoplugin.NewRunner(
 	template.Must(template.New("tmpl").Funcs(
 		template.FuncMap{
 			"unaryMethods":                 unaryMethods,
@@ -567,6 +606,10 @@ var Runner = protoplugin.NewRunner(
 			"serverStreamingMethods":       serverStreamingMethods,
 			"clientServerStreamingMethods": clientServerStreamingMethods,
 			"trimPrefixPeriod":             trimPrefixPeriod,
+			"hasProcedureOptions":          hasProcedureOptions,
+			"procedureOptions":             procedureOptions,
+			"fileDescriptorClosure":        fileDescriptorClosure,
+			"fileDescriptorClosureVarName": fileDescriptorClosureVarName,
 		}).Parse(tmpl)),
 	checkTemplateInfo,
 	[]string{
@@ -595,7 +638,14 @@ func checkTemplateInfo(templateInfo *protoplugin.TemplateInfo) error {
 func unaryMethods(service *protoplugin.Service) ([]*protoplugin.Method, error) {
 	methods := make([]*protoplugin.Method, 0, len(service.Methods))
 	for _, method := range service.Methods {
-		if !method.GetClientStreaming() && !method.GetServerStreaming() && method.ResponseType.FQMN() != ".uber.yarpc.Oneway" {
+		if method.GetClientStreaming() || method.GetServerStreaming() {
+			continue
+		}
+		oneway, err := isOneway(method)
+		if err != nil {
+			return nil, err
+		}
+		if !oneway {
 			methods = append(methods, method)
 		}
 	}
@@ -605,13 +655,27 @@ func unaryMethods(service *protoplugin.Service) ([]*protoplugin.Method, error) {
 func onewayMethods(service *protoplugin.Service) ([]*protoplugin.Method, error) {
 	methods := make([]*protoplugin.Method, 0, len(service.Methods))
 	for _, method := range service.Methods {
-		if !method.GetClientStreaming() && !method.GetServerStreaming() && method.ResponseType.FQMN() == ".uber.yarpc.Oneway" {
+		if method.GetClientStreaming() || method.GetServerStreaming() {
+			continue
+		}
+		oneway, err := isOneway(method)
+		if err != nil {
+			return nil, err
+		}
+		if oneway {
 			methods = append(methods, method)
 		}
 	}
 	return methods, nil
 }
 
+// isOneway returns whether a method returns the uber.yarpc.Oneway type or
+// has the oneway option set, by the same rule as code that reads the
+// descriptors at runtime.
+func isOneway(method *protoplugin.Method) (bool, error) {
+	return dynamic.IsOneway(method.MethodDescriptorProto)
+}
+
 func clientStreamingMethods(service *protoplugin.Service) ([]*protoplugin.Method, error) {
 	methods := make([]*protoplugin.Method, 0, len(service.Methods))
 	for _, method := range service.Methods {
@@ -645,3 +709,150 @@ func clientServerStreamingMethods(service *protoplugin.Service) ([]*protoplugin.
 func trimPrefixPeriod(s string) string {
 	return strings.TrimPrefix(s, ".")
 }
+
+// methodOptions returns the uber.yarpc.method option of a method, or nil if
+// it is not set.
+func methodOptions(method *protoplugin.Method) (*yarpcproto.MethodOptions, error) {
+	if method.GetOptions() == nil || !proto.HasExtension(method.GetOptions(), yarpcproto.E_Method) {
+		return nil, nil
+	}
+	extension, err := proto.GetExtension(method.GetOptions(), yarpcproto.E_Method)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the uber.yarpc.method option of method %s: %v", method.GetName(), err)
+	}
+	options, ok := extension.(*yarpcproto.MethodOptions)
+	if !ok {
+		return nil, fmt.Errorf("expected the uber.yarpc.method option of method %s to be a %T but was a %T", method.GetName(), options, extension)
+	}
+	return options, nil
+}
+
+func hasProcedureOptions(service *protoplugin.Service) (bool, error) {
+	for _, method := range service.Methods {
+		literal, err := procedureOptions(method)
+		if err != nil {
+			return false, err
+		}
+		if literal != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// procedureOptions returns the transport.ProcedureOptions declared for a
+// method as the contents of a Go composite literal, or an empty string if the
+// method declares none.
+//
+// Durations are written in nanoseconds so that generated code does not have
+// to import the time package.
+func procedureOptions(method *protoplugin.Method) (string, error) {
+	options, err := methodOptions(method)
+	if err != nil || options == nil {
+		return "", err
+	}
+	var fields []string
+	if options.GetTimeoutMs() > 0 {
+		fields = append(fields, fmt.Sprintf("Timeout: %d", msToNanos(options.GetTimeoutMs())))
+	}
+	if options.GetIdempotent() {
+		fields = append(fields, "Idempotent: true")
+	}
+	if retry := options.GetRetry(); retry != nil && (retry.GetMaxAttempts() > 0 || retry.GetBackoffMs() > 0) {
+		var retryFields []string
+		if retry.GetMaxAttempts() > 0 {
+			retryFields = append(retryFields, fmt.Sprintf("MaxAttempts: %d", retry.GetMaxAttempts()))
+		}
+		if retry.GetBackoffMs() > 0 {
+			retryFields = append(retryFields, fmt.Sprintf("Backoff: %d", msToNanos(retry.GetBackoffMs())))
+		}
+		fields = append(fields, fmt.Sprintf("Retry: transport.RetryPolicy{%s}", strings.Join(retryFields, ", ")))
+	}
+	if headers := options.GetRequiredHeaders(); len(headers) > 0 {
+		quoted := make([]string, len(headers))
+		for i, header := range headers {
+			quoted[i] = fmt.Sprintf("%q", header)
+		}
+		fields = append(fields, fmt.Sprintf("RequiredHeaders: []string{%s}", strings.Join(quoted, ", ")))
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("{%s}", strings.Join(fields, ", ")), nil
+}
+
+func msToNanos(ms uint32) int64 {
+	return int64(ms) * 1000 * 1000
+}
+
+type fileDescriptor struct {
+	Name string
+	// Bytes is the gzipped FileDescriptorProto of the file, formatted as the
+	// contents of a Go byte slice literal.
+	Bytes string
+}
+
+// fileDescriptorClosure returns the file descriptors of the file being
+// generated and of all files it imports, for embedding in generated code.
+func fileDescriptorClosure(templateInfo *protoplugin.TemplateInfo) ([]*fileDescriptor, error) {
+	files := append([]*protoplugin.File{templateInfo.File}, templateInfo.Dependencies...)
+	fileDescriptors := make([]*fileDescriptor, 0, len(files))
+	for _, file := range files {
+		data, err := encodeFileDescriptor(file.FileDescriptorProto)
+		if err != nil {
+			return nil, err
+		}
+		fileDescriptors = append(fileDescriptors, &fileDescriptor{
+			Name:  file.GetName(),
+			Bytes: formatBytes(data),
+		})
+	}
+	return fileDescriptors, nil
+}
+
+// encodeFileDescriptor serializes and gzips a FileDescriptorProto without
+// its source code info, like protoc-gen-gogo does.
+func encodeFileDescriptor(file *descriptor.FileDescriptorProto) ([]byte, error) {
+	file = proto.Clone(file).(*descriptor.FileDescriptorProto)
+	file.SourceCodeInfo = nil
+	data, err := proto.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatBytes(data []byte) string {
+	var buf bytes.Buffer
+	for i, b := range data {
+		if i > 0 {
+			if i%16 == 0 {
+				buf.WriteString("\n")
+			} else {
+				buf.WriteByte(' ')
+			}
+		}
+		fmt.Fprintf(&buf, "0x%02x,", b)
+	}
+	return buf.String()
+}
+
+// fileDescriptorClosureVarName returns the name of the variable holding the
+// file descriptors of the file being generated, which is unique among the
+// files of a Go package.
+func fileDescriptorClosureVarName(templateInfo *protoplugin.TemplateInfo) string {
+	sum := sha256.Sum256([]byte(templateInfo.GetName()))
+	return "yarpcFileDescriptorClosure" + hex.EncodeToString(sum[:8])
+}
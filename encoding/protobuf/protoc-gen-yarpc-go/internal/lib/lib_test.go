@@ -0,0 +1,140 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"go/parser"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/internal/protoplugin"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+func newTestMethod(t *testing.T, responseType string, options *yarpcproto.MethodOptions) *protoplugin.Method {
+	methodDescriptor := &descriptor.MethodDescriptorProto{
+		Name:       proto.String("Get"),
+		InputType:  proto.String(".uber.yarpc.GetRequest"),
+		OutputType: proto.String(".uber.yarpc." + responseType),
+	}
+	if options != nil {
+		// Round-trip the options through their wire representation, as they
+		// are received from protoc.
+		withExtension := &descriptor.MethodOptions{}
+		require.NoError(t, proto.SetExtension(withExtension, yarpcproto.E_Method, options))
+		data, err := proto.Marshal(withExtension)
+		require.NoError(t, err)
+		methodDescriptor.Options = &descriptor.MethodOptions{}
+		require.NoError(t, proto.Unmarshal(data, methodDescriptor.Options))
+	}
+	file := &protoplugin.File{
+		FileDescriptorProto: &descriptor.FileDescriptorProto{Package: proto.String("uber.yarpc")},
+	}
+	return &protoplugin.Method{
+		MethodDescriptorProto: methodDescriptor,
+		RequestType: &protoplugin.Message{
+			DescriptorProto: &descriptor.DescriptorProto{Name: proto.String("GetRequest")},
+			File:            file,
+		},
+		ResponseType: &protoplugin.Message{
+			DescriptorProto: &descriptor.DescriptorProto{Name: proto.String(responseType)},
+			File:            file,
+		},
+	}
+}
+
+func TestProcedureOptions(t *testing.T) {
+	tests := []struct {
+		msg        string
+		options    *yarpcproto.MethodOptions
+		want       string
+		wantOneway bool
+	}{
+		{
+			msg: "no options",
+		},
+		{
+			msg:     "empty options",
+			options: &yarpcproto.MethodOptions{},
+		},
+		{
+			msg: "all options",
+			options: &yarpcproto.MethodOptions{
+				TimeoutMs:       1500,
+				Idempotent:      true,
+				Retry:           &yarpcproto.RetryPolicy{MaxAttempts: 3, BackoffMs: 100},
+				RequiredHeaders: []string{"x-tenant", "x-user"},
+			},
+			want: `{Timeout: 1500000000, Idempotent: true, Retry: transport.RetryPolicy{MaxAttempts: 3, Backoff: 100000000}, RequiredHeaders: []string{"x-tenant", "x-user"}}`,
+		},
+		{
+			msg:     "partial retry policy",
+			options: &yarpcproto.MethodOptions{Retry: &yarpcproto.RetryPolicy{MaxAttempts: 2}},
+			want:    `{Retry: transport.RetryPolicy{MaxAttempts: 2}}`,
+		},
+		{
+			msg:        "oneway",
+			options:    &yarpcproto.MethodOptions{Oneway: true},
+			wantOneway: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			method := newTestMethod(t, "GetResponse", tt.options)
+			got, err := procedureOptions(method)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			oneway, err := isOneway(method)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOneway, oneway)
+			if got != "" {
+				_, err := parser.ParseExpr(`map[string]transport.ProcedureOptions{"Get": ` + got + `}`)
+				assert.NoError(t, err, "generated options must be a valid Go expression")
+			}
+		})
+	}
+}
+
+func TestIsOnewayResponseType(t *testing.T) {
+	oneway, err := isOneway(newTestMethod(t, "Oneway", nil))
+	require.NoError(t, err)
+	assert.True(t, oneway)
+
+	oneway, err = isOneway(newTestMethod(t, "GetResponse", nil))
+	require.NoError(t, err)
+	assert.False(t, oneway)
+}
+
+func TestInvalidOnewayOption(t *testing.T) {
+	method := newTestMethod(t, "GetResponse", nil)
+	method.Options = &descriptor.MethodOptions{}
+	field := yarpcproto.E_Method.Field
+	proto.SetRawExtension(method.Options, field, []byte{byte(field<<3 | 2), 10, 1})
+	service := &protoplugin.Service{Methods: []*protoplugin.Method{method}}
+
+	_, err := unaryMethods(service)
+	assert.Error(t, err, "unary methods must surface invalid options")
+	_, err = onewayMethods(service)
+	assert.Error(t, err, "oneway methods must surface invalid options")
+}
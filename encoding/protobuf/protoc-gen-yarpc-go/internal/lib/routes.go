@@ -0,0 +1,229 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/gogo/googleapis/google/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc/internal/protoplugin"
+)
+
+const routesTmpl = `
+// Code generated by protoc-gen-yarpc-go
+// source: {{.GetName}}
+// DO NOT EDIT!
+
+package {{.GoPackage.Name}}
+
+import (
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/transport/http"
+)
+{{range $service := .Services}}{{with httpRules $service}}
+// Build{{$service.GetName}}YARPCHTTPRoutes returns the routes that serve the
+// procedures of the {{$service.GetName}} service on the RESTful URLs declared
+// with the google.api.http option of its methods. Give them to an HTTP inbound
+// with the http.Routes option, and register the procedures built by
+// Build{{$service.GetName}}YARPCProcedures.
+func Build{{$service.GetName}}YARPCHTTPRoutes() []http.Route {
+	return protobuf.BuildHTTPRoutes(
+		protobuf.BuildHTTPRoutesParams{
+			ServiceName: "{{trimPrefixPeriod $service.FQSN}}",
+			Rules: []protobuf.HTTPRule{
+			{{range $rule := .}}{
+					MethodName: "{{$rule.Method.GetName}}",
+					HTTPMethod: "{{$rule.HTTPMethod}}",
+					Pattern: {{$rule.Pattern | printf "%q"}},
+					{{if $rule.Body}}Body: "{{$rule.Body}}",
+					{{end}}{{if $rule.ResponseBody}}ResponseBody: "{{$rule.ResponseBody}}",
+					{{end}}NewRequest: new{{$service.GetName}}Service{{$rule.Method.GetName}}YARPCRequest,
+					NewResponse: new{{$service.GetName}}Service{{$rule.Method.GetName}}YARPCResponse,
+				},
+			{{end}}
+			},
+		},
+	)
+}
+{{end}}{{end}}`
+
+var routesRunner = protoplugin.NewRunner(
+	template.Must(template.New("routesTmpl").Funcs(
+		template.FuncMap{
+			"httpRules":        httpRules,
+			"trimPrefixPeriod": trimPrefixPeriod,
+		}).Parse(routesTmpl)),
+	checkRoutesTemplateInfo,
+	nil,
+	func(file *protoplugin.File) (string, error) {
+		name := file.GetName()
+		return fmt.Sprintf("%s.pb.http.go", strings.TrimSuffix(name, filepath.Ext(name))), nil
+	},
+	func(key string, value string) error {
+		return nil
+	},
+)
+
+// checkRoutesTemplateInfo skips files whose methods declare no HTTP rules.
+func checkRoutesTemplateInfo(templateInfo *protoplugin.TemplateInfo) error {
+	for _, service := range templateInfo.Services {
+		rules, err := httpRules(service)
+		if err != nil {
+			return err
+		}
+		if len(rules) > 0 {
+			return nil
+		}
+	}
+	return protoplugin.ErrNoTargetService
+}
+
+type httpRule struct {
+	Method       *protoplugin.Method
+	HTTPMethod   string
+	Pattern      string
+	Body         string
+	ResponseBody string
+}
+
+// _patternVariableRegexp matches the variables of a path template, such as
+// {id} or {name=shelves/*}.
+var _patternVariableRegexp = regexp.MustCompile(`\{([^=}]*)(=[^}]*)?\}`)
+
+// httpRules returns the HTTP rules declared with the google.api.http option
+// of the methods of a service, including their additional bindings.
+func httpRules(service *protoplugin.Service) ([]*httpRule, error) {
+	var rules []*httpRule
+	for _, method := range service.Methods {
+		if method.GetOptions() == nil || !proto.HasExtension(method.GetOptions(), api.E_Http) {
+			continue
+		}
+		extension, err := proto.GetExtension(method.GetOptions(), api.E_Http)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the google.api.http option of method %s: %v", method.GetName(), err)
+		}
+		rule, ok := extension.(*api.HttpRule)
+		if !ok {
+			return nil, fmt.Errorf("expected the google.api.http option of method %s to be a %T but was a %T", method.GetName(), rule, extension)
+		}
+		if method.GetClientStreaming() || method.GetServerStreaming() {
+			return nil, fmt.Errorf("the google.api.http option is not supported on streaming method %s", method.GetName())
+		}
+		bindings := append([]*api.HttpRule{rule}, rule.GetAdditionalBindings()...)
+		for i, binding := range bindings {
+			if i > 0 && len(binding.GetAdditionalBindings()) > 0 {
+				return nil, fmt.Errorf("additional bindings of method %s must not have additional bindings", method.GetName())
+			}
+			r, err := newHTTPRule(method, binding)
+			if err != nil {
+				return nil, fmt.Errorf("invalid google.api.http option of method %s: %v", method.GetName(), err)
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func newHTTPRule(method *protoplugin.Method, binding *api.HttpRule) (*httpRule, error) {
+	r := &httpRule{
+		Method:       method,
+		Body:         binding.GetBody(),
+		ResponseBody: binding.GetResponseBody(),
+	}
+	switch pattern := binding.GetPattern().(type) {
+	case *api.HttpRule_Get:
+		r.HTTPMethod, r.Pattern = "GET", pattern.Get
+	case *api.HttpRule_Put:
+		r.HTTPMethod, r.Pattern = "PUT", pattern.Put
+	case *api.HttpRule_Post:
+		r.HTTPMethod, r.Pattern = "POST", pattern.Post
+	case *api.HttpRule_Delete:
+		r.HTTPMethod, r.Pattern = "DELETE", pattern.Delete
+	case *api.HttpRule_Patch:
+		r.HTTPMethod, r.Pattern = "PATCH", pattern.Patch
+	case *api.HttpRule_Custom:
+		r.HTTPMethod, r.Pattern = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("no HTTP method and path")
+	}
+	if r.HTTPMethod == "" || !strings.HasPrefix(r.Pattern, "/") {
+		return nil, fmt.Errorf("invalid HTTP method %q or path %q", r.HTTPMethod, r.Pattern)
+	}
+
+	for _, match := range _patternVariableRegexp.FindAllStringSubmatch(r.Pattern, -1) {
+		field, err := lookupFieldPath(method.RequestType, match[1])
+		if err != nil {
+			return nil, fmt.Errorf("variable %q of path %q: %v", match[1], r.Pattern, err)
+		}
+		if field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+			return nil, fmt.Errorf("variable %q of path %q must not be a repeated field", match[1], r.Pattern)
+		}
+	}
+	if r.Body != "" && r.Body != "*" {
+		if _, err := lookupFieldPath(method.RequestType, r.Body); err != nil {
+			return nil, fmt.Errorf("body %q: %v", r.Body, err)
+		}
+		if strings.Contains(r.Body, ".") {
+			return nil, fmt.Errorf("body %q must be a top-level field", r.Body)
+		}
+	}
+	if r.ResponseBody != "" {
+		if strings.Contains(r.ResponseBody, ".") {
+			return nil, fmt.Errorf("response body %q must be a top-level field", r.ResponseBody)
+		}
+		if _, err := lookupFieldPath(method.ResponseType, r.ResponseBody); err != nil {
+			return nil, fmt.Errorf("response body %q: %v", r.ResponseBody, err)
+		}
+	}
+	return r, nil
+}
+
+// lookupFieldPath returns the field named by a dot-separated path of field
+// names from a message.
+func lookupFieldPath(message *protoplugin.Message, path string) (*protoplugin.Field, error) {
+	var field *protoplugin.Field
+	for _, name := range strings.Split(path, ".") {
+		if message == nil {
+			return nil, fmt.Errorf("%s is not a message field", field.GetName())
+		}
+		field = nil
+		for _, f := range message.Fields {
+			if f.GetName() == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return nil, fmt.Errorf("message %s has no field %s", trimPrefixPeriod(message.FQMN()), name)
+		}
+		if field.FieldMessage != nil && field.FieldMessage.GetOptions().GetMapEntry() {
+			return nil, fmt.Errorf("map field %s is not supported", name)
+		}
+		message = field.FieldMessage
+	}
+	return field, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/gogo/googleapis/google/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/internal/protoplugin"
+)
+
+func newTestRouteMethod() *protoplugin.Method {
+	file := &protoplugin.File{
+		FileDescriptorProto: &descriptor.FileDescriptorProto{Package: proto.String("uber.yarpc")},
+	}
+	newMessage := func(name string) *protoplugin.Message {
+		return &protoplugin.Message{
+			DescriptorProto: &descriptor.DescriptorProto{Name: proto.String(name)},
+			File:            file,
+		}
+	}
+	addField := func(message *protoplugin.Message, name string, label descriptor.FieldDescriptorProto_Label, fieldMessage *protoplugin.Message) {
+		message.Fields = append(message.Fields, &protoplugin.Field{
+			FieldDescriptorProto: &descriptor.FieldDescriptorProto{
+				Name:  proto.String(name),
+				Label: label.Enum(),
+			},
+			Message:      message,
+			FieldMessage: fieldMessage,
+		})
+	}
+	optional := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+
+	entry := newMessage("LabelsEntry")
+	entry.Options = &descriptor.MessageOptions{MapEntry: proto.Bool(true)}
+	key := newMessage("Key")
+	addField(key, "id", optional, nil)
+	request := newMessage("GetRequest")
+	addField(request, "key", optional, key)
+	addField(request, "ids", repeated, nil)
+	addField(request, "labels", repeated, entry)
+	response := newMessage("GetResponse")
+	addField(response, "value", optional, key)
+
+	return &protoplugin.Method{
+		MethodDescriptorProto: &descriptor.MethodDescriptorProto{Name: proto.String("Get")},
+		RequestType:           request,
+		ResponseType:          response,
+	}
+}
+
+func TestNewHTTPRule(t *testing.T) {
+	tests := []struct {
+		msg       string
+		binding   *api.HttpRule
+		want      *httpRule
+		wantError string
+	}{
+		{
+			msg: "nested variable",
+			binding: &api.HttpRule{
+				Pattern:      &api.HttpRule_Get{Get: "/v1/keys/{key.id=ids/*}"},
+				ResponseBody: "value",
+			},
+			want: &httpRule{HTTPMethod: "GET", Pattern: "/v1/keys/{key.id=ids/*}", ResponseBody: "value"},
+		},
+		{
+			msg: "custom method",
+			binding: &api.HttpRule{
+				Pattern: &api.HttpRule_Custom{Custom: &api.CustomHttpPattern{Kind: "HEAD", Path: "/v1/keys"}},
+				Body:    "key",
+			},
+			want: &httpRule{HTTPMethod: "HEAD", Pattern: "/v1/keys", Body: "key"},
+		},
+		{
+			msg:       "no pattern",
+			binding:   &api.HttpRule{},
+			wantError: "no HTTP method and path",
+		},
+		{
+			msg:       "relative path",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Get{Get: "v1/keys"}},
+			wantError: `invalid HTTP method "GET" or path "v1/keys"`,
+		},
+		{
+			msg:       "unknown variable",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Get{Get: "/v1/keys/{name}"}},
+			wantError: `variable "name" of path "/v1/keys/{name}": message uber.yarpc.GetRequest has no field name`,
+		},
+		{
+			msg:       "variable below a scalar field",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Get{Get: "/v1/keys/{key.id.name}"}},
+			wantError: `variable "key.id.name" of path "/v1/keys/{key.id.name}": id is not a message field`,
+		},
+		{
+			msg:       "repeated variable",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Get{Get: "/v1/keys/{ids}"}},
+			wantError: `variable "ids" of path "/v1/keys/{ids}" must not be a repeated field`,
+		},
+		{
+			msg:       "map body",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Post{Post: "/v1/keys"}, Body: "labels"},
+			wantError: `body "labels": map field labels is not supported`,
+		},
+		{
+			msg:       "nested body",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Post{Post: "/v1/keys"}, Body: "key.id"},
+			wantError: `body "key.id" must be a top-level field`,
+		},
+		{
+			msg:       "unknown response body",
+			binding:   &api.HttpRule{Pattern: &api.HttpRule_Get{Get: "/v1/keys"}, ResponseBody: "values"},
+			wantError: `response body "values": message uber.yarpc.GetResponse has no field values`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			method := newTestRouteMethod()
+			rule, err := newHTTPRule(method, tt.binding)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			tt.want.Method = method
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
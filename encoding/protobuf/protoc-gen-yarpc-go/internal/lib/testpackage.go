@@ -0,0 +1,342 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"go.uber.org/yarpc/internal/protoplugin"
+)
+
+// testTmpl generates the test package of a Protobuf file, with gomock
+// compatible mock clients and in-process fake servers for its services.
+const testTmpl = `{{$pkg := .GoPackage}}{{$testPackageName := testPackageName .File}}
+// Code generated by protoc-gen-yarpc-go
+// source: {{.GetName}}
+// DO NOT EDIT!
+
+package {{$testPackageName}}
+
+import (
+	{{range $i := .Imports}}{{if $i.Standard}}{{$i | printf "%s\n"}}{{end}}{{end}}
+
+	{{range $i := .Imports}}{{if not $i.Standard}}{{$i | printf "%s\n"}}{{end}}{{end}}{{$pkg}}
+)
+{{with onewayResponseTypes .File}}
+// The response types of oneway methods are imported but not otherwise used.
+var (
+	{{range .}}_ *{{.}}
+	{{end}}
+)
+{{end}}
+{{range $service := .Services}}{{$client := printf "%s.%sYARPCClient" (packageQualifier $pkg) $service.GetName}}
+// Mock{{$service.GetName}}YARPCClient implements a gomock-compatible mock client for service
+// {{$service.GetName}}.
+type Mock{{$service.GetName}}YARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_Mock{{$service.GetName}}YARPCClientRecorder
+}
+
+var _ {{$client}} = (*Mock{{$service.GetName}}YARPCClient)(nil)
+
+type _Mock{{$service.GetName}}YARPCClientRecorder struct {
+	mock *Mock{{$service.GetName}}YARPCClient
+}
+
+// NewMock{{$service.GetName}}YARPCClient builds a new mock client for service {{$service.GetName}}.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := {{$testPackageName}}.NewMock{{$service.GetName}}YARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMock{{$service.GetName}}YARPCClient(ctrl *gomock.Controller) *Mock{{$service.GetName}}YARPCClient {
+	mock := &Mock{{$service.GetName}}YARPCClient{ctrl: ctrl}
+	mock.recorder = &_Mock{{$service.GetName}}YARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// {{$service.GetName}} mock client.
+func (m *Mock{{$service.GetName}}YARPCClient) EXPECT() *_Mock{{$service.GetName}}YARPCClientRecorder {
+	return m.recorder
+}
+{{range $method := unaryMethods $service}}
+// {{$method.GetName}} responds to a {{$method.GetName}} call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().{{$method.GetName}}(gomock.Any(), ...).Return(...)
+// 	... := client.{{$method.GetName}}(...)
+func (m *Mock{{$service.GetName}}YARPCClient) {{$method.GetName}}(ctx context.Context, request *{{$method.RequestType.GoType ""}}, opts ...yarpc.CallOption) (response *{{$method.ResponseType.GoType ""}}, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "{{$method.GetName}}", args...)
+	response, _ = ret[0].(*{{$method.ResponseType.GoType ""}})
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_Mock{{$service.GetName}}YARPCClientRecorder) {{$method.GetName}}(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "{{$method.GetName}}", args...)
+}
+{{end}}{{range $method := onewayMethods $service}}
+// {{$method.GetName}} responds to a {{$method.GetName}} call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().{{$method.GetName}}(gomock.Any(), ...).Return(...)
+// 	... := client.{{$method.GetName}}(...)
+func (m *Mock{{$service.GetName}}YARPCClient) {{$method.GetName}}(ctx context.Context, request *{{$method.RequestType.GoType ""}}, opts ...yarpc.CallOption) (ack yarpc.Ack, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "{{$method.GetName}}", args...)
+	ack, _ = ret[0].(yarpc.Ack)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_Mock{{$service.GetName}}YARPCClientRecorder) {{$method.GetName}}(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "{{$method.GetName}}", args...)
+}
+{{end}}{{range $method := serverStreamingMethods $service}}{{$stream := printf "%s.%sService%sYARPCClient" (packageQualifier $pkg) $service.GetName $method.GetName}}
+// {{$method.GetName}} responds to a {{$method.GetName}} call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().{{$method.GetName}}(gomock.Any(), ...).Return(...)
+// 	... := client.{{$method.GetName}}(...)
+func (m *Mock{{$service.GetName}}YARPCClient) {{$method.GetName}}(ctx context.Context, request *{{$method.RequestType.GoType ""}}, opts ...yarpc.CallOption) (stream {{$stream}}, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "{{$method.GetName}}", args...)
+	stream, _ = ret[0].({{$stream}})
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_Mock{{$service.GetName}}YARPCClientRecorder) {{$method.GetName}}(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "{{$method.GetName}}", args...)
+}
+{{end}}{{range $method := clientStreamingMethods $service}}{{template "streamMock" (streamMockInfo $pkg $service $method)}}{{end}}{{range $method := clientServerStreamingMethods $service}}{{template "streamMock" (streamMockInfo $pkg $service $method)}}{{end}}
+// Fake{{$service.GetName}}YARPCServer serves a {{$service.GetName}}YARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type Fake{{$service.GetName}}YARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFake{{$service.GetName}}YARPCServer starts a YARPC dispatcher serving the given
+// {{$service.GetName}}YARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := {{$testPackageName}}.NewFake{{$service.GetName}}YARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFake{{$service.GetName}}YARPCServer(server {{packageQualifier $pkg}}.{{$service.GetName}}YARPCServer, options ...protobuf.ProceduresOption) (*Fake{{$service.GetName}}YARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "{{fakeServiceName $service}}"})
+	dispatcher.Register({{packageQualifier $pkg}}.Build{{$service.GetName}}YARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &Fake{{$service.GetName}}YARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the {{$service.GetName}} service.
+func (s *Fake{{$service.GetName}}YARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *Fake{{$service.GetName}}YARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "{{$testPackageName}}",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *Fake{{$service.GetName}}YARPCServer) Client(options ...protobuf.ClientOption) {{$client}} {
+	return {{packageQualifier $pkg}}.New{{$service.GetName}}YARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the {{$service.GetName}} service.
+func (s *Fake{{$service.GetName}}YARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+{{end}}
+{{define "streamMock"}}
+// {{.Method.GetName}} responds to a {{.Method.GetName}} call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().{{.Method.GetName}}(gomock.Any(), ...).Return(...)
+// 	... := client.{{.Method.GetName}}(...)
+func (m *Mock{{.Service.GetName}}YARPCClient) {{.Method.GetName}}(ctx context.Context, opts ...yarpc.CallOption) (stream {{.Stream}}, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "{{.Method.GetName}}", args...)
+	stream, _ = ret[0].({{.Stream}})
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_Mock{{.Service.GetName}}YARPCClientRecorder) {{.Method.GetName}}(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "{{.Method.GetName}}", args...)
+}
+{{end}}`
+
+var testRunner = protoplugin.NewRunner(
+	template.Must(template.New("testTmpl").Funcs(
+		template.FuncMap{
+			"unaryMethods":                 unaryMethods,
+			"onewayMethods":                onewayMethods,
+			"clientStreamingMethods":       clientStreamingMethods,
+			"serverStreamingMethods":       serverStreamingMethods,
+			"clientServerStreamingMethods": clientServerStreamingMethods,
+			"fakeServiceName":              fakeServiceName,
+			"testPackageName":              testPackageName,
+			"packageQualifier":             packageQualifier,
+			"streamMockInfo":               newStreamMockInfo,
+			"onewayResponseTypes":          onewayResponseTypes,
+		}).Parse(testTmpl)),
+	checkTestTemplateInfo,
+	[]string{
+		"context",
+		"github.com/golang/mock/gomock",
+		"go.uber.org/yarpc",
+		"go.uber.org/yarpc/api/transport",
+		"go.uber.org/yarpc/encoding/protobuf",
+		"go.uber.org/yarpc/yarpctest",
+	},
+	func(file *protoplugin.File) (string, error) {
+		name := file.GetName()
+		base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		// foo/bar.proto => foo/<package>test/bar.pb.yarpc.go
+		return filepath.Join(filepath.Dir(name), testPackageName(file), fmt.Sprintf("%s.pb.yarpc.go", base)), nil
+	},
+	func(key string, value string) error {
+		return nil
+	},
+)
+
+// checkTestTemplateInfo skips the test package of files without services.
+//
+// The test package imports the package of the file, so it is also skipped if
+// the import path of that package is not known. This is the case unless the
+// file has a go_package option with a full import path or an M parameter
+// maps it to one, in which case the path is taken from the directory of the
+// file, without a domain name.
+func checkTestTemplateInfo(templateInfo *protoplugin.TemplateInfo) error {
+	if len(templateInfo.Services) == 0 || templateInfo.GoPackage.Standard() {
+		return protoplugin.ErrNoTargetService
+	}
+	return nil
+}
+
+// testPackageName returns the name of the test package for a file.
+func testPackageName(file *protoplugin.File) string {
+	return file.GoPackage.Name + "test"
+}
+
+// fakeServiceName returns the name of the dispatcher of the fake server of a
+// service. Dispatcher names may only contain lowercase letters, digits and
+// hyphens, so the name of the service is lowercased and stripped of other
+// characters.
+func fakeServiceName(service *protoplugin.Service) string {
+	name := strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(service.GetName()))
+	return "fake-" + name
+}
+
+// packageQualifier returns the name that code in another package uses to
+// refer to the given package.
+func packageQualifier(pkg *protoplugin.GoPackage) string {
+	if pkg.Alias != "" {
+		return pkg.Alias
+	}
+	return pkg.Name
+}
+
+// onewayResponseTypes returns the qualified Go types of the responses of the
+// oneway methods of a file.
+func onewayResponseTypes(file *protoplugin.File) ([]string, error) {
+	var types []string
+	seen := make(map[string]bool)
+	for _, service := range file.Services {
+		methods, err := onewayMethods(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, method := range methods {
+			goType := method.ResponseType.GoType("")
+			if !seen[goType] {
+				seen[goType] = true
+				types = append(types, goType)
+			}
+		}
+	}
+	return types, nil
+}
+
+type streamMockInfo struct {
+	Service *protoplugin.Service
+	Method  *protoplugin.Method
+	// Stream is the qualified name of the client stream interface of the
+	// method.
+	Stream string
+}
+
+func newStreamMockInfo(pkg *protoplugin.GoPackage, service *protoplugin.Service, method *protoplugin.Method) *streamMockInfo {
+	return &streamMockInfo{
+		Service: service,
+		Method:  method,
+		Stream:  fmt.Sprintf("%s.%sService%sYARPCClient", packageQualifier(pkg), service.GetName(), method.GetName()),
+	}
+}
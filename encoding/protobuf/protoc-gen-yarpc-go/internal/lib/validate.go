@@ -0,0 +1,427 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gogo/protobuf/gogoproto"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+	"go.uber.org/yarpc/internal/protoplugin"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+const validateTmpl = `{{$validateFile := validateFile .File}}
+// Code generated by protoc-gen-yarpc-go
+// source: {{.GetName}}
+// DO NOT EDIT!
+
+package {{.GoPackage.Name}}
+
+import (
+{{range $i := $validateFile.Imports}}	{{$i | printf "%q"}}
+{{end}}{{if $validateFile.Imports}}
+{{end}}	"go.uber.org/yarpc/encoding/protobuf"
+)
+{{if $validateFile.Patterns}}
+var (
+{{range $pattern := $validateFile.Patterns}}	{{$pattern.VarName}} = regexp.MustCompile({{$pattern.Expr | printf "%q"}})
+{{end}})
+{{end}}
+{{range $message := $validateFile.Messages}}
+// Validate checks the constraints declared on the fields of {{$message.GoType}}
+// with the uber.yarpc.field option. It returns a *protobuf.ValidationError
+// listing the fields that do not satisfy them.
+func (m *{{$message.GoType}}) Validate() error {
+	if m == nil {
+		return nil
+	}
+	var violations protobuf.FieldViolations
+{{range $check := $message.Checks}}{{$check}}
+{{end}}	return violations.Err()
+}
+{{end}}
+`
+
+var validateRunner = protoplugin.NewRunner(
+	template.Must(template.New("validateTmpl").Funcs(
+		template.FuncMap{
+			"validateFile": newValidateFile,
+		}).Parse(validateTmpl)),
+	checkValidateTemplateInfo,
+	nil,
+	func(file *protoplugin.File) (string, error) {
+		name := file.GetName()
+		return fmt.Sprintf("%s.pb.validate.go", strings.TrimSuffix(name, filepath.Ext(name))), nil
+	},
+	func(key string, value string) error {
+		return nil
+	},
+)
+
+// checkValidateTemplateInfo skips files whose fields declare no constraints.
+func checkValidateTemplateInfo(templateInfo *protoplugin.TemplateInfo) error {
+	for _, message := range templateInfo.Messages {
+		for _, field := range message.Fields {
+			rules, err := fieldRules(field)
+			if err != nil {
+				return err
+			}
+			if rules != nil {
+				return nil
+			}
+		}
+	}
+	return protoplugin.ErrNoTargetService
+}
+
+type validateFile struct {
+	// Imports are the standard packages used by the checks.
+	Imports  []string
+	Patterns []*validatePattern
+	Messages []*validateMessage
+}
+
+type validatePattern struct {
+	VarName string
+	Expr    string
+}
+
+type validateMessage struct {
+	GoType string
+	// Checks are the statements of the Validate method of the message.
+	Checks []string
+}
+
+// newValidateFile builds the Validate methods of all the messages of a file,
+// so that messages without constraints of their own validate the messages
+// they hold.
+func newValidateFile(file *protoplugin.File) (*validateFile, error) {
+	v := &validateFile{}
+	var usesRegexp, usesUTF8 bool
+	for _, message := range file.Messages {
+		if message.GetOptions().GetMapEntry() {
+			continue
+		}
+		validateMessage := &validateMessage{GoType: message.GoType(file.GoPackage.Path)}
+		for _, field := range message.Fields {
+			checks, err := newFieldChecks(validateMessage.GoType, field)
+			if err != nil {
+				return nil, fmt.Errorf("could not validate field %s of message %s: %v", field.GetName(), message.FQMN(), err)
+			}
+			validateMessage.Checks = append(validateMessage.Checks, checks.statements...)
+			if checks.pattern != nil {
+				v.Patterns = append(v.Patterns, checks.pattern)
+				usesRegexp = true
+			}
+			usesUTF8 = usesUTF8 || checks.usesUTF8
+		}
+		v.Messages = append(v.Messages, validateMessage)
+	}
+	if usesRegexp {
+		v.Imports = append(v.Imports, "regexp")
+	}
+	if usesUTF8 {
+		v.Imports = append(v.Imports, "unicode/utf8")
+	}
+	return v, nil
+}
+
+func fieldRules(field *protoplugin.Field) (*yarpcproto.FieldRules, error) {
+	if field.GetOptions() == nil || !proto.HasExtension(field.GetOptions(), yarpcproto.E_Field) {
+		return nil, nil
+	}
+	extension, err := proto.GetExtension(field.GetOptions(), yarpcproto.E_Field)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the uber.yarpc.field option of field %s: %v", field.GetName(), err)
+	}
+	rules, ok := extension.(*yarpcproto.FieldRules)
+	if !ok {
+		return nil, fmt.Errorf("expected the uber.yarpc.field option of field %s to be a %T but was a %T", field.GetName(), rules, extension)
+	}
+	return rules, nil
+}
+
+type fieldChecks struct {
+	statements []string
+	pattern    *validatePattern
+	usesUTF8   bool
+
+	// chained is whether the last statement is an if statement that checks
+	// a constraint, which the next constraint is chained to with else so
+	// that only the first violated constraint of a value is reported.
+	chained bool
+}
+
+// newFieldChecks returns the statements that check the constraints declared
+// on a field, and that validate the messages it holds.
+func newFieldChecks(goType string, field *protoplugin.Field) (*fieldChecks, error) {
+	rules, err := fieldRules(field)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = &yarpcproto.FieldRules{}
+	}
+	checks := &fieldChecks{}
+	name := field.GetName()
+	goName := goFieldName(field)
+	if goName == "Validate" {
+		return nil, fmt.Errorf("the field conflicts with the Validate method of its message")
+	}
+	value := fmt.Sprintf("m.Get%s()", goName)
+	isMessage := field.FieldMessage != nil
+	isMap := isMessage && field.FieldMessage.GetOptions().GetMapEntry()
+	isRepeated := field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED
+
+	if field.OneofIndex != nil {
+		if !rules.Equal(&yarpcproto.FieldRules{}) {
+			return nil, fmt.Errorf("constraints are not supported on fields of a oneof")
+		}
+		if isMessage {
+			checks.add("violations.AddNested(%q, %s)", name, value)
+		}
+		return checks, nil
+	}
+
+	if isRepeated {
+		// The required, min_len and max_len rules apply to the number of
+		// elements.
+		if rules.Required {
+			checks.addViolation(name, "must not be empty", "if len(%s) == 0", value)
+		}
+		if rules.MinLen > 0 {
+			checks.addViolation(name, fmt.Sprintf("must have at least %d elements", rules.MinLen), "if len(%s) < %d", value, rules.MinLen)
+		}
+		if rules.MaxLen > 0 {
+			checks.addViolation(name, fmt.Sprintf("must have at most %d elements", rules.MaxLen), "if len(%s) > %d", value, rules.MaxLen)
+		}
+		elementRules := &yarpcproto.FieldRules{
+			MinValue:    rules.MinValue,
+			MaxValue:    rules.MaxValue,
+			Pattern:     rules.Pattern,
+			DefinedOnly: rules.DefinedOnly,
+		}
+		if isMap {
+			if !elementRules.Equal(&yarpcproto.FieldRules{}) {
+				return nil, fmt.Errorf("only the required, min_len and max_len constraints are supported on maps")
+			}
+			if valueField := field.FieldMessage.GetField(); len(valueField) == 2 && valueField[1].GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+				checks.add("for k, v := range %s {\n\tviolations.AddNested(protobuf.ElementPath(%q, k), v)\n}", value, name)
+			}
+			return checks, nil
+		}
+		elementChecks := &fieldChecks{}
+		if err := elementChecks.addValueChecks(goType, goName, `protobuf.ElementPath(`+strconv.Quote(name)+`, i)`, "v", field, elementRules); err != nil {
+			return nil, err
+		}
+		if len(elementChecks.statements) > 0 {
+			checks.add("for i, v := range %s {\n%s\n}", value, strings.Join(elementChecks.statements, "\n"))
+			checks.pattern = elementChecks.pattern
+			checks.usesUTF8 = elementChecks.usesUTF8
+		}
+		return checks, nil
+	}
+
+	if rules.Required {
+		var condition string
+		switch {
+		case isMessage:
+			condition = "if %s == nil"
+		case field.GetType() == descriptor.FieldDescriptorProto_TYPE_STRING:
+			condition = `if %s == ""`
+		case field.GetType() == descriptor.FieldDescriptorProto_TYPE_BYTES:
+			condition = "if len(%s) == 0"
+		case field.GetType() == descriptor.FieldDescriptorProto_TYPE_BOOL:
+			condition = "if !%s"
+		default:
+			condition = "if %s == 0"
+		}
+		checks.addViolation(name, "must be set", condition, value)
+	}
+	if rules.MinLen > 0 || rules.MaxLen > 0 {
+		var length, unit string
+		switch field.GetType() {
+		case descriptor.FieldDescriptorProto_TYPE_STRING:
+			length, unit = "utf8.RuneCountInString(%s)", "characters"
+			checks.usesUTF8 = true
+		case descriptor.FieldDescriptorProto_TYPE_BYTES:
+			length, unit = "len(%s)", "bytes"
+		default:
+			return nil, fmt.Errorf("the min_len and max_len constraints are only supported on strings, bytes, repeated fields and maps")
+		}
+		if rules.MinLen > 0 {
+			checks.addViolation(name, fmt.Sprintf("must be at least %d %s long", rules.MinLen, unit), "if "+length+" < %d", value, rules.MinLen)
+		}
+		if rules.MaxLen > 0 {
+			checks.addViolation(name, fmt.Sprintf("must be at most %d %s long", rules.MaxLen, unit), "if "+length+" > %d", value, rules.MaxLen)
+		}
+	}
+	return checks, checks.addValueChecks(goType, goName, strconv.Quote(name), value, field, rules)
+}
+
+// addValueChecks adds the checks of the min, max, pattern and defined_only
+// constraints on a value, which is either the value of a field or an element
+// of a repeated field, and validates the value if it is a message.
+func (c *fieldChecks) addValueChecks(goType string, goName string, path string, value string, field *protoplugin.Field, rules *yarpcproto.FieldRules) error {
+	if rules.MinValue != nil || rules.MaxValue != nil {
+		if rules.MinValue != nil {
+			min, err := numberLiteral(field.GetType(), rules.GetMin())
+			if err != nil {
+				return fmt.Errorf("invalid min constraint: %v", err)
+			}
+			c.addViolationAt(path, "must be at least "+min, "if %s < %s", value, min)
+		}
+		if rules.MaxValue != nil {
+			max, err := numberLiteral(field.GetType(), rules.GetMax())
+			if err != nil {
+				return fmt.Errorf("invalid max constraint: %v", err)
+			}
+			c.addViolationAt(path, "must be at most "+max, "if %s > %s", value, max)
+		}
+	}
+	if rules.Pattern != "" {
+		if field.GetType() != descriptor.FieldDescriptorProto_TYPE_STRING {
+			return fmt.Errorf("the pattern constraint is only supported on strings")
+		}
+		if _, err := regexp.Compile(rules.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern constraint: %v", err)
+		}
+		c.pattern = &validatePattern{
+			VarName: fmt.Sprintf("_%s_%sPattern", goType, goName),
+			Expr:    rules.Pattern,
+		}
+		c.addViolationAt(path, fmt.Sprintf("must match the pattern %q", rules.Pattern), "if !%s.MatchString(%s)", c.pattern.VarName, value)
+	}
+	if rules.DefinedOnly {
+		if field.FieldEnum == nil {
+			return fmt.Errorf("the defined_only constraint is only supported on enums")
+		}
+		var numbers []string
+		seen := make(map[int32]bool)
+		for _, enumValue := range field.FieldEnum.GetValue() {
+			if !seen[enumValue.GetNumber()] {
+				seen[enumValue.GetNumber()] = true
+				numbers = append(numbers, strconv.Itoa(int(enumValue.GetNumber())))
+			}
+		}
+		c.add(
+			"switch %s {\ncase %s:\ndefault:\n\tviolations.Add(%s, %q)\n}",
+			value,
+			strings.Join(numbers, ", "),
+			path,
+			"must be a defined value of "+strings.TrimPrefix(field.FieldEnum.FQEN(), "."),
+		)
+	}
+	if field.FieldMessage != nil {
+		c.add("violations.AddNested(%s, %s)", path, value)
+	}
+	return nil
+}
+
+func (c *fieldChecks) add(format string, args ...interface{}) {
+	c.statements = append(c.statements, fmt.Sprintf(format, args...))
+	c.chained = false
+}
+
+func (c *fieldChecks) addViolation(name string, description string, condition string, args ...interface{}) {
+	c.addViolationAt(strconv.Quote(name), description, condition, args...)
+}
+
+func (c *fieldChecks) addViolationAt(path string, description string, condition string, args ...interface{}) {
+	statement := fmt.Sprintf("%s {\n\tviolations.Add(%s, %q)\n}", fmt.Sprintf(condition, args...), path, description)
+	if c.chained {
+		c.statements[len(c.statements)-1] += " else " + statement
+		return
+	}
+	c.statements = append(c.statements, statement)
+	c.chained = true
+}
+
+// goMethodNames are the names of the methods that gogo protobuf may generate
+// for a message. Fields with these names get an underscore appended.
+var goMethodNames = map[string]bool{
+	"Reset":               true,
+	"String":              true,
+	"ProtoMessage":        true,
+	"Marshal":             true,
+	"Unmarshal":           true,
+	"ExtensionRangeArray": true,
+	"ExtensionMap":        true,
+	"Descriptor":          true,
+	"MarshalTo":           true,
+	"Equal":               true,
+	"VerboseEqual":        true,
+	"GoString":            true,
+	"ProtoSize":           true,
+}
+
+// goFieldName returns the name of the Go field of a field, which its getter
+// is named after, following the rules of gogo protobuf.
+func goFieldName(field *protoplugin.Field) string {
+	name := generator.CamelCase(field.GetName())
+	if gogoproto.IsCustomName(field.FieldDescriptorProto) {
+		name = gogoproto.GetCustomName(field.FieldDescriptorProto)
+	}
+	if goMethodNames[name] {
+		return name + "_"
+	}
+	message := field.Message
+	if name == "Size" && !gogoproto.IsProtoSizer(message.File.FileDescriptorProto, message.DescriptorProto) {
+		return name + "_"
+	}
+	return name
+}
+
+// numberLiteral returns the Go literal of a bound of a numeric field, failing
+// if the field is not numeric or the bound cannot be represented by its type.
+func numberLiteral(fieldType descriptor.FieldDescriptorProto_Type, bound float64) (string, error) {
+	var min, max float64
+	switch fieldType {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE, descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return strconv.FormatFloat(bound, 'g', -1, 64), nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_SINT32, descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		min, max = math.MinInt32, math.MaxInt32
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_SINT64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		min, max = math.MinInt64, math.MaxInt64
+	case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		min, max = 0, math.MaxUint32
+	case descriptor.FieldDescriptorProto_TYPE_UINT64, descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		min, max = 0, math.MaxUint64
+	default:
+		return "", fmt.Errorf("bounds are only supported on numbers")
+	}
+	if bound != math.Trunc(bound) {
+		return "", fmt.Errorf("%v is not an integer", bound)
+	}
+	if bound < min || bound > max {
+		return "", fmt.Errorf("%v is out of the range of the field", bound)
+	}
+	return strconv.FormatFloat(bound, 'f', -1, 64), nil
+}
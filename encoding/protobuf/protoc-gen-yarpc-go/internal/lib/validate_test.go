@@ -0,0 +1,224 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package lib
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/internal/protoplugin"
+	"go.uber.org/yarpc/yarpcproto"
+)
+
+func newTestField(t *testing.T, name string, fieldType descriptor.FieldDescriptorProto_Type, rules *yarpcproto.FieldRules) *protoplugin.Field {
+	fieldDescriptor := &descriptor.FieldDescriptorProto{
+		Name:  proto.String(name),
+		Type:  fieldType.Enum(),
+		Label: descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if rules != nil {
+		// Round-trip the rules through their wire representation, as they
+		// are received from protoc.
+		withExtension := &descriptor.FieldOptions{}
+		require.NoError(t, proto.SetExtension(withExtension, yarpcproto.E_Field, rules))
+		data, err := proto.Marshal(withExtension)
+		require.NoError(t, err)
+		fieldDescriptor.Options = &descriptor.FieldOptions{}
+		require.NoError(t, proto.Unmarshal(data, fieldDescriptor.Options))
+	}
+	return &protoplugin.Field{
+		FieldDescriptorProto: fieldDescriptor,
+		Message: &protoplugin.Message{
+			DescriptorProto: &descriptor.DescriptorProto{Name: proto.String("GetRequest")},
+			File: &protoplugin.File{
+				FileDescriptorProto: &descriptor.FileDescriptorProto{Package: proto.String("uber.yarpc")},
+			},
+		},
+	}
+}
+
+func TestNumberLiteral(t *testing.T) {
+	tests := []struct {
+		msg       string
+		fieldType descriptor.FieldDescriptorProto_Type
+		bound     float64
+		want      string
+		wantError string
+	}{
+		{
+			msg:       "double",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_DOUBLE,
+			bound:     0.5,
+			want:      "0.5",
+		},
+		{
+			msg:       "int32",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_INT32,
+			bound:     -10,
+			want:      "-10",
+		},
+		{
+			msg:       "large uint64",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_UINT64,
+			bound:     1e15,
+			want:      "1000000000000000",
+		},
+		{
+			msg:       "int32 out of range",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_INT32,
+			bound:     math.MaxInt32 + 1,
+			wantError: "out of the range",
+		},
+		{
+			msg:       "negative uint32",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_UINT32,
+			bound:     -1,
+			wantError: "out of the range",
+		},
+		{
+			msg:       "fractional int64",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_INT64,
+			bound:     1.5,
+			wantError: "1.5 is not an integer",
+		},
+		{
+			msg:       "string",
+			fieldType: descriptor.FieldDescriptorProto_TYPE_STRING,
+			bound:     1,
+			wantError: "only supported on numbers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			got, err := numberLiteral(tt.fieldType, tt.bound)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGoFieldName(t *testing.T) {
+	tests := map[string]string{
+		"name":      "Name",
+		"user_id":   "UserId",
+		"size":      "Size_",
+		"string":    "String_",
+		"byte_size": "ByteSize",
+	}
+	for name, want := range tests {
+		field := newTestField(t, name, descriptor.FieldDescriptorProto_TYPE_STRING, nil)
+		assert.Equal(t, want, goFieldName(field), name)
+	}
+}
+
+func TestNewFieldChecksErrors(t *testing.T) {
+	tests := []struct {
+		msg       string
+		field     func(t *testing.T) *protoplugin.Field
+		wantError string
+	}{
+		{
+			msg: "pattern on an integer",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "count", descriptor.FieldDescriptorProto_TYPE_INT32, &yarpcproto.FieldRules{Pattern: "^a$"})
+			},
+			wantError: "the pattern constraint is only supported on strings",
+		},
+		{
+			msg: "invalid pattern",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "name", descriptor.FieldDescriptorProto_TYPE_STRING, &yarpcproto.FieldRules{Pattern: "("})
+			},
+			wantError: "invalid pattern constraint",
+		},
+		{
+			msg: "defined_only on a string",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "name", descriptor.FieldDescriptorProto_TYPE_STRING, &yarpcproto.FieldRules{DefinedOnly: true})
+			},
+			wantError: "the defined_only constraint is only supported on enums",
+		},
+		{
+			msg: "min_len on an integer",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "count", descriptor.FieldDescriptorProto_TYPE_INT32, &yarpcproto.FieldRules{MinLen: 1})
+			},
+			wantError: "the min_len and max_len constraints are only supported",
+		},
+		{
+			msg: "fractional min on an integer",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "count", descriptor.FieldDescriptorProto_TYPE_INT32, &yarpcproto.FieldRules{
+					MinValue: &yarpcproto.FieldRules_Min{Min: 0.5},
+				})
+			},
+			wantError: "invalid min constraint",
+		},
+		{
+			msg: "constraints on a oneof member",
+			field: func(t *testing.T) *protoplugin.Field {
+				field := newTestField(t, "name", descriptor.FieldDescriptorProto_TYPE_STRING, &yarpcproto.FieldRules{Required: true})
+				field.OneofIndex = proto.Int32(0)
+				return field
+			},
+			wantError: "constraints are not supported on fields of a oneof",
+		},
+		{
+			msg: "field named validate",
+			field: func(t *testing.T) *protoplugin.Field {
+				return newTestField(t, "validate", descriptor.FieldDescriptorProto_TYPE_BOOL, nil)
+			},
+			wantError: "conflicts with the Validate method",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			_, err := newFieldChecks("GetRequest", tt.field(t))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+func TestNewFieldChecksChained(t *testing.T) {
+	field := newTestField(t, "name", descriptor.FieldDescriptorProto_TYPE_STRING, &yarpcproto.FieldRules{
+		Required: true,
+		MaxLen:   3,
+	})
+	checks, err := newFieldChecks("GetRequest", field)
+	require.NoError(t, err)
+	require.Len(t, checks.statements, 1, "checks of the same value must be chained")
+	assert.Equal(t, `if m.GetName() == "" {
+	violations.Add("name", "must be set")
+} else if utf8.RuneCountInString(m.GetName()) > 3 {
+	violations.Add("name", "must be at most 3 characters long")
+}`, checks.statements[0])
+	assert.True(t, checks.usesUTF8)
+}
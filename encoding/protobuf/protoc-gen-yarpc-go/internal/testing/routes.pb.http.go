@@ -0,0 +1,91 @@
+// Code generated by protoc-gen-yarpc-go
+// source: encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testing
+
+import (
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/transport/http"
+)
+
+// BuildUsersYARPCHTTPRoutes returns the routes that serve the
+// procedures of the Users service on the RESTful URLs declared
+// with the google.api.http option of its methods. Give them to an HTTP inbound
+// with the http.Routes option, and register the procedures built by
+// BuildUsersYARPCProcedures.
+func BuildUsersYARPCHTTPRoutes() []http.Route {
+	return protobuf.BuildHTTPRoutes(
+		protobuf.BuildHTTPRoutesParams{
+			ServiceName: "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Users",
+			Rules: []protobuf.HTTPRule{
+				{
+					MethodName:  "GetUser",
+					HTTPMethod:  "GET",
+					Pattern:     "/v1/users/{id}",
+					NewRequest:  newUsersServiceGetUserYARPCRequest,
+					NewResponse: newUsersServiceGetUserYARPCResponse,
+				},
+				{
+					MethodName:  "CreateUser",
+					HTTPMethod:  "POST",
+					Pattern:     "/v1/{group=groups/*}/users",
+					Body:        "user",
+					NewRequest:  newUsersServiceCreateUserYARPCRequest,
+					NewResponse: newUsersServiceCreateUserYARPCResponse,
+				},
+				{
+					MethodName:  "UpdateUser",
+					HTTPMethod:  "PATCH",
+					Pattern:     "/v1/users/{id}",
+					Body:        "*",
+					NewRequest:  newUsersServiceUpdateUserYARPCRequest,
+					NewResponse: newUsersServiceUpdateUserYARPCResponse,
+				},
+				{
+					MethodName:  "UpdateUser",
+					HTTPMethod:  "PUT",
+					Pattern:     "/v1/users/{id}",
+					Body:        "*",
+					NewRequest:  newUsersServiceUpdateUserYARPCRequest,
+					NewResponse: newUsersServiceUpdateUserYARPCResponse,
+				},
+				{
+					MethodName:   "ListUsers",
+					HTTPMethod:   "GET",
+					Pattern:      "/v1/{group=groups/*}/users",
+					ResponseBody: "users",
+					NewRequest:   newUsersServiceListUsersYARPCRequest,
+					NewResponse:  newUsersServiceListUsersYARPCResponse,
+				},
+				{
+					MethodName:  "DisableUser",
+					HTTPMethod:  "POST",
+					Pattern:     "/v1/users/{id}:disable",
+					NewRequest:  newUsersServiceDisableUserYARPCRequest,
+					NewResponse: newUsersServiceDisableUserYARPCResponse,
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,715 @@
+// Code generated by protoc-gen-yarpc-go
+// source: encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testing
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/fx"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+)
+
+var _ = ioutil.NopCloser
+
+// UsersYARPCClient is the YARPC client-side interface for the Users service.
+type UsersYARPCClient interface {
+	GetUser(context.Context, *GetUserRequest, ...yarpc.CallOption) (*User, error)
+	CreateUser(context.Context, *CreateUserRequest, ...yarpc.CallOption) (*User, error)
+	UpdateUser(context.Context, *User, ...yarpc.CallOption) (*User, error)
+	ListUsers(context.Context, *ListUsersRequest, ...yarpc.CallOption) (*ListUsersResponse, error)
+	DisableUser(context.Context, *GetUserRequest, ...yarpc.CallOption) (*User, error)
+	ResetUsers(context.Context, *ListUsersRequest, ...yarpc.CallOption) (*ListUsersResponse, error)
+}
+
+// NewUsersYARPCClient builds a new YARPC client for the Users service.
+func NewUsersYARPCClient(clientConfig transport.ClientConfig, options ...protobuf.ClientOption) UsersYARPCClient {
+	return &_UsersYARPCCaller{protobuf.NewStreamClient(
+		protobuf.ClientParams{
+			ServiceName:  "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Users",
+			ClientConfig: clientConfig,
+			Options:      options,
+		},
+	)}
+}
+
+// UsersYARPCServer is the YARPC server-side interface for the Users service.
+type UsersYARPCServer interface {
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	UpdateUser(context.Context, *User) (*User, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	DisableUser(context.Context, *GetUserRequest) (*User, error)
+	ResetUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+}
+
+// BuildUsersYARPCProcedures prepares an implementation of the Users service for YARPC registration.
+func BuildUsersYARPCProcedures(server UsersYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
+	handler := &_UsersYARPCHandler{server}
+	return protobuf.BuildProcedures(
+		protobuf.BuildProceduresParams{
+			ServiceName:     "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Users",
+			FileDescriptors: yarpcFileDescriptorClosure0d81357a36f542fb,
+			Options:         options,
+			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
+				{
+					MethodName: "GetUser",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.GetUser,
+							NewRequest: newUsersServiceGetUserYARPCRequest,
+						},
+					),
+				},
+				{
+					MethodName: "CreateUser",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.CreateUser,
+							NewRequest: newUsersServiceCreateUserYARPCRequest,
+						},
+					),
+				},
+				{
+					MethodName: "UpdateUser",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.UpdateUser,
+							NewRequest: newUsersServiceUpdateUserYARPCRequest,
+						},
+					),
+				},
+				{
+					MethodName: "ListUsers",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.ListUsers,
+							NewRequest: newUsersServiceListUsersYARPCRequest,
+						},
+					),
+				},
+				{
+					MethodName: "DisableUser",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.DisableUser,
+							NewRequest: newUsersServiceDisableUserYARPCRequest,
+						},
+					),
+				},
+				{
+					MethodName: "ResetUsers",
+					Handler: protobuf.NewUnaryHandler(
+						protobuf.UnaryHandlerParams{
+							Handle:     handler.ResetUsers,
+							NewRequest: newUsersServiceResetUsersYARPCRequest,
+						},
+					),
+				},
+			},
+			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{},
+			StreamHandlerParams: []protobuf.BuildProceduresStreamHandlerParams{},
+		},
+	)
+}
+
+// FxUsersYARPCClientParams defines the input
+// for NewFxUsersYARPCClient. It provides the
+// paramaters to get a UsersYARPCClient in an
+// Fx application.
+type FxUsersYARPCClientParams struct {
+	fx.In
+
+	Provider yarpc.ClientConfig
+}
+
+// FxUsersYARPCClientResult defines the output
+// of NewFxUsersYARPCClient. It provides a
+// UsersYARPCClient to an Fx application.
+type FxUsersYARPCClientResult struct {
+	fx.Out
+
+	Client UsersYARPCClient
+
+	// We are using an fx.Out struct here instead of just returning a client
+	// so that we can add more values or add named versions of the client in
+	// the future without breaking any existing code.
+}
+
+// NewFxUsersYARPCClient provides a UsersYARPCClient
+// to an Fx application using the given name for routing.
+//
+//	fx.Provide(
+//	  testing.NewFxUsersYARPCClient("service-name"),
+//	  ...
+//	)
+func NewFxUsersYARPCClient(name string, options ...protobuf.ClientOption) interface{} {
+	return func(params FxUsersYARPCClientParams) FxUsersYARPCClientResult {
+		return FxUsersYARPCClientResult{
+			Client: NewUsersYARPCClient(params.Provider.ClientConfig(name), options...),
+		}
+	}
+}
+
+// FxUsersYARPCProceduresParams defines the input
+// for NewFxUsersYARPCProcedures. It provides the
+// paramaters to get UsersYARPCServer procedures in an
+// Fx application.
+type FxUsersYARPCProceduresParams struct {
+	fx.In
+
+	Server UsersYARPCServer
+}
+
+// FxUsersYARPCProceduresResult defines the output
+// of NewFxUsersYARPCProcedures. It provides
+// UsersYARPCServer procedures to an Fx application.
+//
+// The procedures are provided to the "yarpcfx" value group.
+// Dig 1.2 or newer must be used for this feature to work.
+type FxUsersYARPCProceduresResult struct {
+	fx.Out
+
+	Procedures []transport.Procedure `group:"yarpcfx"`
+}
+
+// NewFxUsersYARPCProcedures provides UsersYARPCServer procedures to an Fx application.
+// It expects a UsersYARPCServer to be present in the container.
+//
+//	fx.Provide(
+//	  testing.NewFxUsersYARPCProcedures(),
+//	  ...
+//	)
+func NewFxUsersYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
+	return func(params FxUsersYARPCProceduresParams) FxUsersYARPCProceduresResult {
+		return FxUsersYARPCProceduresResult{
+			Procedures: BuildUsersYARPCProcedures(params.Server, options...),
+		}
+	}
+}
+
+type _UsersYARPCCaller struct {
+	streamClient protobuf.StreamClient
+}
+
+func (c *_UsersYARPCCaller) GetUser(ctx context.Context, request *GetUserRequest, options ...yarpc.CallOption) (*User, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "GetUser", request, newUsersServiceGetUserYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*User)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceGetUserYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_UsersYARPCCaller) CreateUser(ctx context.Context, request *CreateUserRequest, options ...yarpc.CallOption) (*User, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "CreateUser", request, newUsersServiceCreateUserYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*User)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceCreateUserYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_UsersYARPCCaller) UpdateUser(ctx context.Context, request *User, options ...yarpc.CallOption) (*User, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "UpdateUser", request, newUsersServiceUpdateUserYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*User)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceUpdateUserYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_UsersYARPCCaller) ListUsers(ctx context.Context, request *ListUsersRequest, options ...yarpc.CallOption) (*ListUsersResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "ListUsers", request, newUsersServiceListUsersYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*ListUsersResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceListUsersYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_UsersYARPCCaller) DisableUser(ctx context.Context, request *GetUserRequest, options ...yarpc.CallOption) (*User, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "DisableUser", request, newUsersServiceDisableUserYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*User)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceDisableUserYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+func (c *_UsersYARPCCaller) ResetUsers(ctx context.Context, request *ListUsersRequest, options ...yarpc.CallOption) (*ListUsersResponse, error) {
+	responseMessage, err := c.streamClient.Call(ctx, "ResetUsers", request, newUsersServiceResetUsersYARPCResponse, options...)
+	if responseMessage == nil {
+		return nil, err
+	}
+	response, ok := responseMessage.(*ListUsersResponse)
+	if !ok {
+		return nil, protobuf.CastError(emptyUsersServiceResetUsersYARPCResponse, responseMessage)
+	}
+	return response, err
+}
+
+type _UsersYARPCHandler struct {
+	server UsersYARPCServer
+}
+
+func (h *_UsersYARPCHandler) GetUser(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *GetUserRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*GetUserRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceGetUserYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.GetUser(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_UsersYARPCHandler) CreateUser(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *CreateUserRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*CreateUserRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceCreateUserYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.CreateUser(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_UsersYARPCHandler) UpdateUser(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *User
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*User)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceUpdateUserYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.UpdateUser(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_UsersYARPCHandler) ListUsers(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *ListUsersRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*ListUsersRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceListUsersYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.ListUsers(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_UsersYARPCHandler) DisableUser(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *GetUserRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*GetUserRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceDisableUserYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.DisableUser(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func (h *_UsersYARPCHandler) ResetUsers(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	var request *ListUsersRequest
+	var ok bool
+	if requestMessage != nil {
+		request, ok = requestMessage.(*ListUsersRequest)
+		if !ok {
+			return nil, protobuf.CastError(emptyUsersServiceResetUsersYARPCRequest, requestMessage)
+		}
+	}
+	response, err := h.server.ResetUsers(ctx, request)
+	if response == nil {
+		return nil, err
+	}
+	return response, err
+}
+
+func newUsersServiceGetUserYARPCRequest() proto.Message {
+	return &GetUserRequest{}
+}
+
+func newUsersServiceGetUserYARPCResponse() proto.Message {
+	return &User{}
+}
+
+func newUsersServiceCreateUserYARPCRequest() proto.Message {
+	return &CreateUserRequest{}
+}
+
+func newUsersServiceCreateUserYARPCResponse() proto.Message {
+	return &User{}
+}
+
+func newUsersServiceUpdateUserYARPCRequest() proto.Message {
+	return &User{}
+}
+
+func newUsersServiceUpdateUserYARPCResponse() proto.Message {
+	return &User{}
+}
+
+func newUsersServiceListUsersYARPCRequest() proto.Message {
+	return &ListUsersRequest{}
+}
+
+func newUsersServiceListUsersYARPCResponse() proto.Message {
+	return &ListUsersResponse{}
+}
+
+func newUsersServiceDisableUserYARPCRequest() proto.Message {
+	return &GetUserRequest{}
+}
+
+func newUsersServiceDisableUserYARPCResponse() proto.Message {
+	return &User{}
+}
+
+func newUsersServiceResetUsersYARPCRequest() proto.Message {
+	return &ListUsersRequest{}
+}
+
+func newUsersServiceResetUsersYARPCResponse() proto.Message {
+	return &ListUsersResponse{}
+}
+
+var (
+	emptyUsersServiceGetUserYARPCRequest      = &GetUserRequest{}
+	emptyUsersServiceGetUserYARPCResponse     = &User{}
+	emptyUsersServiceCreateUserYARPCRequest   = &CreateUserRequest{}
+	emptyUsersServiceCreateUserYARPCResponse  = &User{}
+	emptyUsersServiceUpdateUserYARPCRequest   = &User{}
+	emptyUsersServiceUpdateUserYARPCResponse  = &User{}
+	emptyUsersServiceListUsersYARPCRequest    = &ListUsersRequest{}
+	emptyUsersServiceListUsersYARPCResponse   = &ListUsersResponse{}
+	emptyUsersServiceDisableUserYARPCRequest  = &GetUserRequest{}
+	emptyUsersServiceDisableUserYARPCResponse = &User{}
+	emptyUsersServiceResetUsersYARPCRequest   = &ListUsersRequest{}
+	emptyUsersServiceResetUsersYARPCResponse  = &ListUsersResponse{}
+)
+
+var yarpcFileDescriptorClosure0d81357a36f542fb = [][]byte{
+	// encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x53, 0xcf, 0x6b, 0xd4, 0x4e,
+		0x14, 0xcf, 0x6c, 0x76, 0xbf, 0xed, 0xbe, 0x42, 0x69, 0xe7, 0x2b, 0x25, 0xac, 0x32, 0x2c, 0xf1,
+		0xb2, 0x16, 0x9a, 0x60, 0xbd, 0x2d, 0xd4, 0x83, 0x2d, 0x78, 0x11, 0xb1, 0x91, 0x82, 0xd4, 0x83,
+		0x24, 0x9b, 0x67, 0x1c, 0x68, 0x67, 0x62, 0x26, 0x29, 0xd8, 0x52, 0x10, 0xfd, 0x07, 0x04, 0xff,
+		0x09, 0xaf, 0x5e, 0xf4, 0x0f, 0xf0, 0xd2, 0x9b, 0x0b, 0x5e, 0x0a, 0x5e, 0xdc, 0xac, 0x07, 0x8f,
+		0xfd, 0x13, 0x24, 0x93, 0xdd, 0xba, 0xdd, 0x42, 0x2f, 0x9b, 0x83, 0x5e, 0x86, 0xf7, 0x86, 0x37,
+		0x9f, 0x1f, 0xf3, 0xde, 0x83, 0x4d, 0x14, 0x3d, 0x19, 0x72, 0x11, 0xb9, 0x71, 0x22, 0x53, 0x19,
+		0x64, 0xcf, 0xcb, 0xa0, 0xb7, 0x16, 0xa1, 0x58, 0x7b, 0xe5, 0x27, 0x71, 0x6f, 0x2d, 0x92, 0x2e,
+		0x17, 0x29, 0x26, 0xc2, 0xdf, 0x73, 0x53, 0x54, 0x69, 0x51, 0x9d, 0xc8, 0x2c, 0x45, 0xe5, 0xe8,
+		0x5a, 0x7a, 0x37, 0x0b, 0x30, 0x71, 0x74, 0xb1, 0x33, 0xc6, 0x73, 0xc6, 0x78, 0x65, 0xd0, 0x8b,
+		0x50, 0xe8, 0x82, 0x48, 0x3a, 0x63, 0x30, 0x67, 0x04, 0xd6, 0xba, 0x11, 0x49, 0x19, 0xed, 0xa1,
+		0xeb, 0xc7, 0xdc, 0xf5, 0x85, 0x90, 0xa9, 0x9f, 0x72, 0x29, 0x46, 0xe8, 0xf6, 0x0b, 0xa8, 0xef,
+		0x28, 0x4c, 0xe8, 0x22, 0xd4, 0x78, 0x68, 0x91, 0x36, 0xe9, 0x34, 0xbd, 0x1a, 0x0f, 0xe9, 0x35,
+		0x68, 0x44, 0x89, 0xcc, 0x62, 0xab, 0xa6, 0xaf, 0xca, 0x84, 0x52, 0xa8, 0x0b, 0x7f, 0x1f, 0x2d,
+		0x53, 0x5f, 0xea, 0x98, 0x2e, 0x81, 0xe9, 0x47, 0x68, 0xd5, 0xdb, 0xa4, 0x63, 0x7a, 0x45, 0x58,
+		0xbc, 0xf5, 0xc3, 0x7d, 0x2e, 0xac, 0x46, 0x9b, 0x74, 0xe6, 0xbd, 0x32, 0xb1, 0xbb, 0xb0, 0x78,
+		0x1f, 0xd3, 0x82, 0xcc, 0xc3, 0x97, 0x19, 0xaa, 0xf4, 0x12, 0xa7, 0x05, 0x73, 0x07, 0x98, 0x04,
+		0x52, 0xa1, 0x66, 0x9d, 0xf7, 0xc6, 0xa9, 0xfd, 0x96, 0xc0, 0xf2, 0x66, 0x82, 0x7e, 0x8a, 0x93,
+		0xef, 0xcf, 0x35, 0x92, 0x49, 0x8d, 0x4f, 0xa0, 0x9e, 0x29, 0x4c, 0x34, 0xc4, 0xc2, 0xfa, 0x96,
+		0x33, 0xdb, 0xf7, 0x39, 0x9a, 0x50, 0x23, 0xda, 0x4f, 0x61, 0xe9, 0x01, 0x57, 0xda, 0x82, 0xba,
+		0x5a, 0xc3, 0x75, 0x68, 0xc6, 0x7e, 0x84, 0xcf, 0x14, 0x3f, 0x2c, 0xbd, 0x34, 0xbc, 0xf9, 0xe2,
+		0xe2, 0x31, 0x3f, 0xd4, 0xdf, 0x53, 0x7c, 0x9c, 0xb2, 0xcc, 0xb6, 0x59, 0x3c, 0xd1, 0x89, 0x2d,
+		0x61, 0x79, 0x02, 0x5c, 0xc5, 0x52, 0x28, 0xa4, 0xbb, 0xd0, 0x28, 0x98, 0x95, 0x45, 0xda, 0x66,
+		0x65, 0x66, 0x4a, 0xc8, 0xf5, 0x4f, 0x4d, 0x68, 0x68, 0x36, 0xfa, 0x99, 0xc0, 0xdc, 0xa8, 0x35,
+		0xf4, 0xe1, 0xac, 0x14, 0x17, 0x7b, 0xdc, 0xaa, 0x44, 0xb2, 0xbd, 0xf2, 0xe6, 0xdb, 0xcf, 0xf7,
+		0xb5, 0x25, 0xba, 0xe8, 0x1e, 0xdc, 0x76, 0xb5, 0x7c, 0xf7, 0x88, 0x87, 0xc7, 0xf4, 0x2b, 0x01,
+		0xf8, 0x33, 0x17, 0x74, 0x7b, 0x56, 0xb2, 0x4b, 0x33, 0x56, 0x91, 0xfe, 0x8e, 0xd6, 0x6f, 0x77,
+		0xcb, 0x39, 0x6a, 0x15, 0x2e, 0x8e, 0xf4, 0xa4, 0x6c, 0xe8, 0x53, 0xb9, 0xab, 0xc7, 0xa5, 0x2d,
+		0x7a, 0x42, 0x00, 0x76, 0xe2, 0x70, 0xec, 0xa8, 0x12, 0xfa, 0x8a, 0x4c, 0x38, 0xda, 0x44, 0xa7,
+		0x4b, 0x56, 0xd7, 0xa7, 0xfa, 0xb0, 0xfb, 0x7f, 0x97, 0xac, 0xb6, 0xa6, 0x9b, 0xf3, 0x9d, 0x40,
+		0xf3, 0x7c, 0xa4, 0xe9, 0xa3, 0x59, 0x35, 0x4c, 0xaf, 0x5e, 0x6b, 0xbb, 0x42, 0xc4, 0x72, 0xdf,
+		0xec, 0x5b, 0xda, 0xe2, 0x4d, 0x7a, 0x45, 0x87, 0x82, 0x72, 0x7d, 0xe8, 0x17, 0x02, 0x0b, 0x5b,
+		0x5c, 0xf9, 0xc1, 0x1e, 0xfe, 0xc5, 0x8b, 0xc3, 0xb4, 0x21, 0xcb, 0x5e, 0xb9, 0xd8, 0x9b, 0x6e,
+		0x58, 0x2a, 0xa7, 0x1f, 0x09, 0x80, 0x87, 0x0a, 0xff, 0xa1, 0x26, 0xdd, 0xdb, 0xe8, 0x0f, 0x98,
+		0x71, 0x3a, 0x60, 0xc6, 0xd9, 0x80, 0x91, 0xd7, 0x39, 0x23, 0x1f, 0x72, 0x46, 0x4e, 0x72, 0x46,
+		0xfa, 0x39, 0x23, 0x3f, 0x72, 0x46, 0x7e, 0xe5, 0xcc, 0x38, 0xcb, 0x19, 0x79, 0x37, 0x64, 0x46,
+		0x7f, 0xc8, 0x8c, 0xd3, 0x21, 0x33, 0x76, 0xe7, 0x46, 0x80, 0xc1, 0x7f, 0x9a, 0xf3, 0xce, 0xef,
+		0x01, 0x00, 0x1f, 0x85, 0xd9, 0xeb, 0x9d, 0x07, 0x00, 0x00,
+	},
+	// google/api/annotations.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x49, 0xcf, 0xcf, 0x4f,
+		0xcf, 0x49, 0xd5, 0x4f, 0x2c, 0xc8, 0xd4, 0x4f, 0xcc, 0xcb, 0xcb, 0x2f, 0x49, 0x2c, 0xc9, 0xcc,
+		0xcf, 0x2b, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x82, 0xc8, 0xea, 0x25, 0x16, 0x64,
+		0x4a, 0x89, 0x22, 0xa9, 0xcc, 0x28, 0x29, 0x29, 0x80, 0x28, 0x91, 0x52, 0x80, 0x0a, 0x83, 0x79,
+		0x49, 0xa5, 0x69, 0xfa, 0x29, 0xa9, 0xc5, 0xc9, 0x45, 0x99, 0x05, 0x25, 0xf9, 0x45, 0x10, 0x15,
+		0x56, 0xde, 0x5c, 0x2c, 0x20, 0xf5, 0x42, 0x72, 0x7a, 0x50, 0xd3, 0x60, 0x4a, 0xf5, 0x7c, 0x53,
+		0x4b, 0x32, 0xf2, 0x53, 0xfc, 0x0b, 0xc0, 0x56, 0x4a, 0x6c, 0x38, 0xb5, 0x47, 0x49, 0x81, 0x51,
+		0x83, 0xdb, 0x48, 0x44, 0x0f, 0x61, 0xad, 0x9e, 0x47, 0x49, 0x49, 0x41, 0x50, 0x69, 0x4e, 0x6a,
+		0x10, 0xd8, 0x10, 0xa7, 0x98, 0x1b, 0x0f, 0xe5, 0x18, 0x3e, 0x3c, 0x94, 0x63, 0xfc, 0xf1, 0x50,
+		0x8e, 0xb1, 0xe1, 0x91, 0x1c, 0xe3, 0x8a, 0x47, 0x72, 0x8c, 0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78,
+		0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x8b, 0x47, 0x72, 0x0c, 0x1f, 0x40, 0x62, 0x8f, 0xe5,
+		0x18, 0xb9, 0xf8, 0x92, 0xf3, 0x73, 0x91, 0x0c, 0x73, 0x12, 0x70, 0x44, 0x78, 0x31, 0x00, 0xe4,
+		0x8a, 0x00, 0xc6, 0x28, 0xe6, 0xc4, 0x82, 0xcc, 0x45, 0x4c, 0x2c, 0xee, 0x8e, 0x01, 0x9e, 0x49,
+		0x6c, 0x60, 0xa7, 0x19, 0x03, 0x06, 0x00, 0xd0, 0xe9, 0xae, 0xdf, 0x16, 0x01, 0x00, 0x00,
+	},
+	// google/api/http.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xb1, 0x8f, 0xd3, 0x30,
+		0x14, 0xc6, 0xeb, 0x36, 0xed, 0xb5, 0xaf, 0x07, 0x12, 0xe6, 0x40, 0x16, 0x02, 0x53, 0x95, 0xa5,
+		0x62, 0xe8, 0x49, 0xc7, 0xc0, 0xc0, 0x44, 0xa0, 0xe2, 0xd8, 0xaa, 0x4c, 0x88, 0x25, 0x72, 0xe3,
+		0x47, 0x6b, 0x91, 0x8b, 0xad, 0xd8, 0x41, 0x74, 0xe3, 0x6f, 0x61, 0xe2, 0x4f, 0x61, 0x64, 0x44,
+		0x4c, 0x34, 0x2c, 0x8c, 0x37, 0xde, 0x88, 0xec, 0xa4, 0x5c, 0x25, 0x24, 0xb6, 0xf7, 0x7d, 0xef,
+		0x97, 0x97, 0x2f, 0x2f, 0x0f, 0xee, 0xac, 0xb5, 0x5e, 0xe7, 0x78, 0x2a, 0x8c, 0x3a, 0xdd, 0x38,
+		0x67, 0xe6, 0xa6, 0xd4, 0x4e, 0x53, 0x68, 0xec, 0xb9, 0x30, 0x6a, 0xba, 0x85, 0xe8, 0xdc, 0x39,
+		0x43, 0x1f, 0x43, 0xbf, 0xac, 0x72, 0xb4, 0x8c, 0x4c, 0x7a, 0xb3, 0xf1, 0xd9, 0xc9, 0xfc, 0x9a,
+		0x99, 0x7b, 0x20, 0xa9, 0x72, 0x4c, 0x1a, 0x84, 0x2e, 0xe0, 0xe1, 0xbb, 0x2a, 0xcf, 0xb7, 0xa9,
+		0xc4, 0x4c, 0x4b, 0x4c, 0x4b, 0xb4, 0x58, 0x7e, 0x40, 0x99, 0xe2, 0x47, 0x23, 0x0a, 0xab, 0x74,
+		0xc1, 0xba, 0x13, 0x32, 0x1b, 0x26, 0xf7, 0x03, 0xf6, 0x32, 0x50, 0x49, 0x0b, 0x2d, 0xf6, 0xcc,
+		0xf4, 0x47, 0x17, 0x86, 0xfb, 0xd1, 0xf4, 0x1e, 0x0c, 0x2d, 0xe6, 0x98, 0x39, 0x5d, 0x32, 0x32,
+		0x21, 0xb3, 0x51, 0xf2, 0x57, 0x53, 0x0a, 0xbd, 0x35, 0xba, 0x30, 0x73, 0x74, 0xde, 0x49, 0xbc,
+		0xf0, 0x9e, 0xa9, 0x1c, 0xeb, 0xed, 0x3d, 0x53, 0x39, 0x7a, 0x02, 0x91, 0xd1, 0xd6, 0xb1, 0xa8,
+		0x35, 0x83, 0xa2, 0x0c, 0x06, 0x12, 0x73, 0x74, 0xc8, 0xfa, 0xad, 0xdf, 0x6a, 0x7a, 0x17, 0xfa,
+		0x46, 0xb8, 0x6c, 0xc3, 0x06, 0x6d, 0xa3, 0x91, 0xf4, 0x29, 0x0c, 0xb2, 0xca, 0x3a, 0x7d, 0xc1,
+		0x86, 0x13, 0x32, 0x1b, 0x9f, 0x3d, 0x38, 0x5c, 0xc6, 0x8b, 0xd0, 0xf1, 0xb9, 0x97, 0xc2, 0x39,
+		0x2c, 0x0b, 0x3f, 0xb0, 0xc1, 0x29, 0x85, 0x68, 0xa5, 0xe5, 0x96, 0x1d, 0x85, 0x0f, 0x08, 0x35,
+		0x7d, 0x04, 0x37, 0x4a, 0xb4, 0x46, 0x17, 0x16, 0xd3, 0xd0, 0x3c, 0x0e, 0xcd, 0xe3, 0xbd, 0x19,
+		0x7b, 0x68, 0x01, 0xb7, 0x85, 0x94, 0xca, 0x29, 0x5d, 0x88, 0x3c, 0x5d, 0xa9, 0x42, 0xaa, 0x62,
+		0x6d, 0xd9, 0xf8, 0x3f, 0xff, 0x82, 0x5e, 0x3f, 0x10, 0xb7, 0x7c, 0x3c, 0x82, 0x23, 0xd3, 0x84,
+		0x9a, 0x3e, 0x83, 0x5b, 0xff, 0x24, 0xf5, 0xf9, 0xde, 0xab, 0x42, 0xb6, 0x0b, 0x0e, 0xb5, 0xf7,
+		0x8c, 0x70, 0x9b, 0x66, 0xbb, 0x49, 0xa8, 0xe3, 0x37, 0xdf, 0x77, 0xbc, 0x73, 0xb9, 0xe3, 0xe4,
+		0x6a, 0xc7, 0xc9, 0xa7, 0x9a, 0x93, 0x2f, 0x35, 0x27, 0x5f, 0x6b, 0x4e, 0xbe, 0xd5, 0x9c, 0xfc,
+		0xac, 0x39, 0xf9, 0x5d, 0xf3, 0xce, 0xa5, 0xf7, 0x7e, 0x71, 0x02, 0x37, 0x33, 0x7d, 0x71, 0x10,
+		0x31, 0x1e, 0x85, 0x57, 0xfa, 0x4b, 0x5b, 0x92, 0xb7, 0x3d, 0x61, 0xd4, 0x15, 0x21, 0x9f, 0xbb,
+		0xd1, 0xab, 0xe7, 0xcb, 0xd7, 0xab, 0x41, 0xb8, 0xc0, 0x27, 0x7f, 0x06, 0x00, 0x1e, 0x40, 0xdc,
+		0xc0, 0x9a, 0x02, 0x00, 0x00,
+	},
+	// google/protobuf/descriptor.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x5b, 0x8f, 0xdb, 0xc6,
+		0x15, 0x8e, 0xa8, 0xcb, 0x4a, 0x47, 0x5a, 0x2d, 0x77, 0x76, 0x63, 0xd3, 0x9b, 0x8b, 0xd7, 0xca,
+		0xc5, 0x6b, 0x27, 0x91, 0x03, 0xc7, 0xde, 0x38, 0x9b, 0x22, 0xad, 0x56, 0xa2, 0x37, 0x4a, 0x75,
+		0x2b, 0xa5, 0x6d, 0x2e, 0x45, 0x41, 0xcc, 0x92, 0x23, 0x89, 0x0e, 0x45, 0x32, 0x24, 0x65, 0x7b,
+		0x83, 0x3e, 0x18, 0xe8, 0x53, 0x81, 0xfe, 0x80, 0xa2, 0x28, 0xfa, 0xd0, 0x97, 0x00, 0xfd, 0x01,
+		0x05, 0xda, 0xf7, 0xbe, 0x16, 0xe8, 0x7b, 0x1f, 0x0a, 0xb4, 0x40, 0xfb, 0x13, 0xfa, 0x58, 0xcc,
+		0x0c, 0x49, 0x91, 0x94, 0x14, 0x6f, 0x02, 0xc4, 0x79, 0xda, 0x9d, 0x6f, 0xbe, 0x73, 0xe6, 0xcc,
+		0xe1, 0x37, 0x33, 0x67, 0x46, 0xb0, 0x3f, 0xb1, 0xed, 0x89, 0x49, 0x6e, 0x39, 0xae, 0xed, 0xdb,
+		0x67, 0xf3, 0xf1, 0x2d, 0x9d, 0x78, 0x9a, 0x6b, 0x38, 0xbe, 0xed, 0xd6, 0x19, 0x86, 0xb6, 0x38,
+		0xa3, 0x1e, 0x32, 0x6a, 0x5d, 0xd8, 0xbe, 0x6f, 0x98, 0xa4, 0x15, 0x11, 0x87, 0xc4, 0x47, 0xf7,
+		0x20, 0x37, 0x36, 0x4c, 0x22, 0x65, 0xf6, 0xb3, 0x07, 0xe5, 0xdb, 0xaf, 0xd6, 0x53, 0x46, 0xf5,
+		0xa4, 0xc5, 0x80, 0xc2, 0x0a, 0xb3, 0xa8, 0xfd, 0x3b, 0x07, 0x3b, 0x2b, 0x7a, 0x11, 0x82, 0x9c,
+		0x85, 0x67, 0xd4, 0x63, 0xe6, 0xa0, 0xa4, 0xb0, 0xff, 0x91, 0x04, 0x1b, 0x0e, 0xd6, 0x3e, 0xc7,
+		0x13, 0x22, 0x09, 0x0c, 0x0e, 0x9b, 0xe8, 0x65, 0x00, 0x9d, 0x38, 0xc4, 0xd2, 0x89, 0xa5, 0x9d,
+		0x4b, 0xd9, 0xfd, 0xec, 0x41, 0x49, 0x89, 0x21, 0xe8, 0x0d, 0xd8, 0x76, 0xe6, 0x67, 0xa6, 0xa1,
+		0xa9, 0x31, 0x1a, 0xec, 0x67, 0x0f, 0xf2, 0x8a, 0xc8, 0x3b, 0x5a, 0x0b, 0xf2, 0x75, 0xd8, 0x7a,
+		0x44, 0xf0, 0xe7, 0x71, 0x6a, 0x99, 0x51, 0xab, 0x14, 0x8e, 0x11, 0x9b, 0x50, 0x99, 0x11, 0xcf,
+		0xc3, 0x13, 0xa2, 0xfa, 0xe7, 0x0e, 0x91, 0x72, 0x6c, 0xf6, 0xfb, 0x4b, 0xb3, 0x4f, 0xcf, 0xbc,
+		0x1c, 0x58, 0x8d, 0xce, 0x1d, 0x82, 0x1a, 0x50, 0x22, 0xd6, 0x7c, 0xc6, 0x3d, 0xe4, 0xd7, 0xe4,
+		0x4f, 0xb6, 0xe6, 0xb3, 0xb4, 0x97, 0x22, 0x35, 0x0b, 0x5c, 0x6c, 0x78, 0xc4, 0x7d, 0x68, 0x68,
+		0x44, 0x2a, 0x30, 0x07, 0xd7, 0x97, 0x1c, 0x0c, 0x79, 0x7f, 0xda, 0x47, 0x68, 0x87, 0x9a, 0x50,
+		0x22, 0x8f, 0x7d, 0x62, 0x79, 0x86, 0x6d, 0x49, 0x1b, 0xcc, 0xc9, 0x6b, 0x2b, 0xbe, 0x22, 0x31,
+		0xf5, 0xb4, 0x8b, 0x85, 0x1d, 0x3a, 0x84, 0x0d, 0xdb, 0xf1, 0x0d, 0xdb, 0xf2, 0xa4, 0xe2, 0x7e,
+		0xe6, 0xa0, 0x7c, 0xfb, 0xc5, 0x95, 0x42, 0xe8, 0x73, 0x8e, 0x12, 0x92, 0x51, 0x1b, 0x44, 0xcf,
+		0x9e, 0xbb, 0x1a, 0x51, 0x35, 0x5b, 0x27, 0xaa, 0x61, 0x8d, 0x6d, 0xa9, 0xc4, 0x1c, 0x5c, 0x5d,
+		0x9e, 0x08, 0x23, 0x36, 0x6d, 0x9d, 0xb4, 0xad, 0xb1, 0xad, 0x54, 0xbd, 0x44, 0x1b, 0x5d, 0x82,
+		0x82, 0x77, 0x6e, 0xf9, 0xf8, 0xb1, 0x54, 0x61, 0x0a, 0x09, 0x5a, 0xb5, 0x3f, 0x17, 0x60, 0xeb,
+		0x22, 0x12, 0x7b, 0x1f, 0xf2, 0x63, 0x3a, 0x4b, 0x49, 0xf8, 0x26, 0x39, 0xe0, 0x36, 0xc9, 0x24,
+		0x16, 0xbe, 0x65, 0x12, 0x1b, 0x50, 0xb6, 0x88, 0xe7, 0x13, 0x9d, 0x2b, 0x22, 0x7b, 0x41, 0x4d,
+		0x01, 0x37, 0x5a, 0x96, 0x54, 0xee, 0x5b, 0x49, 0xea, 0x13, 0xd8, 0x8a, 0x42, 0x52, 0x5d, 0x6c,
+		0x4d, 0x42, 0x6d, 0xde, 0x7a, 0x5a, 0x24, 0x75, 0x39, 0xb4, 0x53, 0xa8, 0x99, 0x52, 0x25, 0x89,
+		0x36, 0x6a, 0x01, 0xd8, 0x16, 0xb1, 0xc7, 0xaa, 0x4e, 0x34, 0x53, 0x2a, 0xae, 0xc9, 0x52, 0x9f,
+		0x52, 0x96, 0xb2, 0x64, 0x73, 0x54, 0x33, 0xd1, 0x7b, 0x0b, 0xa9, 0x6d, 0xac, 0x51, 0x4a, 0x97,
+		0x2f, 0xb2, 0x25, 0xb5, 0x9d, 0x42, 0xd5, 0x25, 0x54, 0xf7, 0x44, 0x0f, 0x66, 0x56, 0x62, 0x41,
+		0xd4, 0x9f, 0x3a, 0x33, 0x25, 0x30, 0xe3, 0x13, 0xdb, 0x74, 0xe3, 0x4d, 0xf4, 0x0a, 0x44, 0x80,
+		0xca, 0x64, 0x05, 0x6c, 0x17, 0xaa, 0x84, 0x60, 0x0f, 0xcf, 0xc8, 0xde, 0x97, 0x50, 0x4d, 0xa6,
+		0x07, 0xed, 0x42, 0xde, 0xf3, 0xb1, 0xeb, 0x33, 0x15, 0xe6, 0x15, 0xde, 0x40, 0x22, 0x64, 0x89,
+		0xa5, 0xb3, 0x5d, 0x2e, 0xaf, 0xd0, 0x7f, 0xd1, 0x8f, 0x16, 0x13, 0xce, 0xb2, 0x09, 0xbf, 0xbe,
+		0xfc, 0x45, 0x13, 0x9e, 0xd3, 0xf3, 0xde, 0x7b, 0x17, 0x36, 0x13, 0x13, 0xb8, 0xe8, 0xd0, 0xb5,
+		0x5f, 0xc0, 0xf3, 0x2b, 0x5d, 0xa3, 0x4f, 0x60, 0x77, 0x6e, 0x19, 0x96, 0x4f, 0x5c, 0xc7, 0x25,
+		0x54, 0xb1, 0x7c, 0x28, 0xe9, 0x3f, 0x1b, 0x6b, 0x34, 0x77, 0x1a, 0x67, 0x73, 0x2f, 0xca, 0xce,
+		0x7c, 0x19, 0xbc, 0x59, 0x2a, 0xfe, 0x77, 0x43, 0x7c, 0xf2, 0xe4, 0xc9, 0x13, 0xa1, 0xf6, 0x9b,
+		0x02, 0xec, 0xae, 0x5a, 0x33, 0x2b, 0x97, 0xef, 0x25, 0x28, 0x58, 0xf3, 0xd9, 0x19, 0x71, 0x59,
+		0x92, 0xf2, 0x4a, 0xd0, 0x42, 0x0d, 0xc8, 0x9b, 0xf8, 0x8c, 0x98, 0x52, 0x6e, 0x3f, 0x73, 0x50,
+		0xbd, 0xfd, 0xc6, 0x85, 0x56, 0x65, 0xbd, 0x43, 0x4d, 0x14, 0x6e, 0x89, 0x3e, 0x80, 0x5c, 0xb0,
+		0x45, 0x53, 0x0f, 0x37, 0x2f, 0xe6, 0x81, 0xae, 0x25, 0x85, 0xd9, 0xa1, 0x17, 0xa0, 0x44, 0xff,
+		0x72, 0x6d, 0x14, 0x58, 0xcc, 0x45, 0x0a, 0x50, 0x5d, 0xa0, 0x3d, 0x28, 0xb2, 0x65, 0xa2, 0x93,
+		0xf0, 0x68, 0x8b, 0xda, 0x54, 0x58, 0x3a, 0x19, 0xe3, 0xb9, 0xe9, 0xab, 0x0f, 0xb1, 0x39, 0x27,
+		0x4c, 0xf0, 0x25, 0xa5, 0x12, 0x80, 0x3f, 0xa5, 0x18, 0xba, 0x0a, 0x65, 0xbe, 0xaa, 0x0c, 0x4b,
+		0x27, 0x8f, 0xd9, 0xee, 0x99, 0x57, 0xf8, 0x42, 0x6b, 0x53, 0x84, 0x0e, 0xff, 0xc0, 0xb3, 0xad,
+		0x50, 0x9a, 0x6c, 0x08, 0x0a, 0xb0, 0xe1, 0xdf, 0x4d, 0x6f, 0xdc, 0x2f, 0xad, 0x9e, 0x5e, 0x5a,
+		0x53, 0xb5, 0x3f, 0x09, 0x90, 0x63, 0xfb, 0xc5, 0x16, 0x94, 0x47, 0x9f, 0x0e, 0x64, 0xb5, 0xd5,
+		0x3f, 0x3d, 0xee, 0xc8, 0x62, 0x06, 0x55, 0x01, 0x18, 0x70, 0xbf, 0xd3, 0x6f, 0x8c, 0x44, 0x21,
+		0x6a, 0xb7, 0x7b, 0xa3, 0xc3, 0x3b, 0x62, 0x36, 0x32, 0x38, 0xe5, 0x40, 0x2e, 0x4e, 0x78, 0xe7,
+		0xb6, 0x98, 0x47, 0x22, 0x54, 0xb8, 0x83, 0xf6, 0x27, 0x72, 0xeb, 0xf0, 0x8e, 0x58, 0x48, 0x22,
+		0xef, 0xdc, 0x16, 0x37, 0xd0, 0x26, 0x94, 0x18, 0x72, 0xdc, 0xef, 0x77, 0xc4, 0x62, 0xe4, 0x73,
+		0x38, 0x52, 0xda, 0xbd, 0x13, 0xb1, 0x14, 0xf9, 0x3c, 0x51, 0xfa, 0xa7, 0x03, 0x11, 0x22, 0x0f,
+		0x5d, 0x79, 0x38, 0x6c, 0x9c, 0xc8, 0x62, 0x39, 0x62, 0x1c, 0x7f, 0x3a, 0x92, 0x87, 0x62, 0x25,
+		0x11, 0xd6, 0x3b, 0xb7, 0xc5, 0xcd, 0x68, 0x08, 0xb9, 0x77, 0xda, 0x15, 0xab, 0x68, 0x1b, 0x36,
+		0xf9, 0x10, 0x61, 0x10, 0x5b, 0x29, 0xe8, 0xf0, 0x8e, 0x28, 0x2e, 0x02, 0xe1, 0x5e, 0xb6, 0x13,
+		0xc0, 0xe1, 0x1d, 0x11, 0xd5, 0x9a, 0x90, 0x67, 0xea, 0x42, 0x08, 0xaa, 0x9d, 0xc6, 0xb1, 0xdc,
+		0x51, 0xfb, 0x83, 0x51, 0xbb, 0xdf, 0x6b, 0x74, 0xc4, 0xcc, 0x02, 0x53, 0xe4, 0x9f, 0x9c, 0xb6,
+		0x15, 0xb9, 0x25, 0x0a, 0x71, 0x6c, 0x20, 0x37, 0x46, 0x72, 0x4b, 0xcc, 0xd6, 0x34, 0xd8, 0x5d,
+		0xb5, 0x4f, 0xae, 0x5c, 0x19, 0xb1, 0x4f, 0x2c, 0xac, 0xf9, 0xc4, 0xcc, 0xd7, 0xd2, 0x27, 0xfe,
+		0x97, 0x00, 0x3b, 0x2b, 0xce, 0x8a, 0x95, 0x83, 0xfc, 0x10, 0xf2, 0x5c, 0xa2, 0xfc, 0xf4, 0xbc,
+		0xb1, 0xf2, 0xd0, 0x61, 0x82, 0x5d, 0x3a, 0x41, 0x99, 0x5d, 0xbc, 0x82, 0xc8, 0xae, 0xa9, 0x20,
+		0xa8, 0x8b, 0xa5, 0x3d, 0xfd, 0xe7, 0x4b, 0x7b, 0x3a, 0x3f, 0xf6, 0x0e, 0x2f, 0x72, 0xec, 0x31,
+		0xec, 0x9b, 0xed, 0xed, 0xf9, 0x15, 0x7b, 0xfb, 0xfb, 0xb0, 0xbd, 0xe4, 0xe8, 0xc2, 0x7b, 0xec,
+		0x2f, 0x33, 0x20, 0xad, 0x4b, 0xce, 0x53, 0x76, 0x3a, 0x21, 0xb1, 0xd3, 0xbd, 0x9f, 0xce, 0xe0,
+		0xb5, 0xf5, 0x1f, 0x61, 0xe9, 0x5b, 0x7f, 0x95, 0x81, 0x4b, 0xab, 0x2b, 0xc5, 0x95, 0x31, 0x7c,
+		0x00, 0x85, 0x19, 0xf1, 0xa7, 0x76, 0x58, 0x2d, 0xbd, 0xbe, 0xe2, 0x0c, 0xa6, 0xdd, 0xe9, 0x8f,
+		0x1d, 0x58, 0xa1, 0xf7, 0xd2, 0xb1, 0x5e, 0x5d, 0x57, 0xb7, 0x2e, 0x45, 0xfa, 0x2b, 0x01, 0x9e,
+		0x5f, 0xe9, 0x7c, 0x65, 0xa0, 0x2f, 0x01, 0x18, 0x96, 0x33, 0xf7, 0x79, 0x45, 0xc4, 0x37, 0xd8,
+		0x12, 0x43, 0xd8, 0xe6, 0x45, 0x37, 0xcf, 0xb9, 0x1f, 0xf5, 0x67, 0x59, 0x3f, 0x70, 0x88, 0x11,
+		0xee, 0x2d, 0x02, 0xcd, 0xb1, 0x40, 0x5f, 0x5e, 0x33, 0xd3, 0x25, 0x61, 0xbe, 0x0d, 0xa2, 0x66,
+		0x1a, 0xc4, 0xf2, 0x55, 0xcf, 0x77, 0x09, 0x9e, 0x19, 0xd6, 0x84, 0x9d, 0x20, 0xc5, 0xa3, 0xfc,
+		0x18, 0x9b, 0x1e, 0x51, 0xb6, 0x78, 0xf7, 0x30, 0xec, 0xa5, 0x16, 0x4c, 0x40, 0x6e, 0xcc, 0xa2,
+		0x90, 0xb0, 0xe0, 0xdd, 0x91, 0x45, 0xed, 0xd7, 0x25, 0x28, 0xc7, 0xea, 0x6a, 0x74, 0x0d, 0x2a,
+		0x0f, 0xf0, 0x43, 0xac, 0x86, 0x77, 0x25, 0x9e, 0x89, 0x32, 0xc5, 0x06, 0x1c, 0x42, 0x6f, 0xc3,
+		0x2e, 0xa3, 0xd8, 0x73, 0x9f, 0xb8, 0xaa, 0x66, 0x62, 0xcf, 0x63, 0x49, 0x2b, 0x32, 0x2a, 0xa2,
+		0x7d, 0x7d, 0xda, 0xd5, 0x0c, 0x7b, 0xd0, 0x5d, 0xd8, 0x61, 0x16, 0xb3, 0xb9, 0xe9, 0x1b, 0x8e,
+		0x49, 0x54, 0x7a, 0x7b, 0xf3, 0x24, 0x88, 0x47, 0xb6, 0x4d, 0x19, 0xdd, 0x80, 0x40, 0x23, 0xf2,
+		0x50, 0x0b, 0x5e, 0x62, 0x66, 0x13, 0x62, 0x11, 0x17, 0xfb, 0x44, 0x25, 0x5f, 0xcc, 0xb1, 0xe9,
+		0xa9, 0xd8, 0xd2, 0xd5, 0x29, 0xf6, 0xa6, 0xd2, 0x2e, 0x75, 0x70, 0x2c, 0x48, 0x19, 0xe5, 0x0a,
+		0x25, 0x9e, 0x04, 0x3c, 0x99, 0xd1, 0x1a, 0x96, 0xfe, 0x21, 0xf6, 0xa6, 0xe8, 0x08, 0x2e, 0x31,
+		0x2f, 0x9e, 0xef, 0x1a, 0xd6, 0x44, 0xd5, 0xa6, 0x44, 0xfb, 0x5c, 0x9d, 0xfb, 0xe3, 0x7b, 0xd2,
+		0x0b, 0xf1, 0xf1, 0x59, 0x84, 0x43, 0xc6, 0x69, 0x52, 0xca, 0xa9, 0x3f, 0xbe, 0x87, 0x86, 0x50,
+		0xa1, 0x1f, 0x63, 0x66, 0x7c, 0x49, 0xd4, 0xb1, 0xed, 0xb2, 0xa3, 0xb1, 0xba, 0x62, 0x6b, 0x8a,
+		0x65, 0xb0, 0xde, 0x0f, 0x0c, 0xba, 0xb6, 0x4e, 0x8e, 0xf2, 0xc3, 0x81, 0x2c, 0xb7, 0x94, 0x72,
+		0xe8, 0xe5, 0xbe, 0xed, 0x52, 0x41, 0x4d, 0xec, 0x28, 0xc1, 0x65, 0x2e, 0xa8, 0x89, 0x1d, 0xa6,
+		0xf7, 0x2e, 0xec, 0x68, 0x1a, 0x9f, 0xb3, 0xa1, 0xa9, 0xc1, 0x1d, 0xcb, 0x93, 0xc4, 0x44, 0xb2,
+		0x34, 0xed, 0x84, 0x13, 0x02, 0x8d, 0x7b, 0xe8, 0x3d, 0x78, 0x7e, 0x91, 0xac, 0xb8, 0xe1, 0xf6,
+		0xd2, 0x2c, 0xd3, 0xa6, 0x77, 0x61, 0xc7, 0x39, 0x5f, 0x36, 0x44, 0x89, 0x11, 0x9d, 0xf3, 0xb4,
+		0xd9, 0xbb, 0xb0, 0xeb, 0x4c, 0x9d, 0x65, 0xbb, 0x9b, 0x71, 0x3b, 0xe4, 0x4c, 0x9d, 0xb4, 0xe1,
+		0x6b, 0xec, 0xc2, 0xed, 0x12, 0x0d, 0xfb, 0x44, 0x97, 0x2e, 0xc7, 0xe9, 0xb1, 0x0e, 0x74, 0x0b,
+		0x44, 0x4d, 0x53, 0x89, 0x85, 0xcf, 0x4c, 0xa2, 0x62, 0x97, 0x58, 0xd8, 0x93, 0xae, 0xc6, 0xc9,
+		0x55, 0x4d, 0x93, 0x59, 0x6f, 0x83, 0x75, 0xa2, 0x9b, 0xb0, 0x6d, 0x9f, 0x3d, 0xd0, 0xb8, 0x24,
+		0x55, 0xc7, 0x25, 0x63, 0xe3, 0xb1, 0xf4, 0x2a, 0xcb, 0xef, 0x16, 0xed, 0x60, 0x82, 0x1c, 0x30,
+		0x18, 0xdd, 0x00, 0x51, 0xf3, 0xa6, 0xd8, 0x75, 0xd8, 0x9e, 0xec, 0x39, 0x58, 0x23, 0xd2, 0x6b,
+		0x9c, 0xca, 0xf1, 0x5e, 0x08, 0xd3, 0x25, 0xe1, 0x3d, 0x32, 0xc6, 0x7e, 0xe8, 0xf1, 0x3a, 0x5f,
+		0x12, 0x0c, 0x0b, 0xbc, 0x1d, 0x80, 0x48, 0x53, 0x91, 0x18, 0xf8, 0x80, 0xd1, 0xaa, 0xce, 0xd4,
+		0x89, 0x8f, 0xfb, 0x0a, 0x6c, 0x3a, 0xd3, 0xf8, 0xa0, 0x37, 0x78, 0x41, 0xe6, 0x4c, 0x63, 0x23,
+		0xde, 0x81, 0x4b, 0x94, 0x34, 0x23, 0x3e, 0xd6, 0xb1, 0x8f, 0x63, 0xec, 0x37, 0x19, 0x9b, 0xe6,
+		0xbd, 0x1b, 0x74, 0x26, 0xe2, 0x74, 0xe7, 0x67, 0xe7, 0x91, 0xb2, 0xde, 0xe2, 0x71, 0x52, 0x2c,
+		0xd4, 0xd6, 0x77, 0x56, 0x74, 0xd7, 0x8e, 0xa0, 0x12, 0x17, 0x3e, 0x2a, 0x01, 0x97, 0xbe, 0x98,
+		0xa1, 0x55, 0x50, 0xb3, 0xdf, 0xa2, 0xf5, 0xcb, 0x67, 0xb2, 0x28, 0xd0, 0x3a, 0xaa, 0xd3, 0x1e,
+		0xc9, 0xaa, 0x72, 0xda, 0x1b, 0xb5, 0xbb, 0xb2, 0x98, 0x8d, 0x17, 0xec, 0x7f, 0x15, 0xa0, 0x9a,
+		0xbc, 0x7b, 0xa1, 0x1f, 0xc0, 0xe5, 0xf0, 0xa1, 0xc4, 0x23, 0xbe, 0xfa, 0xc8, 0x70, 0xd9, 0x5a,
+		0x9c, 0x61, 0x7e, 0x2e, 0x46, 0x6a, 0xd8, 0x0d, 0x58, 0x43, 0xe2, 0x7f, 0x6c, 0xb8, 0x74, 0xa5,
+		0xcd, 0xb0, 0x8f, 0x3a, 0x70, 0xd5, 0xb2, 0x55, 0xcf, 0xc7, 0x96, 0x8e, 0x5d, 0x5d, 0x5d, 0x3c,
+		0x51, 0xa9, 0x58, 0xd3, 0x88, 0xe7, 0xd9, 0xfc, 0x0c, 0x8c, 0xbc, 0xbc, 0x68, 0xd9, 0xc3, 0x80,
+		0xbc, 0x38, 0x1c, 0x1a, 0x01, 0x35, 0xa5, 0xdc, 0xec, 0x3a, 0xe5, 0xbe, 0x00, 0xa5, 0x19, 0x76,
+		0x54, 0x62, 0xf9, 0xee, 0x39, 0xab, 0xb8, 0x8b, 0x4a, 0x71, 0x86, 0x1d, 0x99, 0xb6, 0x9f, 0xcd,
+		0xc5, 0xe7, 0x1f, 0x59, 0xa8, 0xc4, 0xab, 0x6e, 0x7a, 0x89, 0xd1, 0xd8, 0x01, 0x95, 0x61, 0x5b,
+		0xd8, 0x2b, 0x5f, 0x5b, 0xa3, 0xd7, 0x9b, 0xf4, 0xe4, 0x3a, 0x2a, 0xf0, 0x5a, 0x58, 0xe1, 0x96,
+		0xb4, 0x6a, 0xa0, 0xd2, 0x22, 0xbc, 0xf6, 0x28, 0x2a, 0x41, 0x0b, 0x9d, 0x40, 0xe1, 0x81, 0xc7,
+		0x7c, 0x17, 0x98, 0xef, 0x57, 0xbf, 0xde, 0xf7, 0x47, 0x43, 0xe6, 0xbc, 0xf4, 0xd1, 0x50, 0xed,
+		0xf5, 0x95, 0x6e, 0xa3, 0xa3, 0x04, 0xe6, 0xe8, 0x0a, 0xe4, 0x4c, 0xfc, 0xe5, 0x79, 0xf2, 0x8c,
+		0x63, 0xd0, 0x45, 0x13, 0x7f, 0x05, 0x72, 0xf4, 0x99, 0x2d, 0x79, 0xb2, 0x30, 0xe8, 0x3b, 0x94,
+		0xfe, 0x2d, 0xc8, 0xb3, 0x7c, 0x21, 0x80, 0x20, 0x63, 0xe2, 0x73, 0xa8, 0x08, 0xb9, 0x66, 0x5f,
+		0xa1, 0xf2, 0x17, 0xa1, 0xc2, 0x51, 0x75, 0xd0, 0x96, 0x9b, 0xb2, 0x28, 0xd4, 0xee, 0x42, 0x81,
+		0x27, 0x81, 0x2e, 0x8d, 0x28, 0x0d, 0xe2, 0x73, 0x41, 0x33, 0xf0, 0x91, 0x09, 0x7b, 0x4f, 0xbb,
+		0xc7, 0xb2, 0x22, 0x0a, 0xf1, 0xcf, 0xeb, 0x41, 0x25, 0x5e, 0x70, 0x3f, 0x1b, 0x4d, 0xfd, 0x25,
+		0x03, 0xe5, 0x58, 0x01, 0x4d, 0x2b, 0x1f, 0x6c, 0x9a, 0xf6, 0x23, 0x15, 0x9b, 0x06, 0xf6, 0x02,
+		0x51, 0x00, 0x83, 0x1a, 0x14, 0xb9, 0xe8, 0x47, 0x7b, 0x26, 0xc1, 0xff, 0x3e, 0x03, 0x62, 0xba,
+		0x76, 0x4d, 0x05, 0x98, 0xf9, 0x5e, 0x03, 0xfc, 0x5d, 0x06, 0xaa, 0xc9, 0x82, 0x35, 0x15, 0xde,
+		0xb5, 0xef, 0x35, 0xbc, 0x7f, 0x0a, 0xb0, 0x99, 0x28, 0x53, 0x2f, 0x1a, 0xdd, 0x17, 0xb0, 0x6d,
+		0xe8, 0x64, 0xe6, 0xd8, 0x3e, 0x7d, 0xf6, 0x56, 0x4d, 0xf2, 0x90, 0x98, 0x52, 0x8d, 0x6d, 0x14,
+		0xb7, 0xbe, 0xbe, 0x10, 0xae, 0xb7, 0x17, 0x76, 0x1d, 0x6a, 0x76, 0xb4, 0xd3, 0x6e, 0xc9, 0xdd,
+		0x41, 0x7f, 0x24, 0xf7, 0x9a, 0x9f, 0xaa, 0xa7, 0xbd, 0x1f, 0xf7, 0xfa, 0x1f, 0xf7, 0x14, 0xd1,
+		0x48, 0xd1, 0xbe, 0xc3, 0xa5, 0x3e, 0x00, 0x31, 0x1d, 0x14, 0xba, 0x0c, 0xab, 0xc2, 0x12, 0x9f,
+		0x43, 0x3b, 0xb0, 0xd5, 0xeb, 0xab, 0xc3, 0x76, 0x4b, 0x56, 0xe5, 0xfb, 0xf7, 0xe5, 0xe6, 0x68,
+		0xc8, 0x9f, 0x36, 0x22, 0xf6, 0x28, 0xb9, 0xa8, 0x7f, 0x9b, 0x85, 0x9d, 0x15, 0x91, 0xa0, 0x46,
+		0x70, 0x29, 0xe1, 0xf7, 0xa4, 0xb7, 0x2e, 0x12, 0x7d, 0x9d, 0x56, 0x05, 0x03, 0xec, 0xfa, 0xc1,
+		0x1d, 0xe6, 0x06, 0xd0, 0x2c, 0x59, 0xbe, 0x31, 0x36, 0x88, 0x1b, 0xbc, 0x04, 0xf1, 0x9b, 0xca,
+		0xd6, 0x02, 0xe7, 0x8f, 0x41, 0x6f, 0x02, 0x72, 0x6c, 0xcf, 0xf0, 0x8d, 0x87, 0x44, 0x35, 0xac,
+		0xf0, 0xd9, 0x88, 0xde, 0x5c, 0x72, 0x8a, 0x18, 0xf6, 0xb4, 0x2d, 0x3f, 0x62, 0x5b, 0x64, 0x82,
+		0x53, 0x6c, 0xba, 0x81, 0x67, 0x15, 0x31, 0xec, 0x89, 0xd8, 0xd7, 0xa0, 0xa2, 0xdb, 0x73, 0x5a,
+		0xce, 0x71, 0x1e, 0x3d, 0x2f, 0x32, 0x4a, 0x99, 0x63, 0x11, 0x25, 0x28, 0xd4, 0x17, 0xef, 0x55,
+		0x15, 0xa5, 0xcc, 0x31, 0x4e, 0xb9, 0x0e, 0x5b, 0x78, 0x32, 0x71, 0xa9, 0xf3, 0xd0, 0x11, 0xbf,
+		0x7a, 0x54, 0x23, 0x98, 0x11, 0xf7, 0x3e, 0x82, 0x62, 0x98, 0x07, 0x7a, 0x24, 0xd3, 0x4c, 0xa8,
+		0x0e, 0xbf, 0x4f, 0x0b, 0xf4, 0x09, 0xcb, 0x0a, 0x3b, 0xaf, 0x41, 0xc5, 0xf0, 0xd4, 0xc5, 0xf3,
+		0xbb, 0xb0, 0x2f, 0x1c, 0x14, 0x95, 0xb2, 0xe1, 0x45, 0x4f, 0x97, 0xb5, 0xaf, 0x04, 0xa8, 0x26,
+		0x7f, 0x3e, 0x40, 0x2d, 0x28, 0x9a, 0xb6, 0x86, 0x99, 0xb4, 0xf8, 0x6f, 0x57, 0x07, 0x4f, 0xf9,
+		0xc5, 0xa1, 0xde, 0x09, 0xf8, 0x4a, 0x64, 0xb9, 0xf7, 0xb7, 0x0c, 0x14, 0x43, 0x18, 0x5d, 0x82,
+		0x9c, 0x83, 0xfd, 0x29, 0x73, 0x97, 0x3f, 0x16, 0xc4, 0x8c, 0xc2, 0xda, 0x14, 0xf7, 0x1c, 0x6c,
+		0x49, 0xc2, 0x02, 0xa7, 0x6d, 0xfa, 0x5d, 0x4d, 0x82, 0x75, 0x76, 0xaf, 0xb1, 0x67, 0x33, 0x62,
+		0xf9, 0x5e, 0xf8, 0x5d, 0x03, 0xbc, 0x19, 0xc0, 0xf4, 0x57, 0x2c, 0xdf, 0xc5, 0x86, 0x99, 0xe0,
+		0xe6, 0x18, 0x57, 0x0c, 0x3b, 0x22, 0xf2, 0x11, 0x5c, 0x09, 0xfd, 0xea, 0xc4, 0xc7, 0xda, 0x94,
+		0xe8, 0x0b, 0xa3, 0x02, 0x7b, 0xbf, 0xb8, 0x1c, 0x10, 0x5a, 0x41, 0x7f, 0x68, 0x5b, 0xfb, 0x7b,
+		0x06, 0xb6, 0xc3, 0x9b, 0x98, 0x1e, 0x25, 0xab, 0x0b, 0x80, 0x2d, 0xcb, 0xf6, 0xe3, 0xe9, 0x5a,
+		0x96, 0xf2, 0x92, 0x5d, 0xbd, 0x11, 0x19, 0x29, 0x31, 0x07, 0x7b, 0x33, 0x80, 0x45, 0xcf, 0xda,
+		0xb4, 0x5d, 0x85, 0x72, 0xf0, 0xdb, 0x10, 0xfb, 0x81, 0x91, 0xdf, 0xdd, 0x81, 0x43, 0xf4, 0xca,
+		0x46, 0x5f, 0x58, 0xce, 0xc8, 0xc4, 0xb0, 0x82, 0x17, 0x5f, 0xde, 0x08, 0x5f, 0x58, 0x72, 0xd1,
+		0x0b, 0xcb, 0xf1, 0xcf, 0x60, 0x47, 0xb3, 0x67, 0xe9, 0x70, 0x8f, 0xc5, 0xd4, 0xfb, 0x81, 0xf7,
+		0x61, 0xe6, 0x33, 0x58, 0x94, 0x98, 0xff, 0xcb, 0x64, 0xfe, 0x20, 0x64, 0x4f, 0x06, 0xc7, 0x7f,
+		0x14, 0xf6, 0x4e, 0xb8, 0xe9, 0x20, 0x9c, 0xa9, 0x42, 0xc6, 0x26, 0xd1, 0x68, 0xf4, 0xff, 0x1f,
+		0x00, 0xb5, 0xd3, 0x26, 0xaa, 0x48, 0x1d, 0x00, 0x00,
+	},
+}
+
+func init() {
+	yarpc.RegisterClientBuilder(
+		func(clientConfig transport.ClientConfig, structField reflect.StructField) UsersYARPCClient {
+			return NewUsersYARPCClient(clientConfig, protobuf.ClientBuilderOptions(clientConfig, structField)...)
+		},
+	)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testing
+
+import (
+	"context"
+	"io/ioutil"
+	nethttp "net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/transport/http"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestRoutes(t *testing.T) {
+	inbound := http.NewTransport().NewInbound("127.0.0.1:0", http.Routes(BuildUsersYARPCHTTPRoutes()...))
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+		Name:     "users",
+		Inbounds: yarpc.Inbounds{inbound},
+	})
+	dispatcher.Register(BuildUsersYARPCProcedures(newUsersServer()))
+	require.NoError(t, dispatcher.Start())
+	defer func() { assert.NoError(t, dispatcher.Stop()) }()
+	url := "http://" + inbound.Addr().String()
+
+	tests := []struct {
+		msg        string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			msg:        "get with path variable",
+			method:     nethttp.MethodGet,
+			path:       "/v1/users/1",
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"1","group":"groups/a","name":"alice","age":"30"}`,
+		},
+		{
+			msg:        "get with query parameter",
+			method:     nethttp.MethodGet,
+			path:       "/v1/users/1?verbose=true",
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"1","group":"groups/a","name":"alice","age":"30","admin":true}`,
+		},
+		{
+			msg:        "get unknown user",
+			method:     nethttp.MethodGet,
+			path:       "/v1/users/3",
+			wantStatus: nethttp.StatusNotFound,
+			wantBody:   "user 3 not found\n",
+		},
+		{
+			msg:        "invalid query parameter",
+			method:     nethttp.MethodGet,
+			path:       "/v1/users/1?verbose=sure",
+			wantStatus: nethttp.StatusBadRequest,
+		},
+		{
+			msg:        "create with field body",
+			method:     nethttp.MethodPost,
+			path:       "/v1/groups/b/users",
+			body:       `{"name":"carol","age":"41"}`,
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"3","group":"groups/b","name":"carol","age":"41"}`,
+		},
+		{
+			msg:        "update with whole body",
+			method:     nethttp.MethodPatch,
+			path:       "/v1/users/2",
+			body:       `{"name":"bobby","age":26}`,
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"2","name":"bobby","age":"26"}`,
+		},
+		{
+			msg:        "update with additional binding",
+			method:     nethttp.MethodPut,
+			path:       "/v1/users/2",
+			body:       `{"name":"bob","admin":true}`,
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"2","name":"bob","admin":true}`,
+		},
+		{
+			msg:        "list with response body field",
+			method:     nethttp.MethodGet,
+			path:       "/v1/groups/a/users?names=alice&names=dave",
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `[{"id":"1","group":"groups/a","name":"alice","age":"30"}]`,
+		},
+		{
+			msg:        "custom verb",
+			method:     nethttp.MethodPost,
+			path:       "/v1/users/1:disable",
+			wantStatus: nethttp.StatusOK,
+			wantBody:   `{"id":"1","group":"groups/a","name":"alice","age":"30"}`,
+		},
+		{
+			msg:        "no matching route",
+			method:     nethttp.MethodDelete,
+			path:       "/v1/users/1",
+			wantStatus: nethttp.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			req, err := nethttp.NewRequest(tt.method, url+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			res, err := nethttp.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, res.StatusCode, "unexpected status: %s", body)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
+
+type usersServer struct {
+	users map[string]*User
+}
+
+func newUsersServer() *usersServer {
+	return &usersServer{
+		users: map[string]*User{
+			"1": {Id: "1", Group: "groups/a", Name: "alice", Age: 30},
+			"2": {Id: "2", Group: "groups/b", Name: "bob", Age: 25},
+		},
+	}
+}
+
+func (s *usersServer) GetUser(ctx context.Context, request *GetUserRequest) (*User, error) {
+	user, ok := s.users[request.Id]
+	if !ok {
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeNotFound, "user %s not found", request.Id)
+	}
+	if request.Verbose {
+		return &User{Id: user.Id, Group: user.Group, Name: user.Name, Age: user.Age, Admin: true}, nil
+	}
+	return user, nil
+}
+
+func (s *usersServer) CreateUser(ctx context.Context, request *CreateUserRequest) (*User, error) {
+	user := request.User
+	if user == nil {
+		user = &User{}
+	}
+	user.Id = "3"
+	user.Group = request.Group
+	return user, nil
+}
+
+func (s *usersServer) UpdateUser(ctx context.Context, request *User) (*User, error) {
+	return request, nil
+}
+
+func (s *usersServer) ListUsers(ctx context.Context, request *ListUsersRequest) (*ListUsersResponse, error) {
+	response := &ListUsersResponse{}
+	for _, name := range request.Names {
+		for _, id := range []string{"1", "2"} {
+			if user := s.users[id]; user.Group == request.Group && user.Name == name {
+				response.Users = append(response.Users, user)
+			}
+		}
+	}
+	return response, nil
+}
+
+func (s *usersServer) DisableUser(ctx context.Context, request *GetUserRequest) (*User, error) {
+	return s.GetUser(ctx, request)
+}
+
+func (s *usersServer) ResetUsers(ctx context.Context, request *ListUsersRequest) (*ListUsersResponse, error) {
+	return &ListUsersResponse{}, nil
+}
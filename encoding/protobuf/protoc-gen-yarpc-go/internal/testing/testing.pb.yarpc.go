@@ -63,11 +63,13 @@ type KeyValueYARPCServer interface {
 }
 
 // BuildKeyValueYARPCProcedures prepares an implementation of the KeyValue service for YARPC registration.
-func BuildKeyValueYARPCProcedures(server KeyValueYARPCServer) []transport.Procedure {
+func BuildKeyValueYARPCProcedures(server KeyValueYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_KeyValueYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
-			ServiceName: "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.KeyValue",
+			ServiceName:     "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.KeyValue",
+			FileDescriptors: yarpcFileDescriptorClosure301ba429865f230b,
+			Options:         options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 				{
 					MethodName: "GetValue",
@@ -161,10 +163,10 @@ type FxKeyValueYARPCProceduresResult struct {
 //    testing.NewFxKeyValueYARPCProcedures(),
 //    ...
 //  )
-func NewFxKeyValueYARPCProcedures() interface{} {
+func NewFxKeyValueYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxKeyValueYARPCProceduresParams) FxKeyValueYARPCProceduresResult {
 		return FxKeyValueYARPCProceduresResult{
-			Procedures: BuildKeyValueYARPCProcedures(params.Server),
+			Procedures: BuildKeyValueYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -278,11 +280,13 @@ type SinkYARPCServer interface {
 }
 
 // BuildSinkYARPCProcedures prepares an implementation of the Sink service for YARPC registration.
-func BuildSinkYARPCProcedures(server SinkYARPCServer) []transport.Procedure {
+func BuildSinkYARPCProcedures(server SinkYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_SinkYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
 			ServiceName:        "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Sink",
+			FileDescriptors:    yarpcFileDescriptorClosure301ba429865f230b,
+			Options:            options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{},
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{
 				{
@@ -367,10 +371,10 @@ type FxSinkYARPCProceduresResult struct {
 //    testing.NewFxSinkYARPCProcedures(),
 //    ...
 //  )
-func NewFxSinkYARPCProcedures() interface{} {
+func NewFxSinkYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxSinkYARPCProceduresParams) FxSinkYARPCProceduresResult {
 		return FxSinkYARPCProceduresResult{
-			Procedures: BuildSinkYARPCProcedures(params.Server),
+			Procedures: BuildSinkYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -485,11 +489,13 @@ type AllServiceHelloThreeYARPCServer interface {
 }
 
 // BuildAllYARPCProcedures prepares an implementation of the All service for YARPC registration.
-func BuildAllYARPCProcedures(server AllYARPCServer) []transport.Procedure {
+func BuildAllYARPCProcedures(server AllYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_AllYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
-			ServiceName: "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.All",
+			ServiceName:     "uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.All",
+			FileDescriptors: yarpcFileDescriptorClosure301ba429865f230b,
+			Options:         options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 				{
 					MethodName: "GetValue",
@@ -620,10 +626,10 @@ type FxAllYARPCProceduresResult struct {
 //    testing.NewFxAllYARPCProcedures(),
 //    ...
 //  )
-func NewFxAllYARPCProcedures() interface{} {
+func NewFxAllYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxAllYARPCProceduresParams) FxAllYARPCProceduresResult {
 		return FxAllYARPCProceduresResult{
-			Procedures: BuildAllYARPCProcedures(params.Server),
+			Procedures: BuildAllYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -958,6 +964,68 @@ var (
 	emptyAllServiceHelloThreeYARPCResponse = &HelloResponse{}
 )
 
+var yarpcFileDescriptorClosure301ba429865f230b = [][]byte{
+	// encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x94, 0xbd, 0x6e, 0xd4, 0x40,
+		0x14, 0x85, 0x3d, 0x4e, 0x20, 0xcb, 0xe5, 0x27, 0xd1, 0x08, 0xa1, 0xc8, 0xc5, 0x80, 0x9c, 0x66,
+		0x9b, 0x1d, 0x47, 0x8b, 0x28, 0x68, 0x10, 0x20, 0x04, 0x48, 0x01, 0x2d, 0x8a, 0x11, 0x05, 0x9d,
+		0xd7, 0xbe, 0x18, 0x2b, 0xd6, 0x8c, 0xe3, 0x1f, 0x22, 0x77, 0x3c, 0x02, 0x8f, 0x81, 0x44, 0xc1,
+		0x6b, 0x50, 0xa6, 0xa4, 0x64, 0x87, 0x86, 0x0a, 0xe5, 0x11, 0x90, 0xc7, 0xf6, 0xae, 0xb5, 0x08,
+		0x21, 0xb1, 0x06, 0x6d, 0x41, 0xb5, 0x77, 0xc7, 0xdf, 0x9c, 0x7b, 0xe6, 0x9e, 0xb1, 0xe1, 0x01,
+		0x0a, 0x5f, 0x06, 0x91, 0x08, 0x9d, 0x24, 0x95, 0xb9, 0x9c, 0x16, 0xaf, 0xea, 0xc2, 0x1f, 0x85,
+		0x28, 0x46, 0xa5, 0x97, 0x26, 0xfe, 0x28, 0x94, 0x4e, 0x24, 0x72, 0x4c, 0x85, 0x17, 0x3b, 0x39,
+		0x66, 0x79, 0x45, 0x37, 0xbf, 0x5c, 0xc3, 0xf4, 0x4e, 0x31, 0xc5, 0x94, 0x6b, 0x9a, 0xb7, 0x82,
+		0xbc, 0x15, 0xac, 0x0b, 0x3f, 0x44, 0xa1, 0x81, 0x50, 0xf2, 0x56, 0x8d, 0x37, 0x2a, 0xd6, 0xdd,
+		0x3f, 0x74, 0x11, 0x60, 0x52, 0xab, 0x5b, 0xd7, 0x34, 0xa4, 0x6b, 0x47, 0x97, 0xf5, 0xba, 0xbd,
+		0x07, 0xdb, 0x8f, 0x30, 0x7f, 0xe1, 0xc5, 0x05, 0x1e, 0xe2, 0x71, 0x81, 0x59, 0x4e, 0x77, 0x60,
+		0xe3, 0x08, 0xcb, 0x5d, 0x72, 0x83, 0x0c, 0x2f, 0x1c, 0x56, 0xa5, 0x3d, 0x84, 0x9d, 0x05, 0x94,
+		0x25, 0x52, 0x64, 0x48, 0xaf, 0xc2, 0xb9, 0x37, 0xd5, 0xc2, 0xae, 0xa9, 0xb9, 0xfa, 0x8f, 0x7d,
+		0x1b, 0xb6, 0xdd, 0xdf, 0xc9, 0xfd, 0x62, 0xeb, 0x1e, 0x5c, 0x7c, 0x18, 0xa5, 0xf3, 0x6d, 0x73,
+		0x88, 0x74, 0x21, 0x06, 0x97, 0x1e, 0x63, 0x1c, 0xcb, 0x96, 0xba, 0x02, 0x66, 0x14, 0x34, 0x88,
+		0x19, 0x05, 0xf6, 0x75, 0xb8, 0xdc, 0x3c, 0x6f, 0x6c, 0x2e, 0x01, 0xe3, 0xef, 0x26, 0x0c, 0x0e,
+		0xb0, 0xd4, 0x0e, 0xe9, 0x47, 0x02, 0x83, 0xf6, 0x60, 0x74, 0xc2, 0x57, 0x0b, 0x89, 0x2f, 0xcd,
+		0xd1, 0x7a, 0xd6, 0x9f, 0x60, 0x7d, 0x18, 0xdb, 0xd0, 0x8e, 0xdd, 0xde, 0x1c, 0xbb, 0x7d, 0x3b,
+		0x76, 0x7f, 0x72, 0x3c, 0x3e, 0x86, 0x4d, 0x37, 0x12, 0x47, 0x34, 0x82, 0xcd, 0x2a, 0x5e, 0x7a,
+		0xb0, 0x6a, 0x8f, 0xce, 0x25, 0xb1, 0x68, 0x57, 0x6c, 0x22, 0xf0, 0xc4, 0x2b, 0x6d, 0x63, 0xac,
+		0xb6, 0x60, 0xe3, 0x5e, 0x1c, 0xff, 0x8f, 0xf7, 0xef, 0xc7, 0xfb, 0x0f, 0x63, 0xa5, 0x1f, 0x08,
+		0x0c, 0xf4, 0xcb, 0x3d, 0x11, 0x48, 0x9f, 0xac, 0xda, 0xaf, 0xfb, 0x19, 0xb1, 0x9e, 0xf6, 0xa4,
+		0xd6, 0x8e, 0x65, 0x48, 0x16, 0x6e, 0x9f, 0x9f, 0xc8, 0x35, 0x77, 0xbb, 0x4f, 0xaa, 0x8b, 0x07,
+		0xb5, 0xdb, 0xd7, 0x29, 0xae, 0xff, 0x74, 0xf7, 0xc9, 0xfd, 0x5b, 0xa7, 0x33, 0x66, 0x7c, 0x9e,
+		0x31, 0xe3, 0x6c, 0xc6, 0xc8, 0x5b, 0xc5, 0xc8, 0x7b, 0xc5, 0xc8, 0x27, 0xc5, 0xc8, 0xa9, 0x62,
+		0xe4, 0x8b, 0x62, 0xe4, 0x9b, 0x62, 0xc6, 0x99, 0x62, 0xe4, 0xdd, 0x57, 0x66, 0xbc, 0xdc, 0x6a,
+		0xa4, 0xa6, 0xe7, 0x75, 0xb7, 0x9b, 0x3f, 0x06, 0x00, 0xfe, 0x99, 0xcb, 0x4b, 0xd8, 0x07, 0x00,
+		0x00,
+	},
+	// encoding/protobuf/protoc-gen-yarpc-go/internal/testing/dep.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x72, 0x48, 0xcd, 0x4b, 0xce,
+		0x4f, 0xc9, 0xcc, 0x4b, 0xd7, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x4f, 0x2a, 0x4d, 0x83, 0x30, 0x92,
+		0x75, 0xd3, 0x53, 0xf3, 0x74, 0x2b, 0x13, 0x8b, 0x0a, 0x92, 0x75, 0xd3, 0xf3, 0xf5, 0x33, 0xf3,
+		0x4a, 0x52, 0x8b, 0xf2, 0x12, 0x73, 0xf4, 0x4b, 0x52, 0x8b, 0x4b, 0x40, 0xaa, 0x53, 0x52, 0x0b,
+		0xf4, 0xc0, 0x0a, 0x85, 0xec, 0x4a, 0x93, 0x52, 0x8b, 0xf4, 0xc0, 0x2a, 0xf5, 0x60, 0x86, 0xe9,
+		0xc1, 0x0c, 0x83, 0x30, 0x92, 0xd3, 0x53, 0xf3, 0xc0, 0x0a, 0xd2, 0xf3, 0xf5, 0x60, 0x26, 0xe9,
+		0x41, 0x4d, 0x52, 0x12, 0xe2, 0x12, 0x08, 0x4e, 0x2d, 0x09, 0x4b, 0xcc, 0x29, 0x4d, 0x0d, 0x4a,
+		0x2d, 0x2e, 0xc8, 0xcf, 0x2b, 0x4e, 0x75, 0x32, 0xbd, 0xf0, 0x50, 0x8e, 0xe1, 0xc6, 0x43, 0x39,
+		0x86, 0x0f, 0x0f, 0xe5, 0x18, 0x1b, 0x1e, 0xc9, 0x31, 0xae, 0x78, 0x24, 0xc7, 0x78, 0xe2, 0x91,
+		0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0xbe, 0x78, 0x24, 0xc7, 0xf0, 0xe1,
+		0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c, 0x51, 0xec, 0x50, 0xa3, 0x92, 0xd8, 0xc0, 0xb6, 0x19,
+		0x03, 0x06, 0x00, 0xf3, 0x71, 0x66, 0x0a, 0xd5, 0x00, 0x00, 0x00,
+	},
+	// yarpcproto/yarpc.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xab, 0x4c, 0x2c, 0x2a,
+		0x48, 0x2e, 0x28, 0xca, 0x2f, 0xc9, 0xd7, 0x07, 0x33, 0xf5, 0xc0, 0x6c, 0x21, 0xae, 0xd2, 0xa4,
+		0xd4, 0x22, 0x3d, 0xb0, 0x88, 0x92, 0x14, 0x17, 0x9b, 0x7f, 0x5e, 0x6a, 0x79, 0x62, 0xa5, 0x90,
+		0x00, 0x17, 0x73, 0x62, 0x72, 0xb6, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x47, 0x10, 0x88, 0xe9, 0x64,
+		0x71, 0xe1, 0xa1, 0x1c, 0xc3, 0x8d, 0x87, 0x72, 0x0c, 0x1f, 0x1e, 0xca, 0x31, 0x36, 0x3c, 0x92,
+		0x63, 0x5c, 0xf1, 0x48, 0x8e, 0xf1, 0xc4, 0x23, 0x39, 0xc6, 0x0b, 0x8f, 0xe4, 0x18, 0x1f, 0x3c,
+		0x92, 0x63, 0x7c, 0xf1, 0x48, 0x8e, 0xe1, 0xc3, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18, 0xa2,
+		0xb8, 0x10, 0xb6, 0x25, 0xb1, 0x81, 0x29, 0x63, 0xc0, 0x00, 0x75, 0xef, 0xbb, 0x4c, 0x82, 0x00,
+		0x00, 0x00,
+	},
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(
 		func(clientConfig transport.ClientConfig, structField reflect.StructField) KeyValueYARPCClient {
@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io/ioutil"
+	"sort"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
@@ -39,8 +40,10 @@ func TestGolden(t *testing.T) {
 	testGolden(
 		t,
 		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.proto",
-		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.pb.yarpc.go",
-		"testing.pb.yarpc.go.golden",
+		map[string]string{
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.pb.yarpc.go":             "testing.pb.yarpc.go.golden",
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testingtest/testing.pb.yarpc.go": "testingtest/testing.pb.yarpc.go.golden",
+		},
 	)
 }
 
@@ -48,25 +51,61 @@ func TestGoldenNoService(t *testing.T) {
 	testGolden(
 		t,
 		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing_no_service.proto",
-		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing_no_service.pb.yarpc.go",
-		"testing_no_service.pb.yarpc.go.golden",
+		map[string]string{
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing_no_service.pb.yarpc.go": "testing_no_service.pb.yarpc.go.golden",
+		},
+	)
+}
+
+func TestGoldenValidate(t *testing.T) {
+	testGolden(
+		t,
+		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/validate.proto",
+		map[string]string{
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/validate.pb.validate.go": "validate.pb.validate.go.golden",
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/validate.pb.yarpc.go":    "validate.pb.yarpc.go.golden",
+		},
 	)
 }
 
+func TestGoldenRoutes(t *testing.T) {
+	testGolden(
+		t,
+		"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto",
+		map[string]string{
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.pb.http.go":              "routes.pb.http.go.golden",
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.pb.yarpc.go":             "routes.pb.yarpc.go.golden",
+			"encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testingtest/routes.pb.yarpc.go": "testingtest/routes.pb.yarpc.go.golden",
+		},
+	)
+}
+
+// testGolden generates code for the given file and compares it to the golden
+// files of the given output files.
 func testGolden(
 	t *testing.T,
 	inputFilePath string,
-	outputFilePath string,
-	outputGoldenFilePath string,
+	outputFilePathToGoldenFilePath map[string]string,
 ) {
 	codeGeneratorRequest := &plugin_go.CodeGeneratorRequest{
-		Parameter: proto.String("Myarpcproto/yarpc.proto=go.uber.org/yarpc/yarpcproto"),
+		Parameter: proto.String(
+			"Myarpcproto/yarpc.proto=go.uber.org/yarpc/yarpcproto," +
+				"Myarpcproto/options.proto=go.uber.org/yarpc/yarpcproto," +
+				"Mgoogle/api/annotations.proto=github.com/gogo/googleapis/google/api," +
+				"Mgoogle/api/http.proto=github.com/gogo/googleapis/google/api," +
+				"Mencoding/protobuf/protoc-gen-yarpc-go/internal/testing/dep.proto=go.uber.org/yarpc/encoding/protobuf/protoc-gen-yarpc-go/internal/testing," +
+				"M" + inputFilePath + "=go.uber.org/yarpc/encoding/protobuf/protoc-gen-yarpc-go/internal/testing",
+		),
 		FileToGenerate: []string{
 			inputFilePath,
 		},
 		ProtoFile: []*descriptor.FileDescriptorProto{
+			getDescriptorFileDescriptorProto(t),
 			getFileDescriptorProto(t, "encoding/protobuf/protoc-gen-yarpc-go/internal/testing/dep.proto"),
 			getFileDescriptorProto(t, "yarpcproto/yarpc.proto"),
+			getFileDescriptorProto(t, "yarpcproto/options.proto"),
+			getFileDescriptorProto(t, "google/api/http.proto"),
+			getFileDescriptorProto(t, "google/api/annotations.proto"),
 			getFileDescriptorProto(t, inputFilePath),
 		},
 	}
@@ -79,16 +118,18 @@ func testGolden(
 	codeGeneratorResponse := &plugin_go.CodeGeneratorResponse{}
 	require.NoError(t, proto.Unmarshal(writer.Bytes(), codeGeneratorResponse))
 
-	content, err := ioutil.ReadFile(outputGoldenFilePath)
-	require.NoError(t, err)
-	expectedCodeGeneratorResponse := &plugin_go.CodeGeneratorResponse{
-		File: []*plugin_go.CodeGeneratorResponse_File{
-			{
-				Name:    proto.String(outputFilePath),
-				Content: proto.String(string(content)),
-			},
-		},
+	expectedCodeGeneratorResponse := &plugin_go.CodeGeneratorResponse{}
+	for outputFilePath, outputGoldenFilePath := range outputFilePathToGoldenFilePath {
+		content, err := ioutil.ReadFile(outputGoldenFilePath)
+		require.NoError(t, err)
+		expectedCodeGeneratorResponse.File = append(expectedCodeGeneratorResponse.File, &plugin_go.CodeGeneratorResponse_File{
+			Name:    proto.String(outputFilePath),
+			Content: proto.String(string(content)),
+		})
 	}
+	sort.Slice(expectedCodeGeneratorResponse.File, func(i, j int) bool {
+		return expectedCodeGeneratorResponse.File[i].GetName() < expectedCodeGeneratorResponse.File[j].GetName()
+	})
 
 	require.Equal(t, expectedCodeGeneratorResponse, codeGeneratorResponse)
 }
@@ -104,3 +145,12 @@ func getFileDescriptorProto(t *testing.T, name string) *descriptor.FileDescripto
 	require.NoError(t, proto.Unmarshal(data, fileDescriptorProto))
 	return fileDescriptorProto
 }
+
+// getDescriptorFileDescriptorProto returns the FileDescriptorProto of
+// google/protobuf/descriptor.proto, which yarpcproto/options.proto imports.
+func getDescriptorFileDescriptorProto(t *testing.T) *descriptor.FileDescriptorProto {
+	fileDescriptor, _ := descriptor.ForMessage(&descriptor.FieldOptions{})
+	fileDescriptorProto := proto.Clone(fileDescriptor).(*descriptor.FileDescriptorProto)
+	fileDescriptorProto.Name = proto.String("google/protobuf/descriptor.proto")
+	return fileDescriptorProto
+}
@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-yarpc-go
+// source: encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testingtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/encoding/protobuf/protoc-gen-yarpc-go/internal/testing"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// MockUsersYARPCClient implements a gomock-compatible mock client for service
+// Users.
+type MockUsersYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockUsersYARPCClientRecorder
+}
+
+var _ testing.UsersYARPCClient = (*MockUsersYARPCClient)(nil)
+
+type _MockUsersYARPCClientRecorder struct {
+	mock *MockUsersYARPCClient
+}
+
+// NewMockUsersYARPCClient builds a new mock client for service Users.
+//
+//	mockCtrl := gomock.NewController(t)
+//	client := testingtest.NewMockUsersYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockUsersYARPCClient(ctrl *gomock.Controller) *MockUsersYARPCClient {
+	mock := &MockUsersYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockUsersYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Users mock client.
+func (m *MockUsersYARPCClient) EXPECT() *_MockUsersYARPCClientRecorder {
+	return m.recorder
+}
+
+// GetUser responds to a GetUser call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().GetUser(gomock.Any(), ...).Return(...)
+//	... := client.GetUser(...)
+func (m *MockUsersYARPCClient) GetUser(ctx context.Context, request *testing.GetUserRequest, opts ...yarpc.CallOption) (response *testing.User, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "GetUser", args...)
+	response, _ = ret[0].(*testing.User)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) GetUser(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "GetUser", args...)
+}
+
+// CreateUser responds to a CreateUser call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().CreateUser(gomock.Any(), ...).Return(...)
+//	... := client.CreateUser(...)
+func (m *MockUsersYARPCClient) CreateUser(ctx context.Context, request *testing.CreateUserRequest, opts ...yarpc.CallOption) (response *testing.User, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "CreateUser", args...)
+	response, _ = ret[0].(*testing.User)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) CreateUser(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "CreateUser", args...)
+}
+
+// UpdateUser responds to a UpdateUser call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().UpdateUser(gomock.Any(), ...).Return(...)
+//	... := client.UpdateUser(...)
+func (m *MockUsersYARPCClient) UpdateUser(ctx context.Context, request *testing.User, opts ...yarpc.CallOption) (response *testing.User, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "UpdateUser", args...)
+	response, _ = ret[0].(*testing.User)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) UpdateUser(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "UpdateUser", args...)
+}
+
+// ListUsers responds to a ListUsers call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().ListUsers(gomock.Any(), ...).Return(...)
+//	... := client.ListUsers(...)
+func (m *MockUsersYARPCClient) ListUsers(ctx context.Context, request *testing.ListUsersRequest, opts ...yarpc.CallOption) (response *testing.ListUsersResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "ListUsers", args...)
+	response, _ = ret[0].(*testing.ListUsersResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) ListUsers(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "ListUsers", args...)
+}
+
+// DisableUser responds to a DisableUser call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().DisableUser(gomock.Any(), ...).Return(...)
+//	... := client.DisableUser(...)
+func (m *MockUsersYARPCClient) DisableUser(ctx context.Context, request *testing.GetUserRequest, opts ...yarpc.CallOption) (response *testing.User, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "DisableUser", args...)
+	response, _ = ret[0].(*testing.User)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) DisableUser(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "DisableUser", args...)
+}
+
+// ResetUsers responds to a ResetUsers call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+//	client.EXPECT().ResetUsers(gomock.Any(), ...).Return(...)
+//	... := client.ResetUsers(...)
+func (m *MockUsersYARPCClient) ResetUsers(ctx context.Context, request *testing.ListUsersRequest, opts ...yarpc.CallOption) (response *testing.ListUsersResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "ResetUsers", args...)
+	response, _ = ret[0].(*testing.ListUsersResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockUsersYARPCClientRecorder) ResetUsers(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "ResetUsers", args...)
+}
+
+// FakeUsersYARPCServer serves a UsersYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeUsersYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeUsersYARPCServer starts a YARPC dispatcher serving the given
+// UsersYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+//	server, err := testingtest.NewFakeUsersYARPCServer(impl)
+//	...
+//	defer server.Stop()
+//	client := server.Client()
+func NewFakeUsersYARPCServer(server testing.UsersYARPCServer, options ...protobuf.ProceduresOption) (*FakeUsersYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-users"})
+	dispatcher.Register(testing.BuildUsersYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeUsersYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Users service.
+func (s *FakeUsersYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeUsersYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "testingtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeUsersYARPCServer) Client(options ...protobuf.ClientOption) testing.UsersYARPCClient {
+	return testing.NewUsersYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Users service.
+func (s *FakeUsersYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
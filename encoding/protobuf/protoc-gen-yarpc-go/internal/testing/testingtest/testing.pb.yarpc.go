@@ -0,0 +1,501 @@
+// Code generated by protoc-gen-yarpc-go
+// source: encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testingtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/encoding/protobuf/protoc-gen-yarpc-go/internal/testing"
+	"go.uber.org/yarpc/yarpcproto"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// The response types of oneway methods are imported but not otherwise used.
+var (
+	_ *yarpcproto.Oneway
+)
+
+// MockKeyValueYARPCClient implements a gomock-compatible mock client for service
+// KeyValue.
+type MockKeyValueYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockKeyValueYARPCClientRecorder
+}
+
+var _ testing.KeyValueYARPCClient = (*MockKeyValueYARPCClient)(nil)
+
+type _MockKeyValueYARPCClientRecorder struct {
+	mock *MockKeyValueYARPCClient
+}
+
+// NewMockKeyValueYARPCClient builds a new mock client for service KeyValue.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := testingtest.NewMockKeyValueYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockKeyValueYARPCClient(ctrl *gomock.Controller) *MockKeyValueYARPCClient {
+	mock := &MockKeyValueYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockKeyValueYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// KeyValue mock client.
+func (m *MockKeyValueYARPCClient) EXPECT() *_MockKeyValueYARPCClientRecorder {
+	return m.recorder
+}
+
+// GetValue responds to a GetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().GetValue(gomock.Any(), ...).Return(...)
+// 	... := client.GetValue(...)
+func (m *MockKeyValueYARPCClient) GetValue(ctx context.Context, request *testing.GetValueRequest, opts ...yarpc.CallOption) (response *testing.GetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "GetValue", args...)
+	response, _ = ret[0].(*testing.GetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockKeyValueYARPCClientRecorder) GetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "GetValue", args...)
+}
+
+// SetValue responds to a SetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().SetValue(gomock.Any(), ...).Return(...)
+// 	... := client.SetValue(...)
+func (m *MockKeyValueYARPCClient) SetValue(ctx context.Context, request *testing.SetValueRequest, opts ...yarpc.CallOption) (response *testing.SetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "SetValue", args...)
+	response, _ = ret[0].(*testing.SetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockKeyValueYARPCClientRecorder) SetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "SetValue", args...)
+}
+
+// FakeKeyValueYARPCServer serves a KeyValueYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeKeyValueYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeKeyValueYARPCServer starts a YARPC dispatcher serving the given
+// KeyValueYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := testingtest.NewFakeKeyValueYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeKeyValueYARPCServer(server testing.KeyValueYARPCServer, options ...protobuf.ProceduresOption) (*FakeKeyValueYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-keyvalue"})
+	dispatcher.Register(testing.BuildKeyValueYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeKeyValueYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the KeyValue service.
+func (s *FakeKeyValueYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeKeyValueYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "testingtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeKeyValueYARPCServer) Client(options ...protobuf.ClientOption) testing.KeyValueYARPCClient {
+	return testing.NewKeyValueYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the KeyValue service.
+func (s *FakeKeyValueYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+
+// MockSinkYARPCClient implements a gomock-compatible mock client for service
+// Sink.
+type MockSinkYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockSinkYARPCClientRecorder
+}
+
+var _ testing.SinkYARPCClient = (*MockSinkYARPCClient)(nil)
+
+type _MockSinkYARPCClientRecorder struct {
+	mock *MockSinkYARPCClient
+}
+
+// NewMockSinkYARPCClient builds a new mock client for service Sink.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := testingtest.NewMockSinkYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockSinkYARPCClient(ctrl *gomock.Controller) *MockSinkYARPCClient {
+	mock := &MockSinkYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockSinkYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Sink mock client.
+func (m *MockSinkYARPCClient) EXPECT() *_MockSinkYARPCClientRecorder {
+	return m.recorder
+}
+
+// Fire responds to a Fire call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().Fire(gomock.Any(), ...).Return(...)
+// 	... := client.Fire(...)
+func (m *MockSinkYARPCClient) Fire(ctx context.Context, request *testing.FireRequest, opts ...yarpc.CallOption) (ack yarpc.Ack, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "Fire", args...)
+	ack, _ = ret[0].(yarpc.Ack)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockSinkYARPCClientRecorder) Fire(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "Fire", args...)
+}
+
+// FakeSinkYARPCServer serves a SinkYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeSinkYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeSinkYARPCServer starts a YARPC dispatcher serving the given
+// SinkYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := testingtest.NewFakeSinkYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeSinkYARPCServer(server testing.SinkYARPCServer, options ...protobuf.ProceduresOption) (*FakeSinkYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-sink"})
+	dispatcher.Register(testing.BuildSinkYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeSinkYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Sink service.
+func (s *FakeSinkYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeSinkYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "testingtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeSinkYARPCServer) Client(options ...protobuf.ClientOption) testing.SinkYARPCClient {
+	return testing.NewSinkYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Sink service.
+func (s *FakeSinkYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+
+// MockAllYARPCClient implements a gomock-compatible mock client for service
+// All.
+type MockAllYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAllYARPCClientRecorder
+}
+
+var _ testing.AllYARPCClient = (*MockAllYARPCClient)(nil)
+
+type _MockAllYARPCClientRecorder struct {
+	mock *MockAllYARPCClient
+}
+
+// NewMockAllYARPCClient builds a new mock client for service All.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := testingtest.NewMockAllYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockAllYARPCClient(ctrl *gomock.Controller) *MockAllYARPCClient {
+	mock := &MockAllYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockAllYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// All mock client.
+func (m *MockAllYARPCClient) EXPECT() *_MockAllYARPCClientRecorder {
+	return m.recorder
+}
+
+// GetValue responds to a GetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().GetValue(gomock.Any(), ...).Return(...)
+// 	... := client.GetValue(...)
+func (m *MockAllYARPCClient) GetValue(ctx context.Context, request *testing.GetValueRequest, opts ...yarpc.CallOption) (response *testing.GetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "GetValue", args...)
+	response, _ = ret[0].(*testing.GetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) GetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "GetValue", args...)
+}
+
+// SetValue responds to a SetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().SetValue(gomock.Any(), ...).Return(...)
+// 	... := client.SetValue(...)
+func (m *MockAllYARPCClient) SetValue(ctx context.Context, request *testing.SetValueRequest, opts ...yarpc.CallOption) (response *testing.SetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "SetValue", args...)
+	response, _ = ret[0].(*testing.SetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) SetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "SetValue", args...)
+}
+
+// Fire responds to a Fire call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().Fire(gomock.Any(), ...).Return(...)
+// 	... := client.Fire(...)
+func (m *MockAllYARPCClient) Fire(ctx context.Context, request *testing.FireRequest, opts ...yarpc.CallOption) (ack yarpc.Ack, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "Fire", args...)
+	ack, _ = ret[0].(yarpc.Ack)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) Fire(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "Fire", args...)
+}
+
+// HelloTwo responds to a HelloTwo call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloTwo(gomock.Any(), ...).Return(...)
+// 	... := client.HelloTwo(...)
+func (m *MockAllYARPCClient) HelloTwo(ctx context.Context, request *testing.HelloRequest, opts ...yarpc.CallOption) (stream testing.AllServiceHelloTwoYARPCClient, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloTwo", args...)
+	stream, _ = ret[0].(testing.AllServiceHelloTwoYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) HelloTwo(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloTwo", args...)
+}
+
+// HelloOne responds to a HelloOne call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloOne(gomock.Any(), ...).Return(...)
+// 	... := client.HelloOne(...)
+func (m *MockAllYARPCClient) HelloOne(ctx context.Context, opts ...yarpc.CallOption) (stream testing.AllServiceHelloOneYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloOne", args...)
+	stream, _ = ret[0].(testing.AllServiceHelloOneYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) HelloOne(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloOne", args...)
+}
+
+// HelloThree responds to a HelloThree call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloThree(gomock.Any(), ...).Return(...)
+// 	... := client.HelloThree(...)
+func (m *MockAllYARPCClient) HelloThree(ctx context.Context, opts ...yarpc.CallOption) (stream testing.AllServiceHelloThreeYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloThree", args...)
+	stream, _ = ret[0].(testing.AllServiceHelloThreeYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockAllYARPCClientRecorder) HelloThree(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloThree", args...)
+}
+
+// FakeAllYARPCServer serves a AllYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeAllYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeAllYARPCServer starts a YARPC dispatcher serving the given
+// AllYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := testingtest.NewFakeAllYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeAllYARPCServer(server testing.AllYARPCServer, options ...protobuf.ProceduresOption) (*FakeAllYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-all"})
+	dispatcher.Register(testing.BuildAllYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeAllYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the All service.
+func (s *FakeAllYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeAllYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "testingtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeAllYARPCServer) Client(options ...protobuf.ClientOption) testing.AllYARPCClient {
+	return testing.NewAllYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the All service.
+func (s *FakeAllYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-yarpc-go
+// source: encoding/protobuf/protoc-gen-yarpc-go/internal/testing/validate.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testing
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"go.uber.org/yarpc/encoding/protobuf"
+)
+
+var (
+	_ValidatedRequest_NamePattern = regexp.MustCompile("^[a-z]+$")
+	_ValidatedRequest_TagsPattern = regexp.MustCompile("^[a-z]+$")
+)
+
+// Validate checks the constraints declared on the fields of ValidatedRequest
+// with the uber.yarpc.field option. It returns a *protobuf.ValidationError
+// listing the fields that do not satisfy them.
+func (m *ValidatedRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+	var violations protobuf.FieldViolations
+	if m.GetName() == "" {
+		violations.Add("name", "must be set")
+	} else if utf8.RuneCountInString(m.GetName()) < 2 {
+		violations.Add("name", "must be at least 2 characters long")
+	} else if utf8.RuneCountInString(m.GetName()) > 8 {
+		violations.Add("name", "must be at most 8 characters long")
+	} else if !_ValidatedRequest_NamePattern.MatchString(m.GetName()) {
+		violations.Add("name", "must match the pattern \"^[a-z]+$\"")
+	}
+	if m.GetCount() < 1 {
+		violations.Add("count", "must be at least 1")
+	} else if m.GetCount() > 10 {
+		violations.Add("count", "must be at most 10")
+	}
+	if m.GetRatio() < 0 {
+		violations.Add("ratio", "must be at least 0")
+	} else if m.GetRatio() > 1 {
+		violations.Add("ratio", "must be at most 1")
+	}
+	if len(m.GetData()) > 4 {
+		violations.Add("data", "must be at most 4 bytes long")
+	}
+	switch m.GetColor() {
+	case 0, 1, 2:
+	default:
+		violations.Add("color", "must be a defined value of uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Color")
+	}
+	if len(m.GetTags()) > 2 {
+		violations.Add("tags", "must have at most 2 elements")
+	}
+	for i, v := range m.GetTags() {
+		if !_ValidatedRequest_TagsPattern.MatchString(v) {
+			violations.Add(protobuf.ElementPath("tags", i), "must match the pattern \"^[a-z]+$\"")
+		}
+	}
+	if m.GetChild() == nil {
+		violations.Add("child", "must be set")
+	}
+	violations.AddNested("child", m.GetChild())
+	for i, v := range m.GetChildren() {
+		violations.AddNested(protobuf.ElementPath("children", i), v)
+	}
+	if len(m.GetChildrenByName()) > 2 {
+		violations.Add("children_by_name", "must have at most 2 elements")
+	}
+	for k, v := range m.GetChildrenByName() {
+		violations.AddNested(protobuf.ElementPath("children_by_name", k), v)
+	}
+	if m.GetSize_() > 100 {
+		violations.Add("size", "must be at most 100")
+	}
+	return violations.Err()
+}
+
+// Validate checks the constraints declared on the fields of ValidatedChild
+// with the uber.yarpc.field option. It returns a *protobuf.ValidationError
+// listing the fields that do not satisfy them.
+func (m *ValidatedChild) Validate() error {
+	if m == nil {
+		return nil
+	}
+	var violations protobuf.FieldViolations
+	if m.GetId() == "" {
+		violations.Add("id", "must be set")
+	}
+	return violations.Err()
+}
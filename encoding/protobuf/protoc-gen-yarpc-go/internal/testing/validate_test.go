@@ -0,0 +1,156 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/encoding/protobuf"
+)
+
+func TestValidate(t *testing.T) {
+	newValid := func() *ValidatedRequest {
+		return &ValidatedRequest{
+			Name:     "foo",
+			Count:    1,
+			Ratio:    0.5,
+			Data:     []byte("abcd"),
+			Color:    COLOR_RED,
+			Tags:     []string{"a", "b"},
+			Child:    &ValidatedChild{Id: "1"},
+			Children: []*ValidatedChild{{Id: "2"}},
+			ChildrenByName: map[string]*ValidatedChild{
+				"bar": {Id: "3"},
+			},
+			Size_: 100,
+		}
+	}
+
+	tests := []struct {
+		msg        string
+		modify     func(*ValidatedRequest)
+		violations []protobuf.FieldViolation
+	}{
+		{
+			msg:    "valid",
+			modify: func(*ValidatedRequest) {},
+		},
+		{
+			msg:    "missing name",
+			modify: func(r *ValidatedRequest) { r.Name = "" },
+			violations: []protobuf.FieldViolation{
+				{Field: "name", Description: "must be set"},
+			},
+		},
+		{
+			msg:    "name too short and not matching the pattern",
+			modify: func(r *ValidatedRequest) { r.Name = "F" },
+			violations: []protobuf.FieldViolation{
+				{Field: "name", Description: "must be at least 2 characters long"},
+			},
+		},
+		{
+			msg:    "name not matching the pattern",
+			modify: func(r *ValidatedRequest) { r.Name = "Foo" },
+			violations: []protobuf.FieldViolation{
+				{Field: "name", Description: `must match the pattern "^[a-z]+$"`},
+			},
+		},
+		{
+			msg: "numbers out of range",
+			modify: func(r *ValidatedRequest) {
+				r.Count = 11
+				r.Ratio = -0.5
+				r.Size_ = 101
+			},
+			violations: []protobuf.FieldViolation{
+				{Field: "count", Description: "must be at most 10"},
+				{Field: "ratio", Description: "must be at least 0"},
+				{Field: "size", Description: "must be at most 100"},
+			},
+		},
+		{
+			msg:    "data too long",
+			modify: func(r *ValidatedRequest) { r.Data = []byte("abcde") },
+			violations: []protobuf.FieldViolation{
+				{Field: "data", Description: "must be at most 4 bytes long"},
+			},
+		},
+		{
+			msg:    "undefined color",
+			modify: func(r *ValidatedRequest) { r.Color = Color(3) },
+			violations: []protobuf.FieldViolation{
+				{Field: "color", Description: "must be a defined value of uber.yarpc.encoding.protobuf.protocgenyarpcgo.internal.testing.Color"},
+			},
+		},
+		{
+			msg:    "invalid tags",
+			modify: func(r *ValidatedRequest) { r.Tags = []string{"a", "B", "c"} },
+			violations: []protobuf.FieldViolation{
+				{Field: "tags", Description: "must have at most 2 elements"},
+				{Field: "tags[1]", Description: `must match the pattern "^[a-z]+$"`},
+			},
+		},
+		{
+			msg:    "missing child",
+			modify: func(r *ValidatedRequest) { r.Child = nil },
+			violations: []protobuf.FieldViolation{
+				{Field: "child", Description: "must be set"},
+			},
+		},
+		{
+			msg: "invalid nested children",
+			modify: func(r *ValidatedRequest) {
+				r.Child.Id = ""
+				r.Children = append(r.Children, &ValidatedChild{})
+				r.ChildrenByName["bar"].Id = ""
+			},
+			violations: []protobuf.FieldViolation{
+				{Field: "child.id", Description: "must be set"},
+				{Field: "children[1].id", Description: "must be set"},
+				{Field: `children_by_name["bar"].id`, Description: "must be set"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			request := newValid()
+			tt.modify(request)
+			err := request.Validate()
+			if len(tt.violations) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			validationErr, ok := err.(*protobuf.ValidationError)
+			require.True(t, ok, "unexpected error type %T", err)
+			assert.Equal(t, tt.violations, validationErr.Violations)
+		})
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	var request *ValidatedRequest
+	assert.NoError(t, request.Validate())
+}
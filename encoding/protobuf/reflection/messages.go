@@ -0,0 +1,345 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflection
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// The messages below are wire-compatible with those of the
+// grpc.reflection.v1alpha package, declared in
+// https://github.com/grpc/grpc/blob/master/src/proto/grpc/reflection/v1alpha/reflection.proto.
+//
+// Members of the oneofs of requests and responses are pointers, and exactly
+// one of them should be set.
+
+var errTruncated = errors.New("reflection: message is truncated")
+
+// ServerReflectionRequest is a request for information about the services of
+// a server.
+type ServerReflectionRequest struct {
+	Host string
+
+	// FileByFilename requests the file with the given name, like
+	// "foo/bar.proto".
+	FileByFilename *string
+	// FileContainingSymbol requests the file declaring the given
+	// fully-qualified service, method, message or enum.
+	FileContainingSymbol *string
+	// FileContainingExtension requests the file declaring an extension.
+	// Extensions are not supported.
+	FileContainingExtension *ExtensionRequest
+	// AllExtensionNumbersOfType requests the field numbers of all extensions
+	// of a message. Extensions are not supported.
+	AllExtensionNumbersOfType *string
+	// ListServices requests the names of all services. Its value is ignored.
+	ListServices *string
+}
+
+// ExtensionRequest identifies an extension by the message it extends and its
+// field number.
+type ExtensionRequest struct {
+	ContainingType  string
+	ExtensionNumber int32
+}
+
+// ServerReflectionResponse is a response to a ServerReflectionRequest.
+type ServerReflectionResponse struct {
+	ValidHost       string
+	OriginalRequest *ServerReflectionRequest
+
+	// FileDescriptorResponse answers FileByFilename and FileContainingSymbol
+	// requests.
+	FileDescriptorResponse *FileDescriptorResponse
+	// ListServicesResponse answers ListServices requests.
+	ListServicesResponse *ListServiceResponse
+	// ErrorResponse is set if a request failed.
+	ErrorResponse *ErrorResponse
+}
+
+// FileDescriptorResponse holds serialized FileDescriptorProtos.
+type FileDescriptorResponse struct {
+	FileDescriptorProto [][]byte
+}
+
+// ListServiceResponse lists the services of a server.
+type ListServiceResponse struct {
+	Service []*ServiceResponse
+}
+
+// ServiceResponse describes a service.
+type ServiceResponse struct {
+	// Name is the fully-qualified name of the service.
+	Name string
+}
+
+// ErrorResponse holds the gRPC status code and message of a failed request.
+type ErrorResponse struct {
+	ErrorCode    int32
+	ErrorMessage string
+}
+
+// Reset resets the request to its zero value.
+func (m *ServerReflectionRequest) Reset() { *m = ServerReflectionRequest{} }
+
+// ProtoMessage marks ServerReflectionRequest as a proto.Message.
+func (*ServerReflectionRequest) ProtoMessage() {}
+
+// String returns a human-readable representation of the request.
+func (m *ServerReflectionRequest) String() string {
+	return fmt.Sprintf("%+v", *m)
+}
+
+// Marshal serializes the request in the protobuf wire format.
+func (m *ServerReflectionRequest) Marshal() ([]byte, error) {
+	b := proto.NewBuffer(nil)
+	encodeString(b, 1, m.Host)
+	encodeOptionalString(b, 3, m.FileByFilename)
+	encodeOptionalString(b, 4, m.FileContainingSymbol)
+	if m.FileContainingExtension != nil {
+		ext := proto.NewBuffer(nil)
+		encodeString(ext, 1, m.FileContainingExtension.ContainingType)
+		encodeVarint(ext, 2, uint64(m.FileContainingExtension.ExtensionNumber))
+		encodeBytes(b, 5, ext.Bytes())
+	}
+	encodeOptionalString(b, 6, m.AllExtensionNumbersOfType)
+	encodeOptionalString(b, 7, m.ListServices)
+	return b.Bytes(), nil
+}
+
+// Unmarshal parses a request in the protobuf wire format.
+func (m *ServerReflectionRequest) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(number int32, v fieldValue) error {
+		switch number {
+		case 1:
+			m.Host = string(v.bytes)
+		case 3:
+			m.FileByFilename = stringPointer(v.bytes)
+		case 4:
+			m.FileContainingSymbol = stringPointer(v.bytes)
+		case 5:
+			ext := &ExtensionRequest{}
+			if err := decodeMessage(v.bytes, func(number int32, v fieldValue) error {
+				switch number {
+				case 1:
+					ext.ContainingType = string(v.bytes)
+				case 2:
+					ext.ExtensionNumber = int32(v.varint)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			m.FileContainingExtension = ext
+		case 6:
+			m.AllExtensionNumbersOfType = stringPointer(v.bytes)
+		case 7:
+			m.ListServices = stringPointer(v.bytes)
+		}
+		return nil
+	})
+}
+
+// Reset resets the response to its zero value.
+func (m *ServerReflectionResponse) Reset() { *m = ServerReflectionResponse{} }
+
+// ProtoMessage marks ServerReflectionResponse as a proto.Message.
+func (*ServerReflectionResponse) ProtoMessage() {}
+
+// String returns a human-readable representation of the response.
+func (m *ServerReflectionResponse) String() string {
+	return fmt.Sprintf("%+v", *m)
+}
+
+// Marshal serializes the response in the protobuf wire format.
+func (m *ServerReflectionResponse) Marshal() ([]byte, error) {
+	b := proto.NewBuffer(nil)
+	encodeString(b, 1, m.ValidHost)
+	if m.OriginalRequest != nil {
+		request, err := m.OriginalRequest.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		encodeBytes(b, 2, request)
+	}
+	if m.FileDescriptorResponse != nil {
+		files := proto.NewBuffer(nil)
+		for _, file := range m.FileDescriptorResponse.FileDescriptorProto {
+			encodeBytes(files, 1, file)
+		}
+		encodeBytes(b, 4, files.Bytes())
+	}
+	if m.ListServicesResponse != nil {
+		services := proto.NewBuffer(nil)
+		for _, service := range m.ListServicesResponse.Service {
+			name := proto.NewBuffer(nil)
+			encodeString(name, 1, service.Name)
+			encodeBytes(services, 1, name.Bytes())
+		}
+		encodeBytes(b, 6, services.Bytes())
+	}
+	if m.ErrorResponse != nil {
+		e := proto.NewBuffer(nil)
+		encodeVarint(e, 1, uint64(m.ErrorResponse.ErrorCode))
+		encodeString(e, 2, m.ErrorResponse.ErrorMessage)
+		encodeBytes(b, 7, e.Bytes())
+	}
+	return b.Bytes(), nil
+}
+
+// Unmarshal parses a response in the protobuf wire format.
+func (m *ServerReflectionResponse) Unmarshal(data []byte) error {
+	return decodeMessage(data, func(number int32, v fieldValue) error {
+		switch number {
+		case 1:
+			m.ValidHost = string(v.bytes)
+		case 2:
+			m.OriginalRequest = &ServerReflectionRequest{}
+			return m.OriginalRequest.Unmarshal(v.bytes)
+		case 4:
+			files := &FileDescriptorResponse{}
+			if err := decodeMessage(v.bytes, func(number int32, v fieldValue) error {
+				if number == 1 {
+					files.FileDescriptorProto = append(files.FileDescriptorProto, v.bytes)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			m.FileDescriptorResponse = files
+		case 6:
+			services := &ListServiceResponse{}
+			if err := decodeMessage(v.bytes, func(number int32, v fieldValue) error {
+				if number != 1 {
+					return nil
+				}
+				service := &ServiceResponse{}
+				services.Service = append(services.Service, service)
+				return decodeMessage(v.bytes, func(number int32, v fieldValue) error {
+					if number == 1 {
+						service.Name = string(v.bytes)
+					}
+					return nil
+				})
+			}); err != nil {
+				return err
+			}
+			m.ListServicesResponse = services
+		case 7:
+			e := &ErrorResponse{}
+			if err := decodeMessage(v.bytes, func(number int32, v fieldValue) error {
+				switch number {
+				case 1:
+					e.ErrorCode = int32(v.varint)
+				case 2:
+					e.ErrorMessage = string(v.bytes)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			m.ErrorResponse = e
+		}
+		return nil
+	})
+}
+
+func encodeVarint(b *proto.Buffer, number int32, x uint64) {
+	if x != 0 {
+		b.EncodeVarint(uint64(number)<<3 | proto.WireVarint)
+		b.EncodeVarint(x)
+	}
+}
+
+func encodeString(b *proto.Buffer, number int32, s string) {
+	if s != "" {
+		encodeBytes(b, number, []byte(s))
+	}
+}
+
+func encodeOptionalString(b *proto.Buffer, number int32, s *string) {
+	if s != nil {
+		encodeBytes(b, number, []byte(*s))
+	}
+}
+
+func encodeBytes(b *proto.Buffer, number int32, data []byte) {
+	b.EncodeVarint(uint64(number)<<3 | proto.WireBytes)
+	b.EncodeRawBytes(data)
+}
+
+func stringPointer(b []byte) *string {
+	s := string(b)
+	return &s
+}
+
+// fieldValue is the value of a field: varint holds the values of varint
+// fields and bytes holds the contents of length-delimited fields.
+type fieldValue struct {
+	varint uint64
+	bytes  []byte
+}
+
+// decodeMessage calls decode for every varint and length-delimited field of
+// an encoded message, skipping fields of other wire types.
+func decodeMessage(data []byte, decode func(number int32, v fieldValue) error) error {
+	for len(data) > 0 {
+		tag, n := proto.DecodeVarint(data)
+		if n == 0 {
+			return errTruncated
+		}
+		data = data[n:]
+
+		var v fieldValue
+		switch tag & 7 {
+		case proto.WireVarint:
+			if v.varint, n = proto.DecodeVarint(data); n == 0 {
+				return errTruncated
+			}
+		case proto.WireFixed64:
+			n = 8
+		case proto.WireFixed32:
+			n = 4
+		case proto.WireBytes:
+			length, m := proto.DecodeVarint(data)
+			if m == 0 || uint64(len(data)-m) < length {
+				return errTruncated
+			}
+			v.bytes, n = data[m:m+int(length)], m+int(length)
+		default:
+			return fmt.Errorf("reflection: unsupported wire type %d", tag&7)
+		}
+		if len(data) < n {
+			return errTruncated
+		}
+		data = data[n:]
+
+		if tag&7 == proto.WireVarint || tag&7 == proto.WireBytes {
+			if err := decode(int32(tag>>3), v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
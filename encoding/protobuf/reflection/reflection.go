@@ -0,0 +1,345 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package reflection serves the descriptors of the protobuf services
+// registered on a dispatcher, so that tools can discover and call them
+// without access to their protobuf files.
+//
+// Code generated by protoc-gen-yarpc-go embeds the descriptors of the file
+// declaring each service and of all files it imports, and
+// protobuf.BuildProcedures makes them available once the procedures of a
+// service are built.
+//
+//   dispatcher.Register(examplepb.BuildKeyValueYARPCProcedures(server))
+//   reflection.Register(dispatcher)
+//
+// Two procedures answer the same requests. StreamProcedure implements the
+// gRPC server reflection protocol over a gRPC inbound. UnaryProcedure
+// answers one request per call over any transport.
+//
+// Like any request to a gRPC inbound, reflection requests must carry the
+// rpc-caller and rpc-service metadata, and name the proto encoding, either
+// with the rpc-encoding metadata or with the application/grpc+proto content
+// type. Generic gRPC tools send none of these by default, so they must be
+// told to add them, for example with grpcurl:
+//
+//   grpcurl -plaintext \
+//     -H 'rpc-caller: grpcurl' \
+//     -H 'rpc-service: myservice' \
+//     -H 'rpc-encoding: proto' \
+//     localhost:8080 list
+//
+// Methods that return the uber.yarpc.Oneway type, and methods whose
+// uber.yarpc.method option sets oneway, are oneway procedures, which tools
+// must call without waiting for a response.
+//
+// Extensions are not supported: requests for extensions are answered with
+// an error response.
+package reflection
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/pkg/procedure"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	// StreamProcedure is the name of the streaming reflection procedure. Over
+	// a gRPC inbound, it is the ServerReflectionInfo method of the
+	// grpc.reflection.v1alpha.ServerReflection service.
+	StreamProcedure = "grpc.reflection.v1alpha.ServerReflection::ServerReflectionInfo"
+
+	// UnaryProcedure is the name of the unary reflection procedure, which
+	// takes a ServerReflectionRequest and returns a ServerReflectionResponse.
+	UnaryProcedure = "yarpc::reflection"
+)
+
+// Register registers the reflection procedures on a dispatcher, describing
+// the protobuf services registered on it.
+func Register(d *yarpc.Dispatcher) {
+	d.Register(Procedures(d.Router()))
+}
+
+// Procedures returns the reflection procedures, describing the protobuf
+// services registered on the given router at the time of each request.
+func Procedures(router transport.Router) []transport.Procedure {
+	s := &server{router: router}
+	return []transport.Procedure{
+		{
+			Name: UnaryProcedure,
+			HandlerSpec: transport.NewUnaryHandlerSpec(protobuf.NewUnaryHandler(
+				protobuf.UnaryHandlerParams{
+					Handle:     s.handleUnary,
+					NewRequest: newRequest,
+				},
+			)),
+			Encoding: protobuf.Encoding,
+		},
+		{
+			Name: StreamProcedure,
+			HandlerSpec: transport.NewStreamHandlerSpec(protobuf.NewStreamHandler(
+				protobuf.StreamHandlerParams{
+					Handle: s.handleStream,
+				},
+			)),
+			Encoding: protobuf.Encoding,
+		},
+	}
+}
+
+func newRequest() proto.Message {
+	return &ServerReflectionRequest{}
+}
+
+type server struct {
+	router transport.Router
+}
+
+func (s *server) handleUnary(ctx context.Context, requestMessage proto.Message) (proto.Message, error) {
+	request, ok := requestMessage.(*ServerReflectionRequest)
+	if !ok {
+		if requestMessage != nil {
+			return nil, protobuf.CastError(&ServerReflectionRequest{}, requestMessage)
+		}
+		request = &ServerReflectionRequest{}
+	}
+	return s.respond(s.files(), request, nil)
+}
+
+func (s *server) handleStream(stream *protobuf.ServerStream) error {
+	// As with gRPC servers, each file is sent at most once per stream since
+	// clients keep track of the files they received.
+	sent := make(map[string]struct{})
+	for {
+		requestMessage, err := stream.Receive(newRequest)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		request, ok := requestMessage.(*ServerReflectionRequest)
+		if !ok {
+			return protobuf.CastError(&ServerReflectionRequest{}, requestMessage)
+		}
+		response, err := s.respond(s.files(), request, sent)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) respond(files *fileSet, request *ServerReflectionRequest, sent map[string]struct{}) (*ServerReflectionResponse, error) {
+	response := &ServerReflectionResponse{
+		ValidHost:       request.Host,
+		OriginalRequest: request,
+	}
+	switch {
+	case request.FileByFilename != nil:
+		name := *request.FileByFilename
+		if _, ok := files.byName[name]; !ok {
+			response.ErrorResponse = errorResponse(yarpcerrors.NotFoundErrorf("file %q not found", name))
+			break
+		}
+		response.FileDescriptorResponse = files.closure(name, sent)
+	case request.FileContainingSymbol != nil:
+		symbol := *request.FileContainingSymbol
+		name, ok := files.bySymbol[symbol]
+		if !ok {
+			response.ErrorResponse = errorResponse(yarpcerrors.NotFoundErrorf("symbol %q not found", symbol))
+			break
+		}
+		response.FileDescriptorResponse = files.closure(name, sent)
+	case request.FileContainingExtension != nil, request.AllExtensionNumbersOfType != nil:
+		response.ErrorResponse = errorResponse(yarpcerrors.UnimplementedErrorf("extensions are not supported"))
+	case request.ListServices != nil:
+		response.ListServicesResponse = &ListServiceResponse{}
+		for _, name := range files.services {
+			response.ListServicesResponse.Service = append(response.ListServicesResponse.Service, &ServiceResponse{Name: name})
+		}
+	default:
+		return nil, yarpcerrors.InvalidArgumentErrorf("reflection request does not specify what is requested")
+	}
+	return response, nil
+}
+
+func errorResponse(err error) *ErrorResponse {
+	status := yarpcerrors.FromError(err)
+	return &ErrorResponse{
+		ErrorCode:    int32(status.Code()),
+		ErrorMessage: status.Message(),
+	}
+}
+
+// files returns the files of the protobuf services that currently have
+// procedures on the router.
+func (s *server) files() *fileSet {
+	files := newFileSet()
+	seen := make(map[string]struct{})
+	for _, p := range s.router.Procedures() {
+		if p.Encoding != protobuf.Encoding {
+			continue
+		}
+		serviceName, _ := procedure.FromName(p.Name)
+		if _, ok := seen[serviceName]; ok {
+			continue
+		}
+		seen[serviceName] = struct{}{}
+
+		if fileDescriptors, ok := protobuf.FileDescriptors(serviceName); ok {
+			files.add(serviceName, fileDescriptors)
+		}
+	}
+	sort.Strings(files.services)
+	return files
+}
+
+type file struct {
+	data         []byte // serialized FileDescriptorProto
+	dependencies []string
+}
+
+// fileSet indexes files by name and by the symbols they declare.
+type fileSet struct {
+	services []string
+	byName   map[string]*file
+	bySymbol map[string]string
+}
+
+func newFileSet() *fileSet {
+	return &fileSet{
+		byName:   make(map[string]*file),
+		bySymbol: make(map[string]string),
+	}
+}
+
+func (fs *fileSet) add(serviceName string, fileDescriptors [][]byte) {
+	fs.services = append(fs.services, serviceName)
+	for _, compressed := range fileDescriptors {
+		data, fd, err := decodeFileDescriptor(compressed)
+		if err != nil {
+			// Descriptors are embedded in generated code by protoc-gen-yarpc-go
+			// and can only be invalid if they were tampered with.
+			continue
+		}
+		if _, ok := fs.byName[fd.GetName()]; ok {
+			continue
+		}
+		fs.byName[fd.GetName()] = &file{data: data, dependencies: fd.GetDependency()}
+		fs.indexSymbols(fd)
+	}
+}
+
+func (fs *fileSet) indexSymbols(fd *descriptor.FileDescriptorProto) {
+	prefix := fd.GetPackage()
+	if prefix != "" {
+		prefix += "."
+	}
+
+	var addMessage func(string, *descriptor.DescriptorProto)
+	addMessage = func(prefix string, m *descriptor.DescriptorProto) {
+		name := prefix + m.GetName()
+		fs.bySymbol[name] = fd.GetName()
+		for _, f := range m.Field {
+			fs.bySymbol[name+"."+f.GetName()] = fd.GetName()
+		}
+		for _, nested := range m.NestedType {
+			addMessage(name+".", nested)
+		}
+		for _, e := range m.EnumType {
+			fs.bySymbol[name+"."+e.GetName()] = fd.GetName()
+		}
+	}
+
+	for _, m := range fd.MessageType {
+		addMessage(prefix, m)
+	}
+	for _, e := range fd.EnumType {
+		fs.bySymbol[prefix+e.GetName()] = fd.GetName()
+	}
+	for _, s := range fd.Service {
+		name := prefix + s.GetName()
+		fs.bySymbol[name] = fd.GetName()
+		for _, m := range s.Method {
+			fs.bySymbol[name+"."+m.GetName()] = fd.GetName()
+		}
+	}
+}
+
+// closure returns the named file and the files it imports, directly or
+// transitively, except for those already sent.
+func (fs *fileSet) closure(name string, sent map[string]struct{}) *FileDescriptorResponse {
+	response := &FileDescriptorResponse{}
+	seen := make(map[string]struct{})
+	var visit func(string)
+	visit = func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+
+		f, ok := fs.byName[name]
+		if !ok {
+			return
+		}
+		if _, ok := sent[name]; !ok {
+			response.FileDescriptorProto = append(response.FileDescriptorProto, f.data)
+			if sent != nil {
+				sent[name] = struct{}{}
+			}
+		}
+		for _, dependency := range f.dependencies {
+			visit(dependency)
+		}
+	}
+	visit(name)
+	return response
+}
+
+func decodeFileDescriptor(compressed []byte) ([]byte, *descriptor.FileDescriptorProto, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(data, &fd); err != nil {
+		return nil, nil, err
+	}
+	return data, &fd, nil
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package reflection
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/yarpcerrors"
+
+	_ "github.com/gogo/protobuf/types" // registers google/protobuf/struct.proto
+)
+
+func testFileDescriptor(t *testing.T) *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:       proto.String("test/kv.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("GetRequest"),
+				Field: []*descriptor.FieldDescriptorProto{
+					{
+						Name:   proto.String("key"),
+						Number: proto.Int32(1),
+						Type:   descriptor.FieldDescriptorProto_TYPE_STRING.Enum(),
+						Label:  descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					},
+				},
+				NestedType: []*descriptor.DescriptorProto{{Name: proto.String("Nested")}},
+			},
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name:  proto.String("Kind"),
+				Value: []*descriptor.EnumValueDescriptorProto{{Name: proto.String("DEFAULT"), Number: proto.Int32(0)}},
+			},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{
+			{
+				Name: proto.String("KeyValue"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{
+						Name:       proto.String("Get"),
+						InputType:  proto.String(".test.GetRequest"),
+						OutputType: proto.String(".google.protobuf.Struct"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func gunzipped(t *testing.T, data []byte) []byte {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return data
+}
+
+// newTestRouter returns a router with the procedures of the test.KeyValue
+// service, whose file descriptors are registered, and of the
+// test.Undescribed service, whose are not.
+func newTestRouter(t *testing.T) (transport.Router, [][]byte) {
+	kv, err := proto.Marshal(testFileDescriptor(t))
+	require.NoError(t, err)
+	structProto := proto.FileDescriptor("google/protobuf/struct.proto")
+	require.NotEmpty(t, structProto)
+	fileDescriptors := [][]byte{gzipped(t, kv), structProto}
+
+	handler := protobuf.NewUnaryHandler(protobuf.UnaryHandlerParams{
+		Handle: func(context.Context, proto.Message) (proto.Message, error) {
+			return nil, nil
+		},
+		NewRequest: newRequest,
+	})
+
+	router := yarpc.NewMapRouter("test")
+	router.Register(protobuf.BuildProcedures(protobuf.BuildProceduresParams{
+		ServiceName:        "test.KeyValue",
+		UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{{MethodName: "Get", Handler: handler}},
+		FileDescriptors:    fileDescriptors,
+	}))
+	router.Register(protobuf.BuildProcedures(protobuf.BuildProceduresParams{
+		ServiceName:        "test.Undescribed",
+		UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{{MethodName: "Get", Handler: handler}},
+	}))
+	router.Register(Procedures(router))
+	return router, [][]byte{kv, gunzipped(t, structProto)}
+}
+
+func stringPtr(s string) *string { return &s }
+
+func TestRespond(t *testing.T) {
+	router, files := newTestRouter(t)
+	s := &server{router: router}
+
+	tests := []struct {
+		msg          string
+		give         *ServerReflectionRequest
+		wantFiles    [][]byte
+		wantServices []string
+		wantCode     yarpcerrors.Code
+	}{
+		{
+			msg:          "list services",
+			give:         &ServerReflectionRequest{ListServices: stringPtr("*")},
+			wantServices: []string{"test.KeyValue"},
+		},
+		{
+			msg:       "file by filename",
+			give:      &ServerReflectionRequest{FileByFilename: stringPtr("test/kv.proto")},
+			wantFiles: files,
+		},
+		{
+			msg:       "dependency by filename",
+			give:      &ServerReflectionRequest{FileByFilename: stringPtr("google/protobuf/struct.proto")},
+			wantFiles: files[1:],
+		},
+		{
+			msg:      "unknown file",
+			give:     &ServerReflectionRequest{FileByFilename: stringPtr("test/unknown.proto")},
+			wantCode: yarpcerrors.CodeNotFound,
+		},
+		{
+			msg:       "service",
+			give:      &ServerReflectionRequest{FileContainingSymbol: stringPtr("test.KeyValue")},
+			wantFiles: files,
+		},
+		{
+			msg:       "method",
+			give:      &ServerReflectionRequest{FileContainingSymbol: stringPtr("test.KeyValue.Get")},
+			wantFiles: files,
+		},
+		{
+			msg:       "nested message",
+			give:      &ServerReflectionRequest{FileContainingSymbol: stringPtr("test.GetRequest.Nested")},
+			wantFiles: files,
+		},
+		{
+			msg:       "enum",
+			give:      &ServerReflectionRequest{FileContainingSymbol: stringPtr("test.Kind")},
+			wantFiles: files,
+		},
+		{
+			msg:       "message of a dependency",
+			give:      &ServerReflectionRequest{FileContainingSymbol: stringPtr("google.protobuf.Struct")},
+			wantFiles: files[1:],
+		},
+		{
+			msg:      "unknown symbol",
+			give:     &ServerReflectionRequest{FileContainingSymbol: stringPtr("test.Unknown")},
+			wantCode: yarpcerrors.CodeNotFound,
+		},
+		{
+			msg:      "extension",
+			give:     &ServerReflectionRequest{FileContainingExtension: &ExtensionRequest{ContainingType: "test.GetRequest", ExtensionNumber: 100}},
+			wantCode: yarpcerrors.CodeUnimplemented,
+		},
+		{
+			msg:      "extension numbers",
+			give:     &ServerReflectionRequest{AllExtensionNumbersOfType: stringPtr("test.GetRequest")},
+			wantCode: yarpcerrors.CodeUnimplemented,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			tt.give.Host = "localhost"
+			response, err := s.respond(s.files(), tt.give, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "localhost", response.ValidHost)
+			assert.Equal(t, tt.give, response.OriginalRequest)
+
+			if tt.wantCode != yarpcerrors.CodeOK {
+				require.NotNil(t, response.ErrorResponse)
+				assert.Equal(t, int32(tt.wantCode), response.ErrorResponse.ErrorCode)
+				return
+			}
+			require.Nil(t, response.ErrorResponse)
+
+			if tt.wantServices != nil {
+				require.NotNil(t, response.ListServicesResponse)
+				var services []string
+				for _, service := range response.ListServicesResponse.Service {
+					services = append(services, service.Name)
+				}
+				assert.Equal(t, tt.wantServices, services)
+			}
+			if tt.wantFiles != nil {
+				require.NotNil(t, response.FileDescriptorResponse)
+				assert.Equal(t, tt.wantFiles, response.FileDescriptorResponse.FileDescriptorProto)
+			}
+		})
+	}
+
+	_, err := s.respond(s.files(), &ServerReflectionRequest{}, nil)
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+}
+
+func TestUnaryProcedure(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	request, err := proto.Marshal(&ServerReflectionRequest{ListServices: stringPtr("")})
+	require.NoError(t, err)
+	transportRequest := &transport.Request{
+		Caller:    "caller",
+		Service:   "test",
+		Procedure: UnaryProcedure,
+		Encoding:  protobuf.Encoding,
+		Body:      bytes.NewReader(request),
+	}
+	handlerSpec, err := router.Choose(context.Background(), transportRequest)
+	require.NoError(t, err)
+
+	var resw responseWriter
+	require.NoError(t, handlerSpec.Unary().Handle(context.Background(), transportRequest, &resw))
+
+	var response ServerReflectionResponse
+	require.NoError(t, proto.Unmarshal(resw.Bytes(), &response))
+	assert.Equal(t, &ListServiceResponse{Service: []*ServiceResponse{{Name: "test.KeyValue"}}}, response.ListServicesResponse)
+}
+
+func TestStreamProcedure(t *testing.T) {
+	router, files := newTestRouter(t)
+
+	var requests []*transport.StreamMessage
+	for _, request := range []*ServerReflectionRequest{
+		{FileContainingSymbol: stringPtr("google.protobuf.Value")},
+		{FileByFilename: stringPtr("test/kv.proto")},
+		{FileByFilename: stringPtr("test/kv.proto")},
+	} {
+		data, err := proto.Marshal(request)
+		require.NoError(t, err)
+		requests = append(requests, &transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewReader(data))})
+	}
+
+	stream := &fakeStream{
+		ctx: context.Background(),
+		request: &transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "test",
+			Procedure: StreamProcedure,
+			Encoding:  protobuf.Encoding,
+		}},
+		received: requests,
+	}
+	handlerSpec, err := router.Choose(context.Background(), stream.request.Meta.ToRequest())
+	require.NoError(t, err)
+	serverStream, err := transport.NewServerStream(stream)
+	require.NoError(t, err)
+	require.NoError(t, handlerSpec.Stream().HandleStream(serverStream))
+
+	// Files are sent at most once on a stream.
+	require.Len(t, stream.sent, 3)
+	var responses []*ServerReflectionResponse
+	for _, data := range stream.sent {
+		var response ServerReflectionResponse
+		require.NoError(t, proto.Unmarshal(data, &response))
+		responses = append(responses, &response)
+	}
+	assert.Equal(t, files[1:], responses[0].FileDescriptorResponse.FileDescriptorProto)
+	assert.Equal(t, files[:1], responses[1].FileDescriptorResponse.FileDescriptorProto)
+	assert.Empty(t, responses[2].FileDescriptorResponse.FileDescriptorProto)
+}
+
+func TestMessagesRoundTrip(t *testing.T) {
+	requests := []*ServerReflectionRequest{
+		{},
+		{Host: "localhost", ListServices: stringPtr("")},
+		{FileByFilename: stringPtr("foo.proto")},
+		{FileContainingSymbol: stringPtr("foo.Bar")},
+		{FileContainingExtension: &ExtensionRequest{ContainingType: "foo.Bar", ExtensionNumber: 42}},
+		{AllExtensionNumbersOfType: stringPtr("foo.Bar")},
+	}
+	for _, request := range requests {
+		data, err := proto.Marshal(request)
+		require.NoError(t, err)
+		var got ServerReflectionRequest
+		require.NoError(t, proto.Unmarshal(data, &got))
+		assert.Equal(t, request, &got)
+	}
+
+	responses := []*ServerReflectionResponse{
+		{},
+		{
+			ValidHost:              "localhost",
+			OriginalRequest:        &ServerReflectionRequest{FileByFilename: stringPtr("foo.proto")},
+			FileDescriptorResponse: &FileDescriptorResponse{FileDescriptorProto: [][]byte{{1, 2}, {3}}},
+		},
+		{ListServicesResponse: &ListServiceResponse{Service: []*ServiceResponse{{Name: "foo.Bar"}, {Name: "foo.Baz"}}}},
+		{ErrorResponse: &ErrorResponse{ErrorCode: 5, ErrorMessage: "not found"}},
+	}
+	for _, response := range responses {
+		data, err := proto.Marshal(response)
+		require.NoError(t, err)
+		var got ServerReflectionResponse
+		require.NoError(t, proto.Unmarshal(data, &got))
+		assert.Equal(t, response, &got)
+	}
+}
+
+func TestMessagesWireFormat(t *testing.T) {
+	// The list_services request of grpcurl, as encoded by grpc-go.
+	var request ServerReflectionRequest
+	require.NoError(t, proto.Unmarshal([]byte{0x3a, 0x01, '*'}, &request))
+	assert.Equal(t, &ServerReflectionRequest{ListServices: stringPtr("*")}, &request)
+
+	// Unknown fields of all wire types are skipped.
+	require.NoError(t, proto.Unmarshal([]byte{
+		0x0a, 0x01, 'h', // host
+		0x40, 0x01, // field 8, varint
+		0x49, 0, 0, 0, 0, 0, 0, 0, 0, // field 9, fixed64
+		0x55, 0, 0, 0, 0, // field 10, fixed32
+	}, &request))
+	assert.Equal(t, &ServerReflectionRequest{Host: "h"}, &request)
+
+	assert.Error(t, proto.Unmarshal([]byte{0x0a, 0x05, 'h'}, &request))
+	assert.Error(t, proto.Unmarshal([]byte{0x49, 0}, &request))
+}
+
+type responseWriter struct {
+	bytes.Buffer
+}
+
+func (*responseWriter) AddHeaders(transport.Headers) {}
+func (*responseWriter) SetApplicationError()         {}
+
+type fakeStream struct {
+	ctx      context.Context
+	request  *transport.StreamRequest
+	received []*transport.StreamMessage
+	sent     [][]byte
+}
+
+func (s *fakeStream) Context() context.Context          { return s.ctx }
+func (s *fakeStream) Request() *transport.StreamRequest { return s.request }
+
+func (s *fakeStream) SendMessage(_ context.Context, msg *transport.StreamMessage) error {
+	data, err := ioutil.ReadAll(msg.Body)
+	if err != nil {
+		return err
+	}
+	s.sent = append(s.sent, data)
+	return nil
+}
+
+func (s *fakeStream) ReceiveMessage(context.Context) (*transport.StreamMessage, error) {
+	if len(s.received) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.received[0]
+	s.received = s.received[1:]
+	return msg, nil
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/yarpc/pkg/procedure"
+	yarpchttp "go.uber.org/yarpc/transport/http"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// errUnknownField is returned when a field path does not name a field of a
+// message.
+var errUnknownField = errors.New("unknown field")
+
+// ***all below functions should only be called by generated code***
+
+// HTTPRule binds a method to RESTful HTTP requests, as declared with the
+// google.api.http option of the method.
+type HTTPRule struct {
+	// MethodName is the name of the method.
+	MethodName string
+	// HTTPMethod is the HTTP method of the requests, such as GET.
+	HTTPMethod string
+	// Pattern is the path template of the requests.
+	Pattern string
+	// Body is the name of the request field that the body of HTTP requests
+	// is mapped to, "*" if the body is mapped to the whole request, or
+	// empty if HTTP requests have no body.
+	Body string
+	// ResponseBody is the name of the response field that the body of HTTP
+	// responses is mapped from, or empty if it is the whole response.
+	ResponseBody string
+	// NewRequest creates a new request message.
+	NewRequest func() proto.Message
+	// NewResponse creates a new response message.
+	NewResponse func() proto.Message
+}
+
+// BuildHTTPRoutesParams contains the parameters for BuildHTTPRoutes.
+type BuildHTTPRoutesParams struct {
+	ServiceName string
+	Rules       []HTTPRule
+}
+
+// BuildHTTPRoutes builds the routes of an HTTP inbound that serve the
+// methods of a service on RESTful URLs.
+//
+// The routes call the procedures built by BuildProcedures with
+// JSONEncoding. The variables of the path, the query parameters and the
+// body of HTTP requests are mapped to the fields of the request as
+// described by the google.api.http option: the body is mapped to the field
+// named by Body, or to the whole request if Body is "*", each variable is
+// mapped to the field named by its path, and if Body is not "*", query
+// parameters are mapped to the fields that they name, such as
+// "?page_size=10&filter.tags=a&filter.tags=b". Query parameters that do not
+// name a field are ignored.
+func BuildHTTPRoutes(params BuildHTTPRoutesParams) []yarpchttp.Route {
+	routes := make([]yarpchttp.Route, 0, len(params.Rules))
+	for _, rule := range params.Rules {
+		r := httpRoute{rule}
+		route := yarpchttp.Route{
+			Method:      rule.HTTPMethod,
+			Pattern:     rule.Pattern,
+			Procedure:   procedure.ToName(params.ServiceName, rule.MethodName),
+			Encoding:    JSONEncoding,
+			RequestBody: r.requestBody,
+		}
+		if rule.ResponseBody != "" {
+			route.ResponseBody = r.responseBody
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+type httpRoute struct {
+	rule HTTPRule
+}
+
+// requestBody builds the JSON representation of the request from an HTTP
+// request.
+func (r httpRoute) requestBody(req *http.Request, variables map[string]string) ([]byte, error) {
+	requestType := reflect.TypeOf(r.rule.NewRequest())
+	object := make(map[string]interface{})
+
+	var bodyField string
+	if r.rule.Body != "" {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if len(bytes.TrimSpace(body)) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			if err := decoder.Decode(&value); err != nil {
+				return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "could not decode the body of the request: %v", err)
+			}
+		}
+		if r.rule.Body == "*" {
+			if value != nil {
+				var ok bool
+				if object, ok = value.(map[string]interface{}); !ok {
+					return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "the body of the request must be a JSON object")
+				}
+			}
+		} else {
+			field, ok := lookupMessageField(requestType, r.rule.Body)
+			if !ok {
+				return nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "body %q of route %s %s does not name a field of the request", r.rule.Body, r.rule.HTTPMethod, r.rule.Pattern)
+			}
+			bodyField = field.props.OrigName
+			if value != nil {
+				object[bodyField] = value
+			}
+		}
+	}
+
+	for name, value := range variables {
+		if err := setJSONField(object, requestType, strings.Split(name, "."), []string{value}); err != nil {
+			if err == errUnknownField {
+				return nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "variable %q of route %s %s does not name a field of the request", name, r.rule.HTTPMethod, r.rule.Pattern)
+			}
+			return nil, err
+		}
+	}
+
+	if r.rule.Body != "*" {
+		for key, values := range req.URL.Query() {
+			if _, ok := variables[key]; ok {
+				continue
+			}
+			path := strings.Split(key, ".")
+			if field, ok := lookupMessageField(requestType, path[0]); ok && field.props.OrigName == bodyField {
+				continue
+			}
+			if err := setJSONField(object, requestType, path, values); err != nil && err != errUnknownField {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(object)
+}
+
+// responseBody extracts the JSON representation of the field named by
+// ResponseBody from the JSON representation of a response.
+func (r httpRoute) responseBody(body []byte) ([]byte, error) {
+	field, ok := lookupMessageField(reflect.TypeOf(r.rule.NewResponse()), r.rule.ResponseBody)
+	if !ok {
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "response body %q of route %s %s does not name a field of the response", r.rule.ResponseBody, r.rule.HTTPMethod, r.rule.Pattern)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInternal, "could not decode the body of the response: %v", err)
+	}
+	if value, ok := object[field.props.OrigName]; ok {
+		return value, nil
+	}
+	if value, ok := object[jsonName(field.props)]; ok {
+		return value, nil
+	}
+
+	// Fields with default values are omitted from the response unless the
+	// procedure emits defaults.
+	var defaults bytes.Buffer
+	if err := (&jsonpb.Marshaler{EmitDefaults: true}).Marshal(&defaults, r.rule.NewResponse()); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(defaults.Bytes(), &object); err != nil {
+		return nil, err
+	}
+	return object[jsonName(field.props)], nil
+}
+
+// messageField is a field of a generated message.
+type messageField struct {
+	typ   reflect.Type
+	props *proto.Properties
+}
+
+// lookupMessageField returns the field of the message of the given type
+// with the given name, either its name in the .proto file or its JSON name.
+func lookupMessageField(messageType reflect.Type, name string) (messageField, bool) {
+	if messageType.Kind() != reflect.Ptr || messageType.Elem().Kind() != reflect.Struct {
+		return messageField{}, false
+	}
+	structType := messageType.Elem()
+	properties := proto.GetProperties(structType)
+	for i, props := range properties.Prop {
+		if structType.Field(i).Tag.Get("protobuf_oneof") != "" || props.OrigName == "" {
+			continue
+		}
+		if props.OrigName == name || jsonName(props) == name {
+			return messageField{typ: structType.Field(i).Type, props: props}, true
+		}
+	}
+	for _, oneof := range properties.OneofTypes {
+		if oneof.Prop.OrigName == name || jsonName(oneof.Prop) == name {
+			return messageField{typ: oneof.Type.Elem().Field(0).Type, props: oneof.Prop}, true
+		}
+	}
+	return messageField{}, false
+}
+
+func jsonName(props *proto.Properties) string {
+	if props.JSONName != "" {
+		return props.JSONName
+	}
+	return props.OrigName
+}
+
+// setJSONField sets the field with the given path in the JSON representation
+// of a message of the given type to the JSON representation of the given
+// values, which are parsed from a path or a query. It returns
+// errUnknownField if the path does not name a field.
+func setJSONField(object map[string]interface{}, messageType reflect.Type, path []string, values []string) error {
+	for i, name := range path {
+		field, ok := lookupMessageField(messageType, name)
+		if !ok {
+			return errUnknownField
+		}
+		// Use a single key for the field, even if the body used its JSON
+		// name.
+		key := field.props.OrigName
+		if alias := jsonName(field.props); alias != key {
+			if value, ok := object[alias]; ok {
+				if _, ok := object[key]; !ok {
+					object[key] = value
+				}
+				delete(object, alias)
+			}
+		}
+		if i == len(path)-1 {
+			value, err := jsonFieldValue(field, values)
+			if err != nil {
+				return err
+			}
+			object[key] = value
+			return nil
+		}
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			object[key] = child
+		}
+		object, messageType = child, field.typ
+	}
+	return nil
+}
+
+// jsonFieldValue returns the JSON representation of the given values of a
+// field.
+func jsonFieldValue(field messageField, values []string) (interface{}, error) {
+	switch {
+	case field.typ.Kind() == reflect.Map:
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "map field %q cannot be set from a path or a query", field.props.OrigName)
+	case field.typ.Kind() == reflect.Slice && field.typ.Elem().Kind() != reflect.Uint8:
+		elements := make([]interface{}, len(values))
+		for i, value := range values {
+			element, err := jsonScalarValue(field, field.typ.Elem(), value)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return elements, nil
+	case len(values) != 1:
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "field %q is not repeated but has %d values", field.props.OrigName, len(values))
+	default:
+		return jsonScalarValue(field, field.typ, values[0])
+	}
+}
+
+// jsonScalarValue returns the JSON representation of a value of a field.
+// Numbers, bytes and well-known types such as google.protobuf.Timestamp are
+// represented by strings, which is accepted by the JSON unmarshaler.
+func jsonScalarValue(field messageField, typ reflect.Type, value string) (interface{}, error) {
+	switch {
+	case typ.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "invalid value %q for boolean field %q", value, field.props.OrigName)
+		}
+		return b, nil
+	case field.props.Enum != "":
+		// Enums are represented by their names or their numbers.
+		if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return json.Number(value), nil
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestBuildHTTPRoutes(t *testing.T) {
+	routes := BuildHTTPRoutes(BuildHTTPRoutesParams{
+		ServiceName: "foo.Bar",
+		Rules: []HTTPRule{
+			{
+				MethodName: "GetField",
+				HTTPMethod: "GET",
+				Pattern:    "/v1/fields/{name}",
+				NewRequest: func() proto.Message { return &types.Field{} },
+			},
+			{
+				MethodName:   "ListFields",
+				HTTPMethod:   "GET",
+				Pattern:      "/v1/fields",
+				ResponseBody: "fields",
+				NewRequest:   func() proto.Message { return &types.Empty{} },
+				NewResponse:  func() proto.Message { return &types.Type{} },
+			},
+		},
+	})
+	require.Len(t, routes, 2)
+	assert.Equal(t, "GET", routes[0].Method)
+	assert.Equal(t, "/v1/fields/{name}", routes[0].Pattern)
+	assert.Equal(t, "foo.Bar::GetField", routes[0].Procedure)
+	assert.Equal(t, JSONEncoding, routes[0].Encoding)
+	assert.Empty(t, routes[0].Service, "the service must be resolved by the inbound")
+	assert.NotNil(t, routes[0].RequestBody)
+	assert.Nil(t, routes[0].ResponseBody)
+	assert.Equal(t, "foo.Bar::ListFields", routes[1].Procedure)
+	assert.NotNil(t, routes[1].ResponseBody)
+}
+
+func TestHTTPRouteRequestBody(t *testing.T) {
+	tests := []struct {
+		msg        string
+		rule       HTTPRule
+		target     string
+		body       string
+		variables  map[string]string
+		want       string
+		wantCode   yarpcerrors.Code
+		wantError  string
+		newMessage func() proto.Message
+	}{
+		{
+			msg:       "path and query",
+			rule:      HTTPRule{},
+			target:    "/?number=3&packed=true&kind=TYPE_STRING&cardinality=2&typeUrl=foo&unknown=1",
+			variables: map[string]string{"name": "bar"},
+			want:      `{"name":"bar","number":"3","packed":true,"kind":"TYPE_STRING","cardinality":2,"type_url":"foo"}`,
+		},
+		{
+			msg:       "variables take precedence over the query",
+			rule:      HTTPRule{},
+			target:    "/?name=foo",
+			variables: map[string]string{"name": "bar"},
+			want:      `{"name":"bar"}`,
+		},
+		{
+			msg:       "whole body",
+			rule:      HTTPRule{Body: "*"},
+			target:    "/?number=3",
+			body:      `{"typeUrl":"foo","number":1,"name":"baz"}`,
+			variables: map[string]string{"name": "bar"},
+			want:      `{"typeUrl":"foo","number":1,"name":"bar"}`,
+		},
+		{
+			msg:    "empty whole body",
+			rule:   HTTPRule{Body: "*"},
+			target: "/",
+			want:   `{}`,
+		},
+		{
+			msg:        "body field",
+			rule:       HTTPRule{Body: "source_context"},
+			target:     "/?sourceContext.fileName=foo.proto&oneofs=a&oneofs=b",
+			body:       `{"fileName":"bar.proto"}`,
+			variables:  map[string]string{"name": "baz"},
+			want:       `{"source_context":{"fileName":"bar.proto"},"oneofs":["a","b"],"name":"baz"}`,
+			newMessage: func() proto.Message { return &types.Type{} },
+		},
+		{
+			msg:        "nested variable",
+			rule:       HTTPRule{},
+			target:     "/",
+			variables:  map[string]string{"source_context.file_name": "foo.proto"},
+			want:       `{"source_context":{"file_name":"foo.proto"}}`,
+			newMessage: func() proto.Message { return &types.Type{} },
+		},
+		{
+			msg:       "invalid boolean",
+			rule:      HTTPRule{},
+			target:    "/?packed=maybe",
+			wantCode:  yarpcerrors.CodeInvalidArgument,
+			wantError: `invalid value "maybe" for boolean field "packed"`,
+		},
+		{
+			msg:       "too many values",
+			rule:      HTTPRule{},
+			target:    "/?number=1&number=2",
+			wantCode:  yarpcerrors.CodeInvalidArgument,
+			wantError: `field "number" is not repeated but has 2 values`,
+		},
+		{
+			msg:       "invalid body",
+			rule:      HTTPRule{Body: "*"},
+			target:    "/",
+			body:      `{"name":`,
+			wantCode:  yarpcerrors.CodeInvalidArgument,
+			wantError: "could not decode the body of the request",
+		},
+		{
+			msg:       "body not an object",
+			rule:      HTTPRule{Body: "*"},
+			target:    "/",
+			body:      `[1]`,
+			wantCode:  yarpcerrors.CodeInvalidArgument,
+			wantError: "must be a JSON object",
+		},
+		{
+			msg:       "unknown variable",
+			rule:      HTTPRule{HTTPMethod: "GET", Pattern: "/v1/{foo}"},
+			target:    "/",
+			variables: map[string]string{"foo": "bar"},
+			wantCode:  yarpcerrors.CodeInternal,
+			wantError: `variable "foo" of route GET /v1/{foo} does not name a field of the request`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			newMessage := tt.newMessage
+			if newMessage == nil {
+				newMessage = func() proto.Message { return &types.Field{} }
+			}
+			tt.rule.NewRequest = newMessage
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+
+			body, err := httpRoute{tt.rule}.requestBody(req, tt.variables)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, yarpcerrors.FromError(err).Code())
+				assert.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(body))
+			assert.NoError(t, jsonpb.Unmarshal(bytes.NewReader(body), newMessage()), "request must be valid")
+		})
+	}
+}
+
+func TestHTTPRouteResponseBody(t *testing.T) {
+	tests := []struct {
+		msg          string
+		responseBody string
+		body         string
+		want         string
+	}{
+		{
+			msg:          "field",
+			responseBody: "name",
+			body:         `{"name":"foo","oneofs":["a"]}`,
+			want:         `"foo"`,
+		},
+		{
+			msg:          "field with JSON name",
+			responseBody: "source_context",
+			body:         `{"sourceContext":{"fileName":"foo.proto"}}`,
+			want:         `{"fileName":"foo.proto"}`,
+		},
+		{
+			msg:          "omitted repeated field",
+			responseBody: "oneofs",
+			body:         `{"name":"foo"}`,
+			want:         `[]`,
+		},
+		{
+			msg:          "omitted string field",
+			responseBody: "name",
+			body:         `{}`,
+			want:         `""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			route := httpRoute{HTTPRule{
+				ResponseBody: tt.responseBody,
+				NewResponse:  func() proto.Message { return &types.Type{} },
+			}}
+			got, err := route.responseBody([]byte(tt.body))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestLookupMessageField(t *testing.T) {
+	field, ok := lookupMessageField(reflect.TypeOf(&types.Value{}), "string_value")
+	require.True(t, ok, "oneof fields must be found")
+	assert.Equal(t, reflect.TypeOf(""), field.typ)
+
+	field, ok = lookupMessageField(reflect.TypeOf(&types.Value{}), "structValue")
+	require.True(t, ok, "oneof fields must be found by JSON name")
+	assert.Equal(t, reflect.TypeOf(&types.Struct{}), field.typ)
+
+	_, ok = lookupMessageField(reflect.TypeOf(&types.Value{}), "kind")
+	assert.False(t, ok, "oneofs are not fields")
+
+	_, ok = lookupMessageField(reflect.TypeOf(""), "name")
+	assert.False(t, ok)
+}
@@ -33,6 +33,7 @@ import (
 func readFromStream(
 	ctx context.Context,
 	stream transport.Stream,
+	codec *codec,
 	newMessage func() proto.Message,
 ) (proto.Message, error) {
 	streamMsg, err := stream.ReceiveMessage(ctx)
@@ -40,7 +41,7 @@ func readFromStream(
 		return nil, err
 	}
 	message := newMessage()
-	if err := unmarshal(stream.Request().Meta.Encoding, streamMsg.Body, message); err != nil {
+	if err := codec.unmarshal(stream.Request().Meta.Encoding, streamMsg.Body, message); err != nil {
 		streamMsg.Body.Close()
 		return nil, err
 	}
@@ -51,8 +52,8 @@ func readFromStream(
 }
 
 // writeToStream writes a proto.Message to a stream.
-func writeToStream(ctx context.Context, stream transport.Stream, message proto.Message) error {
-	messageData, cleanup, err := marshal(stream.Request().Meta.Encoding, message)
+func writeToStream(ctx context.Context, stream transport.Stream, codec *codec, message proto.Message) error {
+	messageData, cleanup, err := codec.marshal(stream.Request().Meta.Encoding, message)
 	if err != nil {
 		return err
 	}
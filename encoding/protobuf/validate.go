@@ -0,0 +1,208 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// ValidateRequests says to validate request messages with their Validate
+// method, as generated by protoc-gen-yarpc-go for messages whose fields are
+// annotated with the uber.yarpc.field option. Requests that fail validation
+// are rejected with an error with code yarpcerrors.CodeInvalidArgument that
+// lists the field violations.
+//
+// ValidateRequests may be given as a ClientOption to clients, which then
+// validate requests before sending them, and as a ProceduresOption to
+// BuildProcedures, whose handlers then validate requests before handling
+// them.
+//
+// 	dispatcher.Register(examplepb.BuildKeyValueYARPCProcedures(
+// 		handler,
+// 		protobuf.ValidateRequests,
+// 	))
+var ValidateRequests = validateRequests{}
+
+type validateRequests struct{}
+
+func (validateRequests) apply(client *client) {
+	client.validateRequests = true
+}
+
+func (validateRequests) applyProcedures(params *BuildProceduresParams) {
+	params.ValidateRequests = true
+}
+
+// FieldViolation describes a field whose value does not satisfy the
+// constraints declared on it.
+type FieldViolation struct {
+	// Field is the path of the field from the validated message, such as
+	// "user.emails[0]".
+	Field string
+	// Description describes the constraint that the field does not satisfy.
+	Description string
+}
+
+// ValidationError is the error returned by the Validate method of a message
+// whose fields do not satisfy the constraints declared on them.
+//
+// It is a YARPC error with code yarpcerrors.CodeInvalidArgument. Clients and
+// handlers that validate requests return it as-is, so callers, middleware and
+// interceptors in the same process may recover the violations:
+//
+// 	if verr, ok := err.(*protobuf.ValidationError); ok {
+// 		for _, violation := range verr.Violations {
+// 			...
+// 		}
+// 	}
+//
+// Transports only send its message to remote callers.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.Field + ": " + violation.Description
+	}
+	return "invalid fields: " + strings.Join(violations, "; ")
+}
+
+// YARPCError returns the YARPC error that transports report for the
+// ValidationError.
+func (e *ValidationError) YARPCError() *yarpcerrors.Status {
+	return yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "%v", e)
+}
+
+// ***all below functions should only be called by generated code***
+
+// FieldViolations accumulates the field violations found by a generated
+// Validate method.
+type FieldViolations []FieldViolation
+
+// Add records that the given field does not satisfy a constraint.
+func (v *FieldViolations) Add(field string, description string) {
+	*v = append(*v, FieldViolation{Field: field, Description: description})
+}
+
+// AddNested validates the message held by the given field, if it has a
+// Validate method, and records its violations under the path of the field.
+func (v *FieldViolations) AddNested(field string, message interface{}) {
+	validator, ok := message.(interface {
+		Validate() error
+	})
+	if !ok {
+		return
+	}
+	err := validator.Validate()
+	if err == nil {
+		return
+	}
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		v.Add(field, err.Error())
+		return
+	}
+	for _, violation := range validationErr.Violations {
+		v.Add(field+"."+violation.Field, violation.Description)
+	}
+}
+
+// Err returns a *ValidationError listing the recorded violations, or nil if
+// there are none.
+func (v FieldViolations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// ElementPath returns the path of an element of a repeated field or of a
+// map, given its index or key.
+func ElementPath(field string, key interface{}) string {
+	if s, ok := key.(string); ok {
+		return fmt.Sprintf("%s[%q]", field, s)
+	}
+	return fmt.Sprintf("%s[%v]", field, key)
+}
+
+// validateMessage calls the Validate method of the given message, if any, and
+// returns an error with code yarpcerrors.CodeInvalidArgument if it fails.
+// A *ValidationError is returned as-is so that its violations remain
+// available to the caller.
+func validateMessage(message proto.Message) error {
+	validator, ok := message.(interface {
+		Validate() error
+	})
+	if !ok {
+		return nil
+	}
+	err := validator.Validate()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*ValidationError); ok {
+		return err
+	}
+	return yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "%v", err)
+}
+
+// withUnaryValidation returns a copy of a handler built by NewUnaryHandler
+// that validates requests. Other handlers are returned as-is.
+func withUnaryValidation(handler transport.UnaryHandler) transport.UnaryHandler {
+	u, ok := handler.(*unaryHandler)
+	if !ok {
+		return handler
+	}
+	withValidation := *u
+	withValidation.validateRequests = true
+	return &withValidation
+}
+
+// withOnewayValidation returns a copy of a handler built by NewOnewayHandler
+// that validates requests. Other handlers are returned as-is.
+func withOnewayValidation(handler transport.OnewayHandler) transport.OnewayHandler {
+	o, ok := handler.(*onewayHandler)
+	if !ok {
+		return handler
+	}
+	withValidation := *o
+	withValidation.validateRequests = true
+	return &withValidation
+}
+
+// withStreamValidation returns a copy of a handler built by NewStreamHandler
+// that validates the messages it receives. Other handlers are returned as-is.
+func withStreamValidation(handler transport.StreamHandler) transport.StreamHandler {
+	s, ok := handler.(*streamHandler)
+	if !ok {
+		return handler
+	}
+	withValidation := *s
+	withValidation.validateRequests = true
+	return &withValidation
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// validatedValue is a message with a Validate method that requires its value
+// to be set, like those generated by protoc-gen-yarpc-go.
+type validatedValue struct {
+	types.StringValue
+}
+
+func (v *validatedValue) Validate() error {
+	var violations FieldViolations
+	if v.Value == "" {
+		violations.Add("value", "must be set")
+	}
+	return violations.Err()
+}
+
+type failingValidator struct{}
+
+func (failingValidator) Validate() error { return errors.New("great sadness") }
+
+func TestFieldViolations(t *testing.T) {
+	var violations FieldViolations
+	assert.NoError(t, violations.Err())
+
+	violations.Add("name", "must be set")
+	violations.AddNested("child", &validatedValue{})
+	violations.AddNested("valid", &validatedValue{types.StringValue{Value: "foo"}})
+	violations.AddNested("other", failingValidator{})
+	violations.AddNested("empty", &types.Empty{})
+
+	err := violations.Err()
+	require.Error(t, err)
+	assert.Equal(t, &ValidationError{
+		Violations: []FieldViolation{
+			{Field: "name", Description: "must be set"},
+			{Field: "child.value", Description: "must be set"},
+			{Field: "other", Description: "great sadness"},
+		},
+	}, err)
+	assert.Equal(t, "invalid fields: name: must be set; child.value: must be set; other: great sadness", err.Error())
+}
+
+func TestValidateMessage(t *testing.T) {
+	assert.NoError(t, validateMessage(&types.Empty{}))
+	assert.NoError(t, validateMessage(&validatedValue{types.StringValue{Value: "foo"}}))
+
+	err := validateMessage(&validatedValue{})
+	require.IsType(t, &ValidationError{}, err)
+	assert.Equal(t, []FieldViolation{{Field: "value", Description: "must be set"}}, err.(*ValidationError).Violations)
+	assert.True(t, yarpcerrors.IsStatus(err))
+	assert.Equal(t, "invalid fields: value: must be set", yarpcerrors.FromError(err).Message())
+}
+
+func TestElementPath(t *testing.T) {
+	assert.Equal(t, "tags[1]", ElementPath("tags", 1))
+	assert.Equal(t, "ids[42]", ElementPath("ids", int64(42)))
+	assert.Equal(t, `users["foo"]`, ElementPath("users", "foo"))
+}
+
+func TestBuildProceduresValidateRequests(t *testing.T) {
+	var called int
+	procedures := BuildProcedures(BuildProceduresParams{
+		ServiceName: "foo",
+		UnaryHandlerParams: []BuildProceduresUnaryHandlerParams{
+			{
+				MethodName: "bar",
+				Handler: NewUnaryHandler(UnaryHandlerParams{
+					Handle: func(context.Context, proto.Message) (proto.Message, error) {
+						called++
+						return &types.Empty{}, nil
+					},
+					NewRequest: func() proto.Message { return &validatedValue{} },
+				}),
+			},
+		},
+		OnewayHandlerParams: []BuildProceduresOnewayHandlerParams{
+			{
+				MethodName: "baz",
+				Handler: NewOnewayHandler(OnewayHandlerParams{
+					Handle: func(context.Context, proto.Message) error {
+						called++
+						return nil
+					},
+					NewRequest: func() proto.Message { return &validatedValue{} },
+				}),
+			},
+		},
+		Options: []ProceduresOption{ValidateRequests},
+	})
+	require.Len(t, procedures, 4)
+
+	valid, err := proto.Marshal(&types.StringValue{Value: "qux"})
+	require.NoError(t, err)
+
+	for _, p := range procedures {
+		if p.Encoding != Encoding {
+			continue
+		}
+		t.Run(p.Name, func(t *testing.T) {
+			called = 0
+			handle := func(body []byte) error {
+				request := &transport.Request{
+					Caller:    "caller",
+					Service:   "service",
+					Procedure: p.Name,
+					Encoding:  Encoding,
+					Body:      bytes.NewReader(body),
+				}
+				if p.HandlerSpec.Type() == transport.Oneway {
+					return p.HandlerSpec.Oneway().HandleOneway(context.Background(), request)
+				}
+				return p.HandlerSpec.Unary().Handle(context.Background(), request, new(transporttest.FakeResponseWriter))
+			}
+
+			err := handle(nil)
+			require.Error(t, err)
+			assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+			assert.Contains(t, err.Error(), "value: must be set")
+			require.IsType(t, &ValidationError{}, err, "violations must be recoverable")
+			assert.Equal(t, []FieldViolation{{Field: "value", Description: "must be set"}}, err.(*ValidationError).Violations)
+			assert.Equal(t, 0, called)
+
+			require.NoError(t, handle(valid))
+			assert.Equal(t, 1, called)
+		})
+	}
+}
+
+func TestClientValidateRequests(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	outbound := transporttest.NewMockUnaryOutbound(mockCtrl)
+	client := newClientFromParams(ClientParams{
+		ServiceName: "foo",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds:  transport.Outbounds{ServiceName: "service", Unary: outbound},
+		},
+		Options: []ClientOption{ValidateRequests},
+	})
+
+	_, err := client.Call(context.Background(), "bar", &validatedValue{}, newEmpty)
+	require.Error(t, err)
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+	assert.Contains(t, err.Error(), "value: must be set")
+	require.IsType(t, &ValidationError{}, err, "violations must be recoverable")
+	assert.Equal(t, []FieldViolation{{Field: "value", Description: "must be set"}}, err.(*ValidationError).Violations)
+
+	outbound.EXPECT().Call(gomock.Any(), gomock.Any()).Return(&transport.Response{}, nil)
+	_, err = client.Call(context.Background(), "bar", &validatedValue{types.StringValue{Value: "qux"}}, newEmpty)
+	require.NoError(t, err)
+}
@@ -42,4 +42,22 @@
 // 	}
 //
 // 	dispatcher.Register(raw.OnewayProcedure("RunTask", RunTask))
+//
+// Use the StreamProcedure function to build streaming procedures that
+// exchange opaque byte frames.
+//
+// 	func Pipe(stream *raw.ServerStream) error {
+// 		frame, err := stream.Receive()
+// 		// ...
+// 		return stream.Send(frame)
+// 	}
+//
+// 	dispatcher.Register(raw.StreamProcedure("pipe", Pipe))
+//
+// To open streams, build a client with NewStreamClient.
+//
+// 	client := raw.NewStreamClient(clientConfig)
+// 	stream, err := client.CallStream(ctx, "pipe", yarpc.WithHeader("k", "v"))
+// 	err = stream.Send([]byte{1, 2, 3})
+// 	frame, err := stream.Receive()
 package raw
@@ -35,6 +35,9 @@ type rawUnaryHandler struct{ UnaryHandler }
 // rawOnewayHandler adapts a Handler into a transport.OnewayHandler
 type rawOnewayHandler struct{ OnewayHandler }
 
+// rawStreamHandler adapts a Handler into a transport.StreamHandler
+type rawStreamHandler struct{ StreamHandler }
+
 func (r rawUnaryHandler) Handle(ctx context.Context, treq *transport.Request, rw transport.ResponseWriter) error {
 	if err := errors.ExpectEncodings(treq, Encoding); err != nil {
 		return err
@@ -85,3 +88,16 @@ func (r rawOnewayHandler) HandleOneway(ctx context.Context, treq *transport.Requ
 
 	return r.OnewayHandler(ctx, reqBody)
 }
+
+func (r rawStreamHandler) HandleStream(stream *transport.ServerStream) error {
+	if err := errors.ExpectEncodings(stream.Request().Meta.ToRequest(), Encoding); err != nil {
+		return err
+	}
+
+	ctx, call := encodingapi.NewInboundCallWithOptions(stream.Context(), encodingapi.DisableResponseHeaders())
+	if err := call.ReadFromRequestMeta(stream.Request().Meta); err != nil {
+		return err
+	}
+
+	return r.StreamHandler(&ServerStream{ctx: ctx, stream: stream})
+}
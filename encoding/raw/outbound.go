@@ -29,6 +29,7 @@ import (
 	encodingapi "go.uber.org/yarpc/api/encoding"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/pkg/encoding"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 // Client makes Raw requests to a single service.
@@ -40,11 +41,26 @@ type Client interface {
 	CallOneway(ctx context.Context, procedure string, body []byte, opts ...yarpc.CallOption) (transport.Ack, error)
 }
 
+// StreamClient makes Raw requests, including streaming requests, to a single
+// service.
+type StreamClient interface {
+	Client
+
+	// CallStream opens a stream of raw frames with the given procedure.
+	CallStream(ctx context.Context, procedure string, opts ...yarpc.CallOption) (*ClientStream, error)
+}
+
 // New builds a new Raw client.
 func New(c transport.ClientConfig) Client {
 	return rawClient{cc: c}
 }
 
+// NewStreamClient builds a new Raw client with streaming. The client
+// configuration must have a stream outbound to open streams.
+func NewStreamClient(c transport.ClientConfig) StreamClient {
+	return rawClient{cc: c}
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(New)
 }
@@ -110,3 +126,33 @@ func (c rawClient) CallOneway(ctx context.Context, procedure string, body []byte
 
 	return c.cc.GetOnewayOutbound().CallOneway(ctx, &treq)
 }
+
+func (c rawClient) CallStream(ctx context.Context, procedure string, opts ...yarpc.CallOption) (*ClientStream, error) {
+	streamRequest := &transport.StreamRequest{
+		Meta: &transport.RequestMeta{
+			Caller:    c.cc.Caller(),
+			Service:   c.cc.Service(),
+			Procedure: procedure,
+			Encoding:  Encoding,
+		},
+	}
+
+	call, err := encodingapi.NewStreamOutboundCall(encoding.FromOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = call.WriteToRequestMeta(ctx, streamRequest.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundConfig, ok := c.cc.(*transport.OutboundConfig)
+	if !ok || outboundConfig.Outbounds.Stream == nil {
+		return nil, yarpcerrors.InternalErrorf("no stream outbounds for client of service %q", c.cc.Service())
+	}
+	stream, err := outboundConfig.Outbounds.Stream.CallStream(ctx, streamRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientStream{stream: stream}, nil
+}
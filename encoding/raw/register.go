@@ -61,3 +61,19 @@ func OnewayProcedure(name string, handler OnewayHandler) []transport.Procedure {
 		},
 	}
 }
+
+// StreamHandler implements a single, bidirectional streaming procedure.
+//
+// Errors returned by the handler end the stream and are propagated to the
+// client.
+type StreamHandler func(*ServerStream) error
+
+// StreamProcedure builds a Procedure from the given raw stream handler.
+func StreamProcedure(name string, handler StreamHandler) []transport.Procedure {
+	return []transport.Procedure{
+		{
+			Name:        name,
+			HandlerSpec: transport.NewStreamHandlerSpec(rawStreamHandler{handler}),
+		},
+	}
+}
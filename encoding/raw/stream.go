@@ -0,0 +1,114 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package raw
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+)
+
+// ClientStream is a raw client stream that sends and receives opaque byte
+// frames.
+type ClientStream struct {
+	stream *transport.ClientStream
+}
+
+// Context returns the context of the stream.
+func (c *ClientStream) Context() context.Context {
+	return c.stream.Context()
+}
+
+// Headers returns the headers that the stream was opened with.
+func (c *ClientStream) Headers() transport.Headers {
+	return c.stream.Request().Meta.Headers
+}
+
+// Receive returns the next frame sent by the server.
+//
+// Returns io.EOF once the server closes the stream, or the error that the
+// server's handler failed with.
+func (c *ClientStream) Receive(options ...yarpc.StreamOption) ([]byte, error) {
+	return readFromStream(context.Background(), c.stream)
+}
+
+// Send sends the given frame to the server.
+func (c *ClientStream) Send(body []byte, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), c.stream, body)
+}
+
+// Close closes the stream, signaling that the client has no more frames to
+// send.
+func (c *ClientStream) Close(options ...yarpc.StreamOption) error {
+	return c.stream.Close(context.Background())
+}
+
+// ServerStream is a raw server stream that sends and receives opaque byte
+// frames.
+type ServerStream struct {
+	ctx    context.Context
+	stream *transport.ServerStream
+}
+
+// Context returns the context of the stream.
+func (s *ServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// Headers returns the headers that the client opened the stream with.
+func (s *ServerStream) Headers() transport.Headers {
+	return s.stream.Request().Meta.Headers
+}
+
+// Receive returns the next frame sent by the client.
+//
+// Returns io.EOF once the client closes the stream.
+func (s *ServerStream) Receive(options ...yarpc.StreamOption) ([]byte, error) {
+	return readFromStream(context.Background(), s.stream)
+}
+
+// Send sends the given frame to the client.
+func (s *ServerStream) Send(body []byte, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), s.stream, body)
+}
+
+// readFromStream reads the body of the next message on the stream.
+func readFromStream(ctx context.Context, stream transport.Stream) ([]byte, error) {
+	msg, err := stream.ReceiveMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Body == nil {
+		return nil, nil
+	}
+	defer msg.Body.Close()
+	return ioutil.ReadAll(msg.Body)
+}
+
+// writeToStream sends the body as a single message on the stream.
+func writeToStream(ctx context.Context, stream transport.Stream, body []byte) error {
+	return stream.SendMessage(ctx, &transport.StreamMessage{
+		Body: ioutil.NopCloser(bytes.NewReader(body)),
+	})
+}
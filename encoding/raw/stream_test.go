@@ -0,0 +1,129 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package raw
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/streamtest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func newPipeClient(procedures []transport.Procedure) StreamClient {
+	return NewStreamClient(&transport.OutboundConfig{
+		CallerName: "caller",
+		Outbounds: transport.Outbounds{
+			ServiceName: "service",
+			Stream:      streamtest.NewOutbound(procedures[0].HandlerSpec.Stream()),
+		},
+	})
+}
+
+func TestStreamEcho(t *testing.T) {
+	client := newPipeClient(StreamProcedure("echo", func(stream *ServerStream) error {
+		assert.Equal(t, "bar", stream.Headers().Items()["foo"])
+		assert.Equal(t, "bar", yarpc.CallFromContext(stream.Context()).Header("foo"))
+		for {
+			frame, err := stream.Receive()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(append(frame, frame...)); err != nil {
+				return err
+			}
+		}
+	}))
+
+	stream, err := client.CallStream(context.Background(), "echo", yarpc.WithHeader("foo", "bar"))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", stream.Headers().Items()["foo"])
+
+	for _, frame := range [][]byte{{1}, {2, 3}, {}} {
+		require.NoError(t, stream.Send(frame))
+		got, err := stream.Receive()
+		require.NoError(t, err)
+		assert.Equal(t, append(frame, frame...), got)
+	}
+
+	require.NoError(t, stream.Close())
+	_, err = stream.Receive()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamHandlerError(t *testing.T) {
+	client := newPipeClient(StreamProcedure("fail", func(stream *ServerStream) error {
+		if _, err := stream.Receive(); err != nil {
+			return err
+		}
+		return yarpcerrors.FailedPreconditionErrorf("not ready")
+	}))
+
+	stream, err := client.CallStream(context.Background(), "fail")
+	require.NoError(t, err)
+	require.NoError(t, stream.Send([]byte("hello")))
+
+	_, err = stream.Receive()
+	require.Error(t, err)
+	assert.True(t, yarpcerrors.IsFailedPrecondition(err))
+}
+
+func TestStreamHandlerInvalidEncoding(t *testing.T) {
+	serverStream, err := transport.NewServerStream(streamtest.NewStream(context.Background(),
+		&transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "procedure",
+			Encoding:  "json",
+		}},
+	))
+	require.NoError(t, err)
+
+	handler := rawStreamHandler{func(*ServerStream) error {
+		t.Fatal("handler must not be called")
+		return nil
+	}}
+	assert.Error(t, handler.HandleStream(serverStream))
+}
+
+func TestCallStreamErrors(t *testing.T) {
+	client := NewStreamClient(&transport.OutboundConfig{
+		CallerName: "caller",
+		Outbounds:  transport.Outbounds{ServiceName: "service"},
+	})
+
+	_, err := client.CallStream(context.Background(), "procedure")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no stream outbounds")
+
+	headers := make(map[string]string)
+	_, err = client.CallStream(context.Background(), "procedure", yarpc.ResponseHeaders(&headers))
+	require.Error(t, err)
+	assert.True(t, yarpcerrors.IsInvalidArgument(err))
+}
@@ -128,4 +128,54 @@
 // The option can be used like so:
 //
 // 	thriftrw --plugin "yarpc --sanitize-tchannel" myservice.thrift
+//
+// Documenting Procedures
+//
+// The `json-schema` flag generates a JSON Schema catalog of the procedures of
+// each service, next to the code generated for it: myservice.schema.json for
+// the service MyService. It lists the schemas of the arguments, result and
+// exceptions of each procedure, for tools such as API portals. Thrift plugins
+// only learn the names of the structs, unions, exceptions, enums and typedefs
+// used by a service, so the catalog refers to them by the Go type generated
+// for them.
+//
+// 	thriftrw --plugin "yarpc --json-schema" myservice.thrift
+//
+// Intercepting Calls
+//
+// Clients given the Intercept option run interceptors around each unary
+// call. Interceptors see the arguments and the result structs generated by
+// ThriftRW for the method, before they are serialized and after they are
+// deserialized, so they can log, redact or cache calls without handling
+// bytes. Unlike outbound middleware, they are installed on a single client.
+//
+// 	client := keyvalueclient.New(clientConfig, thrift.Intercept(logCalls))
+//
+// Streaming
+//
+// Functions annotated with yarpc.stream are streams rather than unary calls.
+// The annotation states which side sends many messages: "client" for a
+// stream of requests answered by a single response, "server" for a single
+// request answered by a stream of responses, and "bidi" for streams of
+// requests and responses. Streams must accept a single struct argument and
+// return a struct, and cannot throw exceptions.
+//
+// 	service KeyValue {
+// 		Change watch(1: WatchRequest request) (yarpc.stream = "server")
+// 	}
+//
+// The client package returns a typed stream client for these functions, and
+// the server interface receives a typed stream, mirroring the code generated
+// for Protobuf streams.
+//
+// 	stream, err := client.Watch(ctx, &kv.WatchRequest{Key: "foo"})
+// 	change, err := stream.Recv()
+//
+// 	func (h *handler) Watch(req *kv.WatchRequest, stream keyvalueserver.WatchStreamServer) error {
+// 		return stream.Send(&kv.Change{Key: req.Key})
+// 	}
+//
+// Each stream message holds a single struct, encoded with the protocol of the
+// client or procedure, without an envelope. Streams need a transport that
+// supports them, such as gRPC.
 package thrift
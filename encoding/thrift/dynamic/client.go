@@ -0,0 +1,230 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/thrift"
+)
+
+// Client makes requests to a Thrift service described by an IDL, taking and
+// returning JSON.
+//
+// Arguments are given as a JSON object keyed by parameter name. Thrift
+// values map to JSON as follows:
+//
+// 	bool                             boolean
+// 	byte, i16, i32, i64, double      number
+// 	string                           string
+// 	binary                           base64-encoded string
+// 	enum                             name of the item, or a number
+// 	struct, union, exception         object keyed by field name
+// 	list, set                        array
+// 	map with string keys             object
+// 	other maps                       array of {"key": ..., "value": ...}
+type Client struct {
+	service *serviceSpec
+
+	// Thrift clients for the service and the services it inherits from,
+	// keyed by service name.
+	clients map[string]thrift.Client
+}
+
+// NewClient builds a new Client for the given service declared in the main
+// file of the IDL. ClientOptions like thrift.Multiplexed and thrift.Protocol
+// are honored the same way as for generated clients.
+//
+// 	idl, err := dynamic.Load("idl/kv.thrift")
+// 	...
+// 	client, err := dynamic.NewClient(idl, "KeyValue", dispatcher.ClientConfig("keyvalue"))
+func NewClient(idl *IDL, serviceName string, c transport.ClientConfig, opts ...thrift.ClientOption) (*Client, error) {
+	service, err := idl.service(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inherited methods are called using the name of the service that
+	// declares them, like generated clients do.
+	clients := make(map[string]thrift.Client)
+	for s := service; s != nil; s = s.parent {
+		clients[s.name] = thrift.New(thrift.Config{
+			Service:      s.name,
+			ClientConfig: c,
+		}, opts...)
+	}
+	return &Client{service: service, clients: clients}, nil
+}
+
+// Call calls the given method with a JSON object holding its arguments, and
+// returns the JSON representation of its result. Calls to void methods
+// return null.
+//
+// If the method fails with one of its declared exceptions, an *Exception is
+// returned.
+//
+// 	res, err := client.Call(ctx, "getValue", []byte(`{"key": "foo"}`))
+func (c *Client) Call(ctx context.Context, method string, request []byte, opts ...yarpc.CallOption) ([]byte, error) {
+	service, function, args, err := c.args(method, request)
+	if err != nil {
+		return nil, err
+	}
+	if function.oneway {
+		return nil, fmt.Errorf("%v::%v is oneway: use CallOneway instead", service.name, method)
+	}
+
+	res, err := c.clients[service.name].Call(ctx, enveloper{
+		name:  method,
+		value: args,
+		typ:   wire.Call,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapResult(service, function, res)
+}
+
+// CallOneway calls the given oneway method with a JSON object holding its
+// arguments.
+func (c *Client) CallOneway(ctx context.Context, method string, request []byte, opts ...yarpc.CallOption) (transport.Ack, error) {
+	service, function, args, err := c.args(method, request)
+	if err != nil {
+		return nil, err
+	}
+	if !function.oneway {
+		return nil, fmt.Errorf("%v::%v is not oneway: use Call instead", service.name, method)
+	}
+
+	return c.clients[service.name].CallOneway(ctx, enveloper{
+		name:  method,
+		value: args,
+		typ:   wire.OneWay,
+	}, opts...)
+}
+
+func (c *Client) args(method string, request []byte) (*serviceSpec, *functionSpec, wire.Value, error) {
+	service, function, ok := c.service.function(method)
+	if !ok {
+		return nil, nil, wire.Value{}, fmt.Errorf("unknown method %q of service %q", method, c.service.name)
+	}
+
+	v, err := decodeJSON(request)
+	if err != nil {
+		return nil, nil, wire.Value{}, fmt.Errorf("cannot decode arguments of %v::%v: %v", service.name, method, err)
+	}
+	if v == nil {
+		v = map[string]interface{}{}
+	}
+	args, err := function.args.toWire(v)
+	if err != nil {
+		return nil, nil, wire.Value{}, fmt.Errorf("invalid arguments for %v::%v: %v", service.name, method, err)
+	}
+	return service, function, args, nil
+}
+
+func unwrapResult(service *serviceSpec, function *functionSpec, res wire.Value) ([]byte, error) {
+	if res.Type() != wire.TStruct {
+		return nil, fmt.Errorf("unexpected %v result for %v::%v", res.Type(), service.name, function.name)
+	}
+
+	var buf bytes.Buffer
+	for _, field := range res.GetStruct().Fields {
+		if function.success != nil && field.ID == function.success.id {
+			if err := function.success.spec.writeJSON(&buf, field.Value); err != nil {
+				return nil, fmt.Errorf("cannot encode result of %v::%v: %v", service.name, function.name, err)
+			}
+			return buf.Bytes(), nil
+		}
+
+		for _, exception := range function.exceptions {
+			if field.ID != exception.id {
+				continue
+			}
+			if err := exception.spec.writeJSON(&buf, field.Value); err != nil {
+				return nil, fmt.Errorf("cannot encode exception of %v::%v: %v", service.name, function.name, err)
+			}
+			exc := &Exception{Name: exception.name, JSON: buf.Bytes()}
+			if s, ok := exception.spec.(*structSpec); ok {
+				exc.Type = s.name
+			}
+			return nil, exc
+		}
+	}
+
+	if function.success != nil {
+		return nil, fmt.Errorf("result of %v::%v is empty", service.name, function.name)
+	}
+	return []byte("null"), nil
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number so
+// that 64-bit integers don't lose precision. Empty input decodes to nil.
+func decodeJSON(b []byte) (interface{}, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// Exception is returned by Client.Call when a method fails with one of the
+// exceptions it declares.
+type Exception struct {
+	// Name of the exception in the throws clause of the method.
+	Name string
+
+	// Type of the exception.
+	Type string
+
+	// JSON representation of the exception.
+	JSON []byte
+}
+
+func (e *Exception) Error() string {
+	return fmt.Sprintf("%v (%v): %s", e.Type, e.Name, e.JSON)
+}
+
+// enveloper sends a wire.Value through a thrift.Client.
+type enveloper struct {
+	name  string
+	value wire.Value
+	typ   wire.EnvelopeType
+}
+
+func (e enveloper) MethodName() string              { return e.name }
+func (e enveloper) EnvelopeType() wire.EnvelopeType { return e.typ }
+func (e enveloper) ToWire() (wire.Value, error)     { return e.value, nil }
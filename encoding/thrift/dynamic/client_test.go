@@ -0,0 +1,326 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/thrifttest"
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/encoding/thrift"
+	"go.uber.org/yarpc/internal/clientconfig"
+	"go.uber.org/yarpc/internal/testtime"
+)
+
+func valueStruct(fields ...wire.Field) wire.Value {
+	return wire.NewValueStruct(wire.Struct{Fields: fields})
+}
+
+func valueList(t wire.Type, values ...wire.Value) wire.ValueList {
+	return wire.ValueListFromSlice(t, values)
+}
+
+func point(x, y int32) wire.Value {
+	return valueStruct(
+		wire.Field{ID: 1, Value: wire.NewValueI32(x)},
+		wire.Field{ID: 2, Value: wire.NewValueI32(y)},
+	)
+}
+
+func TestCall(t *testing.T) {
+	fullItem := valueStruct(
+		wire.Field{ID: 1, Value: wire.NewValueString("abc")},
+		wire.Field{ID: 2, Value: wire.NewValueI32(6)},
+		wire.Field{ID: 3, Value: wire.NewValueSet(valueList(wire.TBinary,
+			wire.NewValueString("a"),
+			wire.NewValueString("b"),
+		))},
+		wire.Field{ID: 4, Value: wire.NewValueMap(wire.MapItemListFromSlice(wire.TBinary, wire.TI64, []wire.MapItem{
+			{Key: wire.NewValueString("x"), Value: wire.NewValueI64(9007199254740993)},
+			{Key: wire.NewValueString("y"), Value: wire.NewValueI64(-1)},
+		}))},
+		wire.Field{ID: 5, Value: wire.NewValueMap(wire.MapItemListFromSlice(wire.TI32, wire.TBinary, []wire.MapItem{
+			{Key: wire.NewValueI32(1), Value: wire.NewValueString("one")},
+		}))},
+		wire.Field{ID: 6, Value: wire.NewValueBinary([]byte("hello"))},
+		wire.Field{ID: 7, Value: wire.NewValueDouble(1.5)},
+		wire.Field{ID: 8, Value: valueStruct(
+			wire.Field{ID: 2, Value: wire.NewValueList(valueList(wire.TStruct, point(1, 2), point(3, 4)))},
+		)},
+		wire.Field{ID: 9, Value: wire.NewValueBool(true)},
+		wire.Field{ID: 10, Value: wire.NewValueI8(-8)},
+		wire.Field{ID: 11, Value: wire.NewValueI16(16)},
+	)
+	fullItemJSON := `{"id":"abc","color":"BLUE","tags":["a","b"],` +
+		`"counts":{"x":9007199254740993,"y":-1},"names":[{"key":1,"value":"one"}],` +
+		`"data":"aGVsbG8=","weight":1.5,"shape":{"polygon":[{"x":1,"y":2},{"x":3,"y":4}]},` +
+		`"enabled":true,"small":-8,"medium":16}`
+
+	tests := []struct {
+		desc    string
+		method  string
+		request string
+
+		wantProcedure string
+		wantArgs      wire.Value
+		giveResult    wire.Value
+
+		wantResponse  string
+		wantException *Exception
+		wantErr       string
+	}{
+		{
+			desc:          "inherited from included service",
+			method:        "healthy",
+			wantProcedure: "BaseService::healthy",
+			wantArgs:      valueStruct(),
+			giveResult:    valueStruct(wire.Field{ID: 0, Value: wire.NewValueBool(true)}),
+			wantResponse:  `true`,
+		},
+		{
+			desc:          "struct result",
+			method:        "get",
+			request:       `{"id": "abc"}`,
+			wantProcedure: "ReadOnlyStore::get",
+			wantArgs:      valueStruct(wire.Field{ID: 1, Value: wire.NewValueString("abc")}),
+			giveResult: valueStruct(wire.Field{ID: 0, Value: valueStruct(
+				wire.Field{ID: 1, Value: wire.NewValueString("abc")},
+				wire.Field{ID: 2, Value: wire.NewValueI32(42)},
+				wire.Field{ID: 99, Value: wire.NewValueString("unknown field")},
+			)}),
+			wantResponse: `{"id":"abc","color":42}`,
+		},
+		{
+			desc:          "exception",
+			method:        "get",
+			request:       `{"id": "abc"}`,
+			wantProcedure: "ReadOnlyStore::get",
+			wantArgs:      valueStruct(wire.Field{ID: 1, Value: wire.NewValueString("abc")}),
+			giveResult: valueStruct(wire.Field{ID: 1, Value: valueStruct(
+				wire.Field{ID: 1, Value: wire.NewValueString("abc")},
+			)}),
+			wantException: &Exception{
+				Name: "notFound",
+				Type: "NotFound",
+				JSON: []byte(`{"id":"abc"}`),
+			},
+		},
+		{
+			desc:          "empty result",
+			method:        "get",
+			request:       `{"id": "abc"}`,
+			wantProcedure: "ReadOnlyStore::get",
+			wantArgs:      valueStruct(wire.Field{ID: 1, Value: wire.NewValueString("abc")}),
+			giveResult:    valueStruct(),
+			wantErr:       "result of ReadOnlyStore::get is empty",
+		},
+		{
+			desc:          "void with all types",
+			method:        "put",
+			request:       fullItemJSON,
+			wantProcedure: "Store::put",
+			wantArgs:      valueStruct(wire.Field{ID: 1, Value: fullItem}),
+			giveResult:    valueStruct(),
+			wantResponse:  `null`,
+		},
+		{
+			desc:    "unknown method",
+			method:  "delete",
+			wantErr: `unknown method "delete" of service "Store"`,
+		},
+		{
+			desc:    "oneway",
+			method:  "forget",
+			request: `{"id": "abc"}`,
+			wantErr: "Store::forget is oneway: use CallOneway instead",
+		},
+		{
+			desc:    "invalid JSON",
+			method:  "get",
+			request: `{"id": `,
+			wantErr: "cannot decode arguments of ReadOnlyStore::get",
+		},
+		{
+			desc:    "trailing JSON",
+			method:  "get",
+			request: `{"id": "abc"} {}`,
+			wantErr: "unexpected data after JSON value",
+		},
+		{
+			desc:    "unknown argument",
+			method:  "get",
+			request: `{"key": "abc"}`,
+			wantErr: `get_args has no field "key"`,
+		},
+		{
+			desc:    "type mismatch",
+			method:  "get",
+			request: `{"id": 42}`,
+			wantErr: `field "id" of get_args: expected string but got number`,
+		},
+		{
+			desc:    "missing required field",
+			method:  "put",
+			request: `{"item": {"color": "RED"}}`,
+			wantErr: `Item is missing required field "id"`,
+		},
+		{
+			desc:    "unknown enum item",
+			method:  "put",
+			request: `{"item": {"id": "abc", "color": "PURPLE"}}`,
+			wantErr: `unknown Color item "PURPLE"`,
+		},
+		{
+			desc:    "integer overflow",
+			method:  "put",
+			request: `{"item": {"id": "abc", "small": 128}}`,
+			wantErr: `invalid i8: 128`,
+		},
+		{
+			desc:    "union without fields",
+			method:  "put",
+			request: `{"item": {"id": "abc", "shape": {}}}`,
+			wantErr: `Shape must have exactly one field set but got 0`,
+		},
+		{
+			desc:    "invalid base64",
+			method:  "put",
+			request: `{"item": {"id": "abc", "data": "!"}}`,
+			wantErr: `invalid base64 string`,
+		},
+	}
+
+	idl, err := Load("testdata/store.thrift")
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+			defer cancel()
+
+			proto := thrifttest.NewMockProtocol(mockCtrl)
+			out := transporttest.NewMockUnaryOutbound(mockCtrl)
+			if tt.wantProcedure != "" {
+				proto.EXPECT().Encode(gomock.Any(), gomock.Any()).
+					Do(func(v wire.Value, w io.Writer) {
+						assert.True(t, wire.ValuesAreEqual(tt.wantArgs, v),
+							"arguments mismatch:\nwant %v\n got %v", tt.wantArgs, v)
+					}).Return(nil)
+				out.EXPECT().Call(gomock.Any(), gomock.Any()).
+					Do(func(_ context.Context, req *transport.Request) {
+						assert.Equal(t, tt.wantProcedure, req.Procedure, "procedure mismatch")
+					}).Return(&transport.Response{Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil)
+				proto.EXPECT().Decode(gomock.Any(), wire.TStruct).Return(tt.giveResult, nil)
+			}
+
+			client, err := NewClient(idl, "Store",
+				clientconfig.MultiOutbound("caller", "service", transport.Outbounds{Unary: out}),
+				thrift.Protocol(proto))
+			require.NoError(t, err)
+
+			res, err := client.Call(ctx, tt.method, []byte(tt.request))
+			switch {
+			case tt.wantException != nil:
+				assert.Equal(t, tt.wantException, err)
+			case tt.wantErr != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantResponse, string(res))
+			}
+		})
+	}
+}
+
+func TestCallRoundTrip(t *testing.T) {
+	idl, err := Load("testdata/store.thrift")
+	require.NoError(t, err)
+	service, err := idl.service("Store")
+	require.NoError(t, err)
+	_, put, ok := service.function("put")
+	require.True(t, ok)
+
+	item := put.args.field("item").spec
+	v, err := decodeJSON([]byte(`{
+		"id": "abc",
+		"color": "GREEN",
+		"tags": ["a"],
+		"counts": {"b": 2, "a": 1},
+		"names": [{"key": 1, "value": "one"}, {"key": 2, "value": "two"}],
+		"shape": {"point": {"x": 1, "y": 2}}
+	}`))
+	require.NoError(t, err)
+
+	w, err := item.toWire(v)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, item.writeJSON(&buf, w))
+	assert.Equal(t,
+		`{"id":"abc","color":"GREEN","tags":["a"],"counts":{"a":1,"b":2},`+
+			`"names":[{"key":1,"value":"one"},{"key":2,"value":"two"}],`+
+			`"shape":{"point":{"x":1,"y":2}}}`,
+		buf.String())
+}
+
+func TestCallOneway(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	idl, err := Load("testdata/store.thrift")
+	require.NoError(t, err)
+
+	out := transporttest.NewMockOnewayOutbound(mockCtrl)
+	out.EXPECT().CallOneway(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *transport.Request) {
+			assert.Equal(t, "Store::forget", req.Procedure)
+		}).Return(nil, nil)
+
+	client, err := NewClient(idl, "Store",
+		clientconfig.MultiOutbound("caller", "service", transport.Outbounds{Oneway: out}),
+		thrift.Multiplexed)
+	require.NoError(t, err)
+
+	_, err = client.CallOneway(ctx, "forget", []byte(`{"id": "abc"}`))
+	assert.NoError(t, err)
+
+	_, err = client.CallOneway(ctx, "get", []byte(`{"id": "abc"}`))
+	assert.EqualError(t, err, "ReadOnlyStore::get is not oneway: use Call instead")
+
+	_, err = NewClient(idl, "Unknown", clientconfig.MultiOutbound("caller", "service", transport.Outbounds{}))
+	assert.EqualError(t, err, `unknown service "Unknown" in "testdata/store.thrift"`)
+}
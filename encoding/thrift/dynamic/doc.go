@@ -0,0 +1,40 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package dynamic calls Thrift procedures without generated code.
+//
+// Thrift files are parsed at runtime, along with the files they include.
+//
+// 	idl, err := dynamic.Load("idl/kv.thrift")
+// 	if err != nil {
+// 		return err
+// 	}
+//
+// Clients take the arguments of a method as a JSON object and return its
+// result as JSON, building requests the same way as generated clients.
+//
+// 	client, err := dynamic.NewClient(idl, "KeyValue", dispatcher.ClientConfig("keyvalue"))
+// 	if err != nil {
+// 		return err
+// 	}
+// 	res, err := client.Call(ctx, "getValue", []byte(`{"key": "foo"}`))
+//
+// Exceptions declared by a method are returned as *Exception errors.
+package dynamic
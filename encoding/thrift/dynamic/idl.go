@@ -0,0 +1,385 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/thriftrw/ast"
+	"go.uber.org/thriftrw/idl"
+	"go.uber.org/thriftrw/thriftreflect"
+)
+
+// IDL is a parsed Thrift file along with all files it includes.
+type IDL struct {
+	main     *program
+	programs map[string]*program // by path
+}
+
+// program is a single parsed Thrift file.
+type program struct {
+	path     string
+	includes map[string]*program // by include name
+
+	structs  map[string]*structSpec
+	enums    map[string]*enumSpec
+	typedefs map[string]*ast.Typedef
+	services map[string]*ast.Service
+
+	// compiled services, by name
+	compiled map[string]*serviceSpec
+}
+
+// Load parses the Thrift file at the given path and all files it includes,
+// directly or transitively.
+func Load(path string) (*IDL, error) {
+	return load(filepath.Clean(path), ioutil.ReadFile)
+}
+
+// LoadModule parses the Thrift file embedded in code generated by thriftrw,
+// and all files it includes.
+//
+// 	idl, err := dynamic.LoadModule(kv.ThriftModule)
+func LoadModule(module *thriftreflect.ThriftModule) (*IDL, error) {
+	files := make(map[string]string)
+	var add func(*thriftreflect.ThriftModule)
+	add = func(m *thriftreflect.ThriftModule) {
+		path := filepath.Clean(m.FilePath)
+		if _, ok := files[path]; ok {
+			return
+		}
+		files[path] = m.Raw
+		for _, include := range m.Includes {
+			add(include)
+		}
+	}
+	add(module)
+
+	return load(filepath.Clean(module.FilePath), func(path string) ([]byte, error) {
+		raw, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("module for %q not found", path)
+		}
+		return []byte(raw), nil
+	})
+}
+
+func load(path string, read func(string) ([]byte, error)) (*IDL, error) {
+	i := &IDL{programs: make(map[string]*program)}
+	main, err := i.parse(path, read, nil)
+	if err != nil {
+		return nil, err
+	}
+	i.main = main
+
+	// All named types are registered before any is resolved since Thrift
+	// files may refer to types declared later, or in files that include
+	// them back.
+	for _, p := range i.programs {
+		if err := p.compileStructs(); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func (i *IDL) parse(path string, read func(string) ([]byte, error), parents []string) (*program, error) {
+	if p, ok := i.programs[path]; ok {
+		return p, nil
+	}
+	for _, parent := range parents {
+		if parent == path {
+			return nil, fmt.Errorf("cyclic include of %q: %v", path, strings.Join(append(parents, path), " -> "))
+		}
+	}
+
+	src, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := idl.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %v", path, err)
+	}
+
+	p := &program{
+		path:     path,
+		includes: make(map[string]*program),
+		structs:  make(map[string]*structSpec),
+		enums:    make(map[string]*enumSpec),
+		typedefs: make(map[string]*ast.Typedef),
+		services: make(map[string]*ast.Service),
+		compiled: make(map[string]*serviceSpec),
+	}
+	for _, header := range tree.Headers {
+		include, ok := header.(*ast.Include)
+		if !ok {
+			continue
+		}
+		includePath := filepath.Join(filepath.Dir(path), include.Path)
+		included, err := i.parse(includePath, read, append(parents, path))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(include.Path), ".thrift")
+		p.includes[name] = included
+	}
+
+	for _, definition := range tree.Definitions {
+		switch d := definition.(type) {
+		case *ast.Struct:
+			p.structs[d.Name] = &structSpec{name: d.Name, kind: d.Type, ast: d}
+		case *ast.Enum:
+			p.enums[d.Name] = compileEnum(d)
+		case *ast.Typedef:
+			p.typedefs[d.Name] = d
+		case *ast.Service:
+			p.services[d.Name] = d
+		}
+	}
+	i.programs[path] = p
+	return p, nil
+}
+
+// Services returns the names of the services declared in the main Thrift
+// file, in lexical order.
+func (i *IDL) Services() []string {
+	names := make([]string, 0, len(i.main.services))
+	for name := range i.main.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Methods returns the names of the methods of a service declared in the main
+// Thrift file, including those it inherits, in lexical order.
+func (i *IDL) Methods(serviceName string) ([]string, error) {
+	s, err := i.service(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for ; s != nil; s = s.parent {
+		for name := range s.functions {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (i *IDL) service(name string) (*serviceSpec, error) {
+	if _, ok := i.main.services[name]; !ok {
+		return nil, fmt.Errorf("unknown service %q in %q", name, i.main.path)
+	}
+	return i.main.compileService(name, nil)
+}
+
+// lookup finds the program declaring a name that may be qualified with the
+// name of an include, like "shared.Foo".
+func (p *program) lookup(name string) (*program, string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		if included, ok := p.includes[name[:i]]; ok {
+			return included, name[i+1:]
+		}
+	}
+	return p, name
+}
+
+func (p *program) compileStructs() error {
+	for _, s := range p.structs {
+		for _, f := range s.ast.Fields {
+			field, err := p.compileField(f)
+			if err != nil {
+				return fmt.Errorf("%v in %q of %q", err, s.name, p.path)
+			}
+			s.fields = append(s.fields, field)
+		}
+	}
+	return nil
+}
+
+func (p *program) compileField(f *ast.Field) (*fieldSpec, error) {
+	spec, err := p.resolve(f.Type, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile field %q: %v", f.Name, err)
+	}
+	return &fieldSpec{
+		id:       int16(f.ID),
+		name:     f.Name,
+		spec:     spec,
+		required: f.Requiredness == ast.Required,
+	}, nil
+}
+
+func (p *program) compileService(name string, children []string) (*serviceSpec, error) {
+	if s, ok := p.compiled[name]; ok {
+		return s, nil
+	}
+	for _, child := range children {
+		if child == name {
+			return nil, fmt.Errorf("service %q inherits from itself", name)
+		}
+	}
+
+	service, ok := p.services[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q in %q", name, p.path)
+	}
+	s := &serviceSpec{name: name, functions: make(map[string]*functionSpec)}
+	if service.Parent != nil {
+		parentProgram, parentName := p.lookup(service.Parent.Name)
+		parent, err := parentProgram.compileService(parentName, append(children, name))
+		if err != nil {
+			return nil, err
+		}
+		s.parent = parent
+	}
+
+	for _, f := range service.Functions {
+		function, err := p.compileFunction(f)
+		if err != nil {
+			return nil, fmt.Errorf("%v in service %q of %q", err, name, p.path)
+		}
+		s.functions[f.Name] = function
+	}
+	p.compiled[name] = s
+	return s, nil
+}
+
+func (p *program) compileFunction(f *ast.Function) (*functionSpec, error) {
+	function := &functionSpec{
+		name:   f.Name,
+		oneway: f.OneWay,
+		args:   &structSpec{name: f.Name + "_args", kind: ast.StructType},
+	}
+	for _, param := range f.Parameters {
+		field, err := p.compileField(param)
+		if err != nil {
+			return nil, fmt.Errorf("%v of function %q", err, f.Name)
+		}
+		function.args.fields = append(function.args.fields, field)
+	}
+	if f.ReturnType != nil {
+		spec, err := p.resolve(f.ReturnType, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compile the return type of function %q: %v", f.Name, err)
+		}
+		function.success = &fieldSpec{id: 0, name: "success", spec: spec}
+	}
+	for _, exception := range f.Exceptions {
+		field, err := p.compileField(exception)
+		if err != nil {
+			return nil, fmt.Errorf("%v of function %q", err, f.Name)
+		}
+		function.exceptions = append(function.exceptions, field)
+	}
+	return function, nil
+}
+
+func compileEnum(e *ast.Enum) *enumSpec {
+	spec := &enumSpec{
+		name:    e.Name,
+		byName:  make(map[string]int32, len(e.Items)),
+		byValue: make(map[int32]string, len(e.Items)),
+	}
+	var next int32
+	for _, item := range e.Items {
+		value := next
+		if item.Value != nil {
+			value = int32(*item.Value)
+		}
+		next = value + 1
+
+		spec.byName[item.Name] = value
+		if _, ok := spec.byValue[value]; !ok {
+			spec.byValue[value] = item.Name
+		}
+	}
+	return spec
+}
+
+// resolve finds the specification for a type referenced from this program.
+// typedefs holds the typedefs being resolved to detect cycles.
+func (p *program) resolve(t ast.Type, typedefs []string) (typeSpec, error) {
+	switch t := t.(type) {
+	case ast.BaseType:
+		spec, ok := _baseTypes[t.ID]
+		if !ok {
+			return nil, fmt.Errorf("unknown base type %v", t)
+		}
+		return spec, nil
+	case ast.ListType:
+		elem, err := p.resolve(t.ValueType, typedefs)
+		if err != nil {
+			return nil, err
+		}
+		return &listSpec{elem: elem}, nil
+	case ast.SetType:
+		elem, err := p.resolve(t.ValueType, typedefs)
+		if err != nil {
+			return nil, err
+		}
+		return &listSpec{elem: elem, set: true}, nil
+	case ast.MapType:
+		key, err := p.resolve(t.KeyType, typedefs)
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.resolve(t.ValueType, typedefs)
+		if err != nil {
+			return nil, err
+		}
+		return &mapSpec{key: key, value: value}, nil
+	case ast.TypeReference:
+		return p.resolveReference(t.Name, typedefs)
+	default:
+		return nil, fmt.Errorf("unknown type %v", t)
+	}
+}
+
+func (p *program) resolveReference(name string, typedefs []string) (typeSpec, error) {
+	declaring, local := p.lookup(name)
+	if s, ok := declaring.structs[local]; ok {
+		return s, nil
+	}
+	if e, ok := declaring.enums[local]; ok {
+		return e, nil
+	}
+	typedef, ok := declaring.typedefs[local]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", name)
+	}
+
+	qualified := declaring.path + ":" + local
+	for _, t := range typedefs {
+		if t == qualified {
+			return nil, fmt.Errorf("typedef %q refers to itself", name)
+		}
+	}
+	return declaring.resolve(typedef.Type, append(typedefs, qualified))
+}
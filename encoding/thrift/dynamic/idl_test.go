@@ -0,0 +1,150 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/thriftreflect"
+)
+
+func TestLoad(t *testing.T) {
+	idl, err := Load("testdata/store.thrift")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ReadOnlyStore", "Store"}, idl.Services())
+
+	methods, err := idl.Methods("Store")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"forget", "get", "healthy", "put"}, methods)
+
+	_, err = idl.Methods("BaseService")
+	assert.EqualError(t, err, `unknown service "BaseService" in "testdata/store.thrift"`)
+}
+
+func TestLoadModule(t *testing.T) {
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		return string(b)
+	}
+	shared := &thriftreflect.ThriftModule{
+		Name:     "shared",
+		FilePath: "shared.thrift",
+		Raw:      read("shared.thrift"),
+	}
+	store := &thriftreflect.ThriftModule{
+		Name:     "store",
+		FilePath: "store.thrift",
+		Includes: []*thriftreflect.ThriftModule{shared},
+		Raw:      read("store.thrift"),
+	}
+
+	idl, err := LoadModule(store)
+	require.NoError(t, err)
+	methods, err := idl.Methods("ReadOnlyStore")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"get", "healthy"}, methods)
+
+	_, err = LoadModule(&thriftreflect.ThriftModule{
+		Name:     "store",
+		FilePath: "store.thrift",
+		Raw:      read("store.thrift"),
+	})
+	assert.EqualError(t, err, `module for "shared.thrift" not found`)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			desc:    "missing file",
+			files:   map[string]string{"main.thrift": `include "./missing.thrift"`},
+			wantErr: "missing.thrift",
+		},
+		{
+			desc:    "parse error",
+			files:   map[string]string{"main.thrift": `struct {`},
+			wantErr: "failed to parse",
+		},
+		{
+			desc: "cyclic include",
+			files: map[string]string{
+				"main.thrift":  `include "./other.thrift"`,
+				"other.thrift": `include "./main.thrift"`,
+			},
+			wantErr: "cyclic include",
+		},
+		{
+			desc:    "unknown type",
+			files:   map[string]string{"main.thrift": `struct Foo { 1: optional Bar bar }`},
+			wantErr: `cannot compile field "bar": unknown type "Bar"`,
+		},
+		{
+			desc: "typedef cycle",
+			files: map[string]string{"main.thrift": `
+				typedef Bar Foo
+				typedef Foo Bar
+				struct Baz { 1: optional Foo foo }
+			`},
+			wantErr: `typedef "Foo" refers to itself`,
+		},
+		{
+			desc: "unknown service",
+			files: map[string]string{"main.thrift": `
+				service Foo extends Bar {}
+			`},
+			wantErr: `unknown service "Bar"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "yarpcthriftdynamic")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			for name, contents := range tt.files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+			}
+
+			idl, err := Load(filepath.Join(dir, "main.thrift"))
+			if err == nil {
+				// Services are compiled lazily.
+				for _, name := range idl.Services() {
+					if _, err = idl.Methods(name); err != nil {
+						break
+					}
+				}
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+typedef string UUID
+
+enum Color {
+    RED
+    GREEN = 5
+    BLUE
+}
+
+struct Point {
+    1: required i32 x
+    2: required i32 y
+}
+
+service BaseService {
+    bool healthy()
+}
@@ -0,0 +1,34 @@
+include "./shared.thrift"
+
+union Shape {
+    1: shared.Point point
+    2: list<shared.Point> polygon
+}
+
+exception NotFound {
+    1: required shared.UUID id
+    2: optional string message
+}
+
+struct Item {
+    1: required shared.UUID id
+    2: optional shared.Color color
+    3: optional set<string> tags
+    4: optional map<string, i64> counts
+    5: optional map<i32, string> names
+    6: optional binary data
+    7: optional double weight
+    8: optional Shape shape
+    9: optional bool enabled
+    10: optional byte small
+    11: optional i16 medium
+}
+
+service ReadOnlyStore extends shared.BaseService {
+    Item get(1: shared.UUID id) throws (1: NotFound notFound)
+}
+
+service Store extends ReadOnlyStore {
+    void put(1: Item item)
+    oneway void forget(1: shared.UUID id)
+}
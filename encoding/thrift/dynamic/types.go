@@ -0,0 +1,552 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"go.uber.org/thriftrw/ast"
+	"go.uber.org/thriftrw/wire"
+)
+
+// typeSpec converts values of a Thrift type between their JSON and wire
+// representations.
+//
+// JSON values are those produced by a json.Decoder with UseNumber enabled.
+type typeSpec interface {
+	// typeCode returns the wire type of values of this type.
+	typeCode() wire.Type
+
+	// toWire converts a decoded JSON value into a wire.Value.
+	toWire(v interface{}) (wire.Value, error)
+
+	// writeJSON writes the JSON representation of a wire.Value.
+	writeJSON(buf *bytes.Buffer, w wire.Value) error
+}
+
+var _baseTypes = map[ast.BaseTypeID]typeSpec{
+	ast.BoolTypeID:   boolSpec{},
+	ast.I8TypeID:     intSpec{wire.TI8, 8},
+	ast.I16TypeID:    intSpec{wire.TI16, 16},
+	ast.I32TypeID:    intSpec{wire.TI32, 32},
+	ast.I64TypeID:    intSpec{wire.TI64, 64},
+	ast.DoubleTypeID: doubleSpec{},
+	ast.StringTypeID: stringSpec{},
+	ast.BinaryTypeID: binarySpec{},
+}
+
+type serviceSpec struct {
+	name      string
+	parent    *serviceSpec
+	functions map[string]*functionSpec
+}
+
+// function looks up a function of this service or the services it inherits
+// from, returning the service that declares it.
+func (s *serviceSpec) function(name string) (*serviceSpec, *functionSpec, bool) {
+	for ; s != nil; s = s.parent {
+		if f, ok := s.functions[name]; ok {
+			return s, f, true
+		}
+	}
+	return nil, nil, false
+}
+
+type functionSpec struct {
+	name       string
+	oneway     bool
+	args       *structSpec
+	success    *fieldSpec // nil for void functions
+	exceptions []*fieldSpec
+}
+
+type fieldSpec struct {
+	id       int16
+	name     string
+	spec     typeSpec
+	required bool
+}
+
+type boolSpec struct{}
+
+func (boolSpec) typeCode() wire.Type { return wire.TBool }
+
+func (boolSpec) toWire(v interface{}) (wire.Value, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return wire.Value{}, newTypeError("bool", v)
+	}
+	return wire.NewValueBool(b), nil
+}
+
+func (boolSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	buf.WriteString(strconv.FormatBool(w.GetBool()))
+	return nil
+}
+
+type intSpec struct {
+	t    wire.Type
+	bits int
+}
+
+func (s intSpec) typeCode() wire.Type { return s.t }
+
+func (s intSpec) toWire(v interface{}) (wire.Value, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return wire.Value{}, newTypeError("integer", v)
+	}
+	i, err := strconv.ParseInt(n.String(), 10, s.bits)
+	if err != nil {
+		return wire.Value{}, fmt.Errorf("invalid %v: %v", s.t, n)
+	}
+	switch s.t {
+	case wire.TI8:
+		return wire.NewValueI8(int8(i)), nil
+	case wire.TI16:
+		return wire.NewValueI16(int16(i)), nil
+	case wire.TI32:
+		return wire.NewValueI32(int32(i)), nil
+	default:
+		return wire.NewValueI64(i), nil
+	}
+}
+
+func (s intSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	var i int64
+	switch s.t {
+	case wire.TI8:
+		i = int64(w.GetI8())
+	case wire.TI16:
+		i = int64(w.GetI16())
+	case wire.TI32:
+		i = int64(w.GetI32())
+	default:
+		i = w.GetI64()
+	}
+	buf.WriteString(strconv.FormatInt(i, 10))
+	return nil
+}
+
+type doubleSpec struct{}
+
+func (doubleSpec) typeCode() wire.Type { return wire.TDouble }
+
+func (doubleSpec) toWire(v interface{}) (wire.Value, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return wire.Value{}, newTypeError("number", v)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return wire.Value{}, fmt.Errorf("invalid double: %v", n)
+	}
+	return wire.NewValueDouble(f), nil
+}
+
+func (doubleSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	f := w.GetDouble()
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("cannot represent %v in JSON", f)
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	return nil
+}
+
+type stringSpec struct{}
+
+func (stringSpec) typeCode() wire.Type { return wire.TBinary }
+
+func (stringSpec) toWire(v interface{}) (wire.Value, error) {
+	s, ok := v.(string)
+	if !ok {
+		return wire.Value{}, newTypeError("string", v)
+	}
+	return wire.NewValueString(s), nil
+}
+
+func (stringSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	return writeString(buf, w.GetString())
+}
+
+// binarySpec represents binary values as base64-encoded strings.
+type binarySpec struct{}
+
+func (binarySpec) typeCode() wire.Type { return wire.TBinary }
+
+func (binarySpec) toWire(v interface{}) (wire.Value, error) {
+	s, ok := v.(string)
+	if !ok {
+		return wire.Value{}, newTypeError("base64 string", v)
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return wire.Value{}, fmt.Errorf("invalid base64 string: %v", err)
+	}
+	return wire.NewValueBinary(b), nil
+}
+
+func (binarySpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	return writeString(buf, base64.StdEncoding.EncodeToString(w.GetBinary()))
+}
+
+// enumSpec represents enums by the names of their items. Numbers are accepted
+// as input, and values without a name are rendered as numbers.
+type enumSpec struct {
+	name    string
+	byName  map[string]int32
+	byValue map[int32]string
+}
+
+func (*enumSpec) typeCode() wire.Type { return wire.TI32 }
+
+func (s *enumSpec) toWire(v interface{}) (wire.Value, error) {
+	switch v := v.(type) {
+	case string:
+		i, ok := s.byName[v]
+		if !ok {
+			return wire.Value{}, fmt.Errorf("unknown %v item %q", s.name, v)
+		}
+		return wire.NewValueI32(i), nil
+	case json.Number:
+		i, err := strconv.ParseInt(v.String(), 10, 32)
+		if err != nil {
+			return wire.Value{}, fmt.Errorf("invalid %v: %v", s.name, v)
+		}
+		return wire.NewValueI32(int32(i)), nil
+	default:
+		return wire.Value{}, newTypeError(s.name, v)
+	}
+}
+
+func (s *enumSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	i := w.GetI32()
+	if name, ok := s.byValue[i]; ok {
+		return writeString(buf, name)
+	}
+	buf.WriteString(strconv.FormatInt(int64(i), 10))
+	return nil
+}
+
+// listSpec represents lists and sets as JSON arrays.
+type listSpec struct {
+	elem typeSpec
+	set  bool
+}
+
+func (s *listSpec) typeCode() wire.Type {
+	if s.set {
+		return wire.TSet
+	}
+	return wire.TList
+}
+
+func (s *listSpec) toWire(v interface{}) (wire.Value, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return wire.Value{}, newTypeError("array", v)
+	}
+	values := make([]wire.Value, len(items))
+	for i, item := range items {
+		value, err := s.elem.toWire(item)
+		if err != nil {
+			return wire.Value{}, fmt.Errorf("item %d: %v", i, err)
+		}
+		values[i] = value
+	}
+	list := wire.ValueListFromSlice(s.elem.typeCode(), values)
+	if s.set {
+		return wire.NewValueSet(list), nil
+	}
+	return wire.NewValueList(list), nil
+}
+
+func (s *listSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	var list wire.ValueList
+	if s.set {
+		list = w.GetSet()
+	} else {
+		list = w.GetList()
+	}
+	if list.ValueType() != s.elem.typeCode() && list.Size() > 0 {
+		return fmt.Errorf("expected items of type %v but got %v", s.elem.typeCode(), list.ValueType())
+	}
+
+	buf.WriteByte('[')
+	i := 0
+	err := list.ForEach(func(item wire.Value) error {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+		return s.elem.writeJSON(buf, item)
+	})
+	buf.WriteByte(']')
+	return err
+}
+
+// mapSpec represents maps with string keys as JSON objects, and other maps
+// as arrays of objects with "key" and "value" attributes.
+type mapSpec struct {
+	key   typeSpec
+	value typeSpec
+}
+
+func (*mapSpec) typeCode() wire.Type { return wire.TMap }
+
+func (s *mapSpec) stringKeys() bool {
+	_, ok := s.key.(stringSpec)
+	return ok
+}
+
+func (s *mapSpec) toWire(v interface{}) (wire.Value, error) {
+	var items []wire.MapItem
+	if s.stringKeys() {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return wire.Value{}, newTypeError("object", v)
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, err := s.value.toWire(object[key])
+			if err != nil {
+				return wire.Value{}, fmt.Errorf("value for key %q: %v", key, err)
+			}
+			items = append(items, wire.MapItem{Key: wire.NewValueString(key), Value: value})
+		}
+	} else {
+		pairs, ok := v.([]interface{})
+		if !ok {
+			return wire.Value{}, newTypeError("array of key-value objects", v)
+		}
+		for i, pair := range pairs {
+			item, err := s.itemToWire(pair)
+			if err != nil {
+				return wire.Value{}, fmt.Errorf("item %d: %v", i, err)
+			}
+			items = append(items, item)
+		}
+	}
+	return wire.NewValueMap(wire.MapItemListFromSlice(s.key.typeCode(), s.value.typeCode(), items)), nil
+}
+
+func (s *mapSpec) itemToWire(v interface{}) (wire.MapItem, error) {
+	pair, ok := v.(map[string]interface{})
+	if !ok {
+		return wire.MapItem{}, newTypeError("key-value object", v)
+	}
+	for name := range pair {
+		if name != "key" && name != "value" {
+			return wire.MapItem{}, fmt.Errorf("unknown attribute %q", name)
+		}
+	}
+	key, err := s.key.toWire(pair["key"])
+	if err != nil {
+		return wire.MapItem{}, fmt.Errorf("key: %v", err)
+	}
+	value, err := s.value.toWire(pair["value"])
+	if err != nil {
+		return wire.MapItem{}, fmt.Errorf("value: %v", err)
+	}
+	return wire.MapItem{Key: key, Value: value}, nil
+}
+
+func (s *mapSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	items := w.GetMap()
+	if items.Size() > 0 && (items.KeyType() != s.key.typeCode() || items.ValueType() != s.value.typeCode()) {
+		return fmt.Errorf("expected map<%v, %v> but got map<%v, %v>",
+			s.key.typeCode(), s.value.typeCode(), items.KeyType(), items.ValueType())
+	}
+
+	stringKeys := s.stringKeys()
+	if stringKeys {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+	i := 0
+	err := items.ForEach(func(item wire.MapItem) error {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+		if stringKeys {
+			if err := s.key.writeJSON(buf, item.Key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			return s.value.writeJSON(buf, item.Value)
+		}
+
+		buf.WriteString(`{"key":`)
+		if err := s.key.writeJSON(buf, item.Key); err != nil {
+			return err
+		}
+		buf.WriteString(`,"value":`)
+		if err := s.value.writeJSON(buf, item.Value); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+	})
+	if stringKeys {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+	return err
+}
+
+// structSpec represents structs, unions and exceptions as JSON objects keyed
+// by field name.
+type structSpec struct {
+	name   string
+	kind   ast.StructureType
+	ast    *ast.Struct
+	fields []*fieldSpec
+}
+
+func (*structSpec) typeCode() wire.Type { return wire.TStruct }
+
+func (s *structSpec) toWire(v interface{}) (wire.Value, error) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return wire.Value{}, newTypeError(s.name, v)
+	}
+
+	fields := make([]wire.Field, 0, len(object))
+	known := 0
+	for _, f := range s.fields {
+		value, ok := object[f.name]
+		if !ok {
+			if f.required {
+				return wire.Value{}, fmt.Errorf("%v is missing required field %q", s.name, f.name)
+			}
+			continue
+		}
+		known++
+		if value == nil {
+			// null is treated the same as an unset field.
+			if f.required {
+				return wire.Value{}, fmt.Errorf("%v is missing required field %q", s.name, f.name)
+			}
+			continue
+		}
+
+		w, err := f.spec.toWire(value)
+		if err != nil {
+			return wire.Value{}, fmt.Errorf("field %q of %v: %v", f.name, s.name, err)
+		}
+		fields = append(fields, wire.Field{ID: f.id, Value: w})
+	}
+
+	if known < len(object) {
+		for name := range object {
+			if s.field(name) == nil {
+				return wire.Value{}, fmt.Errorf("%v has no field %q", s.name, name)
+			}
+		}
+	}
+	if s.kind == ast.UnionType && len(fields) != 1 {
+		return wire.Value{}, fmt.Errorf("%v must have exactly one field set but got %d", s.name, len(fields))
+	}
+	return wire.NewValueStruct(wire.Struct{Fields: fields}), nil
+}
+
+func (s *structSpec) writeJSON(buf *bytes.Buffer, w wire.Value) error {
+	values := make(map[int16]wire.Value)
+	for _, f := range w.GetStruct().Fields {
+		values[f.ID] = f.Value
+	}
+
+	buf.WriteByte('{')
+	i := 0
+	for _, f := range s.fields {
+		value, ok := values[f.id]
+		if !ok || value.Type() != f.spec.typeCode() {
+			// Skip unknown fields and fields of mismatched types the same
+			// way that code generated by thriftrw does.
+			continue
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		i++
+		if err := writeString(buf, f.name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := f.spec.writeJSON(buf, value); err != nil {
+			return fmt.Errorf("field %q of %v: %v", f.name, s.name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func (s *structSpec) field(name string) *fieldSpec {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func newTypeError(want string, got interface{}) error {
+	var kind string
+	switch got.(type) {
+	case nil:
+		kind = "null"
+	case bool:
+		kind = "bool"
+	case json.Number:
+		kind = "number"
+	case string:
+		kind = "string"
+	case []interface{}:
+		kind = "array"
+	case map[string]interface{}:
+		kind = "object"
+	default:
+		kind = fmt.Sprintf("%T", got)
+	}
+	return fmt.Errorf("expected %v but got %v", want, kind)
+}
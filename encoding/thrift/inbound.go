@@ -46,6 +46,12 @@ type thriftOnewayHandler struct {
 	Enveloping    bool
 }
 
+// thriftStreamHandler wraps a Thrift Handler into a transport.StreamHandler
+type thriftStreamHandler struct {
+	StreamHandler StreamHandler
+	Protocol      protocol.Protocol
+}
+
 func (t thriftUnaryHandler) Handle(ctx context.Context, treq *transport.Request, rw transport.ResponseWriter) error {
 	buf := bufferpool.Get()
 	defer bufferpool.Put(buf)
@@ -102,6 +108,19 @@ func (t thriftOnewayHandler) HandleOneway(ctx context.Context, treq *transport.R
 	return t.OnewayHandler(ctx, reqValue)
 }
 
+func (t thriftStreamHandler) HandleStream(stream *transport.ServerStream) error {
+	if err := errors.ExpectEncodings(stream.Request().Meta.ToRequest(), Encoding); err != nil {
+		return err
+	}
+
+	ctx, call := encodingapi.NewInboundCallWithOptions(stream.Context(), encodingapi.DisableResponseHeaders())
+	if err := call.ReadFromRequestMeta(stream.Request().Meta); err != nil {
+		return err
+	}
+
+	return t.StreamHandler(&ServerStream{ctx: ctx, stream: stream, p: t.Protocol})
+}
+
 // decodeRequest is a utility shared by Unary and Oneway handlers, to decode
 // the request, regardless of enveloping.
 func decodeRequest(
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package thrift
+
+import (
+	"context"
+
+	"go.uber.org/thriftrw/envelope"
+	"go.uber.org/yarpc"
+)
+
+// UnaryInvoker calls a Thrift method with the given arguments and decodes its
+// result into result.
+type UnaryInvoker func(ctx context.Context, args envelope.Enveloper, result Message, opts ...yarpc.CallOption) error
+
+// UnaryInterceptor intercepts the unary calls made by a generated client. It
+// sees the arguments of the call before they are serialized and its result
+// after it is deserialized, and calls invoke to make the call.
+//
+// The arguments and the result are the structs generated by ThriftRW for the
+// method, like *kv.KeyValue_GetValue_Args and *kv.KeyValue_GetValue_Result.
+// Exceptions declared by the method are set on the result rather than
+// returned as errors. Interceptors may modify the arguments and the result,
+// or fill the result without calling invoke at all, for example from a cache.
+type UnaryInterceptor func(ctx context.Context, args envelope.Enveloper, result Message, invoke UnaryInvoker, opts ...yarpc.CallOption) error
+
+// Intercept is an option that installs interceptors on the unary calls of a
+// generated client. The first interceptor is the outermost one: it sees the
+// arguments first and the result last. Interceptors given with multiple
+// Intercept options run in the order of the options.
+//
+// Unlike outbound middleware, which is installed on the outbounds of a
+// dispatcher and sees serialized requests, interceptors are installed on a
+// single client and see the typed structs of the generated code.
+//
+// 	client := myserviceclient.New(clientConfig, thrift.Intercept(logCalls))
+func Intercept(interceptors ...UnaryInterceptor) ClientOption {
+	return interceptOption(interceptors)
+}
+
+type interceptOption []UnaryInterceptor
+
+func (i interceptOption) applyClientOption(c *clientConfig) {
+	c.Interceptors = append(c.Interceptors, i...)
+}
+
+// Call calls a Thrift method with the given client and decodes its result
+// into result, running the interceptors installed on the client with the
+// Intercept option.
+//
+// Code generated by the ThriftRW plugin for YARPC uses Call for unary calls.
+func Call(ctx context.Context, c Client, args envelope.Enveloper, result Message, opts ...yarpc.CallOption) error {
+	if tc, ok := c.(thriftClient); ok {
+		return tc.invoke(ctx, args, result, opts...)
+	}
+	return callUnary(c)(ctx, args, result, opts...)
+}
+
+// callUnary returns an UnaryInvoker that calls a Thrift method with the given
+// client and decodes its result.
+func callUnary(c Client) UnaryInvoker {
+	return func(ctx context.Context, args envelope.Enveloper, result Message, opts ...yarpc.CallOption) error {
+		body, err := c.Call(ctx, args, opts...)
+		if err != nil {
+			return err
+		}
+		return result.FromWire(body)
+	}
+}
+
+// chainUnaryInterceptors returns an UnaryInvoker that runs the given
+// interceptors, the first one outermost, around invoke.
+func chainUnaryInterceptors(interceptors []UnaryInterceptor, invoke UnaryInvoker) UnaryInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, args envelope.Enveloper, result Message, opts ...yarpc.CallOption) error {
+			return interceptor(ctx, args, result, next, opts...)
+		}
+	}
+	return invoke
+}
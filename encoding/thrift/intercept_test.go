@@ -0,0 +1,147 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package thrift
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/envelope"
+	"go.uber.org/thriftrw/protocol"
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/clientconfig"
+	"go.uber.org/yarpc/internal/testtime"
+)
+
+func newInterceptedClient(trans transport.UnaryOutbound, interceptors ...UnaryInterceptor) Client {
+	return New(Config{
+		Service: "MyService",
+		ClientConfig: clientconfig.MultiOutbound("caller", "service",
+			transport.Outbounds{
+				Unary: trans,
+			}),
+	}, Intercept(interceptors...))
+}
+
+func encodeItem(t *testing.T, item *streamItem) []byte {
+	v, err := item.ToWire()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, protocol.Binary.Encode(v, &buf))
+	return buf.Bytes()
+}
+
+func TestIntercept(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	trans := transporttest.NewMockUnaryOutbound(mockCtrl)
+	trans.EXPECT().Call(gomock.Any(), gomock.Any()).Return(&transport.Response{
+		Body: ioutil.NopCloser(bytes.NewReader(encodeItem(t, &streamItem{Value: 41}))),
+	}, nil)
+
+	var calls []string
+	record := func(name string) UnaryInterceptor {
+		return func(ctx context.Context, args envelope.Enveloper, result Message, invoke UnaryInvoker, opts ...yarpc.CallOption) error {
+			assert.Equal(t, "someMethod", args.MethodName())
+			calls = append(calls, name+" before")
+			err := invoke(ctx, args, result, opts...)
+			calls = append(calls, name+" after")
+			return err
+		}
+	}
+	increment := func(ctx context.Context, args envelope.Enveloper, result Message, invoke UnaryInvoker, opts ...yarpc.CallOption) error {
+		if err := invoke(ctx, args, result, opts...); err != nil {
+			return err
+		}
+		result.(*streamItem).Value++
+		return nil
+	}
+
+	client := New(Config{
+		Service: "MyService",
+		ClientConfig: clientconfig.MultiOutbound("caller", "service",
+			transport.Outbounds{
+				Unary: trans,
+			}),
+	}, Intercept(record("first"), increment), Intercept(record("second")))
+
+	var result streamItem
+	require.NoError(t, Call(ctx, client, fakeEnveloper(wire.Call), &result))
+	assert.Equal(t, int32(42), result.Value)
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, calls)
+}
+
+func TestInterceptWithoutInvoke(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	// The outbound expects no calls.
+	client := newInterceptedClient(transporttest.NewMockUnaryOutbound(mockCtrl),
+		func(ctx context.Context, args envelope.Enveloper, result Message, invoke UnaryInvoker, opts ...yarpc.CallOption) error {
+			return result.FromWire(wire.NewValueStruct(wire.Struct{Fields: []wire.Field{
+				{ID: 1, Value: wire.NewValueI32(7)},
+			}}))
+		})
+
+	var result streamItem
+	require.NoError(t, Call(ctx, client, fakeEnveloper(wire.Call), &result))
+	assert.Equal(t, int32(7), result.Value)
+}
+
+func TestInterceptError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	trans := transporttest.NewMockUnaryOutbound(mockCtrl)
+	trans.EXPECT().Call(gomock.Any(), gomock.Any()).Return(nil, errors.New("great sadness"))
+
+	var sawErr error
+	client := newInterceptedClient(trans,
+		func(ctx context.Context, args envelope.Enveloper, result Message, invoke UnaryInvoker, opts ...yarpc.CallOption) error {
+			sawErr = invoke(ctx, args, result, opts...)
+			return sawErr
+		})
+
+	var result streamItem
+	err := Call(ctx, client, fakeEnveloper(wire.Call), &result)
+	assert.EqualError(t, err, "great sadness")
+	assert.Equal(t, err, sawErr)
+}
@@ -23,9 +23,10 @@ package thrift
 import "go.uber.org/thriftrw/protocol"
 
 type clientConfig struct {
-	Protocol    protocol.Protocol
-	Enveloping  bool
-	Multiplexed bool
+	Protocol     protocol.Protocol
+	Enveloping   bool
+	Multiplexed  bool
+	Interceptors []UnaryInterceptor
 }
 
 // ClientOption customizes the behavior of a Thrift client.
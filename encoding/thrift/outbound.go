@@ -35,6 +35,7 @@ import (
 	"go.uber.org/yarpc/pkg/encoding"
 	"go.uber.org/yarpc/pkg/errors"
 	"go.uber.org/yarpc/pkg/procedure"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 // Client is a generic Thrift client. It speaks in raw Thrift payloads.
@@ -47,6 +48,20 @@ type Client interface {
 	CallOneway(ctx context.Context, reqBody envelope.Enveloper, opts ...yarpc.CallOption) (transport.Ack, error)
 }
 
+// StreamClient is a generic Thrift client that can also open streams.
+//
+// Users should use the client generated by the code generator rather than
+// using this directly.
+type StreamClient interface {
+	Client
+
+	// CallStream opens a stream with the given Thrift method.
+	//
+	// Messages are Thrift structs sent and received on the returned stream
+	// without envelopes.
+	CallStream(ctx context.Context, methodName string, opts ...yarpc.CallOption) (*ClientStream, error)
+}
+
 // Config contains the configuration for the Client.
 type Config struct {
 	// Name of the Thrift service. This is the name used in the Thrift file
@@ -74,6 +89,16 @@ func New(c Config, opts ...ClientOption) Client {
 	// So Config is really the internal config as far as consumers of the
 	// generated client are concerned.
 
+	return newThriftClient(c, opts...)
+}
+
+// NewStreamClient creates a new Thrift client that can open streams. The
+// client configuration must have a stream outbound to open streams.
+func NewStreamClient(c Config, opts ...ClientOption) StreamClient {
+	return newThriftClient(c, opts...)
+}
+
+func newThriftClient(c Config, opts ...ClientOption) thriftClient {
 	var cc clientConfig
 	for _, opt := range opts {
 		opt.applyClientOption(&cc)
@@ -91,12 +116,14 @@ func New(c Config, opts ...ClientOption) Client {
 		}
 	}
 
-	return thriftClient{
+	client := thriftClient{
 		p:             p,
 		cc:            c.ClientConfig,
 		thriftService: c.Service,
 		Enveloping:    cc.Enveloping,
 	}
+	client.invoke = chainUnaryInterceptors(cc.Interceptors, callUnary(client))
+	return client
 }
 
 type thriftClient struct {
@@ -106,6 +133,10 @@ type thriftClient struct {
 	// name of the Thrift service
 	thriftService string
 	Enveloping    bool
+
+	// invoke makes unary calls through the interceptors installed with the
+	// Intercept option.
+	invoke UnaryInvoker
 }
 
 func (c thriftClient) Call(ctx context.Context, reqBody envelope.Enveloper, opts ...yarpc.CallOption) (wire.Value, error) {
@@ -114,9 +145,8 @@ func (c thriftClient) Call(ctx context.Context, reqBody envelope.Enveloper, opts
 	//
 	// 	func (c *MyServiceClient) someMethod(ctx context.Context, arg1 Arg1Type, arg2 arg2Type, opts ...yarpc.CallOption) (returnValue, error) {
 	// 		args := myservice.SomeMethodHelper.Args(arg1, arg2)
-	// 		resBody, err := c.client.Call(ctx, args, opts...)
 	// 		var result myservice.SomeMethodResult
-	// 		if err = result.FromWire(resBody); err != nil {
+	// 		if err := thrift.Call(ctx, c.client, args, &result, opts...); err != nil {
 	// 			return nil, err
 	// 		}
 	// 		success, err := myservice.SomeMethodHelper.UnwrapResponse(&result)
@@ -192,6 +222,36 @@ func (c thriftClient) CallOneway(ctx context.Context, reqBody envelope.Enveloper
 	return out.CallOneway(ctx, treq)
 }
 
+func (c thriftClient) CallStream(ctx context.Context, methodName string, opts ...yarpc.CallOption) (*ClientStream, error) {
+	streamRequest := &transport.StreamRequest{
+		Meta: &transport.RequestMeta{
+			Caller:    c.cc.Caller(),
+			Service:   c.cc.Service(),
+			Encoding:  Encoding,
+			Procedure: procedure.ToName(c.thriftService, methodName),
+		},
+	}
+
+	call, err := encodingapi.NewStreamOutboundCall(encoding.FromOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = call.WriteToRequestMeta(ctx, streamRequest.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	outboundConfig, ok := c.cc.(*transport.OutboundConfig)
+	if !ok || outboundConfig.Outbounds.Stream == nil {
+		return nil, yarpcerrors.InternalErrorf("no stream outbounds for client of service %q", c.cc.Service())
+	}
+	stream, err := outboundConfig.Outbounds.Stream.CallStream(ctx, streamRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientStream{stream: stream, p: c.p}, nil
+}
+
 func (c thriftClient) buildTransportRequest(reqBody envelope.Enveloper) (*transport.Request, protocol.Protocol, error) {
 	proto := c.p
 	if !c.Enveloping {
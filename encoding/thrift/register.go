@@ -47,11 +47,16 @@ type UnaryHandler func(context.Context, wire.Value) (Response, error)
 // OnewayHandler is a convenience type alias for functions that act as OnewayHandlers.
 type OnewayHandler func(context.Context, wire.Value) error
 
+// StreamHandler is a convenience type alias for functions that act as
+// StreamHandlers.
+type StreamHandler func(*ServerStream) error
+
 // HandlerSpec represents the handler behind a Thrift service method.
 type HandlerSpec struct {
 	Type   transport.Type
 	Unary  UnaryHandler
 	Oneway OnewayHandler
+	Stream StreamHandler
 }
 
 // Method represents a Thrift service method.
@@ -109,6 +114,11 @@ func BuildProcedures(s Service, opts ...RegisterOption) []transport.Procedure {
 				Protocol:      proto,
 				Enveloping:    rc.Enveloping,
 			})
+		case transport.Streaming:
+			spec = transport.NewStreamHandlerSpec(thriftStreamHandler{
+				StreamHandler: method.HandlerSpec.Stream,
+				Protocol:      proto,
+			})
 		default:
 			panic(fmt.Sprintf("Invalid handler type for %T", method))
 		}
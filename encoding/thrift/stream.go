@@ -0,0 +1,156 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package thrift
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"go.uber.org/thriftrw/protocol"
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/bufferpool"
+	"go.uber.org/yarpc/pkg/errors"
+)
+
+// Message is a Thrift value that can be sent or received on a stream.
+// Structs generated by ThriftRW implement this interface.
+type Message interface {
+	ToWire() (wire.Value, error)
+	FromWire(wire.Value) error
+}
+
+// ClientStream is a Thrift-specific client stream.
+//
+// Users should use the stream clients generated by the code generator rather
+// than using this directly.
+type ClientStream struct {
+	stream *transport.ClientStream
+	p      protocol.Protocol
+}
+
+// Context returns the context of the stream.
+func (c *ClientStream) Context() context.Context {
+	return c.stream.Context()
+}
+
+// Receive decodes the next message from the stream into msg.
+//
+// Returns io.EOF once the server closes the stream.
+func (c *ClientStream) Receive(msg Message, options ...yarpc.StreamOption) error {
+	return readFromStream(context.Background(), c.stream, c.p, msg, errors.ResponseBodyDecodeError)
+}
+
+// Send encodes the given message and sends it on the stream.
+func (c *ClientStream) Send(msg Message, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), c.stream, c.p, msg, errors.RequestBodyEncodeError)
+}
+
+// Close closes the stream, signaling that the client has no more messages to
+// send.
+func (c *ClientStream) Close(options ...yarpc.StreamOption) error {
+	return c.stream.Close(context.Background())
+}
+
+// ServerStream is a Thrift-specific server stream.
+type ServerStream struct {
+	ctx    context.Context
+	stream *transport.ServerStream
+	p      protocol.Protocol
+}
+
+// Context returns the context of the stream.
+func (s *ServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// Receive decodes the next message from the stream into msg.
+//
+// Returns io.EOF once the client closes the stream.
+func (s *ServerStream) Receive(msg Message, options ...yarpc.StreamOption) error {
+	return readFromStream(context.Background(), s.stream, s.p, msg, errors.RequestBodyDecodeError)
+}
+
+// Send encodes the given message and sends it on the stream.
+func (s *ServerStream) Send(msg Message, options ...yarpc.StreamOption) error {
+	return writeToStream(context.Background(), s.stream, s.p, msg, errors.ResponseBodyEncodeError)
+}
+
+// readFromStream decodes the next message of the stream into msg.
+//
+// Every stream message holds exactly one Thrift struct, encoded without an
+// envelope.
+func readFromStream(
+	ctx context.Context,
+	stream transport.Stream,
+	p protocol.Protocol,
+	msg Message,
+	decodeError func(*transport.Request, error) error,
+) error {
+	streamMsg, err := stream.ReceiveMessage(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Thrift reads sets, maps, and lists lazily so the buffer must outlive
+	// the call to FromWire.
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+
+	if streamMsg.Body != nil {
+		_, err = buf.ReadFrom(streamMsg.Body)
+		streamMsg.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	value, err := p.Decode(bytes.NewReader(buf.Bytes()), wire.TStruct)
+	if err != nil {
+		return decodeError(stream.Request().Meta.ToRequest(), err)
+	}
+	return msg.FromWire(value)
+}
+
+// writeToStream encodes msg without an envelope and sends it as a single
+// message.
+func writeToStream(
+	ctx context.Context,
+	stream transport.Stream,
+	p protocol.Protocol,
+	msg Message,
+	encodeError func(*transport.Request, error) error,
+) error {
+	value, err := msg.ToWire()
+	if err != nil {
+		// ToWire validates the message. If it failed, we should return the
+		// error as-is because it's not an encoding error.
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := p.Encode(value, &buf); err != nil {
+		return encodeError(stream.Request().Meta.ToRequest(), err)
+	}
+	return stream.SendMessage(ctx, &transport.StreamMessage{Body: ioutil.NopCloser(&buf)})
+}
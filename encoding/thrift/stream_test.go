@@ -0,0 +1,265 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package thrift
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/protocol"
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// pipeStream is one end of an in-memory stream.
+type pipeStream struct {
+	ctx  context.Context
+	req  *transport.StreamRequest
+	recv <-chan *transport.StreamMessage
+	send chan<- *transport.StreamMessage
+	once *sync.Once
+}
+
+func (s *pipeStream) Context() context.Context          { return s.ctx }
+func (s *pipeStream) Request() *transport.StreamRequest { return s.req }
+
+func (s *pipeStream) SendMessage(_ context.Context, msg *transport.StreamMessage) error {
+	s.send <- msg
+	return nil
+}
+
+func (s *pipeStream) ReceiveMessage(context.Context) (*transport.StreamMessage, error) {
+	msg, ok := <-s.recv
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (s *pipeStream) Close(context.Context) error {
+	s.once.Do(func() { close(s.send) })
+	return nil
+}
+
+// pipeOutbound is a StreamOutbound that runs a stream handler in-process.
+type pipeOutbound struct {
+	transport.Outbound
+
+	handler transport.StreamHandler
+	wg      sync.WaitGroup
+	err     error
+}
+
+func (o *pipeOutbound) CallStream(ctx context.Context, req *transport.StreamRequest) (*transport.ClientStream, error) {
+	toServer := make(chan *transport.StreamMessage, 16)
+	toClient := make(chan *transport.StreamMessage, 16)
+	client := &pipeStream{ctx: ctx, req: req, recv: toClient, send: toServer, once: new(sync.Once)}
+	server := &pipeStream{ctx: ctx, req: req, recv: toServer, send: toClient, once: new(sync.Once)}
+
+	serverStream, err := transport.NewServerStream(server)
+	if err != nil {
+		return nil, err
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.err = o.handler.HandleStream(serverStream)
+		server.Close(ctx)
+	}()
+
+	return transport.NewClientStream(client)
+}
+
+// handlerErr waits for the server handler to return and returns its error.
+func (o *pipeOutbound) handlerErr() error {
+	o.wg.Wait()
+	return o.err
+}
+
+func newPipeClient(t *testing.T, handle StreamHandler) (StreamClient, *pipeOutbound) {
+	procedures := BuildProcedures(Service{
+		Name: "MyService",
+		Methods: []Method{
+			{
+				Name: "stream",
+				HandlerSpec: HandlerSpec{
+					Type:   transport.Streaming,
+					Stream: handle,
+				},
+			},
+		},
+	})
+	require.Len(t, procedures, 1)
+	assert.Equal(t, "MyService::stream", procedures[0].Name)
+
+	o := &pipeOutbound{handler: procedures[0].HandlerSpec.Stream()}
+	return NewStreamClient(Config{
+		Service: "MyService",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds: transport.Outbounds{
+				ServiceName: "service",
+				Stream:      o,
+			},
+		},
+	}), o
+}
+
+// streamItem is a Thrift struct with a single i32 field.
+type streamItem struct {
+	Value int32
+}
+
+func (i *streamItem) ToWire() (wire.Value, error) {
+	if i.Value < 0 {
+		return wire.Value{}, errors.New("value must not be negative")
+	}
+	return wire.NewValueStruct(wire.Struct{Fields: []wire.Field{
+		{ID: 1, Value: wire.NewValueI32(i.Value)},
+	}}), nil
+}
+
+func (i *streamItem) FromWire(v wire.Value) error {
+	for _, f := range v.GetStruct().Fields {
+		if f.ID == 1 && f.Value.Type() == wire.TI32 {
+			i.Value = f.Value.GetI32()
+		}
+	}
+	return nil
+}
+
+func TestBidirectionalStream(t *testing.T) {
+	client, outbound := newPipeClient(t, func(stream *ServerStream) error {
+		for {
+			var item streamItem
+			if err := stream.Receive(&item); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			item.Value *= 2
+			if err := stream.Send(&item); err != nil {
+				return err
+			}
+		}
+	})
+
+	stream, err := client.CallStream(context.Background(), "stream")
+	require.NoError(t, err)
+
+	for i := int32(1); i <= 3; i++ {
+		require.NoError(t, stream.Send(&streamItem{Value: i}))
+
+		var item streamItem
+		require.NoError(t, stream.Receive(&item))
+		assert.Equal(t, 2*i, item.Value)
+	}
+
+	require.NoError(t, stream.Close())
+	var item streamItem
+	assert.Equal(t, io.EOF, stream.Receive(&item))
+	assert.NoError(t, outbound.handlerErr())
+}
+
+func TestStreamSendInvalidMessage(t *testing.T) {
+	client, outbound := newPipeClient(t, func(stream *ServerStream) error {
+		var item streamItem
+		return stream.Receive(&item)
+	})
+
+	stream, err := client.CallStream(context.Background(), "stream")
+	require.NoError(t, err)
+
+	err = stream.Send(&streamItem{Value: -1})
+	assert.EqualError(t, err, "value must not be negative")
+
+	require.NoError(t, stream.Close())
+	assert.Equal(t, io.EOF, outbound.handlerErr())
+}
+
+func TestStreamReceiveDecodeError(t *testing.T) {
+	msgs := make(chan *transport.StreamMessage, 1)
+	msgs <- &transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewReader([]byte{0xff, 0xff}))}
+	close(msgs)
+
+	serverStream, err := transport.NewServerStream(&pipeStream{
+		req: &transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "MyService::stream",
+			Encoding:  Encoding,
+		}},
+		recv: msgs,
+	})
+	require.NoError(t, err)
+
+	stream := &ServerStream{ctx: context.Background(), stream: serverStream, p: protocol.Binary}
+	var item streamItem
+	err = stream.Receive(&item)
+	require.Error(t, err)
+	assert.True(t, yarpcerrors.IsInvalidArgument(err))
+}
+
+func TestStreamHandlerInvalidEncoding(t *testing.T) {
+	serverStream, err := transport.NewServerStream(&pipeStream{
+		ctx: context.Background(),
+		req: &transport.StreamRequest{Meta: &transport.RequestMeta{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "MyService::stream",
+			Encoding:  "json",
+		}},
+	})
+	require.NoError(t, err)
+
+	handler := thriftStreamHandler{
+		StreamHandler: func(*ServerStream) error {
+			t.Fatal("handler must not be called")
+			return nil
+		},
+		Protocol: protocol.Binary,
+	}
+	assert.Error(t, handler.HandleStream(serverStream))
+}
+
+func TestNoStreamOutbound(t *testing.T) {
+	client := NewStreamClient(Config{
+		Service: "MyService",
+		ClientConfig: &transport.OutboundConfig{
+			CallerName: "caller",
+			Outbounds:  transport.Outbounds{ServiceName: "service"},
+		},
+	})
+
+	_, err := client.CallStream(context.Background(), "stream")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no stream outbounds")
+}
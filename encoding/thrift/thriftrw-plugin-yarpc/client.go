@@ -49,10 +49,11 @@ type Interface interface {
 	<range .Functions>
 		<$context := import "context">
 		<.Name>(
-			ctx <$context>.Context, <range .Arguments>
+			ctx <$context>.Context, <range callArguments .>
 			<.Name> <formatType .Type>,<end>
 			opts ...<$yarpc>.CallOption,
-		)<if .OneWay> (<$yarpc>.Ack, error)
+		)<if isStream .> (<.Name>StreamClient, error)
+		<else if .OneWay> (<$yarpc>.Ack, error)
 		<else if .ReturnType> (<formatType .ReturnType>, error)
 		<else> error
 		<end>
@@ -66,7 +67,7 @@ type Interface interface {
 // 	client := <$pkgname>.New(dispatcher.ClientConfig("<lower .Name>"))
 func New(c <$transport>.ClientConfig, opts ...<$thrift>.ClientOption) Interface {
 	return client{
-		c: <$thrift>.New(<$thrift>.Config{
+		c: <$thrift>.<if .HasStreams>NewStreamClient<else>New<end>(<$thrift>.Config{
 			Service: "<.Name>",
 			ClientConfig: c,
 		}, opts...),
@@ -85,7 +86,7 @@ func init() {
 type client struct {
 	<if .Parent><import .ParentClientPackagePath>.Interface
 	<end>
-	c <$thrift>.Client
+	c <$thrift>.<if .HasStreams>StreamClient<else>Client<end>
 }
 
 <$service := .>
@@ -96,26 +97,84 @@ type client struct {
 <$prefix := printf "%s.%s_%s_" (import $module.ImportPath) $service.Name .Name>
 
 func (c client) <.Name>(
-	ctx <$context>.Context, <range .Arguments>
+	ctx <$context>.Context, <range callArguments .>
 	_<.Name> <formatType .Type>,<end>
 	opts ...<$yarpc>.CallOption,
-<if .OneWay>) (<$yarpc>.Ack, error) {
+<if isStream .>) (<.Name>StreamClient, error) {
+	stream, err := c.c.CallStream(ctx, "<.ThriftName>", opts...)
+	if err != nil {
+		return nil, err
+	}
+	<if isServerStream .>
+	if err := stream.Send(<range .Arguments>_<.Name><end>); err != nil {
+		return nil, err
+	}
+	<end>
+	return &_<.Name>StreamClient{stream: stream}, nil
+}
+
+// <.Name>StreamClient is the client side of a stream opened by <.Name>.
+type <.Name>StreamClient interface {
+	Context() <$context>.Context
+	<if isServerStream . | not>
+	Send(request *<formatType (streamRequest .)>, opts ...<$yarpc>.StreamOption) error
+	<end>
+	<if isClientStream .>
+	CloseAndRecv(opts ...<$yarpc>.StreamOption) (*<formatType (streamResponse .)>, error)
+	<else>
+	Recv(opts ...<$yarpc>.StreamOption) (*<formatType (streamResponse .)>, error)
+	CloseSend(opts ...<$yarpc>.StreamOption) error
+	<end>
+}
+
+type _<.Name>StreamClient struct {
+	stream *<$thrift>.ClientStream
+}
+
+func (s *_<.Name>StreamClient) Context() <$context>.Context {
+	return s.stream.Context()
+}
+
+<if isServerStream . | not>
+func (s *_<.Name>StreamClient) Send(request *<formatType (streamRequest .)>, opts ...<$yarpc>.StreamOption) error {
+	return s.stream.Send(request, opts...)
+}
+<end>
+
+<if isClientStream .>
+func (s *_<.Name>StreamClient) CloseAndRecv(opts ...<$yarpc>.StreamOption) (*<formatType (streamResponse .)>, error) {
+	if err := s.stream.Close(opts...); err != nil {
+		return nil, err
+	}
+	response := new(<formatType (streamResponse .)>)
+	if err := s.stream.Receive(response, opts...); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+<else>
+func (s *_<.Name>StreamClient) Recv(opts ...<$yarpc>.StreamOption) (*<formatType (streamResponse .)>, error) {
+	response := new(<formatType (streamResponse .)>)
+	if err := s.stream.Receive(response, opts...); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *_<.Name>StreamClient) CloseSend(opts ...<$yarpc>.StreamOption) error {
+	return s.stream.Close(opts...)
+}
+<end>
+<else if .OneWay>) (<$yarpc>.Ack, error) {
 	args := <$prefix>Helper.Args(<range .Arguments>_<.Name>, <end>)
 	return c.c.CallOneway(ctx, args, opts...)
 }
 <else>) (<if .ReturnType>success <formatType .ReturnType>,<end> err error) {
-	<$wire := import "go.uber.org/thriftrw/wire">
 	args := <$prefix>Helper.Args(<range .Arguments>_<.Name>, <end>)
 
 	<if $sanitize>ctx = <import "github.com/uber/tchannel-go">.WithoutHeaders(ctx)<end>
-	var body <$wire>.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result <$prefix>Result
-	if err = result.FromWire(body); err != nil {
+	if err = <$thrift>.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -66,6 +66,7 @@ func (m *MockClient) EXPECT() *_MockClientRecorder {
 	return m.recorder
 }
 
+<$service := .>
 <range .AllFunctions>
 <$context := import "context">
 <$yarpc   := import "go.uber.org/yarpc">
@@ -77,30 +78,32 @@ func (m *MockClient) EXPECT() *_MockClientRecorder {
 // 	client.EXPECT().<.Name>(gomock.Any(), ...).Return(...)
 // 	... := client.<.Name>(...)
 func (m *MockClient) <.Name>(
-	ctx <$context>.Context, <range .Arguments>
+	ctx <$context>.Context, <range callArguments .>
 	_<.Name> <formatType .Type>,<end>
 	opts ...<$yarpc>.CallOption,
-) <if .OneWay> (ack <$yarpc>.Ack, err error) {
+) <if isStream .> (stream <import ($service.FunctionClientPackagePath .)>.<.Name>StreamClient, err error) {
+  <else if .OneWay> (ack <$yarpc>.Ack, err error) {
   <else>       (<if .ReturnType>success <formatType .ReturnType>,<end> err error) {
   <end>
-	args := []interface{}{ctx,<range .Arguments> _<.Name>,<end>}
+	args := []interface{}{ctx,<range callArguments .> _<.Name>,<end>}
 	for _, o := range opts {
 		args = append(args, o)
 	}
 	i := 0
 	ret := m.ctrl.Call(m, "<.Name>", args...)
-	<if .OneWay>          ack,     _ = ret[i].(<$yarpc>.Ack); i++
+	<if isStream .>       stream,  _ = ret[i].(<import ($service.FunctionClientPackagePath .)>.<.Name>StreamClient); i++
+	<else if .OneWay>     ack,     _ = ret[i].(<$yarpc>.Ack); i++
 	<else if .ReturnType> success, _ = ret[i].(<formatType .ReturnType>); i++
 	<end>                 err,     _ = ret[i].(error)
 	return
 }
 
 func (mr *_MockClientRecorder) <.Name>(
-	ctx interface{}, <range .Arguments>
+	ctx interface{}, <range callArguments .>
 	_<.Name> interface{},<end>
 	opts ...interface{},
 ) *gomock.Call {
-	args := append([]interface{}{ctx,<range .Arguments> _<.Name>,<end>}, opts...)
+	args := append([]interface{}{ctx,<range callArguments .> _<.Name>,<end>}, opts...)
 	return mr.mock.ctrl.RecordCall(mr.mock, "<.Name>", args...)
 }
 <end>
@@ -5,7 +5,6 @@ package readonlystoreclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -57,17 +56,10 @@ func (c client) Integer(
 	_Key *string,
 	opts ...yarpc.CallOption,
 ) (success int64, err error) {
-
 	args := atomic.ReadOnlyStore_Integer_Helper.Args(_Key)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result atomic.ReadOnlyStore_Integer_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -5,7 +5,6 @@ package storeclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -70,17 +69,10 @@ func (c client) CompareAndSwap(
 	_Request *atomic.CompareAndSwap,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := atomic.Store_CompareAndSwap_Helper.Args(_Request)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result atomic.Store_CompareAndSwap_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -103,17 +95,10 @@ func (c client) Increment(
 	_Value *int64,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := atomic.Store_Increment_Helper.Args(_Key, _Value)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result atomic.Store_Increment_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
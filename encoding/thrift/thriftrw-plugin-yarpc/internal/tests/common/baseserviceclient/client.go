@@ -5,7 +5,6 @@ package baseserviceclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -49,17 +48,10 @@ func (c client) Healthy(
 	ctx context.Context,
 	opts ...yarpc.CallOption,
 ) (success bool, err error) {
-
 	args := common.BaseService_Healthy_Helper.Args()
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result common.BaseService_Healthy_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
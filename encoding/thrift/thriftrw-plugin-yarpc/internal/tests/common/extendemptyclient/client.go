@@ -5,7 +5,6 @@ package extendemptyclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -55,17 +54,10 @@ func (c client) Hello(
 	ctx context.Context,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := common.ExtendEmpty_Hello_Helper.Args()
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result common.ExtendEmpty_Hello_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -6,7 +6,6 @@ package weatherclient
 import (
 	"context"
 	tchannel "github.com/uber/tchannel-go"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -50,18 +49,11 @@ func (c client) Check(
 	ctx context.Context,
 	opts ...yarpc.CallOption,
 ) (success string, err error) {
-
 	args := weather.Weather_Check_Helper.Args()
 
 	ctx = tchannel.WithoutHeaders(ctx)
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result weather.Weather_Check_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -47,6 +47,8 @@ var (
 		"Don't generate gomock mocks for service clients")
 	_noFx             = flag.Bool("no-fx", false, "Don't generate Fx module")
 	_sanitizeTChannel = flag.Bool("sanitize-tchannel", false, "Enable tchannel context sanitization")
+	_jsonSchema       = flag.Bool("json-schema", false,
+		"Generate a JSON Schema catalog of the procedures of each service")
 )
 
 type g struct {
@@ -61,6 +63,9 @@ func (g g) Generate(req *api.GenerateServiceRequest) (*api.GenerateServiceRespon
 	if !*_noGomock {
 		generators = append(generators, gomockGenerator)
 	}
+	if *_jsonSchema {
+		generators = append(generators, schemaGenerator)
+	}
 
 	unaryWrapperImport, unaryWrapperFunc := splitFunctionPath(*_unaryHandlerWrapper)
 	onewayWrapperImport, onewayWrapperFunc := splitFunctionPath(*_onewayHandlerWrapper)
@@ -68,6 +73,10 @@ func (g g) Generate(req *api.GenerateServiceRequest) (*api.GenerateServiceRespon
 	files := make(map[string][]byte)
 	for _, serviceID := range req.RootServices {
 		svc := buildSvc(serviceID, req)
+		if err := validateStreams(svc); err != nil {
+			return nil, err
+		}
+
 		data := templateData{
 			Svc:                 svc,
 			ContextImportPath:   *_context,
@@ -0,0 +1,169 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/thriftrw/plugin/api"
+	"go.uber.org/yarpc/pkg/procedure"
+)
+
+const _jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// schemaCatalog is a JSON Schema catalog of the procedures of a service,
+// including those inherited from its parents.
+type schemaCatalog struct {
+	Schema     string             `json:"$schema"`
+	Service    string             `json:"service"`
+	Procedures []*schemaProcedure `json:"procedures"`
+}
+
+// schemaProcedure describes the arguments, result and exceptions of a
+// procedure.
+type schemaProcedure struct {
+	Name       string                 `json:"name"`
+	OneWay     bool                   `json:"oneway,omitempty"`
+	Stream     string                 `json:"stream,omitempty"`
+	Request    *jsonSchema            `json:"request"`
+	Response   *jsonSchema            `json:"response,omitempty"`
+	Exceptions map[string]*jsonSchema `json:"exceptions,omitempty"`
+}
+
+// jsonSchema is a JSON Schema describing a Thrift type.
+//
+// Plugins only learn the names of the structs, unions, exceptions, enums and
+// typedefs used by services, so these are described by the Go type generated
+// for them in x-go-type.
+type jsonSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Minimum              *int64                 `json:"minimum,omitempty"`
+	Maximum              *int64                 `json:"maximum,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	GoType               string                 `json:"x-go-type,omitempty"`
+}
+
+func schemaGenerator(data *templateData, files map[string][]byte) (err error) {
+	catalog := &schemaCatalog{
+		Schema:  _jsonSchemaDraft,
+		Service: data.Name,
+	}
+	// Inherited functions are registered as procedures of the parent service.
+	for _, svc := range append([]*Svc{data.Svc}, data.Parents...) {
+		for _, f := range svc.Functions {
+			catalog.Procedures = append(catalog.Procedures, newSchemaProcedure(svc.Name, f))
+		}
+	}
+
+	// kv.thrift => .../kv/keyvalue.schema.json
+	path := filepath.Join(data.Module.Directory, strings.ToLower(data.Name)+".schema.json")
+	files[path], err = json.MarshalIndent(catalog, "", "  ")
+	return
+}
+
+func newSchemaProcedure(serviceName string, f *api.Function) *schemaProcedure {
+	p := &schemaProcedure{
+		Name:    procedure.ToName(serviceName, f.ThriftName),
+		OneWay:  f.OneWay != nil && *f.OneWay,
+		Stream:  streamType(f),
+		Request: &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)},
+	}
+	for _, arg := range f.Arguments {
+		p.Request.Properties[arg.Name] = typeSchema(arg.Type)
+	}
+	if p.OneWay {
+		return p
+	}
+	if f.ReturnType != nil {
+		p.Response = typeSchema(f.ReturnType)
+	} else {
+		p.Response = &jsonSchema{Type: "null"}
+	}
+	if len(f.Exceptions) > 0 {
+		p.Exceptions = make(map[string]*jsonSchema, len(f.Exceptions))
+		for _, e := range f.Exceptions {
+			p.Exceptions[e.Name] = typeSchema(e.Type)
+		}
+	}
+	return p
+}
+
+func int64Ptr(i int64) *int64 { return &i }
+
+// typeSchema returns the JSON Schema of the JSON representation of the Go
+// type generated for a Thrift type.
+func typeSchema(t *api.Type) *jsonSchema {
+	switch {
+	case t.SimpleType != nil:
+		switch *t.SimpleType {
+		case api.SimpleTypeBool:
+			return &jsonSchema{Type: "boolean"}
+		case api.SimpleTypeByte, api.SimpleTypeInt8:
+			return &jsonSchema{Type: "integer", Minimum: int64Ptr(-1 << 7), Maximum: int64Ptr(1<<7 - 1)}
+		case api.SimpleTypeInt16:
+			return &jsonSchema{Type: "integer", Minimum: int64Ptr(-1 << 15), Maximum: int64Ptr(1<<15 - 1)}
+		case api.SimpleTypeInt32:
+			return &jsonSchema{Type: "integer", Format: "int32"}
+		case api.SimpleTypeInt64:
+			return &jsonSchema{Type: "integer", Format: "int64"}
+		case api.SimpleTypeFloat64:
+			return &jsonSchema{Type: "number", Format: "double"}
+		case api.SimpleTypeString:
+			return &jsonSchema{Type: "string"}
+		case api.SimpleTypeStructEmpty:
+			return &jsonSchema{Type: "object"}
+		}
+	case t.SliceType != nil:
+		// Thrift binary is a []byte, which encoding/json represents as a
+		// base64 string.
+		if s := t.SliceType.SimpleType; s != nil && *s == api.SimpleTypeByte {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+		return &jsonSchema{Type: "array", Items: typeSchema(t.SliceType)}
+	case t.KeyValueSliceType != nil:
+		return &jsonSchema{
+			Type: "array",
+			Items: &jsonSchema{
+				Type: "object",
+				Properties: map[string]*jsonSchema{
+					"Key":   typeSchema(t.KeyValueSliceType.Left),
+					"Value": typeSchema(t.KeyValueSliceType.Right),
+				},
+			},
+		}
+	case t.MapType != nil:
+		return &jsonSchema{Type: "object", AdditionalProperties: typeSchema(t.MapType.Right)}
+	case t.ReferenceType != nil:
+		return &jsonSchema{
+			Title:  t.ReferenceType.Name,
+			GoType: t.ReferenceType.ImportPath + "." + t.ReferenceType.Name,
+		}
+	case t.PointerType != nil:
+		return typeSchema(t.PointerType)
+	}
+	return &jsonSchema{}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/thriftrw/plugin/api"
+	"go.uber.org/thriftrw/ptr"
+)
+
+func simpleType(t api.SimpleType) *api.Type {
+	return &api.Type{SimpleType: &t}
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	module := &api.Module{
+		ImportPath: "go.uber.org/yarpc/kv",
+		Directory:  "kv",
+	}
+	data := &templateData{
+		Svc: &Svc{
+			Service: &api.Service{
+				Name:       "KeyValue",
+				ThriftName: "KeyValue",
+				Functions: []*api.Function{
+					{
+						Name:       "GetValue",
+						ThriftName: "getValue",
+						Arguments: []*api.Argument{
+							{Name: "Key", Type: &api.Type{PointerType: simpleType(api.SimpleTypeString)}},
+						},
+						ReturnType: simpleType(api.SimpleTypeString),
+						Exceptions: []*api.Argument{
+							{
+								Name: "DoesNotExist",
+								Type: &api.Type{PointerType: &api.Type{
+									ReferenceType: &api.TypeReference{Name: "ResourceDoesNotExist", ImportPath: "go.uber.org/yarpc/kv"},
+								}},
+							},
+						},
+					},
+					{
+						Name:       "SetValue",
+						ThriftName: "setValue",
+						Arguments: []*api.Argument{
+							{Name: "Data", Type: &api.Type{SliceType: simpleType(api.SimpleTypeByte)}},
+							{Name: "Tags", Type: &api.Type{SliceType: simpleType(api.SimpleTypeInt16)}},
+							{Name: "Counts", Type: &api.Type{MapType: &api.TypePair{
+								Left:  simpleType(api.SimpleTypeString),
+								Right: simpleType(api.SimpleTypeInt64),
+							}}},
+						},
+					},
+					{
+						Name:       "Fire",
+						ThriftName: "fire",
+						OneWay:     ptr.Bool(true),
+					},
+				},
+			},
+			Module: module,
+			Parents: []*Svc{
+				{
+					Service: &api.Service{
+						Name:       "BaseService",
+						ThriftName: "BaseService",
+						Functions: []*api.Function{
+							{
+								Name:       "Healthy",
+								ThriftName: "healthy",
+								ReturnType: simpleType(api.SimpleTypeBool),
+							},
+						},
+					},
+					Module: module,
+				},
+			},
+		},
+	}
+
+	files := make(map[string][]byte)
+	require.NoError(t, schemaGenerator(data, files))
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"service": "KeyValue",
+		"procedures": [
+			{
+				"name": "KeyValue::getValue",
+				"request": {"type": "object", "properties": {"Key": {"type": "string"}}},
+				"response": {"type": "string"},
+				"exceptions": {
+					"DoesNotExist": {"title": "ResourceDoesNotExist", "x-go-type": "go.uber.org/yarpc/kv.ResourceDoesNotExist"}
+				}
+			},
+			{
+				"name": "KeyValue::setValue",
+				"request": {
+					"type": "object",
+					"properties": {
+						"Data": {"type": "string", "format": "byte"},
+						"Tags": {"type": "array", "items": {"type": "integer", "minimum": -32768, "maximum": 32767}},
+						"Counts": {"type": "object", "additionalProperties": {"type": "integer", "format": "int64"}}
+					}
+				},
+				"response": {"type": "null"}
+			},
+			{
+				"name": "KeyValue::fire",
+				"oneway": true,
+				"request": {"type": "object"}
+			},
+			{
+				"name": "BaseService::healthy",
+				"request": {"type": "object"},
+				"response": {"type": "boolean"}
+			}
+		]
+	}`, string(files["kv/keyvalue.schema.json"]))
+}
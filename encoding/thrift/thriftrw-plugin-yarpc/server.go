@@ -52,14 +52,19 @@ type Interface interface {
 	<if .Parent><import .ParentServerPackagePath>.Interface
 	<end>
 	<range .Functions>
-		<$context := import $contextImportPath>
+		<if isStream .>
+		<.Name>(<range callArguments .>
+			<.Name> <formatType .Type>,<end>
+			stream <.Name>StreamServer,
+		)<if isClientStream .> (<formatType .ReturnType>, error)<else> error<end>
+		<else><$context := import $contextImportPath>
 		<.Name>(
 			ctx <$context>.Context, <range .Arguments>
 			<.Name> <formatType .Type>,<end>
 		)<if .OneWay> error
 		<else if .ReturnType> (<formatType .ReturnType>, error)
 		<else> error
-		<end>
+		<end><end>
 	<end>
 }
 
@@ -79,7 +84,10 @@ func New(impl Interface, opts ...<$thrift>.RegisterOption) []<$transport>.Proced
 			<$thrift>.Method{
 				Name: "<.ThriftName>",
 				HandlerSpec: <$thrift>.HandlerSpec{
-				<if .OneWay>
+				<if isStream .>
+					Type: <$transport>.Streaming,
+					Stream: <$thrift>.StreamHandler(h.<.Name>),
+				<else if .OneWay>
 					Type: <$transport>.Oneway,
 					Oneway: <import $onewayWrapperImport>.<$onewayWrapperFunc>(h.<.Name>),
 				<else>
@@ -87,7 +95,7 @@ func New(impl Interface, opts ...<$thrift>.RegisterOption) []<$transport>.Proced
 					Unary: <import $unaryWrapperImport>.<$unaryWrapperFunc>(h.<.Name>),
 				<end>
 				},
-				Signature: "<.Name>(<range $i, $v := .Arguments><if ne $i 0>, <end><.Name> <formatType .Type><end>)<if not .OneWay | and .ReturnType> (<formatType .ReturnType>)<end>",
+				Signature: "<if isStream .><.Name>(<range callArguments .><.Name> <formatType .Type>, <end>stream <.Name>StreamServer)<if isClientStream .> (<formatType .ReturnType>)<end><else><.Name>(<range $i, $v := .Arguments><if ne $i 0>, <end><.Name> <formatType .Type><end>)<if not .OneWay | and .ReturnType> (<formatType .ReturnType>)<end><end>",
 				ThriftModule: <import $module.ImportPath>.ThriftModule,
 				},
 		<end>},
@@ -104,6 +112,63 @@ type handler struct{ impl Interface }
 <$service := .>
 <$module := .Module>
 <range .Functions>
+<if isStream .>
+<$context := import "context">
+<$yarpc   := import "go.uber.org/yarpc">
+
+func (h handler) <.Name>(stream *<$thrift>.ServerStream) error {
+	<if isServerStream .>
+	request := new(<formatType (streamRequest .)>)
+	if err := stream.Receive(request); err != nil {
+		return err
+	}
+	return h.impl.<.Name>(request, &_<.Name>StreamServer{stream: stream})
+	<else if isClientStream .>
+	response, err := h.impl.<.Name>(&_<.Name>StreamServer{stream: stream})
+	if err != nil {
+		return err
+	}
+	return stream.Send(response)
+	<else>
+	return h.impl.<.Name>(&_<.Name>StreamServer{stream: stream})
+	<end>
+}
+
+// <.Name>StreamServer is the server side of a <.Name> stream.
+type <.Name>StreamServer interface {
+	Context() <$context>.Context
+	<if isServerStream . | not>
+	Recv(opts ...<$yarpc>.StreamOption) (*<formatType (streamRequest .)>, error)
+	<end>
+	<if isClientStream . | not>
+	Send(response *<formatType (streamResponse .)>, opts ...<$yarpc>.StreamOption) error
+	<end>
+}
+
+type _<.Name>StreamServer struct {
+	stream *<$thrift>.ServerStream
+}
+
+func (s *_<.Name>StreamServer) Context() <$context>.Context {
+	return s.stream.Context()
+}
+
+<if isServerStream . | not>
+func (s *_<.Name>StreamServer) Recv(opts ...<$yarpc>.StreamOption) (*<formatType (streamRequest .)>, error) {
+	request := new(<formatType (streamRequest .)>)
+	if err := s.stream.Receive(request, opts...); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+<end>
+
+<if isClientStream . | not>
+func (s *_<.Name>StreamServer) Send(response *<formatType (streamResponse .)>, opts ...<$yarpc>.StreamOption) error {
+	return s.stream.Send(response, opts...)
+}
+<end>
+<else>
 <$context := import $contextImportPath>
 <$prefix := printf "%s.%s_%s_" (import $module.ImportPath) $service.Name .Name>
 
@@ -143,6 +208,7 @@ func (h handler) <.Name>(ctx <$context>.Context, body <$wire>.Value) (<$thrift>.
 }
 <end>
 <end>
+<end>
 `
 
 func serverGenerator(data *templateData, files map[string][]byte) (err error) {
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"go.uber.org/thriftrw/plugin/api"
+)
+
+// _streamAnnotation marks a Thrift function as a stream. Its value is the
+// direction in which messages are streamed.
+//
+// 	service KeyValue {
+// 		Change watch(1: WatchRequest request) (yarpc.stream = "server")
+// 	}
+const _streamAnnotation = "yarpc.stream"
+
+const (
+	// The client sends any number of requests and the server answers with a
+	// single response.
+	_clientStream = "client"
+
+	// The client sends a single request and the server answers with any
+	// number of responses.
+	_serverStream = "server"
+
+	// The client and the server send any number of messages.
+	_bidiStream = "bidi"
+)
+
+func streamType(f *api.Function) string {
+	return f.Annotations[_streamAnnotation]
+}
+
+func isStream(f *api.Function) bool       { return streamType(f) != "" }
+func isClientStream(f *api.Function) bool { return streamType(f) == _clientStream }
+func isServerStream(f *api.Function) bool { return streamType(f) == _serverStream }
+func isBidiStream(f *api.Function) bool   { return streamType(f) == _bidiStream }
+
+// callArguments returns the arguments given to the client method of a
+// function. Client and bidirectional streams send their requests on the
+// stream instead.
+func callArguments(f *api.Function) []*api.Argument {
+	if isClientStream(f) || isBidiStream(f) {
+		return nil
+	}
+	return f.Arguments
+}
+
+// streamRequest returns the struct sent by the client of a stream.
+func streamRequest(f *api.Function) *api.Type {
+	return f.Arguments[0].Type.PointerType
+}
+
+// streamResponse returns the struct sent by the server of a stream.
+func streamResponse(f *api.Function) *api.Type {
+	return f.ReturnType.PointerType
+}
+
+// validateStreams verifies that the streams declared by a service can be
+// generated. Stream messages are sent without envelopes so streams must
+// exchange structs, and they cannot declare exceptions.
+func validateStreams(s *Svc) error {
+	for _, f := range s.Functions {
+		t := streamType(f)
+		if t == "" {
+			continue
+		}
+
+		switch t {
+		case _clientStream, _serverStream, _bidiStream:
+		default:
+			return fmt.Errorf(
+				"%v.%v: %v must be one of %q, %q or %q, not %q", s.ThriftName, f.ThriftName,
+				_streamAnnotation, _clientStream, _serverStream, _bidiStream, t)
+		}
+
+		switch {
+		case f.OneWay != nil && *f.OneWay:
+			return fmt.Errorf("%v.%v: oneway functions cannot be streams", s.ThriftName, f.ThriftName)
+		case len(f.Arguments) != 1 || !isStruct(f.Arguments[0].Type):
+			return fmt.Errorf("%v.%v: streams must accept a single struct argument", s.ThriftName, f.ThriftName)
+		case f.ReturnType == nil || !isStruct(f.ReturnType):
+			return fmt.Errorf("%v.%v: streams must return a struct", s.ThriftName, f.ThriftName)
+		case len(f.Exceptions) > 0:
+			return fmt.Errorf("%v.%v: streams cannot throw exceptions", s.ThriftName, f.ThriftName)
+		}
+	}
+	return nil
+}
+
+// isStruct returns true if the Go type generated for a Thrift type is a
+// pointer to a named type, as it is for structs, unions and exceptions.
+func isStruct(t *api.Type) bool {
+	return t.PointerType != nil && t.PointerType.ReferenceType != nil
+}
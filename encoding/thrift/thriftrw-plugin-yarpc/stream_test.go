@@ -0,0 +1,181 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/thriftrw/plugin/api"
+	"go.uber.org/thriftrw/ptr"
+)
+
+func structType(name string) *api.Type {
+	return &api.Type{PointerType: &api.Type{
+		ReferenceType: &api.TypeReference{Name: name, ImportPath: "go.uber.org/yarpc/kv"},
+	}}
+}
+
+func TestValidateStreams(t *testing.T) {
+	newStream := func(streamType string) *api.Function {
+		return &api.Function{
+			Name:        "Watch",
+			ThriftName:  "watch",
+			Arguments:   []*api.Argument{{Name: "Request", Type: structType("WatchRequest")}},
+			ReturnType:  structType("Change"),
+			Annotations: map[string]string{"yarpc.stream": streamType},
+		}
+	}
+
+	tests := []struct {
+		desc     string
+		function *api.Function
+		wantErr  string
+	}{
+		{
+			desc: "unary",
+			function: &api.Function{
+				Name:       "GetValue",
+				ThriftName: "getValue",
+				Arguments:  []*api.Argument{{Name: "Key", Type: &api.Type{PointerType: simpleType(api.SimpleTypeString)}}},
+				ReturnType: simpleType(api.SimpleTypeString),
+			},
+		},
+		{desc: "client stream", function: newStream("client")},
+		{desc: "server stream", function: newStream("server")},
+		{desc: "bidi stream", function: newStream("bidi")},
+		{
+			desc:     "unknown stream type",
+			function: newStream("both"),
+			wantErr:  `KeyValue.watch: yarpc.stream must be one of "client", "server" or "bidi", not "both"`,
+		},
+		{
+			desc: "oneway",
+			function: func() *api.Function {
+				f := newStream("client")
+				f.OneWay = ptr.Bool(true)
+				f.ReturnType = nil
+				return f
+			}(),
+			wantErr: "KeyValue.watch: oneway functions cannot be streams",
+		},
+		{
+			desc: "no arguments",
+			function: func() *api.Function {
+				f := newStream("server")
+				f.Arguments = nil
+				return f
+			}(),
+			wantErr: "KeyValue.watch: streams must accept a single struct argument",
+		},
+		{
+			desc: "not a struct argument",
+			function: func() *api.Function {
+				f := newStream("server")
+				f.Arguments[0].Type = &api.Type{PointerType: simpleType(api.SimpleTypeString)}
+				return f
+			}(),
+			wantErr: "KeyValue.watch: streams must accept a single struct argument",
+		},
+		{
+			desc: "no return type",
+			function: func() *api.Function {
+				f := newStream("bidi")
+				f.ReturnType = nil
+				return f
+			}(),
+			wantErr: "KeyValue.watch: streams must return a struct",
+		},
+		{
+			desc: "exceptions",
+			function: func() *api.Function {
+				f := newStream("bidi")
+				f.Exceptions = []*api.Argument{{Name: "DoesNotExist", Type: structType("ResourceDoesNotExist")}}
+				return f
+			}(),
+			wantErr: "KeyValue.watch: streams cannot throw exceptions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			svc := &Svc{Service: &api.Service{
+				Name:       "KeyValue",
+				ThriftName: "KeyValue",
+				Functions:  []*api.Function{tt.function},
+			}}
+
+			err := validateStreams(svc)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCallArguments(t *testing.T) {
+	args := []*api.Argument{{Name: "Request", Type: structType("WatchRequest")}}
+	tests := []struct {
+		streamType string
+		want       []*api.Argument
+	}{
+		{streamType: "", want: args},
+		{streamType: "server", want: args},
+		{streamType: "client"},
+		{streamType: "bidi"},
+	}
+
+	for _, tt := range tests {
+		f := &api.Function{Arguments: args, Annotations: map[string]string{"yarpc.stream": tt.streamType}}
+		assert.Equal(t, tt.want, callArguments(f), "stream type %q", tt.streamType)
+	}
+}
+
+func TestFunctionClientPackagePath(t *testing.T) {
+	module := &api.Module{ImportPath: "go.uber.org/yarpc/kv"}
+	healthy := &api.Function{Name: "Healthy", ThriftName: "healthy"}
+	watch := newTestStream("Watch")
+	svc := &Svc{
+		Service: &api.Service{Name: "KeyValue", Functions: []*api.Function{watch}},
+		Module:  module,
+		Parents: []*Svc{{
+			Service: &api.Service{Name: "Base", Functions: []*api.Function{healthy}},
+			Module:  module,
+		}},
+	}
+
+	assert.Equal(t, "go.uber.org/yarpc/kv/keyvalueclient", svc.FunctionClientPackagePath(watch))
+	assert.Equal(t, "go.uber.org/yarpc/kv/baseclient", svc.FunctionClientPackagePath(healthy))
+	assert.True(t, svc.HasStreams())
+	assert.False(t, svc.Parents[0].HasStreams())
+}
+
+func newTestStream(name string) *api.Function {
+	return &api.Function{
+		Name:        name,
+		ThriftName:  name,
+		Arguments:   []*api.Argument{{Name: "Request", Type: structType("Request")}},
+		ReturnType:  structType("Response"),
+		Annotations: map[string]string{"yarpc.stream": "bidi"},
+	}
+}
@@ -62,6 +62,30 @@ func (s *Svc) AllFunctions() []*api.Function {
 	return functions
 }
 
+// HasStreams returns true if this service declares streams.
+func (s *Svc) HasStreams() bool {
+	for _, f := range s.Functions {
+		if isStream(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// FunctionClientPackagePath returns the import path to the client package of
+// the service, this one or one of its parents, that declares the given
+// function.
+func (s *Svc) FunctionClientPackagePath(f *api.Function) string {
+	for _, svc := range s.Parents {
+		for _, parentFunction := range svc.Functions {
+			if parentFunction == f {
+				return svc.ClientPackagePath()
+			}
+		}
+	}
+	return s.ClientPackagePath()
+}
+
 // Parent returns the immediate parent of this service or nil if it doesn't
 // have any.
 func (s *Svc) Parent() *api.Service {
@@ -134,4 +158,11 @@ type genFunc func(*templateData, map[string][]byte) error
 // Default options for the template
 var templateOptions = []plugin.TemplateOption{
 	plugin.TemplateFunc("lower", strings.ToLower),
+	plugin.TemplateFunc("isStream", isStream),
+	plugin.TemplateFunc("isClientStream", isClientStream),
+	plugin.TemplateFunc("isServerStream", isServerStream),
+	plugin.TemplateFunc("isBidiStream", isBidiStream),
+	plugin.TemplateFunc("callArguments", callArguments),
+	plugin.TemplateFunc("streamRequest", streamRequest),
+	plugin.TemplateFunc("streamResponse", streamResponse),
 }
@@ -30,8 +30,9 @@ protoc_with_imports() {
   protoc \
     -I vendor \
     -I vendor/github.com/gogo/protobuf/protobuf \
+    -I vendor/github.com/gogo/googleapis \
     -I . \
-    "--${1}_out=${2}Mgoogle/protobuf/descriptor.proto=github.com/gogo/protobuf/protoc-gen-gogo/descriptor,Mgogoproto/gogo.proto=github.com/gogo/protobuf/gogoproto,Myarpcproto/yarpc.proto=go.uber.org/yarpc/yarpcproto:." \
+    "--${1}_out=${2}Mgoogle/protobuf/descriptor.proto=github.com/gogo/protobuf/protoc-gen-gogo/descriptor,Mgogoproto/gogo.proto=github.com/gogo/protobuf/gogoproto,Myarpcproto/yarpc.proto=go.uber.org/yarpc/yarpcproto,Myarpcproto/options.proto=go.uber.org/yarpc/yarpcproto,Mgoogle/api/annotations.proto=github.com/gogo/googleapis/google/api,Mgoogle/api/http.proto=github.com/gogo/googleapis/google/api:." \
   "${@:3}"
 }
 
@@ -43,8 +44,14 @@ protoc_go_grpc() {
   protoc_with_imports "gogoslick" "plugins=grpc," $@
 }
 
+# protoc-gen-yarpc-go needs the import paths of the files it generates code
+# for, to import them from their test packages.
 protoc_yarpc_go() {
-  protoc_with_imports "yarpc-go" "" $@
+  local mappings=""
+  for f in "$@"; do
+    mappings="${mappings}M${f}=go.uber.org/yarpc/$(dirname "${f}"),"
+  done
+  protoc_with_imports "yarpc-go" "${mappings}" $@
 }
 
 protoc_all() {
@@ -101,12 +108,15 @@ thrift-gen --generateThrift --outputDir internal/crossdock/thrift/gen-go --input
 thrift --gen go:thrift_import=github.com/apache/thrift/lib/go/thrift --out internal/crossdock/thrift/gen-go internal/crossdock/thrift/gauntlet_apache.thrift | strip_thrift_warnings
 
 protoc_go yarpcproto/yarpc.proto
+protoc_go yarpcproto/options.proto
 protoc_all internal/examples/protobuf/examplepb/example.proto
 protoc_all internal/crossdock/crossdockpb/crossdock.proto
 protoc_all \
   encoding/protobuf/protoc-gen-yarpc-go/internal/testing/dep.proto \
   encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing.proto \
-  encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing_no_service.proto
+  encoding/protobuf/protoc-gen-yarpc-go/internal/testing/testing_no_service.proto \
+  encoding/protobuf/protoc-gen-yarpc-go/internal/testing/validate.proto \
+  encoding/protobuf/protoc-gen-yarpc-go/internal/testing/routes.proto
 protoc_all internal/examples/streaming/stream.proto
 
 ragel -Z -G2 -o internal/interpolate/parse.go internal/interpolate/parse.rl
@@ -61,11 +61,13 @@ type EchoYARPCServer interface {
 }
 
 // BuildEchoYARPCProcedures prepares an implementation of the Echo service for YARPC registration.
-func BuildEchoYARPCProcedures(server EchoYARPCServer) []transport.Procedure {
+func BuildEchoYARPCProcedures(server EchoYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_EchoYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
-			ServiceName: "uber.yarpc.internal.crossdock.Echo",
+			ServiceName:     "uber.yarpc.internal.crossdock.Echo",
+			FileDescriptors: yarpcFileDescriptorClosure6acfd671bab786d8,
+			Options:         options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 				{
 					MethodName: "Echo",
@@ -150,10 +152,10 @@ type FxEchoYARPCProceduresResult struct {
 //    crossdockpb.NewFxEchoYARPCProcedures(),
 //    ...
 //  )
-func NewFxEchoYARPCProcedures() interface{} {
+func NewFxEchoYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxEchoYARPCProceduresParams) FxEchoYARPCProceduresResult {
 		return FxEchoYARPCProceduresResult{
-			Procedures: BuildEchoYARPCProcedures(params.Server),
+			Procedures: BuildEchoYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -229,11 +231,13 @@ type OnewayYARPCServer interface {
 }
 
 // BuildOnewayYARPCProcedures prepares an implementation of the Oneway service for YARPC registration.
-func BuildOnewayYARPCProcedures(server OnewayYARPCServer) []transport.Procedure {
+func BuildOnewayYARPCProcedures(server OnewayYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_OnewayYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
 			ServiceName:        "uber.yarpc.internal.crossdock.Oneway",
+			FileDescriptors:    yarpcFileDescriptorClosure6acfd671bab786d8,
+			Options:            options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{},
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{
 				{
@@ -318,10 +322,10 @@ type FxOnewayYARPCProceduresResult struct {
 //    crossdockpb.NewFxOnewayYARPCProcedures(),
 //    ...
 //  )
-func NewFxOnewayYARPCProcedures() interface{} {
+func NewFxOnewayYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxOnewayYARPCProceduresParams) FxOnewayYARPCProceduresResult {
 		return FxOnewayYARPCProceduresResult{
-			Procedures: BuildOnewayYARPCProcedures(params.Server),
+			Procedures: BuildOnewayYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -363,6 +367,40 @@ var (
 	emptyOnewayServiceEchoYARPCResponse = &yarpcproto.Oneway{}
 )
 
+var yarpcFileDescriptorClosure6acfd671bab786d8 = [][]byte{
+	// internal/crossdock/crossdockpb/crossdock.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0xcb, 0xcc, 0x2b, 0x49,
+		0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x4f, 0x2e, 0xca, 0x2f, 0x2e, 0x4e, 0xc9, 0x4f, 0xce, 0x46, 0xb0,
+		0x0a, 0x92, 0x10, 0x6c, 0xbd, 0x82, 0xa2, 0xfc, 0x92, 0x7c, 0x21, 0xd9, 0xd2, 0xa4, 0xd4, 0x22,
+		0xbd, 0xca, 0xc4, 0xa2, 0x82, 0x64, 0xb8, 0x56, 0x3d, 0xb8, 0x22, 0x29, 0x31, 0xb0, 0x0c, 0x58,
+		0xa9, 0x3e, 0x44, 0x11, 0x98, 0xad, 0x24, 0xc5, 0xc5, 0x12, 0x90, 0x99, 0x97, 0x2e, 0x24, 0xc4,
+		0xc5, 0x92, 0x94, 0x9a, 0x5a, 0x20, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x19, 0x04, 0x66, 0x83, 0xe5,
+		0xf2, 0xa1, 0x72, 0xf9, 0xf9, 0x08, 0xb9, 0xfc, 0xfc, 0x02, 0x25, 0x59, 0x2e, 0xd6, 0x90, 0xfc,
+		0xec, 0xd4, 0x3c, 0x21, 0x11, 0x2e, 0xd6, 0xb2, 0xc4, 0x9c, 0xd2, 0x54, 0xa8, 0x2c, 0x84, 0x63,
+		0x14, 0xc1, 0xc5, 0xe2, 0x9a, 0x9c, 0x91, 0x2f, 0x14, 0x00, 0xa5, 0x95, 0xf5, 0xf0, 0x3a, 0x4f,
+		0x0f, 0xe4, 0x06, 0x29, 0x82, 0x8a, 0xf2, 0xf3, 0xd2, 0x8d, 0xbc, 0xb8, 0xd8, 0xfc, 0xf3, 0x52,
+		0xcb, 0x13, 0x2b, 0x85, 0x1c, 0xa0, 0x66, 0xab, 0x10, 0xd0, 0x06, 0x76, 0xa7, 0x94, 0x10, 0xb2,
+		0x2a, 0x88, 0x09, 0x4e, 0x96, 0x17, 0x1e, 0xca, 0x31, 0xdc, 0x78, 0x28, 0xc7, 0xf0, 0xe1, 0xa1,
+		0x1c, 0x63, 0xc3, 0x23, 0x39, 0xc6, 0x15, 0x8f, 0xe4, 0x18, 0x4f, 0x3c, 0x92, 0x63, 0xbc, 0xf0,
+		0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x17, 0x8f, 0xe4, 0x18, 0x3e, 0x3c, 0x92, 0x63, 0x9c,
+		0xf0, 0x58, 0x8e, 0x21, 0x8a, 0x1b, 0x29, 0xf8, 0x93, 0xd8, 0xc0, 0xc1, 0x67, 0x0c, 0x18, 0x00,
+		0x54, 0xff, 0x2f, 0xb2, 0xa7, 0x01, 0x00, 0x00,
+	},
+	// yarpcproto/yarpc.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xab, 0x4c, 0x2c, 0x2a,
+		0x48, 0x2e, 0x28, 0xca, 0x2f, 0xc9, 0xd7, 0x07, 0x33, 0xf5, 0xc0, 0x6c, 0x21, 0xae, 0xd2, 0xa4,
+		0xd4, 0x22, 0x3d, 0xb0, 0x88, 0x92, 0x14, 0x17, 0x9b, 0x7f, 0x5e, 0x6a, 0x79, 0x62, 0xa5, 0x90,
+		0x00, 0x17, 0x73, 0x62, 0x72, 0xb6, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x47, 0x10, 0x88, 0xe9, 0x64,
+		0x71, 0xe1, 0xa1, 0x1c, 0xc3, 0x8d, 0x87, 0x72, 0x0c, 0x1f, 0x1e, 0xca, 0x31, 0x36, 0x3c, 0x92,
+		0x63, 0x5c, 0xf1, 0x48, 0x8e, 0xf1, 0xc4, 0x23, 0x39, 0xc6, 0x0b, 0x8f, 0xe4, 0x18, 0x1f, 0x3c,
+		0x92, 0x63, 0x7c, 0xf1, 0x48, 0x8e, 0xe1, 0xc3, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18, 0xa2,
+		0xb8, 0x10, 0xb6, 0x25, 0xb1, 0x81, 0x29, 0x63, 0xc0, 0x00, 0x75, 0xef, 0xbb, 0x4c, 0x82, 0x00,
+		0x00, 0x00,
+	},
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(
 		func(clientConfig transport.ClientConfig, structField reflect.StructField) EchoYARPCClient {
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-yarpc-go
+// source: internal/crossdock/crossdockpb/crossdock.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package crossdockpbtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/internal/crossdock/crossdockpb"
+	"go.uber.org/yarpc/yarpcproto"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// The response types of oneway methods are imported but not otherwise used.
+var (
+	_ *yarpcproto.Oneway
+)
+
+// MockEchoYARPCClient implements a gomock-compatible mock client for service
+// Echo.
+type MockEchoYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockEchoYARPCClientRecorder
+}
+
+var _ crossdockpb.EchoYARPCClient = (*MockEchoYARPCClient)(nil)
+
+type _MockEchoYARPCClientRecorder struct {
+	mock *MockEchoYARPCClient
+}
+
+// NewMockEchoYARPCClient builds a new mock client for service Echo.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := crossdockpbtest.NewMockEchoYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockEchoYARPCClient(ctrl *gomock.Controller) *MockEchoYARPCClient {
+	mock := &MockEchoYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockEchoYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Echo mock client.
+func (m *MockEchoYARPCClient) EXPECT() *_MockEchoYARPCClientRecorder {
+	return m.recorder
+}
+
+// Echo responds to a Echo call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().Echo(gomock.Any(), ...).Return(...)
+// 	... := client.Echo(...)
+func (m *MockEchoYARPCClient) Echo(ctx context.Context, request *crossdockpb.Ping, opts ...yarpc.CallOption) (response *crossdockpb.Pong, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "Echo", args...)
+	response, _ = ret[0].(*crossdockpb.Pong)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockEchoYARPCClientRecorder) Echo(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "Echo", args...)
+}
+
+// FakeEchoYARPCServer serves a EchoYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeEchoYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeEchoYARPCServer starts a YARPC dispatcher serving the given
+// EchoYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := crossdockpbtest.NewFakeEchoYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeEchoYARPCServer(server crossdockpb.EchoYARPCServer, options ...protobuf.ProceduresOption) (*FakeEchoYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-echo"})
+	dispatcher.Register(crossdockpb.BuildEchoYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeEchoYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Echo service.
+func (s *FakeEchoYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeEchoYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "crossdockpbtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeEchoYARPCServer) Client(options ...protobuf.ClientOption) crossdockpb.EchoYARPCClient {
+	return crossdockpb.NewEchoYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Echo service.
+func (s *FakeEchoYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+
+// MockOnewayYARPCClient implements a gomock-compatible mock client for service
+// Oneway.
+type MockOnewayYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockOnewayYARPCClientRecorder
+}
+
+var _ crossdockpb.OnewayYARPCClient = (*MockOnewayYARPCClient)(nil)
+
+type _MockOnewayYARPCClientRecorder struct {
+	mock *MockOnewayYARPCClient
+}
+
+// NewMockOnewayYARPCClient builds a new mock client for service Oneway.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := crossdockpbtest.NewMockOnewayYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockOnewayYARPCClient(ctrl *gomock.Controller) *MockOnewayYARPCClient {
+	mock := &MockOnewayYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockOnewayYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Oneway mock client.
+func (m *MockOnewayYARPCClient) EXPECT() *_MockOnewayYARPCClientRecorder {
+	return m.recorder
+}
+
+// Echo responds to a Echo call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().Echo(gomock.Any(), ...).Return(...)
+// 	... := client.Echo(...)
+func (m *MockOnewayYARPCClient) Echo(ctx context.Context, request *crossdockpb.Token, opts ...yarpc.CallOption) (ack yarpc.Ack, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "Echo", args...)
+	ack, _ = ret[0].(yarpc.Ack)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockOnewayYARPCClientRecorder) Echo(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "Echo", args...)
+}
+
+// FakeOnewayYARPCServer serves a OnewayYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeOnewayYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeOnewayYARPCServer starts a YARPC dispatcher serving the given
+// OnewayYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := crossdockpbtest.NewFakeOnewayYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeOnewayYARPCServer(server crossdockpb.OnewayYARPCServer, options ...protobuf.ProceduresOption) (*FakeOnewayYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-oneway"})
+	dispatcher.Register(crossdockpb.BuildOnewayYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeOnewayYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Oneway service.
+func (s *FakeOnewayYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeOnewayYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "crossdockpbtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeOnewayYARPCServer) Client(options ...protobuf.ClientOption) crossdockpb.OnewayYARPCClient {
+	return crossdockpb.NewOnewayYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Oneway service.
+func (s *FakeOnewayYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
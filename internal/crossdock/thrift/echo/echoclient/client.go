@@ -25,7 +25,6 @@ package echoclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -71,17 +70,10 @@ func (c client) Echo(
 	_Ping *echo.Ping,
 	opts ...yarpc.CallOption,
 ) (success *echo.Pong, err error) {
-
 	args := echo.Echo_Echo_Helper.Args(_Ping)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result echo.Echo_Echo_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -25,7 +25,6 @@ package secondserviceclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -75,17 +74,10 @@ func (c client) BlahBlah(
 	ctx context.Context,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := gauntlet.SecondService_BlahBlah_Helper.Args()
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.SecondService_BlahBlah_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -98,17 +90,10 @@ func (c client) SecondtestString(
 	_Thing *string,
 	opts ...yarpc.CallOption,
 ) (success string, err error) {
-
 	args := gauntlet.SecondService_SecondtestString_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.SecondService_SecondtestString_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
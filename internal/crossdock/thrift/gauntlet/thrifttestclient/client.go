@@ -25,7 +25,6 @@ package thrifttestclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -196,17 +195,10 @@ func (c client) TestBinary(
 	_Thing []byte,
 	opts ...yarpc.CallOption,
 ) (success []byte, err error) {
-
 	args := gauntlet.ThriftTest_TestBinary_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestBinary_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -219,17 +211,10 @@ func (c client) TestByte(
 	_Thing *int8,
 	opts ...yarpc.CallOption,
 ) (success int8, err error) {
-
 	args := gauntlet.ThriftTest_TestByte_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestByte_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -242,17 +227,10 @@ func (c client) TestDouble(
 	_Thing *float64,
 	opts ...yarpc.CallOption,
 ) (success float64, err error) {
-
 	args := gauntlet.ThriftTest_TestDouble_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestDouble_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -265,17 +243,10 @@ func (c client) TestEnum(
 	_Thing *gauntlet.Numberz,
 	opts ...yarpc.CallOption,
 ) (success gauntlet.Numberz, err error) {
-
 	args := gauntlet.ThriftTest_TestEnum_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestEnum_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -288,17 +259,10 @@ func (c client) TestException(
 	_Arg *string,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := gauntlet.ThriftTest_TestException_Helper.Args(_Arg)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestException_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -311,17 +275,10 @@ func (c client) TestI32(
 	_Thing *int32,
 	opts ...yarpc.CallOption,
 ) (success int32, err error) {
-
 	args := gauntlet.ThriftTest_TestI32_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestI32_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -334,17 +291,10 @@ func (c client) TestI64(
 	_Thing *int64,
 	opts ...yarpc.CallOption,
 ) (success int64, err error) {
-
 	args := gauntlet.ThriftTest_TestI64_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestI64_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -357,17 +307,10 @@ func (c client) TestInsanity(
 	_Argument *gauntlet.Insanity,
 	opts ...yarpc.CallOption,
 ) (success map[gauntlet.UserId]map[gauntlet.Numberz]*gauntlet.Insanity, err error) {
-
 	args := gauntlet.ThriftTest_TestInsanity_Helper.Args(_Argument)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestInsanity_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -380,17 +323,10 @@ func (c client) TestList(
 	_Thing []int32,
 	opts ...yarpc.CallOption,
 ) (success []int32, err error) {
-
 	args := gauntlet.ThriftTest_TestList_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestList_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -403,17 +339,10 @@ func (c client) TestMap(
 	_Thing map[int32]int32,
 	opts ...yarpc.CallOption,
 ) (success map[int32]int32, err error) {
-
 	args := gauntlet.ThriftTest_TestMap_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestMap_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -426,17 +355,10 @@ func (c client) TestMapMap(
 	_Hello *int32,
 	opts ...yarpc.CallOption,
 ) (success map[int32]map[int32]int32, err error) {
-
 	args := gauntlet.ThriftTest_TestMapMap_Helper.Args(_Hello)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestMapMap_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -454,17 +376,10 @@ func (c client) TestMulti(
 	_Arg5 *gauntlet.UserId,
 	opts ...yarpc.CallOption,
 ) (success *gauntlet.Xtruct, err error) {
-
 	args := gauntlet.ThriftTest_TestMulti_Helper.Args(_Arg0, _Arg1, _Arg2, _Arg3, _Arg4, _Arg5)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestMulti_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -478,17 +393,10 @@ func (c client) TestMultiException(
 	_Arg1 *string,
 	opts ...yarpc.CallOption,
 ) (success *gauntlet.Xtruct, err error) {
-
 	args := gauntlet.ThriftTest_TestMultiException_Helper.Args(_Arg0, _Arg1)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestMultiException_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -501,17 +409,10 @@ func (c client) TestNest(
 	_Thing *gauntlet.Xtruct2,
 	opts ...yarpc.CallOption,
 ) (success *gauntlet.Xtruct2, err error) {
-
 	args := gauntlet.ThriftTest_TestNest_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestNest_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -533,17 +434,10 @@ func (c client) TestSet(
 	_Thing map[int32]struct{},
 	opts ...yarpc.CallOption,
 ) (success map[int32]struct{}, err error) {
-
 	args := gauntlet.ThriftTest_TestSet_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestSet_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -556,17 +450,10 @@ func (c client) TestString(
 	_Thing *string,
 	opts ...yarpc.CallOption,
 ) (success string, err error) {
-
 	args := gauntlet.ThriftTest_TestString_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestString_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -579,17 +466,10 @@ func (c client) TestStringMap(
 	_Thing map[string]string,
 	opts ...yarpc.CallOption,
 ) (success map[string]string, err error) {
-
 	args := gauntlet.ThriftTest_TestStringMap_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestStringMap_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -602,17 +482,10 @@ func (c client) TestStruct(
 	_Thing *gauntlet.Xtruct,
 	opts ...yarpc.CallOption,
 ) (success *gauntlet.Xtruct, err error) {
-
 	args := gauntlet.ThriftTest_TestStruct_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestStruct_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -625,17 +498,10 @@ func (c client) TestTypedef(
 	_Thing *gauntlet.UserId,
 	opts ...yarpc.CallOption,
 ) (success gauntlet.UserId, err error) {
-
 	args := gauntlet.ThriftTest_TestTypedef_Helper.Args(_Thing)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestTypedef_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -647,17 +513,10 @@ func (c client) TestVoid(
 	ctx context.Context,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := gauntlet.ThriftTest_TestVoid_Helper.Args()
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result gauntlet.ThriftTest_TestVoid_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -0,0 +1,120 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package errorrate measures the rate of failed requests for peer selection.
+package errorrate
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// Window tracks the results of requests over a sliding window, approximated
+// by the current and the previous window.
+//
+// The zero value is ready to use.
+type Window struct {
+	lock sync.Mutex
+
+	start    time.Time
+	requests int
+	failures int
+
+	prevRequests int
+	prevFailures int
+}
+
+// rotate advances the window if it has elapsed.
+// Must be called with the lock held.
+func (w *Window) rotate(now time.Time, period time.Duration) {
+	elapsed := now.Sub(w.start)
+	if elapsed < period {
+		return
+	}
+	if elapsed < 2*period {
+		w.prevRequests, w.prevFailures = w.requests, w.failures
+	} else {
+		w.prevRequests, w.prevFailures = 0, 0
+	}
+	w.requests, w.failures = 0, 0
+	w.start = now
+}
+
+// Record records the result of a request.
+func (w *Window) Record(now time.Time, period time.Duration, failed bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.rotate(now, period)
+	w.requests++
+	if failed {
+		w.failures++
+	}
+}
+
+// Rate returns the rate of failed requests and the number of requests over
+// the last two windows.
+func (w *Window) Rate(now time.Time, period time.Duration) (rate float64, requests int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.rotate(now, period)
+	requests = w.requests + w.prevRequests
+	if requests == 0 {
+		return 0, 0
+	}
+	return float64(w.failures+w.prevFailures) / float64(requests), requests
+}
+
+// Reset forgets all recorded results.
+func (w *Window) Reset() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.requests, w.failures = 0, 0
+	w.prevRequests, w.prevFailures = 0, 0
+}
+
+// IsFailure returns whether the error returned by a call indicates that the
+// server, rather than the caller, is at fault.
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if !yarpcerrors.IsStatus(err) {
+		return true
+	}
+	switch yarpcerrors.FromError(err).Code() {
+	case yarpcerrors.CodeCancelled,
+		yarpcerrors.CodeInvalidArgument,
+		yarpcerrors.CodeNotFound,
+		yarpcerrors.CodeAlreadyExists,
+		yarpcerrors.CodePermissionDenied,
+		yarpcerrors.CodeFailedPrecondition,
+		yarpcerrors.CodeAborted,
+		yarpcerrors.CodeOutOfRange,
+		yarpcerrors.CodeUnimplemented,
+		yarpcerrors.CodeUnauthenticated:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package errorrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestWindow(t *testing.T) {
+	var w Window
+	now := time.Unix(0, 0)
+
+	rate, requests := w.Rate(now, time.Second)
+	assert.Equal(t, 0.0, rate)
+	assert.Equal(t, 0, requests)
+
+	w.Record(now, time.Second, true)
+	w.Record(now, time.Second, false)
+	rate, requests = w.Rate(now, time.Second)
+	assert.Equal(t, 0.5, rate)
+	assert.Equal(t, 2, requests)
+
+	now = now.Add(time.Second)
+	w.Record(now, time.Second, false)
+	w.Record(now, time.Second, false)
+	rate, requests = w.Rate(now, time.Second)
+	assert.Equal(t, 0.25, rate, "must include the previous window")
+	assert.Equal(t, 4, requests)
+
+	now = now.Add(time.Second)
+	rate, requests = w.Rate(now, time.Second)
+	assert.Equal(t, 0.0, rate, "must drop windows older than the previous window")
+	assert.Equal(t, 2, requests)
+
+	now = now.Add(2 * time.Second)
+	_, requests = w.Rate(now, time.Second)
+	assert.Equal(t, 0, requests, "must drop both windows after two periods")
+
+	w.Record(now, time.Second, true)
+	w.Reset()
+	_, requests = w.Rate(now, time.Second)
+	assert.Equal(t, 0, requests)
+}
+
+func TestIsFailure(t *testing.T) {
+	assert.False(t, IsFailure(nil))
+	assert.True(t, IsFailure(errors.New("great sadness")))
+	assert.True(t, IsFailure(yarpcerrors.Newf(yarpcerrors.CodeUnavailable, "unavailable")))
+	assert.True(t, IsFailure(yarpcerrors.Newf(yarpcerrors.CodeDeadlineExceeded, "timeout")))
+	assert.False(t, IsFailure(yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "bad request")))
+	assert.False(t, IsFailure(yarpcerrors.Newf(yarpcerrors.CodeNotFound, "not found")))
+}
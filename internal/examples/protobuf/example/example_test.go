@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package example_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/examples/protobuf/example"
+	"go.uber.org/yarpc/internal/examples/protobuf/examplepb"
+	"go.uber.org/yarpc/internal/examples/protobuf/examplepb/examplepbtest"
+)
+
+func TestFakeKeyValueYARPCServer(t *testing.T) {
+	server, err := examplepbtest.NewFakeKeyValueYARPCServer(example.NewKeyValueYARPCServer())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, server.Stop()) }()
+	client := server.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = client.SetValue(ctx, &examplepb.SetValueRequest{Key: "foo", Value: "bar"})
+	require.NoError(t, err)
+	response, err := client.GetValue(ctx, &examplepb.GetValueRequest{Key: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, "bar", response.Value)
+}
+
+func TestFakeSinkYARPCServer(t *testing.T) {
+	sink := example.NewSinkYARPCServer(false)
+	server, err := examplepbtest.NewFakeSinkYARPCServer(sink)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, server.Stop()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = server.Client().Fire(ctx, &examplepb.FireRequest{Value: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, sink.Values())
+}
+
+func TestFakeFooYARPCServer(t *testing.T) {
+	server, err := examplepbtest.NewFakeFooYARPCServer(example.NewFooYARPCServer(transport.NewHeaders()))
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, server.Stop()) }()
+	client := server.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	t.Run("EchoIn", func(t *testing.T) {
+		stream, err := client.EchoIn(ctx, &examplepb.EchoInRequest{Message: "hello", NumResponses: 2})
+		require.NoError(t, err)
+		var messages []string
+		for response, err := stream.Recv(); err != io.EOF; response, err = stream.Recv() {
+			require.NoError(t, err)
+			messages = append(messages, response.Message)
+		}
+		assert.Equal(t, []string{"hello", "hello"}, messages)
+	})
+
+	t.Run("EchoOut", func(t *testing.T) {
+		stream, err := client.EchoOut(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&examplepb.EchoOutRequest{Message: "foo"}))
+		require.NoError(t, stream.Send(&examplepb.EchoOutRequest{Message: "bar"}))
+		response, err := stream.CloseAndRecv()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", "bar"}, response.AllMessages)
+	})
+
+	t.Run("EchoBoth", func(t *testing.T) {
+		stream, err := client.EchoBoth(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&examplepb.EchoBothRequest{Message: "hello", NumResponses: 1}))
+		response, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "hello", response.Message)
+		require.NoError(t, stream.CloseSend())
+		_, err = stream.Recv()
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestMockKeyValueYARPCClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	client := examplepbtest.NewMockKeyValueYARPCClient(mockCtrl)
+	client.EXPECT().
+		GetValue(gomock.Any(), &examplepb.GetValueRequest{Key: "foo"}, gomock.Any()).
+		Return(&examplepb.GetValueResponse{Value: "bar"}, nil)
+
+	response, err := client.GetValue(context.Background(), &examplepb.GetValueRequest{Key: "foo"}, yarpc.WithHeader("key", "value"))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", response.Value)
+}
@@ -63,11 +63,13 @@ type KeyValueYARPCServer interface {
 }
 
 // BuildKeyValueYARPCProcedures prepares an implementation of the KeyValue service for YARPC registration.
-func BuildKeyValueYARPCProcedures(server KeyValueYARPCServer) []transport.Procedure {
+func BuildKeyValueYARPCProcedures(server KeyValueYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_KeyValueYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
-			ServiceName: "uber.yarpc.internal.examples.protobuf.example.KeyValue",
+			ServiceName:     "uber.yarpc.internal.examples.protobuf.example.KeyValue",
+			FileDescriptors: yarpcFileDescriptorClosure43929dec9f67b739,
+			Options:         options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 				{
 					MethodName: "GetValue",
@@ -161,10 +163,10 @@ type FxKeyValueYARPCProceduresResult struct {
 //    examplepb.NewFxKeyValueYARPCProcedures(),
 //    ...
 //  )
-func NewFxKeyValueYARPCProcedures() interface{} {
+func NewFxKeyValueYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxKeyValueYARPCProceduresParams) FxKeyValueYARPCProceduresResult {
 		return FxKeyValueYARPCProceduresResult{
-			Procedures: BuildKeyValueYARPCProcedures(params.Server),
+			Procedures: BuildKeyValueYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -278,11 +280,13 @@ type SinkYARPCServer interface {
 }
 
 // BuildSinkYARPCProcedures prepares an implementation of the Sink service for YARPC registration.
-func BuildSinkYARPCProcedures(server SinkYARPCServer) []transport.Procedure {
+func BuildSinkYARPCProcedures(server SinkYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_SinkYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
 			ServiceName:        "uber.yarpc.internal.examples.protobuf.example.Sink",
+			FileDescriptors:    yarpcFileDescriptorClosure43929dec9f67b739,
+			Options:            options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{},
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{
 				{
@@ -367,10 +371,10 @@ type FxSinkYARPCProceduresResult struct {
 //    examplepb.NewFxSinkYARPCProcedures(),
 //    ...
 //  )
-func NewFxSinkYARPCProcedures() interface{} {
+func NewFxSinkYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxSinkYARPCProceduresParams) FxSinkYARPCProceduresResult {
 		return FxSinkYARPCProceduresResult{
-			Procedures: BuildSinkYARPCProcedures(params.Server),
+			Procedures: BuildSinkYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -479,11 +483,13 @@ type FooServiceEchoBothYARPCServer interface {
 }
 
 // BuildFooYARPCProcedures prepares an implementation of the Foo service for YARPC registration.
-func BuildFooYARPCProcedures(server FooYARPCServer) []transport.Procedure {
+func BuildFooYARPCProcedures(server FooYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_FooYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
 			ServiceName:         "uber.yarpc.internal.examples.protobuf.example.Foo",
+			FileDescriptors:     yarpcFileDescriptorClosure43929dec9f67b739,
+			Options:             options,
 			UnaryHandlerParams:  []protobuf.BuildProceduresUnaryHandlerParams{},
 			OnewayHandlerParams: []protobuf.BuildProceduresOnewayHandlerParams{},
 			StreamHandlerParams: []protobuf.BuildProceduresStreamHandlerParams{
@@ -585,10 +591,10 @@ type FxFooYARPCProceduresResult struct {
 //    examplepb.NewFxFooYARPCProcedures(),
 //    ...
 //  )
-func NewFxFooYARPCProcedures() interface{} {
+func NewFxFooYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxFooYARPCProceduresParams) FxFooYARPCProceduresResult {
 		return FxFooYARPCProceduresResult{
-			Procedures: BuildFooYARPCProcedures(params.Server),
+			Procedures: BuildFooYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -821,6 +827,56 @@ var (
 	emptyFooServiceEchoBothYARPCResponse = &EchoBothResponse{}
 )
 
+var yarpcFileDescriptorClosure43929dec9f67b739 = [][]byte{
+	// internal/examples/protobuf/examplepb/example.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x51, 0x6b, 0xd3, 0x50,
+		0x14, 0xce, 0x69, 0xe6, 0xd6, 0x9e, 0x6d, 0xb6, 0x5c, 0x44, 0x4a, 0x1e, 0x2e, 0x33, 0x7d, 0x29,
+		0xa2, 0xe9, 0xa8, 0x82, 0x28, 0x6e, 0x83, 0x81, 0x93, 0x21, 0x3a, 0x69, 0x40, 0xc4, 0x97, 0x99,
+		0x8e, 0xab, 0x2b, 0x4b, 0x6f, 0x62, 0x6e, 0xa2, 0xf6, 0xcd, 0x57, 0x41, 0x41, 0xff, 0x85, 0xf8,
+		0xec, 0x8f, 0xf0, 0x71, 0x8f, 0x3e, 0xda, 0xf8, 0xe2, 0xe3, 0x7e, 0x82, 0xf4, 0x36, 0x27, 0xad,
+		0x13, 0x2d, 0x8d, 0x7b, 0xbb, 0xf7, 0xe4, 0xfb, 0xce, 0xf7, 0x9d, 0xe4, 0x3b, 0xc1, 0x76, 0x4f,
+		0xc6, 0x22, 0x92, 0x9e, 0xdf, 0x12, 0xaf, 0xbd, 0x7e, 0xe8, 0x0b, 0xd5, 0x0a, 0xa3, 0x20, 0x0e,
+		0xba, 0xc9, 0x33, 0xaa, 0x84, 0x5d, 0x3a, 0x39, 0xfa, 0x11, 0xbb, 0x9a, 0x74, 0x45, 0xe4, 0x0c,
+		0xbc, 0x28, 0x3c, 0x70, 0x88, 0xee, 0x10, 0xdd, 0x21, 0x3a, 0x55, 0xac, 0x8b, 0x1a, 0xa9, 0xcb,
+		0xad, 0x31, 0x49, 0x9f, 0xed, 0x06, 0x56, 0xef, 0x8a, 0xf8, 0x91, 0xe7, 0x27, 0xa2, 0x23, 0x5e,
+		0x24, 0x42, 0xc5, 0xac, 0x86, 0xe6, 0x91, 0x18, 0xd4, 0x61, 0x0d, 0x9a, 0x95, 0xce, 0xe8, 0x68,
+		0x37, 0xb1, 0x36, 0x01, 0xa9, 0x30, 0x90, 0x4a, 0xb0, 0x0b, 0x78, 0xee, 0xe5, 0xa8, 0x50, 0x2f,
+		0x69, 0xdc, 0xf8, 0x62, 0xdf, 0xc4, 0xaa, 0x3b, 0xab, 0xdd, 0x5f, 0xa8, 0x0c, 0x6b, 0xee, 0x29,
+		0x11, 0xbb, 0x81, 0xcb, 0x3b, 0xbd, 0x28, 0x6f, 0x95, 0x13, 0x61, 0x9a, 0x78, 0x19, 0xcf, 0xdf,
+		0x39, 0x38, 0x0c, 0xf6, 0x92, 0x98, 0x70, 0x75, 0x5c, 0xea, 0x0b, 0xa5, 0xbc, 0xe7, 0x84, 0xa4,
+		0xab, 0x7d, 0x1d, 0xab, 0x39, 0x36, 0x1b, 0xe4, 0x12, 0xae, 0x78, 0xbe, 0xbf, 0x9f, 0x21, 0x54,
+		0xbd, 0xb4, 0x66, 0x36, 0x2b, 0x9d, 0x65, 0xcf, 0xf7, 0xef, 0x67, 0x25, 0xfb, 0x01, 0xae, 0x8e,
+		0x58, 0xbb, 0x72, 0xa6, 0x00, 0x6b, 0xe0, 0xaa, 0x4c, 0xfa, 0xfb, 0x51, 0xd6, 0x5d, 0xe9, 0x19,
+		0xcd, 0xce, 0x8a, 0x4c, 0xfa, 0xa4, 0xa8, 0xc8, 0xf1, 0xae, 0xa4, 0xd2, 0x3f, 0x1c, 0x3f, 0x1c,
+		0x3b, 0xde, 0x0e, 0xe2, 0xc3, 0x33, 0x52, 0xbf, 0x82, 0xb5, 0x49, 0xc7, 0x59, 0xfa, 0xed, 0xcf,
+		0x25, 0x2c, 0xdf, 0x13, 0x03, 0xfd, 0x5d, 0xd8, 0x7b, 0xc0, 0x32, 0x25, 0x81, 0x6d, 0x3a, 0x73,
+		0x45, 0xd0, 0x39, 0x95, 0x33, 0x6b, 0xab, 0x30, 0x3f, 0x4b, 0x87, 0xa1, 0xfd, 0xb8, 0x45, 0xfd,
+		0xb8, 0xff, 0xe9, 0xe7, 0x8f, 0xb4, 0x1a, 0xed, 0xa7, 0xb8, 0xe0, 0xf6, 0xe4, 0x11, 0x7b, 0x8c,
+		0x0b, 0xa3, 0xdc, 0xb2, 0x5b, 0x73, 0xb6, 0x9c, 0x0a, 0xbb, 0xc5, 0xa6, 0xb9, 0x7b, 0x52, 0xbc,
+		0xf2, 0x06, 0xb6, 0xd1, 0xfe, 0x62, 0xa2, 0xb9, 0x13, 0x04, 0xec, 0x1d, 0xe0, 0x52, 0x96, 0x64,
+		0xb6, 0x31, 0xa7, 0xca, 0xef, 0xdb, 0x62, 0x6d, 0x16, 0xa5, 0xd3, 0xd8, 0x4d, 0x60, 0x6f, 0x01,
+		0x17, 0xc7, 0x91, 0x66, 0xb7, 0x0b, 0xb4, 0xcb, 0x37, 0xcb, 0xda, 0x28, 0xc8, 0x26, 0x2f, 0xeb,
+		0xc0, 0x3e, 0x02, 0x96, 0x29, 0xe0, 0xac, 0xc8, 0x70, 0x53, 0xbb, 0x66, 0x6d, 0x15, 0xe6, 0x4f,
+		0xde, 0xce, 0x3a, 0x6c, 0xdf, 0x38, 0x1e, 0x72, 0xe3, 0xdb, 0x90, 0x1b, 0x27, 0x43, 0x0e, 0x6f,
+		0x52, 0x0e, 0x9f, 0x52, 0x0e, 0x5f, 0x53, 0x0e, 0xc7, 0x29, 0x87, 0xef, 0x29, 0x87, 0x9f, 0x29,
+		0x37, 0x4e, 0x52, 0x0e, 0x1f, 0x7e, 0x70, 0xe3, 0x49, 0x25, 0xff, 0xe9, 0x77, 0x17, 0xb5, 0xc2,
+		0xb5, 0x5f, 0x03, 0x00, 0xf4, 0xdf, 0x72, 0xc3, 0x23, 0x06, 0x00, 0x00,
+	},
+	// yarpcproto/yarpc.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xab, 0x4c, 0x2c, 0x2a,
+		0x48, 0x2e, 0x28, 0xca, 0x2f, 0xc9, 0xd7, 0x07, 0x33, 0xf5, 0xc0, 0x6c, 0x21, 0xae, 0xd2, 0xa4,
+		0xd4, 0x22, 0x3d, 0xb0, 0x88, 0x92, 0x14, 0x17, 0x9b, 0x7f, 0x5e, 0x6a, 0x79, 0x62, 0xa5, 0x90,
+		0x00, 0x17, 0x73, 0x62, 0x72, 0xb6, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x47, 0x10, 0x88, 0xe9, 0x64,
+		0x71, 0xe1, 0xa1, 0x1c, 0xc3, 0x8d, 0x87, 0x72, 0x0c, 0x1f, 0x1e, 0xca, 0x31, 0x36, 0x3c, 0x92,
+		0x63, 0x5c, 0xf1, 0x48, 0x8e, 0xf1, 0xc4, 0x23, 0x39, 0xc6, 0x0b, 0x8f, 0xe4, 0x18, 0x1f, 0x3c,
+		0x92, 0x63, 0x7c, 0xf1, 0x48, 0x8e, 0xe1, 0xc3, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18, 0xa2,
+		0xb8, 0x10, 0xb6, 0x25, 0xb1, 0x81, 0x29, 0x63, 0xc0, 0x00, 0x75, 0xef, 0xbb, 0x4c, 0x82, 0x00,
+		0x00, 0x00,
+	},
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(
 		func(clientConfig transport.ClientConfig, structField reflect.StructField) KeyValueYARPCClient {
@@ -0,0 +1,435 @@
+// Code generated by protoc-gen-yarpc-go
+// source: internal/examples/protobuf/examplepb/example.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package examplepbtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/internal/examples/protobuf/examplepb"
+	"go.uber.org/yarpc/yarpcproto"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// The response types of oneway methods are imported but not otherwise used.
+var (
+	_ *yarpcproto.Oneway
+)
+
+// MockKeyValueYARPCClient implements a gomock-compatible mock client for service
+// KeyValue.
+type MockKeyValueYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockKeyValueYARPCClientRecorder
+}
+
+var _ examplepb.KeyValueYARPCClient = (*MockKeyValueYARPCClient)(nil)
+
+type _MockKeyValueYARPCClientRecorder struct {
+	mock *MockKeyValueYARPCClient
+}
+
+// NewMockKeyValueYARPCClient builds a new mock client for service KeyValue.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := examplepbtest.NewMockKeyValueYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockKeyValueYARPCClient(ctrl *gomock.Controller) *MockKeyValueYARPCClient {
+	mock := &MockKeyValueYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockKeyValueYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// KeyValue mock client.
+func (m *MockKeyValueYARPCClient) EXPECT() *_MockKeyValueYARPCClientRecorder {
+	return m.recorder
+}
+
+// GetValue responds to a GetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().GetValue(gomock.Any(), ...).Return(...)
+// 	... := client.GetValue(...)
+func (m *MockKeyValueYARPCClient) GetValue(ctx context.Context, request *examplepb.GetValueRequest, opts ...yarpc.CallOption) (response *examplepb.GetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "GetValue", args...)
+	response, _ = ret[0].(*examplepb.GetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockKeyValueYARPCClientRecorder) GetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "GetValue", args...)
+}
+
+// SetValue responds to a SetValue call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().SetValue(gomock.Any(), ...).Return(...)
+// 	... := client.SetValue(...)
+func (m *MockKeyValueYARPCClient) SetValue(ctx context.Context, request *examplepb.SetValueRequest, opts ...yarpc.CallOption) (response *examplepb.SetValueResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "SetValue", args...)
+	response, _ = ret[0].(*examplepb.SetValueResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockKeyValueYARPCClientRecorder) SetValue(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "SetValue", args...)
+}
+
+// FakeKeyValueYARPCServer serves a KeyValueYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeKeyValueYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeKeyValueYARPCServer starts a YARPC dispatcher serving the given
+// KeyValueYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := examplepbtest.NewFakeKeyValueYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeKeyValueYARPCServer(server examplepb.KeyValueYARPCServer, options ...protobuf.ProceduresOption) (*FakeKeyValueYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-keyvalue"})
+	dispatcher.Register(examplepb.BuildKeyValueYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeKeyValueYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the KeyValue service.
+func (s *FakeKeyValueYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeKeyValueYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "examplepbtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeKeyValueYARPCServer) Client(options ...protobuf.ClientOption) examplepb.KeyValueYARPCClient {
+	return examplepb.NewKeyValueYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the KeyValue service.
+func (s *FakeKeyValueYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+
+// MockSinkYARPCClient implements a gomock-compatible mock client for service
+// Sink.
+type MockSinkYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockSinkYARPCClientRecorder
+}
+
+var _ examplepb.SinkYARPCClient = (*MockSinkYARPCClient)(nil)
+
+type _MockSinkYARPCClientRecorder struct {
+	mock *MockSinkYARPCClient
+}
+
+// NewMockSinkYARPCClient builds a new mock client for service Sink.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := examplepbtest.NewMockSinkYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockSinkYARPCClient(ctrl *gomock.Controller) *MockSinkYARPCClient {
+	mock := &MockSinkYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockSinkYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Sink mock client.
+func (m *MockSinkYARPCClient) EXPECT() *_MockSinkYARPCClientRecorder {
+	return m.recorder
+}
+
+// Fire responds to a Fire call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().Fire(gomock.Any(), ...).Return(...)
+// 	... := client.Fire(...)
+func (m *MockSinkYARPCClient) Fire(ctx context.Context, request *examplepb.FireRequest, opts ...yarpc.CallOption) (ack yarpc.Ack, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "Fire", args...)
+	ack, _ = ret[0].(yarpc.Ack)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockSinkYARPCClientRecorder) Fire(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "Fire", args...)
+}
+
+// FakeSinkYARPCServer serves a SinkYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeSinkYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeSinkYARPCServer starts a YARPC dispatcher serving the given
+// SinkYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := examplepbtest.NewFakeSinkYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeSinkYARPCServer(server examplepb.SinkYARPCServer, options ...protobuf.ProceduresOption) (*FakeSinkYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-sink"})
+	dispatcher.Register(examplepb.BuildSinkYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeSinkYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Sink service.
+func (s *FakeSinkYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeSinkYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "examplepbtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeSinkYARPCServer) Client(options ...protobuf.ClientOption) examplepb.SinkYARPCClient {
+	return examplepb.NewSinkYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Sink service.
+func (s *FakeSinkYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
+
+// MockFooYARPCClient implements a gomock-compatible mock client for service
+// Foo.
+type MockFooYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockFooYARPCClientRecorder
+}
+
+var _ examplepb.FooYARPCClient = (*MockFooYARPCClient)(nil)
+
+type _MockFooYARPCClientRecorder struct {
+	mock *MockFooYARPCClient
+}
+
+// NewMockFooYARPCClient builds a new mock client for service Foo.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := examplepbtest.NewMockFooYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockFooYARPCClient(ctrl *gomock.Controller) *MockFooYARPCClient {
+	mock := &MockFooYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockFooYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Foo mock client.
+func (m *MockFooYARPCClient) EXPECT() *_MockFooYARPCClientRecorder {
+	return m.recorder
+}
+
+// EchoIn responds to a EchoIn call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().EchoIn(gomock.Any(), ...).Return(...)
+// 	... := client.EchoIn(...)
+func (m *MockFooYARPCClient) EchoIn(ctx context.Context, request *examplepb.EchoInRequest, opts ...yarpc.CallOption) (stream examplepb.FooServiceEchoInYARPCClient, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "EchoIn", args...)
+	stream, _ = ret[0].(examplepb.FooServiceEchoInYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockFooYARPCClientRecorder) EchoIn(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "EchoIn", args...)
+}
+
+// EchoOut responds to a EchoOut call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().EchoOut(gomock.Any(), ...).Return(...)
+// 	... := client.EchoOut(...)
+func (m *MockFooYARPCClient) EchoOut(ctx context.Context, opts ...yarpc.CallOption) (stream examplepb.FooServiceEchoOutYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "EchoOut", args...)
+	stream, _ = ret[0].(examplepb.FooServiceEchoOutYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockFooYARPCClientRecorder) EchoOut(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "EchoOut", args...)
+}
+
+// EchoBoth responds to a EchoBoth call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().EchoBoth(gomock.Any(), ...).Return(...)
+// 	... := client.EchoBoth(...)
+func (m *MockFooYARPCClient) EchoBoth(ctx context.Context, opts ...yarpc.CallOption) (stream examplepb.FooServiceEchoBothYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "EchoBoth", args...)
+	stream, _ = ret[0].(examplepb.FooServiceEchoBothYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockFooYARPCClientRecorder) EchoBoth(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "EchoBoth", args...)
+}
+
+// FakeFooYARPCServer serves a FooYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeFooYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeFooYARPCServer starts a YARPC dispatcher serving the given
+// FooYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := examplepbtest.NewFakeFooYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeFooYARPCServer(server examplepb.FooYARPCServer, options ...protobuf.ProceduresOption) (*FakeFooYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-foo"})
+	dispatcher.Register(examplepb.BuildFooYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeFooYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Foo service.
+func (s *FakeFooYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeFooYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "examplepbtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeFooYARPCServer) Client(options ...protobuf.ClientOption) examplepb.FooYARPCClient {
+	return examplepb.NewFooYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Foo service.
+func (s *FakeFooYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
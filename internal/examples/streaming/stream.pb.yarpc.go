@@ -107,11 +107,13 @@ type HelloServiceHelloThereYARPCServer interface {
 }
 
 // BuildHelloYARPCProcedures prepares an implementation of the Hello service for YARPC registration.
-func BuildHelloYARPCProcedures(server HelloYARPCServer) []transport.Procedure {
+func BuildHelloYARPCProcedures(server HelloYARPCServer, options ...protobuf.ProceduresOption) []transport.Procedure {
 	handler := &_HelloYARPCHandler{server}
 	return protobuf.BuildProcedures(
 		protobuf.BuildProceduresParams{
-			ServiceName: "uber.yarpc.internal.examples.streaming.Hello",
+			ServiceName:     "uber.yarpc.internal.examples.streaming.Hello",
+			FileDescriptors: yarpcFileDescriptorClosure45d12c3ddf34baf8,
+			Options:         options,
 			UnaryHandlerParams: []protobuf.BuildProceduresUnaryHandlerParams{
 				{
 					MethodName: "HelloUnary",
@@ -223,10 +225,10 @@ type FxHelloYARPCProceduresResult struct {
 //    streaming.NewFxHelloYARPCProcedures(),
 //    ...
 //  )
-func NewFxHelloYARPCProcedures() interface{} {
+func NewFxHelloYARPCProcedures(options ...protobuf.ProceduresOption) interface{} {
 	return func(params FxHelloYARPCProceduresParams) FxHelloYARPCProceduresResult {
 		return FxHelloYARPCProceduresResult{
-			Procedures: BuildHelloYARPCProcedures(params.Server),
+			Procedures: BuildHelloYARPCProcedures(params.Server, options...),
 		}
 	}
 }
@@ -497,6 +499,29 @@ var (
 	emptyHelloServiceHelloInStreamYARPCResponse  = &HelloResponse{}
 )
 
+var yarpcFileDescriptorClosure45d12c3ddf34baf8 = [][]byte{
+	// internal/examples/streaming/stream.proto
+	[]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0xc8, 0xcc, 0x2b, 0x49,
+		0x2d, 0xca, 0x4b, 0xcc, 0xd1, 0x4f, 0xad, 0x48, 0xcc, 0x2d, 0xc8, 0x49, 0x2d, 0xd6, 0x2f, 0x2e,
+		0x29, 0x4a, 0x4d, 0xcc, 0xcd, 0xcc, 0x4b, 0x87, 0xb2, 0xf4, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85,
+		0xd4, 0x4a, 0x93, 0x52, 0x8b, 0xf4, 0x2a, 0x13, 0x8b, 0x0a, 0x92, 0xf5, 0x60, 0x9a, 0xf4, 0x60,
+		0x9a, 0xf4, 0xe0, 0x9a, 0x94, 0xe4, 0xb8, 0x78, 0x3c, 0x52, 0x73, 0x72, 0xf2, 0x83, 0x52, 0x0b,
+		0x4b, 0x53, 0x8b, 0x4b, 0x84, 0xf8, 0xb8, 0x98, 0x32, 0x53, 0x24, 0x18, 0x15, 0x18, 0x35, 0x38,
+		0x83, 0x98, 0x32, 0x53, 0x94, 0xe4, 0xb9, 0x78, 0xa1, 0xf2, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9,
+		0xe8, 0x0a, 0x8c, 0x7a, 0x58, 0xb8, 0x58, 0xc1, 0x2a, 0x84, 0xaa, 0xb9, 0xb8, 0xc0, 0x8c, 0xd0,
+		0xbc, 0xc4, 0xa2, 0x4a, 0x21, 0x13, 0x3d, 0xe2, 0x5c, 0xa0, 0x87, 0x6c, 0xbd, 0x94, 0x29, 0x89,
+		0xba, 0x20, 0x8e, 0x52, 0x62, 0x10, 0xaa, 0x87, 0x5a, 0x1e, 0x92, 0x91, 0x5a, 0x94, 0x4a, 0x67,
+		0xcb, 0x35, 0x18, 0x0d, 0x18, 0x85, 0x1a, 0x19, 0xb9, 0xf8, 0xc0, 0xe2, 0xfe, 0xa5, 0x25, 0xc1,
+		0x60, 0x95, 0x74, 0x77, 0x85, 0x50, 0x03, 0x23, 0x34, 0xb6, 0x3c, 0xf3, 0x06, 0xc4, 0x09, 0x06,
+		0x8c, 0x4e, 0xe6, 0x17, 0x1e, 0xca, 0x31, 0xdc, 0x78, 0x28, 0xc7, 0xf0, 0xe1, 0xa1, 0x1c, 0x63,
+		0xc3, 0x23, 0x39, 0xc6, 0x15, 0x8f, 0xe4, 0x18, 0x4f, 0x3c, 0x92, 0x63, 0xbc, 0xf0, 0x48, 0x8e,
+		0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x17, 0x8f, 0xe4, 0x18, 0x3e, 0x3c, 0x92, 0x63, 0x9c, 0xf0, 0x58,
+		0x8e, 0x21, 0x8a, 0x13, 0x6e, 0x54, 0x12, 0x1b, 0x38, 0xdd, 0x1a, 0x03, 0x06, 0x00, 0x10, 0xed,
+		0xbf, 0x75, 0xe3, 0x02, 0x00, 0x00,
+	},
+}
+
 func init() {
 	yarpc.RegisterClientBuilder(
 		func(clientConfig transport.ClientConfig, structField reflect.StructField) HelloYARPCClient {
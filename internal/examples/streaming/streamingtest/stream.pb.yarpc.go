@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-yarpc-go
+// source: internal/examples/streaming/stream.proto
+// DO NOT EDIT!
+
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package streamingtest
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/internal/examples/streaming"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// MockHelloYARPCClient implements a gomock-compatible mock client for service
+// Hello.
+type MockHelloYARPCClient struct {
+	ctrl     *gomock.Controller
+	recorder *_MockHelloYARPCClientRecorder
+}
+
+var _ streaming.HelloYARPCClient = (*MockHelloYARPCClient)(nil)
+
+type _MockHelloYARPCClientRecorder struct {
+	mock *MockHelloYARPCClient
+}
+
+// NewMockHelloYARPCClient builds a new mock client for service Hello.
+//
+// 	mockCtrl := gomock.NewController(t)
+// 	client := streamingtest.NewMockHelloYARPCClient(mockCtrl)
+//
+// Use EXPECT() to set expectations on the mock.
+func NewMockHelloYARPCClient(ctrl *gomock.Controller) *MockHelloYARPCClient {
+	mock := &MockHelloYARPCClient{ctrl: ctrl}
+	mock.recorder = &_MockHelloYARPCClientRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows you to define an expectation on the
+// Hello mock client.
+func (m *MockHelloYARPCClient) EXPECT() *_MockHelloYARPCClientRecorder {
+	return m.recorder
+}
+
+// HelloUnary responds to a HelloUnary call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloUnary(gomock.Any(), ...).Return(...)
+// 	... := client.HelloUnary(...)
+func (m *MockHelloYARPCClient) HelloUnary(ctx context.Context, request *streaming.HelloRequest, opts ...yarpc.CallOption) (response *streaming.HelloResponse, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloUnary", args...)
+	response, _ = ret[0].(*streaming.HelloResponse)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockHelloYARPCClientRecorder) HelloUnary(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloUnary", args...)
+}
+
+// HelloInStream responds to a HelloInStream call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloInStream(gomock.Any(), ...).Return(...)
+// 	... := client.HelloInStream(...)
+func (m *MockHelloYARPCClient) HelloInStream(ctx context.Context, request *streaming.HelloRequest, opts ...yarpc.CallOption) (stream streaming.HelloServiceHelloInStreamYARPCClient, err error) {
+	args := []interface{}{ctx, request}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloInStream", args...)
+	stream, _ = ret[0].(streaming.HelloServiceHelloInStreamYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockHelloYARPCClientRecorder) HelloInStream(ctx interface{}, request interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx, request}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloInStream", args...)
+}
+
+// HelloOutStream responds to a HelloOutStream call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloOutStream(gomock.Any(), ...).Return(...)
+// 	... := client.HelloOutStream(...)
+func (m *MockHelloYARPCClient) HelloOutStream(ctx context.Context, opts ...yarpc.CallOption) (stream streaming.HelloServiceHelloOutStreamYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloOutStream", args...)
+	stream, _ = ret[0].(streaming.HelloServiceHelloOutStreamYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockHelloYARPCClientRecorder) HelloOutStream(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloOutStream", args...)
+}
+
+// HelloThere responds to a HelloThere call based on the mock expectations. This
+// call will fail if the mock does not expect this call. Use EXPECT to expect
+// a call to this function.
+//
+// 	client.EXPECT().HelloThere(gomock.Any(), ...).Return(...)
+// 	... := client.HelloThere(...)
+func (m *MockHelloYARPCClient) HelloThere(ctx context.Context, opts ...yarpc.CallOption) (stream streaming.HelloServiceHelloThereYARPCClient, err error) {
+	args := []interface{}{ctx}
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	ret := m.ctrl.Call(m, "HelloThere", args...)
+	stream, _ = ret[0].(streaming.HelloServiceHelloThereYARPCClient)
+	err, _ = ret[1].(error)
+	return
+}
+
+func (mr *_MockHelloYARPCClientRecorder) HelloThere(ctx interface{}, opts ...interface{}) *gomock.Call {
+	args := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCall(mr.mock, "HelloThere", args...)
+}
+
+// FakeHelloYARPCServer serves a HelloYARPCServer with a YARPC dispatcher.
+// Clients call it over an in-process outbound, without a network.
+type FakeHelloYARPCServer struct {
+	dispatcher *yarpc.Dispatcher
+	outbound   *yarpctest.InProcessOutbound
+}
+
+// NewFakeHelloYARPCServer starts a YARPC dispatcher serving the given
+// HelloYARPCServer. Stop must be called when the server is no longer
+// needed.
+//
+// 	server, err := streamingtest.NewFakeHelloYARPCServer(impl)
+// 	...
+// 	defer server.Stop()
+// 	client := server.Client()
+func NewFakeHelloYARPCServer(server streaming.HelloYARPCServer, options ...protobuf.ProceduresOption) (*FakeHelloYARPCServer, error) {
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{Name: "fake-hello"})
+	dispatcher.Register(streaming.BuildHelloYARPCProcedures(server, options...))
+	if err := dispatcher.Start(); err != nil {
+		return nil, err
+	}
+	return &FakeHelloYARPCServer{
+		dispatcher: dispatcher,
+		outbound:   yarpctest.NewInProcessOutbound(dispatcher.Router()),
+	}, nil
+}
+
+// Dispatcher returns the dispatcher serving the Hello service.
+func (s *FakeHelloYARPCServer) Dispatcher() *yarpc.Dispatcher {
+	return s.dispatcher
+}
+
+// ClientConfig returns a ClientConfig to call the server over an in-process
+// outbound.
+func (s *FakeHelloYARPCServer) ClientConfig() transport.ClientConfig {
+	return &transport.OutboundConfig{
+		CallerName: "streamingtest",
+		Outbounds: transport.Outbounds{
+			ServiceName: s.dispatcher.Name(),
+			Unary:       s.outbound,
+			Oneway:      s.outbound,
+			Stream:      s.outbound,
+		},
+	}
+}
+
+// Client returns a client that calls the server over an in-process outbound.
+func (s *FakeHelloYARPCServer) Client(options ...protobuf.ClientOption) streaming.HelloYARPCClient {
+	return streaming.NewHelloYARPCClient(s.ClientConfig(), options...)
+}
+
+// Stop stops the dispatcher serving the Hello service.
+func (s *FakeHelloYARPCServer) Stop() error {
+	return s.dispatcher.Stop()
+}
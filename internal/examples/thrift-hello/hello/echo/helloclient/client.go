@@ -25,7 +25,6 @@ package helloclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -71,17 +70,10 @@ func (c client) Echo(
 	_Echo *echo.EchoRequest,
 	opts ...yarpc.CallOption,
 ) (success *echo.EchoResponse, err error) {
-
 	args := echo.Hello_Echo_Helper.Args(_Echo)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result echo.Hello_Echo_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -25,7 +25,6 @@ package keyvalueclient
 
 import (
 	"context"
-	"go.uber.org/thriftrw/wire"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/encoding/thrift"
@@ -78,17 +77,10 @@ func (c client) GetValue(
 	_Key *string,
 	opts ...yarpc.CallOption,
 ) (success string, err error) {
-
 	args := kv.KeyValue_GetValue_Helper.Args(_Key)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result kv.KeyValue_GetValue_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
@@ -102,17 +94,10 @@ func (c client) SetValue(
 	_Value *string,
 	opts ...yarpc.CallOption,
 ) (err error) {
-
 	args := kv.KeyValue_SetValue_Helper.Args(_Key, _Value)
 
-	var body wire.Value
-	body, err = c.c.Call(ctx, args, opts...)
-	if err != nil {
-		return
-	}
-
 	var result kv.KeyValue_SetValue_Result
-	if err = result.FromWire(body); err != nil {
+	if err = thrift.Call(ctx, c.c, args, &result, opts...); err != nil {
 		return
 	}
 
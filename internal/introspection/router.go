@@ -21,6 +21,8 @@
 package introspection
 
 import (
+	"time"
+
 	"go.uber.org/yarpc/api/transport"
 )
 
@@ -30,6 +32,18 @@ type Procedure struct {
 	Encoding  string `json:"encoding"`
 	Signature string `json:"signature"`
 	RPCType   string `json:"rpcType"`
+
+	Options *ProcedureOptions `json:"options,omitempty"`
+}
+
+// ProcedureOptions represent the options declared for a procedure in its
+// IDL.
+type ProcedureOptions struct {
+	TimeoutMS       int64    `json:"timeoutMs,omitempty"`
+	Idempotent      bool     `json:"idempotent,omitempty"`
+	MaxAttempts     int      `json:"maxAttempts,omitempty"`
+	BackoffMS       int64    `json:"backoffMs,omitempty"`
+	RequiredHeaders []string `json:"requiredHeaders,omitempty"`
 }
 
 // IntrospectProcedures is a convenience function that translate a slice of
@@ -43,7 +57,21 @@ func IntrospectProcedures(routerProcs []transport.Procedure) []Procedure {
 			Encoding:  string(p.Encoding),
 			Signature: p.Signature,
 			RPCType:   p.HandlerSpec.Type().String(),
+			Options:   introspectProcedureOptions(p.Options),
 		})
 	}
 	return procedures
 }
+
+func introspectProcedureOptions(o *transport.ProcedureOptions) *ProcedureOptions {
+	if o == nil {
+		return nil
+	}
+	return &ProcedureOptions{
+		TimeoutMS:       int64(o.Timeout / time.Millisecond),
+		Idempotent:      o.Idempotent,
+		MaxAttempts:     o.Retry.MaxAttempts,
+		BackoffMS:       int64(o.Retry.Backoff / time.Millisecond),
+		RequiredHeaders: o.RequiredHeaders,
+	}
+}
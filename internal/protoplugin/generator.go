@@ -33,7 +33,9 @@ import (
 )
 
 var (
-	errNoTargetService = errors.New("no target service defined in the file")
+	// ErrNoTargetService may be returned by the template info checker of a
+	// Runner to skip generating code for a file.
+	ErrNoTargetService = errors.New("no target service defined in the file")
 )
 
 type generator struct {
@@ -82,7 +84,7 @@ func (g *generator) Generate(targets []*File) ([]*plugin_go.CodeGeneratorRespons
 	var files []*plugin_go.CodeGeneratorResponse_File
 	for _, file := range targets {
 		code, err := g.generate(file)
-		if err == errNoTargetService {
+		if err == ErrNoTargetService {
 			continue
 		}
 		if err != nil {
@@ -125,7 +127,11 @@ func (g *generator) generate(file *File) (string, error) {
 			}
 		}
 	}
-	templateInfo := &TemplateInfo{file, imports}
+	dependencies, err := g.dependencies(file)
+	if err != nil {
+		return "", err
+	}
+	templateInfo := &TemplateInfo{file, imports, dependencies}
 	if err := g.templateInfoChecker(templateInfo); err != nil {
 		return "", err
 	}
@@ -135,3 +141,34 @@ func (g *generator) generate(file *File) (string, error) {
 	}
 	return buffer.String(), nil
 }
+
+// dependencies returns the files imported by the given file, directly or
+// transitively, in depth-first order.
+func (g *generator) dependencies(file *File) ([]*File, error) {
+	var (
+		dependencies []*File
+		seen         = map[string]bool{file.GetName(): true}
+		visit        func(*File) error
+	)
+	visit = func(file *File) error {
+		for _, name := range file.GetDependency() {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			dependency, err := g.registry.LookupFile(name)
+			if err != nil {
+				return err
+			}
+			dependencies = append(dependencies, dependency)
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(file); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
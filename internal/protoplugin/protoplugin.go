@@ -112,6 +112,9 @@ func NewMultiRunner(runners ...Runner) Runner {
 type TemplateInfo struct {
 	*File
 	Imports []*GoPackage
+	// Dependencies are the files that File imports, directly or transitively,
+	// each listed once before the files it imports.
+	Dependencies []*File
 }
 
 // GoPackage represents a golang package.
@@ -237,4 +240,6 @@ type Field struct {
 	Message *Message
 	// FieldMessage is the message type of the field.
 	FieldMessage *Message
+	// FieldEnum is the enum type of the field.
+	FieldEnum *Enum
 }
@@ -59,6 +59,11 @@ func (r *registry) Load(req *plugin_go.CodeGeneratorRequest) error {
 	for _, file := range req.GetProtoFile() {
 		r.loadFile(file)
 	}
+	for _, file := range r.files {
+		if err := r.loadFields(file); err != nil {
+			return err
+		}
+	}
 	var targetPkg string
 	for _, name := range req.FileToGenerate {
 		target := r.files[name]
@@ -103,6 +108,14 @@ func (r *registry) LookupMessage(location string, name string) (*Message, error)
 	return nil, fmt.Errorf("no message found: %s", name)
 }
 
+func (r *registry) LookupEnum(name string) (*Enum, error) {
+	e, ok := r.enums[name]
+	if !ok {
+		return nil, fmt.Errorf("no enum found: %s", name)
+	}
+	return e, nil
+}
+
 func (r *registry) LookupFile(name string) (*File, error) {
 	f, ok := r.files[name]
 	if !ok {
@@ -236,6 +249,26 @@ func (r *registry) loadServices(file *File) error {
 	return nil
 }
 
+// loadFields resolves the message and enum types of the fields of the
+// messages in "file". It must be called after loadFile is called for all files.
+func (r *registry) loadFields(file *File) error {
+	for _, m := range file.Messages {
+		for _, f := range m.Fields {
+			var err error
+			switch f.GetType() {
+			case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+				f.FieldMessage, err = r.LookupMessage(file.GetPackage(), f.GetTypeName())
+			case descriptor.FieldDescriptorProto_TYPE_ENUM:
+				f.FieldEnum, err = r.LookupEnum(f.GetTypeName())
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (r *registry) newMethod(svc *Service, md *descriptor.MethodDescriptorProto) (*Method, error) {
 	requestType, err := r.LookupMessage(svc.File.GetPackage(), md.GetInputType())
 	if err != nil {
@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package streamtest provides in-memory streams for testing streaming
+// encodings.
+package streamtest
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"go.uber.org/yarpc/api/transport"
+)
+
+// pipe carries the messages of one direction of an in-memory stream.
+type pipe struct {
+	ch   chan *transport.StreamMessage
+	once sync.Once
+	err  error
+}
+
+func newPipe() *pipe {
+	return &pipe{ch: make(chan *transport.StreamMessage, 16)}
+}
+
+// close closes the pipe. Once the pending messages have been received, the
+// receiving end gets the given error, or io.EOF if it is nil.
+func (p *pipe) close(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.ch)
+	})
+}
+
+// Stream is one end of an in-memory stream.
+type Stream struct {
+	ctx  context.Context
+	req  *transport.StreamRequest
+	recv *pipe
+	send *pipe
+}
+
+var _ transport.Stream = (*Stream)(nil)
+
+// NewStream returns a stream for the given request that receives the given
+// messages, then io.EOF.
+// The stream buffers up to 16 messages sent on it.
+func NewStream(ctx context.Context, req *transport.StreamRequest, msgs ...*transport.StreamMessage) *Stream {
+	recv := &pipe{ch: make(chan *transport.StreamMessage, len(msgs))}
+	for _, msg := range msgs {
+		recv.ch <- msg
+	}
+	recv.close(nil)
+	return &Stream{ctx: ctx, req: req, recv: recv, send: newPipe()}
+}
+
+// Context returns the context of the stream.
+func (s *Stream) Context() context.Context { return s.ctx }
+
+// Request returns the request of the stream.
+func (s *Stream) Request() *transport.StreamRequest { return s.req }
+
+// SendMessage sends a message to the other end of the stream.
+func (s *Stream) SendMessage(_ context.Context, msg *transport.StreamMessage) error {
+	s.send.ch <- msg
+	return nil
+}
+
+// ReceiveMessage receives the next message from the other end of the stream.
+// Once the other end closes the stream, ReceiveMessage returns io.EOF, or the
+// error that the stream handler returned when the other end is a server.
+func (s *Stream) ReceiveMessage(context.Context) (*transport.StreamMessage, error) {
+	msg, ok := <-s.recv.ch
+	if !ok {
+		if s.recv.err != nil {
+			return nil, s.recv.err
+		}
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+// Close closes the stream, signaling that this end has no more messages to
+// send.
+func (s *Stream) Close(context.Context) error {
+	s.send.close(nil)
+	return nil
+}
+
+// Outbound is a stream outbound that runs a stream handler in-process.
+//
+// When the handler returns, the client end of the stream receives the
+// messages that the handler sent, then the error that it returned.
+type Outbound struct {
+	transport.Outbound
+
+	handler transport.StreamHandler
+	wg      sync.WaitGroup
+	lock    sync.Mutex
+	err     error
+}
+
+var _ transport.StreamOutbound = (*Outbound)(nil)
+
+// NewOutbound returns a stream outbound that runs the given handler for each
+// stream.
+func NewOutbound(handler transport.StreamHandler) *Outbound {
+	return &Outbound{handler: handler}
+}
+
+// CallStream starts the handler on a new in-memory stream and returns the
+// client end of the stream.
+func (o *Outbound) CallStream(ctx context.Context, req *transport.StreamRequest) (*transport.ClientStream, error) {
+	toServer, toClient := newPipe(), newPipe()
+	serverStream, err := transport.NewServerStream(&Stream{ctx: ctx, req: req, recv: toServer, send: toClient})
+	if err != nil {
+		return nil, err
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		err := o.handler.HandleStream(serverStream)
+
+		o.lock.Lock()
+		o.err = err
+		o.lock.Unlock()
+		toClient.close(err)
+	}()
+
+	return transport.NewClientStream(&Stream{ctx: ctx, req: req, recv: toClient, send: toServer})
+}
+
+// HandlerError waits for the handlers of all streams to return and returns
+// the error that the last of them returned.
+func (o *Outbound) HandlerError() error {
+	o.wg.Wait()
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.err
+}
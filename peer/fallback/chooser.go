@@ -0,0 +1,290 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fallback
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/internal/errorrate"
+	"go.uber.org/yarpc/internal/introspection"
+	"go.uber.org/yarpc/pkg/lifecycle"
+)
+
+type chooserOptions struct {
+	maxErrorRate    float64
+	minimumRequests int
+	window          time.Duration
+	failbackDelay   time.Duration
+	clock           clock.Clock
+}
+
+var defaultChooserOptions = chooserOptions{
+	maxErrorRate:    0.5,
+	minimumRequests: 20,
+	window:          10 * time.Second,
+	failbackDelay:   30 * time.Second,
+}
+
+// ChooserOption customizes the behavior of a fallback chooser.
+type ChooserOption interface {
+	apply(*chooserOptions)
+}
+
+type chooserOptionFunc func(*chooserOptions)
+
+func (f chooserOptionFunc) apply(options *chooserOptions) { f(options) }
+
+// MaxErrorRate specifies the rate of failed requests to the primary, between
+// 0 and 1, above which requests fail over to the secondary.
+// A rate of 1 or more disables failing over because of errors.
+//
+// Defaults to 0.5.
+func MaxErrorRate(rate float64) ChooserOption {
+	return chooserOptionFunc(func(options *chooserOptions) {
+		options.maxErrorRate = rate
+	})
+}
+
+// MinimumRequests specifies the number of requests the primary must receive
+// within the error rate window before its error rate is considered.
+//
+// Defaults to 20.
+func MinimumRequests(n int) ChooserOption {
+	return chooserOptionFunc(func(options *chooserOptions) {
+		options.minimumRequests = n
+	})
+}
+
+// ErrorRateWindow specifies the period over which the error rate of the
+// primary is measured.
+//
+// Defaults to 10 seconds.
+func ErrorRateWindow(window time.Duration) ChooserOption {
+	return chooserOptionFunc(func(options *chooserOptions) {
+		options.window = window
+	})
+}
+
+// FailbackDelay specifies how long requests go to the secondary after
+// failing over before they may return to the primary.
+//
+// Defaults to 30 seconds.
+func FailbackDelay(delay time.Duration) ChooserOption {
+	return chooserOptionFunc(func(options *chooserOptions) {
+		options.failbackDelay = delay
+	})
+}
+
+// withClock specifies the clock that measures error rates and delays, for
+// tests.
+func withClock(clk clock.Clock) ChooserOption {
+	return chooserOptionFunc(func(options *chooserOptions) {
+		options.clock = clk
+	})
+}
+
+// New creates a fallback chooser that sends requests to the primary peer
+// chooser and fails over to the secondary peer chooser while the primary is
+// unhealthy.
+//
+// The fallback chooser owns the lifecycle of both peer choosers.
+func New(primary, secondary peer.Chooser, opts ...ChooserOption) *Chooser {
+	options := defaultChooserOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.clock == nil {
+		options.clock = clock.NewReal()
+	}
+
+	return &Chooser{
+		once:      lifecycle.NewOnce(),
+		primary:   primary,
+		secondary: secondary,
+		options:   options,
+	}
+}
+
+// Chooser is a peer chooser that fails over from a primary peer chooser to a
+// secondary peer chooser.
+type Chooser struct {
+	once *lifecycle.Once
+	lock sync.Mutex
+
+	primary   peer.Chooser
+	secondary peer.Chooser
+	options   chooserOptions
+
+	stats errorrate.Window
+
+	// failedOver is whether requests currently go to the secondary, and
+	// failedOverAt is when they started to.
+	failedOver   bool
+	failedOverAt time.Time
+}
+
+var (
+	_ peer.Chooser                        = (*Chooser)(nil)
+	_ introspection.IntrospectableChooser = (*Chooser)(nil)
+)
+
+// Choose chooses a peer from the primary peer chooser, or from the secondary
+// peer chooser while failed over.
+func (c *Chooser) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
+	if err := c.once.WaitUntilRunning(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if c.useSecondary() {
+		return c.secondary.Choose(ctx, req)
+	}
+
+	p, onFinish, err := c.primary.Choose(ctx, req)
+	if err != nil {
+		c.stats.Record(c.options.clock.Now(), c.options.window, true)
+		return nil, nil, err
+	}
+	return p, func(err error) {
+		c.stats.Record(c.options.clock.Now(), c.options.window, errorrate.IsFailure(err))
+		onFinish(err)
+	}, nil
+}
+
+// useSecondary returns whether to send a request to the secondary, failing
+// over or back as necessary.
+func (c *Chooser) useSecondary() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.options.clock.Now()
+	if c.failedOver {
+		if now.Sub(c.failedOverAt) < c.options.failbackDelay || !hasAvailablePeers(c.primary) {
+			return true
+		}
+		// Fail back with a clean slate, so that errors from before failing
+		// over do not immediately fail over again.
+		c.failedOver = false
+		c.stats.Reset()
+		return false
+	}
+
+	if hasAvailablePeers(c.primary) && c.healthy(now) {
+		return false
+	}
+	c.failedOver = true
+	c.failedOverAt = now
+	return true
+}
+
+// healthy returns whether the error rate of the primary is acceptable.
+func (c *Chooser) healthy(now time.Time) bool {
+	rate, requests := c.stats.Rate(now, c.options.window)
+	return requests < c.options.minimumRequests || rate < c.options.maxErrorRate
+}
+
+// FailedOver returns whether requests currently go to the secondary peer
+// chooser.
+func (c *Chooser) FailedOver() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.failedOver
+}
+
+// hasAvailablePeers returns whether the peer chooser has any available
+// peers, assuming that it does if it does not report availability.
+func hasAvailablePeers(c peer.Chooser) bool {
+	switch c := c.(type) {
+	case interface{ NumAvailable() int }:
+		return c.NumAvailable() > 0
+	case interface{ ChooserList() peer.ChooserList }:
+		// A peer chooser bound to a peer list updater, like peer.BoundChooser.
+		return hasAvailablePeers(c.ChooserList())
+	default:
+		return true
+	}
+}
+
+// Start starts the primary and secondary peer choosers.
+func (c *Chooser) Start() error {
+	return c.once.Start(c.start)
+}
+
+func (c *Chooser) start() error {
+	if err := c.primary.Start(); err != nil {
+		return err
+	}
+	if err := c.secondary.Start(); err != nil {
+		return multierr.Append(err, c.primary.Stop())
+	}
+	return nil
+}
+
+// Stop stops the primary and secondary peer choosers.
+func (c *Chooser) Stop() error {
+	return c.once.Stop(c.stop)
+}
+
+func (c *Chooser) stop() error {
+	return multierr.Append(c.primary.Stop(), c.secondary.Stop())
+}
+
+// IsRunning returns whether the fallback chooser is running.
+func (c *Chooser) IsRunning() bool {
+	return c.once.IsRunning()
+}
+
+// Introspect returns the peers of both peer choosers.
+func (c *Chooser) Introspect() introspection.ChooserStatus {
+	state := "primary"
+	if c.FailedOver() {
+		state = "secondary"
+	}
+
+	status := introspection.ChooserStatus{
+		Name:  "Fallback",
+		State: fmt.Sprintf("using %s", state),
+	}
+	for _, tier := range []struct {
+		name    string
+		chooser peer.Chooser
+	}{
+		{"primary", c.primary},
+		{"secondary", c.secondary},
+	} {
+		ic, ok := tier.chooser.(introspection.IntrospectableChooser)
+		if !ok {
+			continue
+		}
+		for _, p := range ic.Introspect().Peers {
+			p.State = fmt.Sprintf("%s, %s", p.State, tier.name)
+			status.Peers = append(status.Peers, p)
+		}
+	}
+	return status
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fallback
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// fakePeer is a peer that is always available.
+type fakePeer struct {
+	id string
+}
+
+func (p fakePeer) Identifier() string { return p.id }
+
+func (p fakePeer) Status() peer.Status {
+	return peer.Status{ConnectionStatus: peer.Available}
+}
+
+func (fakePeer) StartRequest() {}
+func (fakePeer) EndRequest()   {}
+
+// fakeChooser always chooses the same peer and reports a configurable
+// number of available peers.
+type fakeChooser struct {
+	lock      sync.Mutex
+	id        string
+	available int
+	running   bool
+}
+
+func (c *fakeChooser) Choose(context.Context, *transport.Request) (peer.Peer, func(error), error) {
+	return fakePeer{id: c.id}, func(error) {}, nil
+}
+
+func (c *fakeChooser) NumAvailable() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.available
+}
+
+func (c *fakeChooser) SetAvailable(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.available = n
+}
+
+func (c *fakeChooser) Start() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.running = true
+	return nil
+}
+
+func (c *fakeChooser) Stop() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.running = false
+	return nil
+}
+
+func (c *fakeChooser) IsRunning() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.running
+}
+
+// failingChooser fails to start.
+type failingChooser struct {
+	fakeChooser
+}
+
+func (*failingChooser) Start() error { return errors.New("great sadness") }
+
+func newTestChooser(t *testing.T, opts ...ChooserOption) (*Chooser, *fakeChooser, *fakeChooser) {
+	primary := &fakeChooser{id: "primary", available: 1}
+	secondary := &fakeChooser{id: "secondary", available: 1}
+	c := New(primary, secondary, opts...)
+	require.NoError(t, c.Start())
+	return c, primary, secondary
+}
+
+func choose(t *testing.T, c *Chooser, err error) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p, onFinish, chooseErr := c.Choose(ctx, &transport.Request{})
+	require.NoError(t, chooseErr)
+	onFinish(err)
+	return p.Identifier()
+}
+
+func TestFailOverWithoutAvailablePeers(t *testing.T) {
+	clk := clock.NewFake()
+	c, primary, _ := newTestChooser(t, FailbackDelay(time.Minute), withClock(clk))
+	defer c.Stop()
+
+	assert.Equal(t, "primary", choose(t, c, nil))
+	assert.False(t, c.FailedOver())
+
+	primary.SetAvailable(0)
+	assert.Equal(t, "secondary", choose(t, c, nil))
+	assert.True(t, c.FailedOver())
+
+	primary.SetAvailable(1)
+	assert.Equal(t, "secondary", choose(t, c, nil), "must wait before failing back")
+
+	clk.Add(time.Minute)
+	primary.SetAvailable(0)
+	assert.Equal(t, "secondary", choose(t, c, nil), "must wait for available peers before failing back")
+
+	primary.SetAvailable(1)
+	assert.Equal(t, "primary", choose(t, c, nil))
+	assert.False(t, c.FailedOver())
+}
+
+func TestFailOverOnErrors(t *testing.T) {
+	clk := clock.NewFake()
+	c, _, _ := newTestChooser(t,
+		MaxErrorRate(0.5),
+		MinimumRequests(4),
+		ErrorRateWindow(10*time.Second),
+		FailbackDelay(time.Minute),
+		withClock(clk),
+	)
+	defer c.Stop()
+
+	failure := yarpcerrors.Newf(yarpcerrors.CodeUnavailable, "unavailable")
+	callerFault := yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "bad request")
+
+	assert.Equal(t, "primary", choose(t, c, failure))
+	assert.Equal(t, "primary", choose(t, c, callerFault))
+	assert.Equal(t, "primary", choose(t, c, nil))
+	assert.Equal(t, "primary", choose(t, c, failure), "must wait for the minimum number of requests")
+	assert.Equal(t, "secondary", choose(t, c, nil), "must fail over once half of the requests fail")
+
+	clk.Add(time.Minute)
+	assert.Equal(t, "primary", choose(t, c, failure), "must fail back after the delay")
+	assert.Equal(t, "primary", choose(t, c, nil), "must forget errors from before failing over")
+}
+
+func TestLifecycle(t *testing.T) {
+	c, primary, secondary := newTestChooser(t)
+	assert.True(t, c.IsRunning())
+	assert.True(t, primary.IsRunning())
+	assert.True(t, secondary.IsRunning())
+
+	require.NoError(t, c.Stop())
+	assert.False(t, c.IsRunning())
+	assert.False(t, primary.IsRunning())
+	assert.False(t, secondary.IsRunning())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, _, err := c.Choose(ctx, &transport.Request{})
+	assert.Error(t, err, "must not choose peers after stopping")
+}
+
+func TestStartFailure(t *testing.T) {
+	primary := &fakeChooser{id: "primary"}
+	c := New(primary, &failingChooser{})
+	assert.EqualError(t, c.Start(), "great sadness")
+	assert.False(t, primary.IsRunning(), "must stop the primary if the secondary fails to start")
+}
+
+func TestIntrospect(t *testing.T) {
+	c, primary, _ := newTestChooser(t)
+	defer c.Stop()
+
+	assert.Equal(t, "using primary", c.Introspect().State)
+	primary.SetAvailable(0)
+	choose(t, c, nil)
+	assert.Equal(t, "using secondary", c.Introspect().State)
+}
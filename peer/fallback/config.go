@@ -0,0 +1,107 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fallback
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/yarpcconfig"
+)
+
+// Configuration describes how to build a fallback peer chooser.
+type Configuration struct {
+	Primary   yarpcconfig.PeerChooser `config:"primary"`
+	Secondary yarpcconfig.PeerChooser `config:"secondary"`
+
+	MaxErrorRate    float64       `config:"maxErrorRate"`
+	MinimumRequests int           `config:"minimumRequests"`
+	ErrorRateWindow time.Duration `config:"errorRateWindow"`
+	FailbackDelay   time.Duration `config:"failbackDelay"`
+}
+
+// Spec returns a configuration specification for the fallback peer chooser,
+// making it possible to fail over between two peer choosers with transports
+// that use outbound peer list configuration (like HTTP).
+//
+//  cfg := yarpcconfig.New()
+//  cfg.MustRegisterPeerChooser(fallback.Spec())
+//
+// This enables the fallback peer chooser, which accepts the configuration of
+// any two peer choosers for the primary and secondary:
+//
+//  outbounds:
+//    otherservice:
+//      unary:
+//        http:
+//          url: https://host:port/rpc
+//          fallback:
+//            primary:
+//              round-robin:
+//                peers:
+//                  - 127.0.0.1:8080
+//            secondary:
+//              round-robin:
+//                peers:
+//                  - 127.0.1.1:8080
+//            maxErrorRate: 0.5
+//            failbackDelay: 1m
+//
+// Peers are identified as host and port pairs.
+func Spec() yarpcconfig.PeerChooserSpec {
+	return yarpcconfig.PeerChooserSpec{
+		Name: "fallback",
+		BuildPeerChooser: func(cfg Configuration, t peer.Transport, k *yarpcconfig.Kit) (peer.Chooser, error) {
+			if cfg.Primary.Empty() || cfg.Secondary.Empty() {
+				return nil, fmt.Errorf("fallback requires both a primary and a secondary peer chooser")
+			}
+
+			var opts []ChooserOption
+			if cfg.MaxErrorRate < 0 {
+				return nil, fmt.Errorf("maxErrorRate must not be negative, got %v", cfg.MaxErrorRate)
+			}
+			if cfg.MaxErrorRate > 0 {
+				opts = append(opts, MaxErrorRate(cfg.MaxErrorRate))
+			}
+			if cfg.MinimumRequests > 0 {
+				opts = append(opts, MinimumRequests(cfg.MinimumRequests))
+			}
+			if cfg.ErrorRateWindow > 0 {
+				opts = append(opts, ErrorRateWindow(cfg.ErrorRateWindow))
+			}
+			if cfg.FailbackDelay > 0 {
+				opts = append(opts, FailbackDelay(cfg.FailbackDelay))
+			}
+
+			primary, err := cfg.Primary.BuildPeerChooser(t, hostport.Identify, k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build primary peer chooser: %v", err)
+			}
+			secondary, err := cfg.Secondary.BuildPeerChooser(t, hostport.Identify, k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build secondary peer chooser: %v", err)
+			}
+			return New(primary, secondary, opts...), nil
+		},
+	}
+}
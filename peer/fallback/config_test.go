@@ -0,0 +1,116 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fallback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/internal/whitespace"
+	peerbind "go.uber.org/yarpc/peer"
+	"go.uber.org/yarpc/peer/roundrobin"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		desc    string
+		given   string
+		wantErr string
+	}{
+		{
+			desc: "primary and secondary",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								fallback:
+									primary:
+										round-robin:
+											peers:
+												- 127.0.0.1:8080
+									secondary:
+										round-robin:
+											peers:
+												- 127.0.1.1:8080
+									maxErrorRate: 0.2
+									failbackDelay: 1m
+			`),
+		},
+		{
+			desc: "missing secondary",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								fallback:
+									primary:
+										peer: 127.0.0.1:8080
+			`),
+			wantErr: "fallback requires both a primary and a secondary peer chooser",
+		},
+		{
+			desc: "invalid secondary",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								fallback:
+									primary:
+										peer: 127.0.0.1:8080
+									secondary:
+										round-robin: {}
+			`),
+			wantErr: "failed to build secondary peer chooser",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			configurator := yarpctest.NewFakeConfigurator()
+			configurator.MustRegisterPeerList(roundrobin.Spec())
+			configurator.MustRegisterPeerChooser(Spec())
+
+			cfg, err := configurator.LoadConfigFromYAML("fake-service", strings.NewReader(tt.given))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			unary := cfg.Outbounds["their-service"].Unary.(*yarpctest.FakeOutbound)
+			chooser, ok := unary.Chooser().(*Chooser)
+			require.True(t, ok, "must use the fallback chooser")
+
+			require.NoError(t, chooser.Start())
+			defer chooser.Stop()
+			assert.IsType(t, &peerbind.BoundChooser{}, chooser.primary)
+			assert.IsType(t, &peerbind.BoundChooser{}, chooser.secondary)
+			assert.False(t, chooser.FailedOver())
+		})
+	}
+}
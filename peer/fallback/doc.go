@@ -0,0 +1,44 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package fallback provides a peer chooser that sends requests to a primary
+// peer chooser and fails over to a secondary peer chooser, for example in
+// another region, when the primary is unhealthy.
+//
+// The primary is unhealthy when it has no available peers, or when the rate
+// of failed requests to it exceeds a threshold.
+// Once failed over, the fallback chooser returns to the primary only after a
+// delay and only if the primary has available peers again, so it does not
+// flap between the two.
+//
+//   chooser := fallback.New(
+//   	peer.Bind(roundrobin.New(transport), peer.BindPeers(primaryPeers)),
+//   	peer.Bind(roundrobin.New(transport), peer.BindPeers(secondaryPeers)),
+//   	fallback.MaxErrorRate(0.5),
+//   	fallback.FailbackDelay(time.Minute),
+//   )
+//
+// The fallback chooser is also available in configuration:
+//
+//   cfg := yarpcconfig.New()
+//   cfg.MustRegisterPeerChooser(fallback.Spec())
+//
+// See Spec for details.
+package fallback
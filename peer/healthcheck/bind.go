@@ -0,0 +1,352 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/pkg/lifecycle"
+	"go.uber.org/zap"
+)
+
+type bindOptions struct {
+	interval           time.Duration
+	timeout            time.Duration
+	unhealthyThreshold int
+	healthyThreshold   int
+	logger             *zap.Logger
+	clock              clock.Clock
+}
+
+var defaultBindOptions = bindOptions{
+	interval:           5 * time.Second,
+	timeout:            time.Second,
+	unhealthyThreshold: 3,
+	healthyThreshold:   2,
+}
+
+// BindOption customizes the behavior of a health checker.
+type BindOption interface {
+	apply(*bindOptions)
+}
+
+type bindOptionFunc func(*bindOptions)
+
+func (f bindOptionFunc) apply(options *bindOptions) { f(options) }
+
+// Interval specifies how often each peer is checked.
+//
+// Defaults to 5 seconds.
+func Interval(interval time.Duration) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.interval = interval
+	})
+}
+
+// Timeout specifies how long a single check may take before it is
+// considered failed.
+//
+// Defaults to 1 second.
+func Timeout(timeout time.Duration) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.timeout = timeout
+	})
+}
+
+// UnhealthyThreshold specifies the number of consecutive failed checks after
+// which a healthy peer is removed from the peer list.
+//
+// Defaults to 3.
+func UnhealthyThreshold(n int) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.unhealthyThreshold = n
+	})
+}
+
+// HealthyThreshold specifies the number of consecutive successful checks
+// after which an unhealthy peer is returned to the peer list.
+//
+// Defaults to 2.
+func HealthyThreshold(n int) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.healthyThreshold = n
+	})
+}
+
+// Logger specifies a logger for peer health transitions.
+func Logger(logger *zap.Logger) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.logger = logger
+	})
+}
+
+// withClock specifies the clock that schedules checks, for tests.
+func withClock(clk clock.Clock) BindOption {
+	return bindOptionFunc(func(options *bindOptions) {
+		options.clock = clk
+	})
+}
+
+// Bind returns a peer list binder that actively checks the health of the
+// peers provided by the given peer list updater.
+//
+// Peers added by the updater are forwarded to the peer list immediately and
+// checked every interval thereafter.
+// Peers that fail consecutive checks are removed from the peer list until
+// they pass consecutive checks again, without being removed from the
+// health checker.
+func Bind(binder peer.Binder, checker Checker, opts ...BindOption) peer.Binder {
+	options := defaultBindOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.unhealthyThreshold < 1 {
+		options.unhealthyThreshold = 1
+	}
+	if options.healthyThreshold < 1 {
+		options.healthyThreshold = 1
+	}
+	if options.logger == nil {
+		options.logger = zap.NewNop()
+	}
+	if options.clock == nil {
+		options.clock = clock.NewReal()
+	}
+
+	return func(pl peer.List) transport.Lifecycle {
+		u := &Updater{
+			once:    lifecycle.NewOnce(),
+			list:    pl,
+			checker: checker,
+			options: options,
+			peers:   make(map[string]*peerHealth),
+		}
+		u.updater = binder(u)
+		return u
+	}
+}
+
+// peerHealth tracks the results of the recent checks of a peer.
+type peerHealth struct {
+	id      peer.Identifier
+	healthy bool
+
+	// Number of consecutive failed or successful checks, whichever was the
+	// most recent.
+	failures  int
+	successes int
+}
+
+// Updater is a peer list updater that forwards only healthy peers from
+// another peer list updater to a peer list.
+type Updater struct {
+	once    *lifecycle.Once
+	lock    sync.Mutex
+	list    peer.List
+	updater transport.Lifecycle
+	checker Checker
+	options bindOptions
+
+	peers map[string]*peerHealth
+
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+var _ peer.List = (*Updater)(nil)
+
+// Update receives additions and removals from the wrapped peer list updater
+// and forwards them to the peer list, except for removals of peers that are
+// already absent from the peer list because they are unhealthy.
+func (u *Updater) Update(updates peer.ListUpdates) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	var errs error
+	var forward peer.ListUpdates
+	for _, pid := range updates.Removals {
+		p, ok := u.peers[pid.Identifier()]
+		if !ok {
+			errs = multierr.Append(errs, peer.ErrPeerRemoveNotInList(pid.Identifier()))
+			continue
+		}
+		delete(u.peers, pid.Identifier())
+		if p.healthy {
+			forward.Removals = append(forward.Removals, pid)
+		}
+	}
+	for _, pid := range updates.Additions {
+		if _, ok := u.peers[pid.Identifier()]; ok {
+			errs = multierr.Append(errs, peer.ErrPeerAddAlreadyInList(pid.Identifier()))
+			continue
+		}
+		u.peers[pid.Identifier()] = &peerHealth{id: pid, healthy: true}
+		forward.Additions = append(forward.Additions, pid)
+	}
+
+	if len(forward.Additions) > 0 || len(forward.Removals) > 0 {
+		errs = multierr.Append(errs, u.list.Update(forward))
+	}
+	return errs
+}
+
+// Healthy returns whether the given peer passed its recent checks.
+// Peers unknown to the health checker are not healthy.
+func (u *Updater) Healthy(pid peer.Identifier) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	p, ok := u.peers[pid.Identifier()]
+	return ok && p.healthy
+}
+
+// Start starts the wrapped peer list updater and begins checking peers.
+func (u *Updater) Start() error {
+	return u.once.Start(u.start)
+}
+
+func (u *Updater) start() error {
+	if err := u.updater.Start(); err != nil {
+		return err
+	}
+	u.stopped = make(chan struct{})
+	u.done = make(chan struct{})
+	go u.run()
+	return nil
+}
+
+// Stop stops checking peers and stops the wrapped peer list updater.
+func (u *Updater) Stop() error {
+	return u.once.Stop(u.stop)
+}
+
+func (u *Updater) stop() error {
+	close(u.stopped)
+	<-u.done
+	return u.updater.Stop()
+}
+
+// IsRunning returns whether the health checker is running.
+func (u *Updater) IsRunning() bool {
+	return u.once.IsRunning()
+}
+
+func (u *Updater) run() {
+	defer close(u.done)
+	for {
+		select {
+		case <-u.stopped:
+			return
+		case <-u.options.clock.After(u.options.interval):
+			u.checkAll()
+		}
+	}
+}
+
+// checkAll checks all known peers concurrently and applies the results.
+func (u *Updater) checkAll() {
+	u.lock.Lock()
+	ids := make([]peer.Identifier, 0, len(u.peers))
+	for _, p := range u.peers {
+		ids = append(ids, p.id)
+	}
+	u.lock.Unlock()
+
+	results := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, pid := range ids {
+		wg.Add(1)
+		go func(i int, pid peer.Identifier) {
+			defer wg.Done()
+			results[i] = u.check(pid)
+		}(i, pid)
+	}
+	wg.Wait()
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	var forward peer.ListUpdates
+	for i, pid := range ids {
+		p, ok := u.peers[pid.Identifier()]
+		if !ok {
+			// The peer was removed while it was being checked.
+			continue
+		}
+		switch u.record(p, results[i]) {
+		case becameHealthy:
+			forward.Additions = append(forward.Additions, pid)
+		case becameUnhealthy:
+			forward.Removals = append(forward.Removals, pid)
+		}
+	}
+
+	if len(forward.Additions) > 0 || len(forward.Removals) > 0 {
+		if err := u.list.Update(forward); err != nil {
+			u.options.logger.Warn("failed to update peer list with peer health", zap.Error(err))
+		}
+	}
+}
+
+func (u *Updater) check(pid peer.Identifier) error {
+	ctx, cancel := context.WithTimeout(context.Background(), u.options.timeout)
+	defer cancel()
+	return u.checker.Check(ctx, pid)
+}
+
+type transition int
+
+const (
+	unchanged transition = iota
+	becameHealthy
+	becameUnhealthy
+)
+
+// record applies the result of a check to the peer health and returns
+// whether the peer changed health.
+func (u *Updater) record(p *peerHealth, err error) transition {
+	if err == nil {
+		p.failures = 0
+		p.successes++
+		if !p.healthy && p.successes >= u.options.healthyThreshold {
+			p.healthy = true
+			u.options.logger.Info("peer became healthy", zap.String("peer", p.id.Identifier()))
+			return becameHealthy
+		}
+		return unchanged
+	}
+
+	p.successes = 0
+	p.failures++
+	if p.healthy && p.failures >= u.options.unhealthyThreshold {
+		p.healthy = false
+		u.options.logger.Info("peer became unhealthy",
+			zap.String("peer", p.id.Identifier()), zap.Error(err))
+		return becameUnhealthy
+	}
+	return unchanged
+}
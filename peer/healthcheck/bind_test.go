@@ -0,0 +1,239 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/internal/clock"
+	peerbind "go.uber.org/yarpc/peer"
+	"go.uber.org/yarpc/peer/hostport"
+)
+
+const testtime = time.Second
+
+// recordingList is a peer list that records its current peers.
+type recordingList struct {
+	lock  sync.Mutex
+	peers map[string]struct{}
+}
+
+func newRecordingList() *recordingList {
+	return &recordingList{peers: make(map[string]struct{})}
+}
+
+func (l *recordingList) Update(updates peer.ListUpdates) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, pid := range updates.Removals {
+		if _, ok := l.peers[pid.Identifier()]; !ok {
+			return peer.ErrPeerRemoveNotInList(pid.Identifier())
+		}
+		delete(l.peers, pid.Identifier())
+	}
+	for _, pid := range updates.Additions {
+		if _, ok := l.peers[pid.Identifier()]; ok {
+			return peer.ErrPeerAddAlreadyInList(pid.Identifier())
+		}
+		l.peers[pid.Identifier()] = struct{}{}
+	}
+	return nil
+}
+
+func (l *recordingList) Peers() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	peers := make([]string, 0, len(l.peers))
+	for id := range l.peers {
+		peers = append(peers, id)
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// fakeChecker fails checks for the peers marked unhealthy.
+type fakeChecker struct {
+	lock      sync.Mutex
+	unhealthy map[string]bool
+}
+
+func newFakeChecker() *fakeChecker {
+	return &fakeChecker{unhealthy: make(map[string]bool)}
+}
+
+func (c *fakeChecker) SetHealthy(id string, healthy bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.unhealthy[id] = !healthy
+}
+
+func (c *fakeChecker) Check(ctx context.Context, pid peer.Identifier) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.unhealthy[pid.Identifier()] {
+		return errors.New("unhealthy")
+	}
+	return nil
+}
+
+func identifiers(n int) []peer.Identifier {
+	pids := make([]peer.Identifier, n)
+	for i := range pids {
+		pids[i] = hostport.PeerIdentifier(fmt.Sprintf("10.0.0.%d:4040", i))
+	}
+	return pids
+}
+
+func TestHealthTransitions(t *testing.T) {
+	list := newRecordingList()
+	checker := newFakeChecker()
+	u := Bind(peerbind.BindPeers(nil), checker,
+		UnhealthyThreshold(2),
+		HealthyThreshold(3),
+	)(list).(*Updater)
+
+	pids := identifiers(2)
+	require.NoError(t, u.Update(peer.ListUpdates{Additions: pids}))
+	assert.Equal(t, []string{"10.0.0.0:4040", "10.0.0.1:4040"}, list.Peers(), "new peers are healthy")
+
+	checker.SetHealthy("10.0.0.0:4040", false)
+	u.checkAll()
+	assert.Len(t, list.Peers(), 2, "a single failure must not remove the peer")
+	assert.True(t, u.Healthy(pids[0]))
+
+	u.checkAll()
+	assert.Equal(t, []string{"10.0.0.1:4040"}, list.Peers(), "consecutive failures remove the peer")
+	assert.False(t, u.Healthy(pids[0]))
+
+	checker.SetHealthy("10.0.0.0:4040", true)
+	u.checkAll()
+	u.checkAll()
+	checker.SetHealthy("10.0.0.0:4040", false)
+	u.checkAll()
+	checker.SetHealthy("10.0.0.0:4040", true)
+	u.checkAll()
+	u.checkAll()
+	assert.Equal(t, []string{"10.0.0.1:4040"}, list.Peers(), "successes must be consecutive")
+
+	u.checkAll()
+	assert.Equal(t, []string{"10.0.0.0:4040", "10.0.0.1:4040"}, list.Peers(), "consecutive successes restore the peer")
+	assert.True(t, u.Healthy(pids[0]))
+}
+
+func TestRemoveUnhealthyPeer(t *testing.T) {
+	list := newRecordingList()
+	checker := newFakeChecker()
+	u := Bind(peerbind.BindPeers(nil), checker, UnhealthyThreshold(1))(list).(*Updater)
+
+	pids := identifiers(2)
+	require.NoError(t, u.Update(peer.ListUpdates{Additions: pids}))
+
+	checker.SetHealthy("10.0.0.0:4040", false)
+	u.checkAll()
+	assert.Equal(t, []string{"10.0.0.1:4040"}, list.Peers())
+
+	require.NoError(t, u.Update(peer.ListUpdates{Removals: pids}), "unhealthy peers must not be removed twice")
+	assert.Empty(t, list.Peers())
+	assert.False(t, u.Healthy(pids[0]))
+}
+
+func TestUpdateErrors(t *testing.T) {
+	list := newRecordingList()
+	u := Bind(peerbind.BindPeers(nil), newFakeChecker())(list).(*Updater)
+
+	pids := identifiers(2)
+	require.NoError(t, u.Update(peer.ListUpdates{Additions: pids[:1]}))
+
+	err := u.Update(peer.ListUpdates{Additions: pids, Removals: pids[1:]})
+	assert.Contains(t, err.Error(), peer.ErrPeerRemoveNotInList("10.0.0.1:4040").Error())
+	assert.Contains(t, err.Error(), peer.ErrPeerAddAlreadyInList("10.0.0.0:4040").Error())
+	assert.Equal(t, []string{"10.0.0.0:4040", "10.0.0.1:4040"}, list.Peers(), "valid updates still apply")
+}
+
+func TestCheckTimeout(t *testing.T) {
+	list := newRecordingList()
+	checker := CheckerFunc(func(ctx context.Context, pid peer.Identifier) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	u := Bind(peerbind.BindPeers(nil), checker,
+		UnhealthyThreshold(1),
+		Timeout(time.Millisecond),
+	)(list).(*Updater)
+
+	require.NoError(t, u.Update(peer.ListUpdates{Additions: identifiers(1)}))
+	u.checkAll()
+	assert.Empty(t, list.Peers(), "checks that time out fail")
+}
+
+func TestLifecycle(t *testing.T) {
+	clk := clock.NewFake()
+	list := newRecordingList()
+	checker := newFakeChecker()
+	pids := identifiers(3)
+
+	updater := Bind(peerbind.BindPeers(pids), checker,
+		UnhealthyThreshold(1),
+		HealthyThreshold(1),
+		Interval(time.Second),
+		withClock(clk),
+	)(list)
+
+	require.NoError(t, updater.Start())
+	assert.True(t, updater.IsRunning())
+	assert.Len(t, list.Peers(), 3, "starts the wrapped updater")
+
+	checker.SetHealthy("10.0.0.2:4040", false)
+	waitFor(t, func() bool {
+		clk.Add(time.Second)
+		return len(list.Peers()) == 2
+	})
+	assert.Equal(t, []string{"10.0.0.0:4040", "10.0.0.1:4040"}, list.Peers())
+
+	checker.SetHealthy("10.0.0.2:4040", true)
+	waitFor(t, func() bool {
+		clk.Add(time.Second)
+		return len(list.Peers()) == 3
+	})
+
+	require.NoError(t, updater.Stop())
+	assert.False(t, updater.IsRunning())
+	assert.Empty(t, list.Peers(), "stops the wrapped updater")
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(testtime)
+	for !cond() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	require.True(t, cond(), "timed out waiting for condition")
+}
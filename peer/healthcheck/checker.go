@@ -0,0 +1,218 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/protobuf"
+	"go.uber.org/yarpc/encoding/raw"
+	peerbind "go.uber.org/yarpc/peer"
+)
+
+// Checker checks the health of a single peer.
+type Checker interface {
+	// Check returns an error if the peer is unhealthy.
+	//
+	// The context has a deadline of the configured check timeout.
+	Check(ctx context.Context, pid peer.Identifier) error
+}
+
+// CheckerFunc adapts a function into a Checker.
+type CheckerFunc func(ctx context.Context, pid peer.Identifier) error
+
+// Check calls the function.
+func (f CheckerFunc) Check(ctx context.Context, pid peer.Identifier) error {
+	return f(ctx, pid)
+}
+
+// Request is the request that a procedure checker sends to each peer.
+type Request struct {
+	Caller    string
+	Service   string
+	Procedure string
+
+	// Encoding of the request body.
+	//
+	// Defaults to raw.
+	Encoding transport.Encoding
+	Body     []byte
+}
+
+// ProcedureChecker returns a Checker that calls a unary procedure on each
+// peer through the given transport.
+// The peer is healthy if the call succeeds without an application error.
+//
+// newOutbound builds an outbound for the transport that sends requests to a
+// single peer, for example:
+//
+//   func(c peer.Chooser) transport.UnaryOutbound {
+//   	return httpTransport.NewOutbound(c)
+//   }
+func ProcedureChecker(t peer.Transport, newOutbound func(peer.Chooser) transport.UnaryOutbound, req Request) Checker {
+	if req.Encoding == "" {
+		req.Encoding = raw.Encoding
+	}
+	return &procedureChecker{
+		transport:   t,
+		newOutbound: newOutbound,
+		req:         req,
+	}
+}
+
+type procedureChecker struct {
+	transport   peer.Transport
+	newOutbound func(peer.Chooser) transport.UnaryOutbound
+	req         Request
+
+	// validate checks the response body, if specified.
+	validate func([]byte) error
+}
+
+func (c *procedureChecker) Check(ctx context.Context, pid peer.Identifier) (err error) {
+	out := c.newOutbound(peerbind.NewSingle(pid, c.transport))
+	if err := out.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		if stopErr := out.Stop(); err == nil {
+			err = stopErr
+		}
+	}()
+
+	res, err := out.Call(ctx, &transport.Request{
+		Caller:    c.req.Caller,
+		Service:   c.req.Service,
+		Procedure: c.req.Procedure,
+		Encoding:  c.req.Encoding,
+		Body:      bytes.NewReader(c.req.Body),
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.ApplicationError {
+		return fmt.Errorf("health check procedure %q returned an application error", c.req.Procedure)
+	}
+	if c.validate == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return c.validate(body)
+}
+
+// _grpcHealthProcedure is the procedure of the standard gRPC health checking
+// protocol.
+const _grpcHealthProcedure = "grpc.health.v1.Health::Check"
+
+// _grpcServing is the SERVING value of the HealthCheckResponse.ServingStatus
+// enumeration.
+const _grpcServing = 1
+
+// healthCheckRequest mirrors grpc.health.v1.HealthCheckRequest.
+type healthCheckRequest struct {
+	Service string `protobuf:"bytes,1,opt,name=service,proto3"`
+}
+
+func (m *healthCheckRequest) Reset()         { *m = healthCheckRequest{} }
+func (m *healthCheckRequest) String() string { return proto.CompactTextString(m) }
+func (*healthCheckRequest) ProtoMessage()    {}
+
+// healthCheckResponse mirrors grpc.health.v1.HealthCheckResponse.
+type healthCheckResponse struct {
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3"`
+}
+
+func (m *healthCheckResponse) Reset()         { *m = healthCheckResponse{} }
+func (m *healthCheckResponse) String() string { return proto.CompactTextString(m) }
+func (*healthCheckResponse) ProtoMessage()    {}
+
+// GRPCChecker returns a Checker that checks each peer with the standard gRPC
+// health checking protocol, grpc.health.v1.Health/Check, through the given
+// transport.
+// The peer is healthy if it reports that the given health service is
+// serving.
+// An empty health service checks the overall health of the server.
+func GRPCChecker(t peer.Transport, newOutbound func(peer.Chooser) transport.UnaryOutbound, caller, service, healthService string) Checker {
+	// Marshaling a message with a single string field cannot fail.
+	body, _ := proto.Marshal(&healthCheckRequest{Service: healthService})
+	return &procedureChecker{
+		transport:   t,
+		newOutbound: newOutbound,
+		req: Request{
+			Caller:    caller,
+			Service:   service,
+			Procedure: _grpcHealthProcedure,
+			Encoding:  protobuf.Encoding,
+			Body:      body,
+		},
+		validate: func(body []byte) error {
+			var res healthCheckResponse
+			if err := proto.Unmarshal(body, &res); err != nil {
+				return err
+			}
+			if res.Status != _grpcServing {
+				return fmt.Errorf("health service %q is not serving, status %d", healthService, res.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// HTTPChecker returns a Checker that sends an HTTP GET request for the given
+// path to each peer, treating the peer identifier as a host and port.
+// The peer is healthy if it responds with a 2xx status code.
+//
+// Uses http.DefaultClient if the client is nil.
+func HTTPChecker(client *http.Client, path string) Checker {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return CheckerFunc(func(ctx context.Context, pid peer.Identifier) error {
+		req, err := http.NewRequest("GET", "http://"+pid.Identifier()+path, nil)
+		if err != nil {
+			return err
+		}
+		res, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		_, _ = ioutil.ReadAll(res.Body)
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			return fmt.Errorf("health check of %q responded with %q", path, res.Status)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package healthcheck
+
+import (
+	"context"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/transport/http"
+)
+
+func serverPeer(server *httptest.Server) peer.Identifier {
+	return hostport.PeerIdentifier(strings.TrimPrefix(server.URL, "http://"))
+}
+
+func TestHTTPChecker(t *testing.T) {
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(nethttp.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	pid := serverPeer(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+
+	assert.NoError(t, HTTPChecker(nil, "/health").Check(ctx, pid))
+
+	err := HTTPChecker(server.Client(), "/other").Check(ctx, pid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503 Service Unavailable")
+}
+
+func newHTTPChecker(t *testing.T, newChecker func(peer.Transport, func(peer.Chooser) transport.UnaryOutbound) Checker) Checker {
+	trans := http.NewTransport()
+	require.NoError(t, trans.Start())
+	return newChecker(trans, func(c peer.Chooser) transport.UnaryOutbound {
+		return trans.NewOutbound(c)
+	})
+}
+
+func TestProcedureChecker(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		assert.Equal(t, "caller", r.Header.Get(http.CallerHeader))
+		assert.Equal(t, "service", r.Header.Get(http.ServiceHeader))
+		assert.Equal(t, "health", r.Header.Get(http.ProcedureHeader))
+		assert.Equal(t, "raw", r.Header.Get(http.EncodingHeader))
+		if !healthy {
+			w.Header().Set(http.ApplicationStatusHeader, http.ApplicationErrorStatus)
+		}
+	}))
+	defer server.Close()
+	pid := serverPeer(server)
+
+	checker := newHTTPChecker(t, func(trans peer.Transport, newOutbound func(peer.Chooser) transport.UnaryOutbound) Checker {
+		return ProcedureChecker(trans, newOutbound, Request{
+			Caller:    "caller",
+			Service:   "service",
+			Procedure: "health",
+		})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+	assert.NoError(t, checker.Check(ctx, pid))
+
+	healthy = false
+	err := checker.Check(ctx, pid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "application error")
+}
+
+func TestGRPCChecker(t *testing.T) {
+	status := int32(_grpcServing)
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		assert.Equal(t, _grpcHealthProcedure, r.Header.Get(http.ProcedureHeader))
+		assert.Equal(t, "proto", r.Header.Get(http.EncodingHeader))
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req healthCheckRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+		assert.Equal(t, "myservice", req.Service)
+
+		res, err := proto.Marshal(&healthCheckResponse{Status: status})
+		require.NoError(t, err)
+		_, _ = w.Write(res)
+	}))
+	defer server.Close()
+	pid := serverPeer(server)
+
+	checker := newHTTPChecker(t, func(trans peer.Transport, newOutbound func(peer.Chooser) transport.UnaryOutbound) Checker {
+		return GRPCChecker(trans, newOutbound, "caller", "service", "myservice")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+	assert.NoError(t, checker.Check(ctx, pid))
+
+	status = 2 // NOT_SERVING
+	err := checker.Check(ctx, pid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `health service "myservice" is not serving, status 2`)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package healthcheck provides a peer list updater wrapper that actively
+// checks the health of each peer.
+//
+// Peer lists only consider the connection status of their peers, so a peer
+// that accepts connections but fails every request remains available.
+// The health checker sits between a peer list updater and its peer list.
+// It periodically checks each peer that the updater provides and only
+// forwards healthy peers to the peer list.
+// A peer becomes unhealthy after a number of consecutive failed checks and
+// healthy again after a number of consecutive successful checks.
+//
+// Peers can be checked by calling a procedure through a transport, with the
+// gRPC health checking protocol, or by requesting an HTTP path.
+//
+//   checker := healthcheck.ProcedureChecker(
+//   	httpTransport,
+//   	func(c peer.Chooser) transport.UnaryOutbound {
+//   		return httpTransport.NewOutbound(c)
+//   	},
+//   	healthcheck.Request{
+//   		Caller:    "myservice",
+//   		Service:   "theirservice",
+//   		Procedure: "health",
+//   	},
+//   )
+//   chooser := peer.Bind(
+//   	roundrobin.New(httpTransport),
+//   	healthcheck.Bind(dns.Updater(...), checker, healthcheck.Interval(time.Second)),
+//   )
+package healthcheck
@@ -0,0 +1,41 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package locality provides a peer list that prefers peers in the same zone
+// as the caller.
+//
+// The locality list groups peers by zone and delegates to a separate peer
+// list for each zone, like a round-robin or fewest-pending-requests list.
+// Requests go to the caller's own zone while it has enough available peers
+// and a low enough error rate.
+// Otherwise, requests spill over to the other zones in order of priority.
+//
+// Peer identifiers carry their zone by implementing Zoned.
+// Peer list updaters can attach a zone to any identifier with
+// PeerIdentifier, and BindPeers binds a static list of peers per zone.
+//
+//   list := locality.New("us-east1-a", func(zone string) (peer.ChooserList, error) {
+//   	return roundrobin.New(transport), nil
+//   }, locality.Priorities("us-east1-b"), locality.MinAvailable(3))
+//   chooser := peer.Bind(list, locality.BindPeers(map[string][]peer.Identifier{
+//   	"us-east1-a": {hostport.PeerIdentifier("10.0.0.1:4040")},
+//   	"us-east1-b": {hostport.PeerIdentifier("10.0.1.1:4040")},
+//   }))
+package locality
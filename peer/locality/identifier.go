@@ -0,0 +1,74 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package locality
+
+import (
+	"sort"
+
+	"go.uber.org/yarpc/api/peer"
+	peerbind "go.uber.org/yarpc/peer"
+)
+
+// Zoned is implemented by peer identifiers that know the zone of their peer.
+type Zoned interface {
+	Zone() string
+}
+
+// ZoneOf returns the zone of the given peer identifier, or an empty string
+// if the identifier does not implement Zoned.
+func ZoneOf(pid peer.Identifier) string {
+	if z, ok := pid.(Zoned); ok {
+		return z.Zone()
+	}
+	return ""
+}
+
+// PeerIdentifier attaches a zone to a peer identifier.
+func PeerIdentifier(pid peer.Identifier, zone string) peer.Identifier {
+	return zonedIdentifier{pid: pid, zone: zone}
+}
+
+type zonedIdentifier struct {
+	pid  peer.Identifier
+	zone string
+}
+
+func (z zonedIdentifier) Identifier() string { return z.pid.Identifier() }
+func (z zonedIdentifier) Zone() string       { return z.zone }
+
+// BindPeers returns a binder (suitable as an argument to peer.Bind) that
+// binds a peer list to a static list of peers in each zone for the duration
+// of its lifecycle.
+func BindPeers(zones map[string][]peer.Identifier) peer.Binder {
+	names := make([]string, 0, len(zones))
+	for zone := range zones {
+		names = append(names, zone)
+	}
+	sort.Strings(names)
+
+	var pids []peer.Identifier
+	for _, zone := range names {
+		for _, pid := range zones[zone] {
+			pids = append(pids, PeerIdentifier(pid, zone))
+		}
+	}
+	return peerbind.BindPeers(pids)
+}
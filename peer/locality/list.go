@@ -0,0 +1,432 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package locality
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/internal/errorrate"
+	"go.uber.org/yarpc/internal/introspection"
+	"go.uber.org/yarpc/pkg/lifecycle"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+type listOptions struct {
+	priorities      []string
+	minAvailable    int
+	maxErrorRate    float64
+	minimumRequests int
+	window          time.Duration
+	zoneOf          func(peer.Identifier) string
+	clock           clock.Clock
+}
+
+var defaultListOptions = listOptions{
+	minAvailable:    1,
+	maxErrorRate:    0.5,
+	minimumRequests: 20,
+	window:          10 * time.Second,
+	zoneOf:          ZoneOf,
+}
+
+// ListOption customizes the behavior of a locality list.
+type ListOption interface {
+	apply(*listOptions)
+}
+
+type listOptionFunc func(*listOptions)
+
+func (f listOptionFunc) apply(options *listOptions) { f(options) }
+
+// Priorities specifies the order in which other zones receive requests that
+// spill over from the local zone.
+// Zones that are not listed come after the listed zones, in lexical order.
+func Priorities(zones ...string) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.priorities = zones
+	})
+}
+
+// MinAvailable specifies the number of available peers a zone must have to
+// receive requests.
+// Requests spill over to the next zone while a zone has fewer available
+// peers.
+//
+// Defaults to 1.
+func MinAvailable(n int) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.minAvailable = n
+	})
+}
+
+// MaxErrorRate specifies the rate of failed requests, between 0 and 1, above
+// which a zone stops receiving requests until its error rate recovers.
+// A rate of 1 or more disables spilling over because of errors.
+//
+// Defaults to 0.5.
+func MaxErrorRate(rate float64) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.maxErrorRate = rate
+	})
+}
+
+// MinimumRequests specifies the number of requests a zone must receive
+// within the error rate window before its error rate is considered.
+//
+// Defaults to 20.
+func MinimumRequests(n int) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.minimumRequests = n
+	})
+}
+
+// ErrorRateWindow specifies the period over which the error rate of each
+// zone is measured.
+//
+// Defaults to 10 seconds.
+func ErrorRateWindow(window time.Duration) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.window = window
+	})
+}
+
+// ZoneFunc specifies how to determine the zone of a peer identifier.
+//
+// Defaults to ZoneOf.
+func ZoneFunc(f func(peer.Identifier) string) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.zoneOf = f
+	})
+}
+
+// withClock specifies the clock that measures error rates, for tests.
+func withClock(clk clock.Clock) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.clock = clk
+	})
+}
+
+// New creates a peer list that prefers peers in the given zone, delegating
+// to a peer list for each zone.
+//
+// newList builds the peer list for a zone when the list receives its first
+// peer in that zone.
+func New(zone string, newList func(zone string) (peer.ChooserList, error), opts ...ListOption) *List {
+	options := defaultListOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.clock == nil {
+		options.clock = clock.NewReal()
+	}
+
+	return &List{
+		once:    lifecycle.NewOnce(),
+		zone:    zone,
+		newList: newList,
+		options: options,
+		zones:   make(map[string]*zoneList),
+		peers:   make(map[string]string),
+	}
+}
+
+// zoneList is the peer list for a single zone.
+type zoneList struct {
+	name  string
+	list  peer.ChooserList
+	stats errorrate.Window
+	peers int
+}
+
+// List is a peer list that chooses peers from the caller's zone, spilling
+// over to other zones when the local zone has too few available peers or too
+// many errors.
+type List struct {
+	once *lifecycle.Once
+	lock sync.RWMutex
+
+	zone    string
+	newList func(zone string) (peer.ChooserList, error)
+	options listOptions
+
+	// zones are the peer lists for each zone, and order is the order in
+	// which zones receive requests.
+	zones map[string]*zoneList
+	order []*zoneList
+
+	// peers maps each peer identifier to its zone.
+	peers map[string]string
+}
+
+var (
+	_ peer.ChooserList                    = (*List)(nil)
+	_ introspection.IntrospectableChooser = (*List)(nil)
+)
+
+// Update applies the additions and removals of peer identifiers to the peer
+// list of each peer's zone.
+func (l *List) Update(updates peer.ListUpdates) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var errs error
+	forward := make(map[string]*peer.ListUpdates)
+	updatesFor := func(zone string) *peer.ListUpdates {
+		u, ok := forward[zone]
+		if !ok {
+			u = &peer.ListUpdates{}
+			forward[zone] = u
+		}
+		return u
+	}
+
+	for _, pid := range updates.Removals {
+		zone, ok := l.peers[pid.Identifier()]
+		if !ok {
+			errs = multierr.Append(errs, peer.ErrPeerRemoveNotInList(pid.Identifier()))
+			continue
+		}
+		delete(l.peers, pid.Identifier())
+		u := updatesFor(zone)
+		u.Removals = append(u.Removals, pid)
+	}
+	for _, pid := range updates.Additions {
+		if _, ok := l.peers[pid.Identifier()]; ok {
+			errs = multierr.Append(errs, peer.ErrPeerAddAlreadyInList(pid.Identifier()))
+			continue
+		}
+		zone := l.options.zoneOf(pid)
+		l.peers[pid.Identifier()] = zone
+		u := updatesFor(zone)
+		u.Additions = append(u.Additions, pid)
+	}
+
+	zones := make([]string, 0, len(forward))
+	for zone := range forward {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	for _, zone := range zones {
+		zl, err := l.getOrCreateZone(zone)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		u := forward[zone]
+		zl.peers += len(u.Additions) - len(u.Removals)
+		errs = multierr.Append(errs, zl.list.Update(*u))
+	}
+	return errs
+}
+
+// getOrCreateZone returns the peer list for the given zone, building and
+// starting it if necessary.
+// Must be called with the list lock held.
+func (l *List) getOrCreateZone(zone string) (*zoneList, error) {
+	if zl, ok := l.zones[zone]; ok {
+		return zl, nil
+	}
+
+	list, err := l.newList(zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build peer list for zone %q: %v", zone, err)
+	}
+	if l.once.IsRunning() {
+		if err := list.Start(); err != nil {
+			return nil, err
+		}
+	}
+
+	zl := &zoneList{name: zone, list: list}
+	l.zones[zone] = zl
+	l.order = append(l.order, zl)
+	sort.Slice(l.order, func(i, j int) bool {
+		return l.less(l.order[i].name, l.order[j].name)
+	})
+	return zl, nil
+}
+
+// less returns whether zone a receives requests before zone b: the local zone
+// first, then zones in order of priority, then the remaining zones in lexical
+// order.
+func (l *List) less(a, b string) bool {
+	if a == l.zone || b == l.zone {
+		return a == l.zone && b != l.zone
+	}
+	pa, pb := l.priority(a), l.priority(b)
+	if pa != pb {
+		return pa < pb
+	}
+	return a < b
+}
+
+func (l *List) priority(zone string) int {
+	for i, z := range l.options.priorities {
+		if z == zone {
+			return i
+		}
+	}
+	return len(l.options.priorities)
+}
+
+// Choose chooses a peer from the first zone, in order of preference, that
+// has enough available peers and a low enough error rate.
+// If no zone qualifies, Choose falls back to the first zone with any
+// available peers, and then to the local zone.
+func (l *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
+	if err := l.once.WaitUntilRunning(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	zl := l.pick()
+	if zl == nil {
+		return nil, nil, yarpcerrors.Newf(yarpcerrors.CodeUnavailable,
+			"locality peer list has no peers in any zone")
+	}
+
+	p, onFinish, err := zl.list.Choose(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, func(err error) {
+		zl.stats.Record(l.options.clock.Now(), l.options.window, errorrate.IsFailure(err))
+		onFinish(err)
+	}, nil
+}
+
+func (l *List) pick() *zoneList {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if len(l.order) == 0 {
+		return nil
+	}
+
+	now := l.options.clock.Now()
+	for _, zl := range l.order {
+		if numAvailable(zl) >= l.options.minAvailable && l.healthy(zl, now) {
+			return zl
+		}
+	}
+	for _, zl := range l.order {
+		if numAvailable(zl) > 0 {
+			return zl
+		}
+	}
+	return l.order[0]
+}
+
+// healthy returns whether the error rate of the zone is acceptable.
+func (l *List) healthy(zl *zoneList, now time.Time) bool {
+	rate, requests := zl.stats.Rate(now, l.options.window)
+	return requests < l.options.minimumRequests || rate < l.options.maxErrorRate
+}
+
+// numAvailable returns the number of available peers in the zone, assuming
+// all peers are available if the peer list does not report availability.
+func numAvailable(zl *zoneList) int {
+	if c, ok := zl.list.(interface{ NumAvailable() int }); ok {
+		return c.NumAvailable()
+	}
+	return zl.peers
+}
+
+// Zones returns the zones with peers, in order of preference.
+func (l *List) Zones() []string {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	zones := make([]string, 0, len(l.order))
+	for _, zl := range l.order {
+		if zl.peers > 0 {
+			zones = append(zones, zl.name)
+		}
+	}
+	return zones
+}
+
+// Start starts the peer lists of all zones.
+func (l *List) Start() error {
+	return l.once.Start(l.start)
+}
+
+func (l *List) start() error {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	var errs error
+	for _, zl := range l.order {
+		errs = multierr.Append(errs, zl.list.Start())
+	}
+	return errs
+}
+
+// Stop stops the peer lists of all zones.
+func (l *List) Stop() error {
+	return l.once.Stop(l.stop)
+}
+
+func (l *List) stop() error {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	var errs error
+	for _, zl := range l.order {
+		errs = multierr.Append(errs, zl.list.Stop())
+	}
+	return errs
+}
+
+// IsRunning returns whether the peer list is running.
+func (l *List) IsRunning() bool {
+	return l.once.IsRunning()
+}
+
+// Introspect returns the peers of all zones.
+func (l *List) Introspect() introspection.ChooserStatus {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	status := introspection.ChooserStatus{
+		Name:  "Locality",
+		State: fmt.Sprintf("zone %q, %d zones", l.zone, len(l.order)),
+	}
+	for _, zl := range l.order {
+		ic, ok := zl.list.(introspection.IntrospectableChooser)
+		if !ok {
+			continue
+		}
+		for _, p := range ic.Introspect().Peers {
+			p.State = fmt.Sprintf("%s, zone %q", p.State, zl.name)
+			status.Peers = append(status.Peers, p)
+		}
+	}
+	return status
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package locality
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	peerbind "go.uber.org/yarpc/peer"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// fakePeer is a peer that is always available.
+type fakePeer struct {
+	id string
+}
+
+func (p fakePeer) Identifier() string { return p.id }
+
+func (p fakePeer) Status() peer.Status {
+	return peer.Status{ConnectionStatus: peer.Available}
+}
+
+func (fakePeer) StartRequest() {}
+func (fakePeer) EndRequest()   {}
+
+// fakeList chooses its peers in lexical order, and reports a configurable
+// number of available peers.
+type fakeList struct {
+	lock      sync.Mutex
+	running   bool
+	peers     []string
+	available *int
+}
+
+func (l *fakeList) Update(updates peer.ListUpdates) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, pid := range updates.Removals {
+		for i, id := range l.peers {
+			if id == pid.Identifier() {
+				l.peers = append(l.peers[:i], l.peers[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, pid := range updates.Additions {
+		l.peers = append(l.peers, pid.Identifier())
+	}
+	sort.Strings(l.peers)
+	return nil
+}
+
+func (l *fakeList) Choose(context.Context, *transport.Request) (peer.Peer, func(error), error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.peers) == 0 {
+		return nil, nil, errors.New("no peers")
+	}
+	return fakePeer{id: l.peers[0]}, func(error) {}, nil
+}
+
+func (l *fakeList) NumAvailable() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.available != nil {
+		return *l.available
+	}
+	return len(l.peers)
+}
+
+func (l *fakeList) Start() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.running = true
+	return nil
+}
+
+func (l *fakeList) Stop() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.running = false
+	return nil
+}
+
+func (l *fakeList) IsRunning() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.running
+}
+
+// newTestList builds a locality list in zone "a" with peers 10.0.<zone>.<i>
+// in each of the given zones, and returns the per-zone fake lists.
+func newTestList(t *testing.T, zones map[string]int, opts ...ListOption) (*List, map[string]*fakeList) {
+	lists := make(map[string]*fakeList)
+	list := New("a", func(zone string) (peer.ChooserList, error) {
+		if zone == "broken" {
+			return nil, errors.New("great sadness")
+		}
+		l := &fakeList{}
+		lists[zone] = l
+		return l, nil
+	}, opts...)
+
+	var updates peer.ListUpdates
+	for zone, n := range zones {
+		for i := 0; i < n; i++ {
+			pid := hostport.PeerIdentifier(fmt.Sprintf("10.0.%s.%d:4040", zone, i))
+			updates.Additions = append(updates.Additions, PeerIdentifier(pid, zone))
+		}
+	}
+	require.NoError(t, list.Update(updates))
+	require.NoError(t, list.Start())
+	return list, lists
+}
+
+func choose(t *testing.T, list *List, err error) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p, onFinish, chooseErr := list.Choose(ctx, &transport.Request{})
+	require.NoError(t, chooseErr)
+	onFinish(err)
+	return p.Identifier()
+}
+
+func TestPrefersLocalZone(t *testing.T) {
+	list, lists := newTestList(t, map[string]int{"a": 2, "b": 2, "c": 2})
+	defer list.Stop()
+
+	assert.Equal(t, []string{"a", "b", "c"}, list.Zones())
+	for _, l := range lists {
+		assert.True(t, l.IsRunning(), "must start zone lists")
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "10.0.a.0:4040", choose(t, list, nil))
+	}
+
+	require.NoError(t, list.Stop())
+	for _, l := range lists {
+		assert.False(t, l.IsRunning(), "must stop zone lists")
+	}
+}
+
+func TestSpillsOverByPriority(t *testing.T) {
+	list, lists := newTestList(t, map[string]int{"a": 2, "b": 2, "c": 2, "d": 2},
+		Priorities("c"),
+		MinAvailable(2),
+	)
+	defer list.Stop()
+
+	assert.Equal(t, []string{"a", "c", "b", "d"}, list.Zones())
+
+	one := 1
+	lists["a"].available = &one
+	assert.Equal(t, "10.0.c.0:4040", choose(t, list, nil), "must spill over to the zone with the highest priority")
+
+	lists["c"].available = &one
+	assert.Equal(t, "10.0.b.0:4040", choose(t, list, nil), "must spill over to the remaining zones in order")
+
+	lists["b"].available = &one
+	lists["d"].available = &one
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, nil), "must fall back to the first zone with available peers")
+
+	zero := 0
+	lists["a"].available = &zero
+	assert.Equal(t, "10.0.c.0:4040", choose(t, list, nil), "must fall back to the first zone with available peers")
+}
+
+func TestSpillsOverOnErrors(t *testing.T) {
+	clk := clock.NewFake()
+	list, _ := newTestList(t, map[string]int{"a": 1, "b": 1},
+		MaxErrorRate(0.5),
+		MinimumRequests(4),
+		ErrorRateWindow(time.Second),
+		withClock(clk),
+	)
+	defer list.Stop()
+
+	failure := yarpcerrors.Newf(yarpcerrors.CodeUnavailable, "unavailable")
+	callerFault := yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "bad request")
+
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, failure))
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, nil))
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, callerFault))
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, failure), "must wait for the minimum number of requests")
+	assert.Equal(t, "10.0.b.0:4040", choose(t, list, nil), "must spill over once half of the requests fail")
+
+	clk.Add(time.Second)
+	assert.Equal(t, "10.0.b.0:4040", choose(t, list, nil), "must remember the previous window")
+
+	clk.Add(2 * time.Second)
+	assert.Equal(t, "10.0.a.0:4040", choose(t, list, nil), "must return once the errors expire")
+}
+
+func TestUpdate(t *testing.T) {
+	list, lists := newTestList(t, map[string]int{"a": 1, "b": 1})
+	defer list.Stop()
+
+	local := hostport.PeerIdentifier("10.0.a.0:4040")
+	unzoned := hostport.PeerIdentifier("10.0.0.0:4040")
+
+	require.NoError(t, list.Update(peer.ListUpdates{
+		Additions: []peer.Identifier{unzoned},
+		Removals:  []peer.Identifier{local},
+	}), "removals need not carry the zone")
+	assert.Equal(t, []string{"", "b"}, list.Zones())
+	assert.Empty(t, lists["a"].peers)
+	assert.Equal(t, []string{"10.0.0.0:4040"}, lists[""].peers)
+	assert.True(t, lists[""].IsRunning(), "must start zone lists created while running")
+
+	err := list.Update(peer.ListUpdates{
+		Additions: []peer.Identifier{unzoned},
+		Removals:  []peer.Identifier{local},
+	})
+	assert.Contains(t, err.Error(), peer.ErrPeerRemoveNotInList("10.0.a.0:4040").Error())
+	assert.Contains(t, err.Error(), peer.ErrPeerAddAlreadyInList("10.0.0.0:4040").Error())
+
+	err = list.Update(peer.ListUpdates{
+		Additions: []peer.Identifier{PeerIdentifier(local, "broken")},
+	})
+	assert.EqualError(t, err, `failed to build peer list for zone "broken": great sadness`)
+}
+
+func TestNoZones(t *testing.T) {
+	list, _ := newTestList(t, nil)
+	defer list.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _, err := list.Choose(ctx, &transport.Request{})
+	assert.Equal(t, yarpcerrors.CodeUnavailable, yarpcerrors.FromError(err).Code())
+}
+
+func TestBindPeers(t *testing.T) {
+	list, lists := newTestList(t, nil)
+	defer list.Stop()
+
+	chooser := peerbind.Bind(list, BindPeers(map[string][]peer.Identifier{
+		"a": {hostport.PeerIdentifier("10.0.0.1:4040")},
+		"b": {hostport.PeerIdentifier("10.0.1.1:4040"), hostport.PeerIdentifier("10.0.1.2:4040")},
+	}))
+	require.NoError(t, chooser.Start())
+	assert.Equal(t, []string{"a", "b"}, list.Zones())
+	assert.Equal(t, []string{"10.0.1.1:4040", "10.0.1.2:4040"}, lists["b"].peers)
+
+	require.NoError(t, chooser.Stop())
+	assert.Empty(t, list.Zones())
+}
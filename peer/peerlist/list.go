@@ -545,16 +545,22 @@ func (pl *List) Peers() []peer.Peer {
 
 // NumAvailable returns how many peers are available.
 func (pl *List) NumAvailable() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.availablePeers)
 }
 
 // NumUnavailable returns how many peers are unavailable.
 func (pl *List) NumUnavailable() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.unavailablePeers)
 }
 
 // NumUninitialized returns how many peers are unavailable.
 func (pl *List) NumUninitialized() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.uninitializedPeers)
 }
 
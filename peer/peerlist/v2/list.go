@@ -29,18 +29,25 @@ import (
 
 	"go.uber.org/atomic"
 	"go.uber.org/multierr"
+	"go.uber.org/net/metrics"
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
 	"go.uber.org/yarpc/internal/introspection"
 	intyarpcerrors "go.uber.org/yarpc/internal/yarpcerrors"
 	"go.uber.org/yarpc/pkg/lifecycle"
 	"go.uber.org/yarpc/yarpcerrors"
+	"go.uber.org/zap"
 )
 
 var (
 	_noContextDeadlineError = "can't wait for peer without a context deadline for a %s peer list"
 )
 
+// _slowStartAttempts is the number of times the peer list consults its
+// implementation for a warm peer before settling for a warming one.
+const _slowStartAttempts = 3
+
 // Implementation is a collection of available peers, with its own
 // subscribers for peer status change notifications.
 // The available peer list encapsulates the logic for selecting from among
@@ -65,9 +72,14 @@ type Implementation interface {
 }
 
 type listOptions struct {
-	capacity  int
-	noShuffle bool
-	seed      int64
+	capacity         int
+	noShuffle        bool
+	seed             int64
+	outlierDetection *OutlierDetectionConfig
+	slowStart        time.Duration
+	logger           *zap.Logger
+	meter            *metrics.Scope
+	clock            clock.Clock
 }
 
 var defaultListOptions = listOptions{
@@ -110,6 +122,63 @@ func Seed(seed int64) ListOption {
 	})
 }
 
+// OutlierDetection enables tracking the results of calls to each peer and
+// temporarily ejecting peers that fail too often from selection.
+// See OutlierDetectionConfig for the details of when a peer gets ejected.
+//
+// Outlier detection is disabled by default.
+func OutlierDetection(cfg OutlierDetectionConfig) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		cfg = cfg.withDefaults()
+		options.outlierDetection = &cfg
+	})
+}
+
+// SlowStart specifies a window over which a peer that becomes available
+// ramps up from receiving almost no requests to its full share of requests.
+// While a peer warms up, the list passes it over in favor of the next peer
+// that its implementation chooses, with a probability that decreases
+// linearly over the window. If the implementation keeps choosing warming
+// peers, the list chooses the warm peer with the fewest pending requests.
+// Peers that become available within the first window after the list starts
+// are not subject to slow start, since there are no warm peers to take their
+// share of requests.
+//
+// Slow start is disabled by default.
+func SlowStart(window time.Duration) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.slowStart = window
+	})
+}
+
+// Logger specifies a logger for the peer list, which reports peer ejections
+// and restorations.
+//
+// Defaults to a no-op logger.
+func Logger(logger *zap.Logger) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.logger = logger
+	})
+}
+
+// Meter specifies a metrics scope for the peer list, which reports the
+// number of peer ejections and of currently ejected peers.
+//
+// Metrics are not reported by default.
+func Meter(meter *metrics.Scope) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.meter = meter
+	})
+}
+
+// withClock specifies the clock used to measure outlier detection intervals
+// and ejection durations.
+func withClock(clk clock.Clock) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.clock = clk
+	})
+}
+
 // New creates a new peer list with an identifier chooser for available peers.
 func New(name string, transport peer.Transport, availableChooser Implementation, opts ...ListOption) *List {
 	options := defaultListOptions
@@ -117,18 +186,64 @@ func New(name string, transport peer.Transport, availableChooser Implementation,
 		o.apply(&options)
 	}
 
-	return &List{
+	logger := options.logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	meter := options.meter
+	if meter == nil {
+		meter = metrics.New().Scope()
+	}
+	clk := options.clock
+	if clk == nil {
+		clk = clock.NewReal()
+	}
+
+	pl := &List{
 		once:               lifecycle.NewOnce(),
 		name:               name,
 		uninitializedPeers: make(map[string]peer.Identifier, options.capacity),
 		unavailablePeers:   make(map[string]*peerThunk, options.capacity),
 		availablePeers:     make(map[string]*peerThunk, options.capacity),
+		ejectedPeers:       make(map[string]*peerThunk),
 		availableChooser:   availableChooser,
 		transport:          transport,
 		noShuffle:          options.noShuffle,
 		randSrc:            rand.NewSource(options.seed),
 		peerAvailableEvent: make(chan struct{}, 1),
+		outlierDetection:   options.outlierDetection,
+		slowStart:          options.slowStart,
+		slowStartRand:      rand.New(rand.NewSource(options.seed)),
+		logger:             logger.With(zap.String("peerList", name)),
+		clock:              clk,
+	}
+	if pl.outlierDetection != nil {
+		pl.newOutlierMetrics(meter)
 	}
+	return pl
+}
+
+// newOutlierMetrics creates the metrics that report peer ejections.
+func (pl *List) newOutlierMetrics(meter *metrics.Scope) {
+	tags := metrics.Tags{"peer_list": pl.name}
+	ejections, err := meter.Counter(metrics.Spec{
+		Name:      "peer_ejections",
+		Help:      "Number of peers ejected from the peer list as outliers.",
+		ConstTags: tags,
+	})
+	if err != nil {
+		pl.logger.Error("Failed to create peer ejections counter.", zap.Error(err))
+	}
+	ejected, err := meter.Gauge(metrics.Spec{
+		Name:      "ejected_peers",
+		Help:      "Number of peers currently ejected from the peer list as outliers.",
+		ConstTags: tags,
+	})
+	if err != nil {
+		pl.logger.Error("Failed to create ejected peers gauge.", zap.Error(err))
+	}
+	pl.ejections = ejections
+	pl.ejectedGauge = ejected
 }
 
 // List is an abstract peer list, backed by an Implementation to
@@ -154,9 +269,24 @@ type List struct {
 	peerAvailableEvent chan struct{}
 	transport          peer.Transport
 
+	// ejectedPeers are connected peers that outlier detection has temporarily
+	// removed from the available chooser.
+	ejectedPeers     map[string]*peerThunk
+	outlierDetection *OutlierDetectionConfig
+	ejections        *metrics.Counter
+	ejectedGauge     *metrics.Gauge
+
+	slowStart     time.Duration
+	startedAt     time.Time
+	slowStartLock sync.Mutex
+	slowStartRand *rand.Rand
+
 	noShuffle bool
 	randSrc   rand.Source
 
+	logger *zap.Logger
+	clock  clock.Clock
+
 	once *lifecycle.Once
 }
 
@@ -256,6 +386,7 @@ func (pl *List) addToAvailablePeers(t *peerThunk) error {
 	if pl.availablePeers[t.peer.Identifier()] != nil {
 		return peer.ErrPeerAddAlreadyInList(t.peer.Identifier())
 	}
+	t.availableSince = pl.clock.Now()
 	sub := pl.availableChooser.Add(t, t.id)
 	t.SetSubscriber(sub)
 	pl.availablePeers[t.Identifier()] = t
@@ -275,6 +406,7 @@ func (pl *List) start() error {
 	if err := pl.availableChooser.Start(); err != nil {
 		return err
 	}
+	pl.startedAt = pl.clock.Now()
 
 	add := values(pl.uninitializedPeers)
 	if !pl.noShuffle {
@@ -316,6 +448,10 @@ func (pl *List) stop() error {
 	errs = pl.releaseAll(errs, unavailablePeers)
 	pl.addToUninitialized(unavailablePeers)
 
+	ejectedPeers := pl.removeAllEjectedPeers(pl.ejectedPeers)
+	errs = pl.releaseAll(errs, ejectedPeers)
+	pl.addToUninitialized(ejectedPeers)
+
 	pl.shouldRetainPeers.Store(false)
 
 	return errs
@@ -352,6 +488,20 @@ func (pl *List) removeAllUnavailablePeers(toRemove map[string]*peerThunk) []*pee
 	return thunks
 }
 
+// removeAllEjectedPeers will clear the ejectedPeers list, cancel their
+// ejection timers and return all the Peers in the list in a slice
+// Must be run in a mutex.Lock()
+func (pl *List) removeAllEjectedPeers(toRemove map[string]*peerThunk) []*peerThunk {
+	thunks := make([]*peerThunk, 0, len(toRemove))
+	for id, t := range toRemove {
+		t.outlier.cancel()
+		thunks = append(thunks, t)
+		delete(toRemove, id)
+	}
+	pl.updateEjectedGauge()
+	return thunks
+}
+
 // releaseAll will iterate through a list of peers and call release
 // on the transport
 func (pl *List) releaseAll(errs error, peers []*peerThunk) error {
@@ -389,6 +539,12 @@ func (pl *List) removePeerIdentifierReferences(pid peer.Identifier) (*peerThunk,
 		return t, nil
 	}
 
+	if t, ok := pl.ejectedPeers[pid.Identifier()]; ok && t != nil {
+		pl.removeFromEjectedPeers(t)
+		t.outlier.cancel()
+		return t, nil
+	}
+
 	return nil, peer.ErrPeerRemoveNotInList(pid.Identifier())
 }
 
@@ -409,6 +565,14 @@ func (pl *List) removeFromUnavailablePeers(t *peerThunk) {
 	delete(pl.unavailablePeers, t.peer.Identifier())
 }
 
+// removeFromEjectedPeers remove a peer from the Ejected Peers list the
+// Peer should already be validated as non-nil and in the Ejected list.
+// Must be run in a mutex.Lock()
+func (pl *List) removeFromEjectedPeers(t *peerThunk) {
+	delete(pl.ejectedPeers, t.peer.Identifier())
+	pl.updateEjectedGauge()
+}
+
 // Choose selects the next available peer in the peer list
 func (pl *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
 	if err := pl.once.WaitUntilRunning(ctx); err != nil {
@@ -418,6 +582,9 @@ func (pl *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer,
 	for {
 		pl.lock.RLock()
 		p := pl.availableChooser.Choose(ctx, req)
+		if p != nil && pl.slowStart > 0 {
+			p = pl.chooseWarm(ctx, req, p)
+		}
 		pl.lock.RUnlock()
 
 		if p != nil {
@@ -432,6 +599,99 @@ func (pl *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer,
 	}
 }
 
+// ChoosePeer chooses the peer with the given identifier, bypassing the
+// implementation, if it is available and has not been ejected as an outlier.
+// Like Choose, ChoosePeer returns a function to call when the request
+// finishes, which reports the result to outlier detection.
+//
+// Wrappers that send requests to a peer they chose earlier, like sticky
+// lists, use ChoosePeer so that the peer list observes those requests.
+func (pl *List) ChoosePeer(pid peer.Identifier) (peer.Peer, func(error), bool) {
+	pl.lock.RLock()
+	t := pl.availablePeers[pid.Identifier()]
+	pl.lock.RUnlock()
+
+	if t == nil {
+		return nil, nil, false
+	}
+	t.StartRequest()
+	return t.peer, t.boundOnFinish, true
+}
+
+// chooseWarm passes over peers that are still warming up, in favor of the
+// next peer the implementation chooses, with a probability proportional to
+// how much of their slow start window remains.
+// Implementations that order peers by load, like the pending requests heap,
+// keep choosing an idle warming peer, so once the implementation offers no
+// other peer, chooseWarm picks the warm peer with the fewest pending requests
+// itself.
+// Must be run in a mutex.RLock()
+func (pl *List) chooseWarm(ctx context.Context, req *transport.Request, p peer.StatusPeer) peer.StatusPeer {
+	now := pl.clock.Now()
+	for i := 0; ; i++ {
+		if pl.acceptWarming(p.(*peerThunk), now) {
+			return p
+		}
+		if i+1 >= _slowStartAttempts {
+			break
+		}
+		next := pl.availableChooser.Choose(ctx, req)
+		if next == nil || next == p {
+			break
+		}
+		p = next
+	}
+
+	if t := pl.leastPendingWarmPeer(now); t != nil {
+		return t
+	}
+	return p
+}
+
+// leastPendingWarmPeer returns the available peer with the fewest pending
+// requests among those that are done warming up, or nil if all available
+// peers are still warming up.
+// Must be run in a mutex.RLock()
+func (pl *List) leastPendingWarmPeer(now time.Time) *peerThunk {
+	var (
+		best    *peerThunk
+		pending int
+	)
+	for _, t := range pl.availablePeers {
+		if !pl.isWarm(t, now) {
+			continue
+		}
+		if n := t.Status().PendingRequestCount; best == nil || n < pending {
+			best, pending = t, n
+		}
+	}
+	return best
+}
+
+// acceptWarming returns whether to choose a peer, given how far into its slow
+// start window it is.
+// Must be run in a mutex.RLock()
+func (pl *List) acceptWarming(t *peerThunk, now time.Time) bool {
+	if pl.isWarm(t, now) {
+		return true
+	}
+
+	pl.slowStartLock.Lock()
+	r := pl.slowStartRand.Float64()
+	pl.slowStartLock.Unlock()
+	return r < float64(now.Sub(t.availableSince))/float64(pl.slowStart)
+}
+
+// isWarm returns whether a peer is done warming up, or never had to because
+// it became available while the list was starting.
+// Must be run in a mutex.RLock()
+func (pl *List) isWarm(t *peerThunk, now time.Time) bool {
+	if t.availableSince.Sub(pl.startedAt) < pl.slowStart {
+		return true
+	}
+	return now.Sub(t.availableSince) >= pl.slowStart
+}
+
 // IsRunning returns whether the peer list is running.
 func (pl *List) IsRunning() bool {
 	return pl.once.IsRunning()
@@ -482,13 +742,16 @@ func (pl *List) NotifyStatusChanged(pid peer.Identifier) {
 	}
 }
 
-// getThunk returns either the available or unavailable peer thunk.
+// getThunk returns either the available, unavailable, or ejected peer thunk.
 // Must be called under a lock.
 func (pl *List) getThunk(pid peer.Identifier) *peerThunk {
 	if t := pl.availablePeers[pid.Identifier()]; t != nil {
 		return t
 	}
-	return pl.unavailablePeers[pid.Identifier()]
+	if t := pl.unavailablePeers[pid.Identifier()]; t != nil {
+		return t
+	}
+	return pl.ejectedPeers[pid.Identifier()]
 }
 
 // notifyStatusChanged gets called by peer thunks
@@ -506,7 +769,9 @@ func (pl *List) notifyStatusChanged(pid peer.Identifier) {
 		// TODO: log error
 		_ = pl.handleUnavailablePeerStatusChange(t)
 	}
-	// No action required
+	// No action required, including for ejected peers, which return to the
+	// available or unavailable peers based on their status when their
+	// ejection expires.
 }
 
 // handleAvailablePeerStatusChange checks the connection status of a connected
@@ -540,6 +805,85 @@ func (pl *List) handleUnavailablePeerStatusChange(t *peerThunk) error {
 	return pl.addToAvailablePeers(t)
 }
 
+// recordResult gets called by peer thunks when a request finishes, and ejects
+// the peer if outlier detection deems it an outlier.
+// Must NOT be run in a mutex.Lock()
+func (pl *List) recordResult(t *peerThunk, err error) {
+	if pl.outlierDetection == nil {
+		return
+	}
+	if !t.outlier.record(pl.outlierDetection, pl.clock.Now(), err) {
+		return
+	}
+
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	pl.ejectPeer(t)
+}
+
+// ejectPeer moves an available peer out of the implementation data
+// structure until its ejection expires, unless doing so would exceed the
+// maximum fraction of ejected peers.
+// Must be run in a mutex.Lock()
+func (pl *List) ejectPeer(t *peerThunk) {
+	id := t.peer.Identifier()
+	if pl.availablePeers[id] != t {
+		// The peer was already ejected, removed, or lost its connection.
+		return
+	}
+
+	total := len(pl.availablePeers) + len(pl.unavailablePeers) + len(pl.ejectedPeers)
+	maxEjected := total * pl.outlierDetection.MaxEjectionPercent / 100
+	if maxEjected < 1 {
+		maxEjected = 1
+	}
+	if len(pl.ejectedPeers) >= maxEjected {
+		pl.logger.Warn("Not ejecting outlier peer: too many peers are already ejected.",
+			zap.String("peer", id),
+			zap.Int("ejectedPeers", len(pl.ejectedPeers)),
+			zap.Int("maxEjectionPercent", pl.outlierDetection.MaxEjectionPercent),
+		)
+		return
+	}
+
+	_ = pl.removeFromAvailablePeers(t)
+	pl.ejectedPeers[id] = t
+	d := t.outlier.eject(pl.outlierDetection, pl.clock, func() {
+		pl.restoreEjectedPeer(t)
+	})
+	pl.ejections.Inc()
+	pl.updateEjectedGauge()
+	pl.logger.Info("Ejected outlier peer.", zap.String("peer", id), zap.Duration("duration", d))
+}
+
+// restoreEjectedPeer gets called when the ejection of a peer expires, and
+// returns the peer to the available or unavailable peers.
+func (pl *List) restoreEjectedPeer(t *peerThunk) {
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	id := t.peer.Identifier()
+	if pl.ejectedPeers[id] != t {
+		// The peer was removed while ejected.
+		return
+	}
+
+	pl.removeFromEjectedPeers(t)
+	t.outlier.restore(pl.clock.Now())
+	if err := pl.addPeer(t); err != nil {
+		pl.logger.Error("Failed to restore ejected peer.", zap.String("peer", id), zap.Error(err))
+		return
+	}
+	pl.logger.Info("Restored ejected peer.", zap.String("peer", id))
+}
+
+// updateEjectedGauge reports the number of currently ejected peers.
+// Must be run in a mutex.Lock()
+func (pl *List) updateEjectedGauge() {
+	pl.ejectedGauge.Store(int64(len(pl.ejectedPeers)))
+}
+
 // Available returns whether the identifier peer is available for traffic.
 func (pl *List) Available(p peer.Identifier) bool {
 	_, ok := pl.availablePeers[p.Identifier()]
@@ -552,8 +896,16 @@ func (pl *List) Uninitialized(p peer.Identifier) bool {
 	return ok
 }
 
-// Peers returns a snapshot of all retained (available and
-// unavailable) peers.
+// Ejected returns whether the identified peer is ejected as an outlier.
+func (pl *List) Ejected(p peer.Identifier) bool {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
+	_, ok := pl.ejectedPeers[p.Identifier()]
+	return ok
+}
+
+// Peers returns a snapshot of all retained (available, unavailable, and
+// ejected) peers.
 func (pl *List) Peers() []peer.Peer {
 	pl.lock.RLock()
 	defer pl.lock.RUnlock()
@@ -564,21 +916,37 @@ func (pl *List) Peers() []peer.Peer {
 	for _, t := range pl.unavailablePeers {
 		peers = append(peers, t.peer)
 	}
+	for _, t := range pl.ejectedPeers {
+		peers = append(peers, t.peer)
+	}
 	return peers
 }
 
 // NumAvailable returns how many peers are available.
 func (pl *List) NumAvailable() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.availablePeers)
 }
 
 // NumUnavailable returns how many peers are unavailable.
 func (pl *List) NumUnavailable() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.unavailablePeers)
 }
 
+// NumEjected returns how many peers are ejected as outliers.
+func (pl *List) NumEjected() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
+	return len(pl.ejectedPeers)
+}
+
 // NumUninitialized returns how many peers are unavailable.
 func (pl *List) NumUninitialized() int {
+	pl.lock.RLock()
+	defer pl.lock.RUnlock()
 	return len(pl.uninitializedPeers)
 }
 
@@ -598,10 +966,14 @@ func (pl *List) Introspect() introspection.ChooserStatus {
 	for _, t := range pl.unavailablePeers {
 		unavailables = append(unavailables, t.peer)
 	}
+	ejecteds := make([]peer.Peer, 0, len(pl.ejectedPeers))
+	for _, t := range pl.ejectedPeers {
+		ejecteds = append(ejecteds, t.peer)
+	}
 	pl.lock.Unlock()
 
-	peersStatus := make([]introspection.PeerStatus, 0,
-		len(availables)+len(unavailables))
+	total := len(availables) + len(unavailables) + len(ejecteds)
+	peersStatus := make([]introspection.PeerStatus, 0, total)
 
 	buildPeerStatus := func(peer peer.Peer) introspection.PeerStatus {
 		ps := peer.Status()
@@ -621,10 +993,16 @@ func (pl *List) Introspect() introspection.ChooserStatus {
 		peersStatus = append(peersStatus, buildPeerStatus(peer))
 	}
 
+	for _, peer := range ejecteds {
+		ps := buildPeerStatus(peer)
+		ps.State += ", ejected"
+		peersStatus = append(peersStatus, ps)
+	}
+
 	return introspection.ChooserStatus{
 		Name: "Single",
 		State: fmt.Sprintf("%s (%d/%d available)", state, len(availables),
-			len(availables)+len(unavailables)),
+			total),
 		Peers: peersStatus,
 	}
 }
@@ -23,12 +23,15 @@ package peerlist
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
 	"go.uber.org/yarpc/peer/hostport"
 	"go.uber.org/yarpc/yarpctest"
 )
@@ -222,3 +225,91 @@ func TestPeerList(t *testing.T) {
 		},
 	}))
 }
+
+// ringList is a peer list implementation for tests that chooses available
+// peers in the order they were added, wrapping around.
+type ringList struct {
+	firstList
+	next int
+}
+
+var _ Implementation = (*ringList)(nil)
+
+func (l *ringList) Choose(ctx context.Context, req *transport.Request) peer.StatusPeer {
+	if len(l.peers) == 0 {
+		return nil
+	}
+	p := l.peers[l.next%len(l.peers)]
+	l.next++
+	return p
+}
+
+func TestSlowStart(t *testing.T) {
+	const window = 10 * time.Second
+
+	clk := clock.NewFake()
+	fake := yarpctest.NewFakeTransport()
+	list := New("slow", fake, &ringList{}, NoShuffle(), Seed(0), withClock(clk), SlowStart(window))
+
+	p1 := hostport.Identify("1.1.1.1:4040")
+	p2 := hostport.Identify("2.2.2.2:4040")
+	p3 := hostport.Identify("3.3.3.3:4040")
+
+	// Peers that become available as the list starts are not subject to slow
+	// start.
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{p1}}))
+	require.NoError(t, list.Start())
+	defer list.Stop()
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{p2}}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+
+	countChosen := func(pid peer.Identifier) int {
+		var n int
+		for i := 0; i < 300; i++ {
+			p, onFinish, err := list.Choose(ctx, &transport.Request{})
+			require.NoError(t, err)
+			onFinish(nil)
+			if p.Identifier() == pid.Identifier() {
+				n++
+			}
+		}
+		return n
+	}
+
+	assert.Equal(t, 150, countChosen(p2), "peers available at start must receive a full share")
+
+	clk.Add(window)
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{p3}}))
+	assert.Equal(t, 0, countChosen(p3), "a new peer must not receive requests at the start of its window")
+
+	clk.Add(window / 2)
+	n := countChosen(p3)
+	assert.True(t, n > 0 && n < 100, "a warming peer must receive a partial share, got %d", n)
+
+	clk.Add(window / 2)
+	assert.Equal(t, 100, countChosen(p3), "a warm peer must receive a full share")
+}
+
+func TestNumAvailableConcurrentWithUpdates(t *testing.T) {
+	fake := yarpctest.NewFakeTransport()
+	list := New("mra", fake, &mraList{})
+	require.NoError(t, list.Start())
+	defer list.Stop()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{id1}}))
+			assert.NoError(t, list.Update(peer.ListUpdates{Removals: []peer.Identifier{id1}}))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		n := list.NumAvailable() + list.NumUnavailable()
+		assert.True(t, n <= 1, "expected at most one peer, got %d", n)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package peerlist
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+var defaultOutlierDetectionConfig = OutlierDetectionConfig{
+	ConsecutiveFailures:  5,
+	FailureRate:          0.5,
+	MinimumRequests:      20,
+	Interval:             10 * time.Second,
+	BaseEjectionDuration: 30 * time.Second,
+	MaxEjectionDuration:  5 * time.Minute,
+	MaxEjectionPercent:   10,
+}
+
+// OutlierDetectionConfig configures how a peer list tracks the results of
+// calls to each of its peers and temporarily ejects peers that misbehave.
+//
+// A peer is ejected when it returns ConsecutiveFailures failures in a row, or
+// when at least MinimumRequests requests finished within the current
+// Interval and the fraction of them that failed reaches FailureRate.
+// Only errors that indicate a fault on the server side or in the transport
+// count as failures; errors caused by the caller, like invalid arguments,
+// do not.
+//
+// An ejected peer is not chosen until its ejection expires.
+// The first ejection lasts BaseEjectionDuration and every consecutive
+// ejection of the same peer lasts twice as long, up to MaxEjectionDuration.
+// A peer that stays healthy for MaxEjectionDuration after returning to
+// rotation starts over at BaseEjectionDuration.
+// The list never ejects more than MaxEjectionPercent of its peers at once,
+// although it always allows ejecting at least one peer.
+//
+// Zero values take their defaults.
+//
+//   outlierDetection:
+//     consecutiveFailures: 5
+//     failureRate: 0.5
+//     minimumRequests: 20
+//     interval: 10s
+//     baseEjectionDuration: 30s
+//     maxEjectionDuration: 5m
+//     maxEjectionPercent: 10
+type OutlierDetectionConfig struct {
+	// Defaults to 5.
+	ConsecutiveFailures int `config:"consecutiveFailures"`
+	// Defaults to 0.5.
+	FailureRate float64 `config:"failureRate"`
+	// Defaults to 20.
+	MinimumRequests int `config:"minimumRequests"`
+	// Defaults to 10s.
+	Interval time.Duration `config:"interval"`
+	// Defaults to 30s.
+	BaseEjectionDuration time.Duration `config:"baseEjectionDuration"`
+	// Defaults to 5m.
+	MaxEjectionDuration time.Duration `config:"maxEjectionDuration"`
+	// Defaults to 10.
+	MaxEjectionPercent int `config:"maxEjectionPercent"`
+}
+
+// withDefaults returns a copy of the configuration with zero values replaced
+// by their defaults.
+func (c OutlierDetectionConfig) withDefaults() OutlierDetectionConfig {
+	if c.ConsecutiveFailures <= 0 {
+		c.ConsecutiveFailures = defaultOutlierDetectionConfig.ConsecutiveFailures
+	}
+	if c.FailureRate <= 0 {
+		c.FailureRate = defaultOutlierDetectionConfig.FailureRate
+	}
+	if c.MinimumRequests <= 0 {
+		c.MinimumRequests = defaultOutlierDetectionConfig.MinimumRequests
+	}
+	if c.Interval <= 0 {
+		c.Interval = defaultOutlierDetectionConfig.Interval
+	}
+	if c.BaseEjectionDuration <= 0 {
+		c.BaseEjectionDuration = defaultOutlierDetectionConfig.BaseEjectionDuration
+	}
+	if c.MaxEjectionDuration <= 0 {
+		c.MaxEjectionDuration = defaultOutlierDetectionConfig.MaxEjectionDuration
+	}
+	if c.MaxEjectionDuration < c.BaseEjectionDuration {
+		c.MaxEjectionDuration = c.BaseEjectionDuration
+	}
+	if c.MaxEjectionPercent <= 0 {
+		c.MaxEjectionPercent = defaultOutlierDetectionConfig.MaxEjectionPercent
+	}
+	if c.MaxEjectionPercent > 100 {
+		c.MaxEjectionPercent = 100
+	}
+	return c
+}
+
+// ejectionDuration returns how long a peer stays ejected for its nth
+// consecutive ejection, counting from 1.
+func (c OutlierDetectionConfig) ejectionDuration(n int) time.Duration {
+	d := c.BaseEjectionDuration
+	for i := 1; i < n; i++ {
+		d *= 2
+		if d >= c.MaxEjectionDuration {
+			return c.MaxEjectionDuration
+		}
+	}
+	return d
+}
+
+// isOutlierFailure returns whether the error returned by a call indicates
+// that the peer, rather than the caller, is at fault.
+func isOutlierFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if !yarpcerrors.IsStatus(err) {
+		return true
+	}
+	switch yarpcerrors.FromError(err).Code() {
+	case yarpcerrors.CodeCancelled,
+		yarpcerrors.CodeInvalidArgument,
+		yarpcerrors.CodeNotFound,
+		yarpcerrors.CodeAlreadyExists,
+		yarpcerrors.CodePermissionDenied,
+		yarpcerrors.CodeFailedPrecondition,
+		yarpcerrors.CodeAborted,
+		yarpcerrors.CodeOutOfRange,
+		yarpcerrors.CodeUnimplemented,
+		yarpcerrors.CodeUnauthenticated:
+		return false
+	}
+	return true
+}
+
+// outlierStats tracks the results of calls to a single peer.
+type outlierStats struct {
+	lock sync.Mutex
+
+	intervalStart       time.Time
+	successes           int
+	failures            int
+	consecutiveFailures int
+
+	// ejections is the number of consecutive times the peer has been ejected.
+	ejections  int
+	restoredAt time.Time
+	timer      clock.Timer
+}
+
+// record captures the outcome of a call and returns whether the peer should
+// be ejected.
+func (s *outlierStats) record(cfg *OutlierDetectionConfig, now time.Time, err error) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if now.Sub(s.intervalStart) >= cfg.Interval {
+		s.intervalStart = now
+		s.successes = 0
+		s.failures = 0
+	}
+
+	if !isOutlierFailure(err) {
+		s.successes++
+		s.consecutiveFailures = 0
+		return false
+	}
+
+	s.failures++
+	s.consecutiveFailures++
+	if s.consecutiveFailures >= cfg.ConsecutiveFailures {
+		return true
+	}
+	total := s.successes + s.failures
+	return total >= cfg.MinimumRequests && float64(s.failures)/float64(total) >= cfg.FailureRate
+}
+
+// eject resets the call statistics, arms the ejection timer and returns
+// the duration of the ejection.
+func (s *outlierStats) eject(cfg *OutlierDetectionConfig, clk clock.Clock, restore func()) time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := clk.Now()
+	if s.ejections > 0 && now.Sub(s.restoredAt) >= cfg.MaxEjectionDuration {
+		s.ejections = 0
+	}
+	s.ejections++
+	s.intervalStart = now
+	s.successes = 0
+	s.failures = 0
+	s.consecutiveFailures = 0
+
+	d := cfg.ejectionDuration(s.ejections)
+	s.timer = clk.AfterFunc(d, restore)
+	return d
+}
+
+// restore records that the peer has returned to rotation.
+func (s *outlierStats) restore(now time.Time) {
+	s.lock.Lock()
+	s.timer = nil
+	s.restoredAt = now
+	s.lock.Unlock()
+}
+
+// cancel stops a pending ejection timer, if any.
+func (s *outlierStats) cancel() {
+	s.lock.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.lock.Unlock()
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package peerlist
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/yarpcerrors"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+// firstList is a peer list implementation for tests that always chooses the
+// earliest added available peer.
+type firstList struct {
+	peers []peer.StatusPeer
+}
+
+var _ Implementation = (*firstList)(nil)
+
+func (l *firstList) Add(p peer.StatusPeer, pid peer.Identifier) peer.Subscriber {
+	l.peers = append(l.peers, p)
+	return &mraSub{}
+}
+
+func (l *firstList) Remove(p peer.StatusPeer, pid peer.Identifier, ps peer.Subscriber) {
+	for i, q := range l.peers {
+		if q == p {
+			l.peers = append(l.peers[:i], l.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *firstList) Choose(ctx context.Context, req *transport.Request) peer.StatusPeer {
+	if len(l.peers) == 0 {
+		return nil
+	}
+	return l.peers[0]
+}
+
+func (l *firstList) Start() error    { return nil }
+func (l *firstList) Stop() error     { return nil }
+func (l *firstList) IsRunning() bool { return true }
+
+func TestOutlierDetectionConfigDefaults(t *testing.T) {
+	assert.Equal(t, defaultOutlierDetectionConfig, OutlierDetectionConfig{}.withDefaults())
+
+	cfg := OutlierDetectionConfig{
+		BaseEjectionDuration: time.Minute,
+		MaxEjectionDuration:  time.Second,
+		MaxEjectionPercent:   200,
+	}.withDefaults()
+	assert.Equal(t, time.Minute, cfg.MaxEjectionDuration, "max ejection duration must not be less than the base")
+	assert.Equal(t, 100, cfg.MaxEjectionPercent)
+}
+
+func TestOutlierDetectionEjectionDuration(t *testing.T) {
+	cfg := OutlierDetectionConfig{
+		BaseEjectionDuration: time.Second,
+		MaxEjectionDuration:  5 * time.Second,
+	}.withDefaults()
+
+	assert.Equal(t, time.Second, cfg.ejectionDuration(1))
+	assert.Equal(t, 2*time.Second, cfg.ejectionDuration(2))
+	assert.Equal(t, 4*time.Second, cfg.ejectionDuration(3))
+	assert.Equal(t, 5*time.Second, cfg.ejectionDuration(4))
+	assert.Equal(t, 5*time.Second, cfg.ejectionDuration(100))
+}
+
+func TestIsOutlierFailure(t *testing.T) {
+	assert.False(t, isOutlierFailure(nil))
+	assert.True(t, isOutlierFailure(errors.New("connection refused")))
+	assert.True(t, isOutlierFailure(yarpcerrors.UnavailableErrorf("unavailable")))
+	assert.True(t, isOutlierFailure(yarpcerrors.InternalErrorf("internal")))
+	assert.True(t, isOutlierFailure(yarpcerrors.DeadlineExceededErrorf("timeout")))
+	assert.False(t, isOutlierFailure(yarpcerrors.InvalidArgumentErrorf("bad request")))
+	assert.False(t, isOutlierFailure(yarpcerrors.NotFoundErrorf("not found")))
+}
+
+func TestOutlierStatsFailureRate(t *testing.T) {
+	cfg := OutlierDetectionConfig{
+		ConsecutiveFailures: 100,
+		FailureRate:         0.5,
+		MinimumRequests:     4,
+		Interval:            time.Second,
+	}.withDefaults()
+	now := time.Unix(0, 0)
+	failure := yarpcerrors.InternalErrorf("internal")
+
+	var s outlierStats
+	assert.False(t, s.record(&cfg, now, nil))
+	assert.False(t, s.record(&cfg, now, failure))
+	assert.False(t, s.record(&cfg, now, nil))
+	assert.True(t, s.record(&cfg, now, failure), "must eject at 50% failures over 4 requests")
+
+	// A new interval starts from scratch.
+	now = now.Add(time.Second)
+	assert.False(t, s.record(&cfg, now, failure))
+	assert.False(t, s.record(&cfg, now, nil))
+	assert.False(t, s.record(&cfg, now, nil))
+	assert.False(t, s.record(&cfg, now, nil))
+}
+
+func TestOutlierDetection(t *testing.T) {
+	clk := clock.NewFake()
+	fake := yarpctest.NewFakeTransport()
+	list := New("outlier", fake, &firstList{}, NoShuffle(), withClock(clk), OutlierDetection(OutlierDetectionConfig{
+		ConsecutiveFailures:  2,
+		BaseEjectionDuration: time.Second,
+		MaxEjectionDuration:  time.Minute,
+		MaxEjectionPercent:   50,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+
+	p1 := hostport.Identify("1.1.1.1:4040")
+	p2 := hostport.Identify("2.2.2.2:4040")
+	require.NoError(t, list.Start())
+	defer list.Stop()
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{p1, p2}}))
+
+	finish := func(pid peer.Identifier, err error) {
+		list.lock.RLock()
+		th := list.getThunk(pid)
+		list.lock.RUnlock()
+		th.StartRequest()
+		th.boundOnFinish(err)
+	}
+
+	// Errors that are the caller's fault do not count.
+	finish(p1, yarpcerrors.InvalidArgumentErrorf("bad request"))
+	finish(p1, yarpcerrors.InvalidArgumentErrorf("bad request"))
+	assert.Equal(t, 0, list.NumEjected())
+
+	// Consecutive failures eject the peer.
+	finish(p1, yarpcerrors.UnavailableErrorf("unavailable"))
+	finish(p1, yarpcerrors.UnavailableErrorf("unavailable"))
+	assert.Equal(t, 1, list.NumEjected())
+	assert.True(t, list.Ejected(p1))
+	assert.Equal(t, 1, list.NumAvailable())
+	assert.Len(t, list.Peers(), 2)
+	assert.Len(t, list.Introspect().Peers, 2)
+
+	p, onFinish, err := list.Choose(ctx, &transport.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, p2.Identifier(), p.Identifier(), "must not choose an ejected peer")
+	onFinish(nil)
+
+	// The maximum ejection percent prevents ejecting the remaining peer.
+	finish(p2, yarpcerrors.UnavailableErrorf("unavailable"))
+	finish(p2, yarpcerrors.UnavailableErrorf("unavailable"))
+	assert.Equal(t, 1, list.NumEjected())
+	assert.False(t, list.Ejected(p2))
+	finish(p2, nil)
+
+	// The ejection expires.
+	clk.Add(time.Second)
+	waitFor(t, func() bool { return list.NumEjected() == 0 })
+	assert.Equal(t, 2, list.NumAvailable())
+
+	// A second ejection lasts twice as long.
+	finish(p1, errors.New("connection reset"))
+	finish(p1, errors.New("connection reset"))
+	assert.Equal(t, 1, list.NumEjected())
+	clk.Add(time.Second)
+	assert.Equal(t, 1, list.NumEjected())
+	clk.Add(time.Second)
+	waitFor(t, func() bool { return list.NumEjected() == 0 })
+
+	// Removing an ejected peer releases it.
+	finish(p1, errors.New("connection reset"))
+	finish(p1, errors.New("connection reset"))
+	assert.Equal(t, 1, list.NumEjected())
+	require.NoError(t, list.Update(peer.ListUpdates{Removals: []peer.Identifier{p1}}))
+	assert.Equal(t, 0, list.NumEjected())
+	assert.Len(t, list.Peers(), 1)
+}
+
+func TestOutlierDetectionStopReleasesEjectedPeers(t *testing.T) {
+	clk := clock.NewFake()
+	fake := yarpctest.NewFakeTransport()
+	list := New("outlier", fake, &firstList{}, withClock(clk), OutlierDetection(OutlierDetectionConfig{
+		ConsecutiveFailures: 1,
+	}))
+
+	pid := hostport.Identify("1.1.1.1:4040")
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{pid}}))
+	require.NoError(t, list.Start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime)
+	defer cancel()
+	_, onFinish, err := list.Choose(ctx, &transport.Request{})
+	require.NoError(t, err)
+	onFinish(yarpcerrors.InternalErrorf("internal"))
+	assert.True(t, list.Ejected(pid))
+
+	require.NoError(t, list.Stop())
+	assert.Equal(t, 0, list.NumEjected())
+	assert.True(t, list.Uninitialized(pid))
+}
+
+func TestChoosePeerRecordsResults(t *testing.T) {
+	fake := yarpctest.NewFakeTransport()
+	list := New("outlier", fake, &firstList{}, OutlierDetection(OutlierDetectionConfig{
+		ConsecutiveFailures: 2,
+		MaxEjectionPercent:  50,
+	}))
+
+	p1 := hostport.Identify("1.1.1.1:4040")
+	p2 := hostport.Identify("2.2.2.2:4040")
+	require.NoError(t, list.Start())
+	defer list.Stop()
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{p1, p2}}))
+
+	_, _, ok := list.ChoosePeer(hostport.Identify("3.3.3.3:4040"))
+	assert.False(t, ok, "must not choose a peer that is not in the list")
+
+	for i := 0; i < 2; i++ {
+		p, onFinish, ok := list.ChoosePeer(p2)
+		require.True(t, ok)
+		assert.Equal(t, p2.Identifier(), p.Identifier())
+		assert.Equal(t, 1, p.(peer.StatusPeer).Status().PendingRequestCount)
+		onFinish(yarpcerrors.UnavailableErrorf("unavailable"))
+	}
+	assert.True(t, list.Ejected(p2), "results of chosen peers must count for outlier detection")
+
+	_, _, ok = list.ChoosePeer(p2)
+	assert.False(t, ok, "must not choose an ejected peer")
+}
+
+func TestNumEjectedConcurrentWithEjections(t *testing.T) {
+	fake := yarpctest.NewFakeTransport()
+	list := New("outlier", fake, &firstList{}, OutlierDetection(OutlierDetectionConfig{
+		ConsecutiveFailures:  1,
+		BaseEjectionDuration: time.Millisecond,
+		MaxEjectionPercent:   100,
+	}))
+
+	pid := hostport.Identify("1.1.1.1:4040")
+	require.NoError(t, list.Start())
+	defer list.Stop()
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{pid}}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, onFinish, ok := list.ChoosePeer(pid); ok {
+				onFinish(yarpcerrors.UnavailableErrorf("unavailable"))
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		assert.True(t, list.NumEjected() <= 1)
+		_ = list.Ejected(pid)
+	}
+	wg.Wait()
+}
+
+const testtime = time.Second
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(testtime)
+	for !cond() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	require.True(t, cond(), "timed out waiting for condition")
+}
@@ -22,6 +22,7 @@ package peerlist
 
 import (
 	"sync"
+	"time"
 
 	"go.uber.org/yarpc/api/peer"
 )
@@ -35,10 +36,16 @@ type peerThunk struct {
 	peer          peer.Peer
 	subscriber    peer.Subscriber
 	boundOnFinish func(error)
+	outlier       outlierStats
+
+	// availableSince is when the peer last became available, which
+	// determines how far it is into its slow start window.
+	availableSince time.Time
 }
 
-func (t *peerThunk) onFinish(error) {
+func (t *peerThunk) onFinish(err error) {
 	t.peer.EndRequest()
+	t.list.recordResult(t, err)
 }
 
 func (t *peerThunk) Identifier() string {
@@ -22,15 +22,19 @@ package pendingheap
 
 import (
 	"fmt"
+	"time"
 
 	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/peer/peerlist/v2"
 	"go.uber.org/yarpc/yarpcconfig"
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
 // Configuration descripes how to build a fewest pending heap peer list.
 type Configuration struct {
-	Capacity *int `config:"capacity"`
+	Capacity         *int                             `config:"capacity"`
+	OutlierDetection *peerlist.OutlierDetectionConfig `config:"outlierDetection"`
+	SlowStart        time.Duration                    `config:"slowStart"`
 }
 
 // Spec returns a configuration specification for the pending heap peer list
@@ -51,20 +55,45 @@ type Configuration struct {
 //            peers:
 //              - 127.0.0.1:8080
 //              - 127.0.0.1:8081
+//
+// The peer list can optionally eject peers that fail too often, and ramp up
+// the share of requests that newly available peers receive:
+//
+//          fewest-pending-requests:
+//            peers:
+//              - 127.0.0.1:8080
+//              - 127.0.0.1:8081
+//            outlierDetection:
+//              consecutiveFailures: 5
+//              baseEjectionDuration: 30s
+//            slowStart: 30s
 func Spec() yarpcconfig.PeerListSpec {
 	return yarpcconfig.PeerListSpec{
 		Name: "fewest-pending-requests",
 		BuildPeerList: func(cfg Configuration, t peer.Transport, k *yarpcconfig.Kit) (peer.ChooserList, error) {
-			if cfg.Capacity == nil {
-				return New(t), nil
+			var opts []ListOption
+
+			if cfg.Capacity != nil {
+				if *cfg.Capacity <= 0 {
+					return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument,
+						fmt.Sprintf("Capacity must be greater than 0. Got: %d.", *cfg.Capacity))
+				}
+				opts = append(opts, Capacity(*cfg.Capacity))
+			}
+
+			if cfg.OutlierDetection != nil {
+				opts = append(opts, OutlierDetection(*cfg.OutlierDetection))
+			}
+			if cfg.SlowStart > 0 {
+				opts = append(opts, SlowStart(cfg.SlowStart))
 			}
 
-			if *cfg.Capacity <= 0 {
-				return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument,
-					fmt.Sprintf("Capacity must be greater than 0. Got: %d.", *cfg.Capacity))
+			opts = append(opts, Logger(k.Logger()))
+			if meter := k.Meter(); meter != nil {
+				opts = append(opts, Meter(meter))
 			}
 
-			return New(t, Capacity(*cfg.Capacity)), nil
+			return New(t, opts...), nil
 		},
 	}
 }
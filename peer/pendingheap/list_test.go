@@ -28,10 +28,14 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/multierr"
 	"go.uber.org/yarpc/api/peer"
 	. "go.uber.org/yarpc/api/peer/peertest"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/peer/hostport"
 	"go.uber.org/yarpc/yarpcerrors"
+	"go.uber.org/yarpc/yarpctest"
 )
 
 var (
@@ -616,3 +620,46 @@ func TestPeerHeapList(t *testing.T) {
 var noShuffle ListOption = func(c *listConfig) {
 	c.shuffle = false
 }
+
+func TestSlowStartPassesOverIdleWarmingPeer(t *testing.T) {
+	const window = 200 * time.Millisecond
+
+	fake := yarpctest.NewFakeTransport()
+	pl := New(fake, noShuffle, SlowStart(window))
+
+	p1 := hostport.Identify("1.1.1.1:4040")
+	p2 := hostport.Identify("2.2.2.2:4040")
+	p3 := hostport.Identify("3.3.3.3:4040")
+
+	require.NoError(t, pl.Update(peer.ListUpdates{Additions: []peer.Identifier{p1, p2}}))
+	require.NoError(t, pl.Start())
+	defer pl.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Keep a request pending on each warm peer, so that the heap always
+	// offers the new, idle peer first.
+	for i := 0; i < 2; i++ {
+		_, _, err := pl.Choose(ctx, &transport.Request{})
+		require.NoError(t, err)
+	}
+	fake.SimulateConnect(p1)
+	fake.SimulateConnect(p2)
+
+	// Peers that become available in the first window after the list starts
+	// are not subject to slow start.
+	time.Sleep(window)
+	require.NoError(t, pl.Update(peer.ListUpdates{Additions: []peer.Identifier{p3}}))
+
+	var n int
+	for i := 0; i < 20; i++ {
+		p, onFinish, err := pl.Choose(ctx, &transport.Request{})
+		require.NoError(t, err)
+		onFinish(nil)
+		if p.Identifier() == p3.Identifier() {
+			n++
+		}
+	}
+	assert.True(t, n < 10, "a peer at the start of its window must receive few requests, got %d", n)
+}
@@ -21,10 +21,19 @@
 package randpeer
 
 import (
+	"time"
+
 	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/peer/peerlist/v2"
 	"go.uber.org/yarpc/yarpcconfig"
 )
 
+// Configuration describes how to build a random peer list.
+type Configuration struct {
+	OutlierDetection *peerlist.OutlierDetectionConfig `config:"outlierDetection"`
+	SlowStart        time.Duration                    `config:"slowStart"`
+}
+
 // Spec returns a configuration specification for the random peer list
 // implementation, making it possible to select a random peer with transports
 // that use outbound peer list configuration (like HTTP).
@@ -43,11 +52,36 @@ import (
 //            peers:
 //              - 127.0.0.1:8080
 //              - 127.0.0.1:8081
+//
+// The peer list can optionally eject peers that fail too often, and ramp up
+// the share of requests that newly available peers receive:
+//
+//          random:
+//            peers:
+//              - 127.0.0.1:8080
+//              - 127.0.0.1:8081
+//            outlierDetection:
+//              consecutiveFailures: 5
+//              baseEjectionDuration: 30s
+//            slowStart: 30s
 func Spec() yarpcconfig.PeerListSpec {
 	return yarpcconfig.PeerListSpec{
 		Name: "random",
-		BuildPeerList: func(c struct{}, t peer.Transport, k *yarpcconfig.Kit) (peer.ChooserList, error) {
-			return New(t), nil
+		BuildPeerList: func(cfg Configuration, t peer.Transport, k *yarpcconfig.Kit) (peer.ChooserList, error) {
+			var opts []ListOption
+			if cfg.OutlierDetection != nil {
+				opts = append(opts, OutlierDetection(*cfg.OutlierDetection))
+			}
+			if cfg.SlowStart > 0 {
+				opts = append(opts, SlowStart(cfg.SlowStart))
+			}
+
+			opts = append(opts, Logger(k.Logger()))
+			if meter := k.Meter(); meter != nil {
+				opts = append(opts, Meter(meter))
+			}
+
+			return New(t, opts...), nil
 		},
 	}
 }
@@ -22,10 +22,12 @@ package roundrobin
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/peer/peerlist/v2"
 	"go.uber.org/yarpc/yarpcconfig"
 	"go.uber.org/yarpc/yarpctest"
 )
@@ -60,6 +62,20 @@ func TestPendingHeapConfig(t *testing.T) {
 				Capacity: &twenty,
 			},
 		},
+		{
+			name: "outlier detection",
+			cfg: Configuration{
+				OutlierDetection: &peerlist.OutlierDetectionConfig{
+					ConsecutiveFailures: 3,
+				},
+			},
+		},
+		{
+			name: "slow start",
+			cfg: Configuration{
+				SlowStart: 30 * time.Second,
+			},
+		},
 	}
 
 	s := Spec()
@@ -23,14 +23,20 @@ package roundrobin
 import (
 	"time"
 
+	"go.uber.org/net/metrics"
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/peer/peerlist/v2"
+	"go.uber.org/zap"
 )
 
 type listConfig struct {
-	capacity int
-	shuffle  bool
-	seed     int64
+	capacity         int
+	shuffle          bool
+	seed             int64
+	outlierDetection *peerlist.OutlierDetectionConfig
+	slowStart        time.Duration
+	logger           *zap.Logger
+	meter            *metrics.Scope
 }
 
 var defaultListConfig = listConfig{
@@ -52,6 +58,50 @@ func Capacity(capacity int) ListOption {
 	}
 }
 
+// OutlierDetection enables temporarily ejecting peers that fail too often
+// from selection.
+// See peerlist.OutlierDetectionConfig for details.
+//
+// Outlier detection is disabled by default.
+func OutlierDetection(cfg peerlist.OutlierDetectionConfig) ListOption {
+	return func(c *listConfig) {
+		c.outlierDetection = &cfg
+	}
+}
+
+// SlowStart specifies a window over which a newly available peer ramps up to
+// its full share of requests.
+// See peerlist.SlowStart for details.
+//
+// Slow start is disabled by default.
+func SlowStart(window time.Duration) ListOption {
+	return func(c *listConfig) {
+		c.slowStart = window
+	}
+}
+
+// Logger specifies a logger for the peer list, which reports peer ejections
+// and restorations.
+// See peerlist.Logger for details.
+//
+// Defaults to a no-op logger.
+func Logger(logger *zap.Logger) ListOption {
+	return func(c *listConfig) {
+		c.logger = logger
+	}
+}
+
+// Meter specifies a metrics scope for the peer list, which reports peer
+// ejections.
+// See peerlist.Meter for details.
+//
+// Metrics are not reported by default.
+func Meter(meter *metrics.Scope) ListOption {
+	return func(c *listConfig) {
+		c.meter = meter
+	}
+}
+
 // New creates a new round robin peer list.
 func New(transport peer.Transport, opts ...ListOption) *List {
 	cfg := defaultListConfig
@@ -66,6 +116,18 @@ func New(transport peer.Transport, opts ...ListOption) *List {
 	if !cfg.shuffle {
 		plOpts = append(plOpts, peerlist.NoShuffle())
 	}
+	if cfg.outlierDetection != nil {
+		plOpts = append(plOpts, peerlist.OutlierDetection(*cfg.outlierDetection))
+	}
+	if cfg.slowStart > 0 {
+		plOpts = append(plOpts, peerlist.SlowStart(cfg.slowStart))
+	}
+	if cfg.logger != nil {
+		plOpts = append(plOpts, peerlist.Logger(cfg.logger))
+	}
+	if cfg.meter != nil {
+		plOpts = append(plOpts, peerlist.Meter(cfg.meter))
+	}
 
 	return &List{
 		List: peerlist.New(
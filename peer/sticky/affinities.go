@@ -0,0 +1,121 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sticky
+
+import (
+	"container/list"
+	"time"
+
+	"go.uber.org/yarpc/api/peer"
+)
+
+// affinity is a routing key pinned to a peer.
+type affinity struct {
+	key     string
+	peer    peer.Peer
+	expires time.Time
+}
+
+// affinities is a least recently used cache of affinities, bounded in size.
+// affinities is not safe for concurrent use.
+type affinities struct {
+	capacity int
+	order    *list.List // of *affinity, most recently used first
+	keys     map[string]*list.Element
+}
+
+func newAffinities(capacity int) *affinities {
+	return &affinities{
+		capacity: capacity,
+		order:    list.New(),
+		keys:     make(map[string]*list.Element),
+	}
+}
+
+// Get returns the peer pinned to the given key, if the affinity has not
+// expired, and marks the affinity as recently used.
+func (a *affinities) Get(key string, now time.Time) (peer.Peer, bool) {
+	e, ok := a.keys[key]
+	if !ok {
+		return nil, false
+	}
+	aff := e.Value.(*affinity)
+	if !now.Before(aff.expires) {
+		a.remove(e)
+		return nil, false
+	}
+	a.order.MoveToFront(e)
+	return aff.peer, true
+}
+
+// Put pins the key to the peer until the given expiry, evicting the least
+// recently used affinity if the cache is full.
+func (a *affinities) Put(key string, p peer.Peer, expires time.Time) {
+	if e, ok := a.keys[key]; ok {
+		aff := e.Value.(*affinity)
+		aff.peer = p
+		aff.expires = expires
+		a.order.MoveToFront(e)
+		return
+	}
+
+	a.keys[key] = a.order.PushFront(&affinity{key: key, peer: p, expires: expires})
+	for a.order.Len() > a.capacity {
+		a.remove(a.order.Back())
+	}
+}
+
+// Touch extends the expiry of the affinity for the given key.
+func (a *affinities) Touch(key string, expires time.Time) {
+	if e, ok := a.keys[key]; ok {
+		e.Value.(*affinity).expires = expires
+	}
+}
+
+// Remove forgets the affinity for the given key.
+func (a *affinities) Remove(key string) {
+	if e, ok := a.keys[key]; ok {
+		a.remove(e)
+	}
+}
+
+// RemovePeers forgets all affinities to the peers with the given
+// identifiers.
+func (a *affinities) RemovePeers(ids map[string]struct{}) {
+	for e := a.order.Front(); e != nil; {
+		next := e.Next()
+		if _, ok := ids[e.Value.(*affinity).peer.Identifier()]; ok {
+			a.remove(e)
+		}
+		e = next
+	}
+}
+
+// Len returns the number of affinities, including expired ones that have
+// not been evicted yet.
+func (a *affinities) Len() int {
+	return a.order.Len()
+}
+
+func (a *affinities) remove(e *list.Element) {
+	delete(a.keys, e.Value.(*affinity).key)
+	a.order.Remove(e)
+}
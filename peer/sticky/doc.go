@@ -0,0 +1,45 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package sticky provides a peer list wrapper that sends requests with the
+// same routing key to the same peer.
+//
+// Session-oriented services often keep state for a session in memory.
+// The sticky list remembers the peer it chose for each routing key and sends
+// subsequent requests with that routing key to the same peer, as long as the
+// peer remains available.
+// With the peer lists of peer/peerlist/v2, like round-robin, requests to a
+// remembered peer still count for the outlier detection of the peer list.
+// When the remembered peer becomes unavailable, is ejected as an outlier, or
+// leaves the peer list, the sticky list chooses a new peer from the peer list
+// it wraps and remembers that peer instead.
+// Requests without a routing key go directly to the wrapped peer list.
+//
+// The sticky list remembers a bounded number of routing keys, forgetting the
+// least recently used routing keys first, and forgets routing keys that have
+// not been used for some time.
+//
+//   list := sticky.New(
+//   	roundrobin.New(transport),
+//   	sticky.Capacity(10000),
+//   	sticky.TTL(time.Hour),
+//   )
+//   chooser := peer.Bind(list, dns.Updater(...))
+package sticky
@@ -0,0 +1,254 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sticky
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/internal/introspection"
+)
+
+type listOptions struct {
+	capacity int
+	ttl      time.Duration
+	clock    clock.Clock
+}
+
+var defaultListOptions = listOptions{
+	capacity: 1000,
+	ttl:      10 * time.Minute,
+}
+
+// ListOption customizes the behavior of a sticky list.
+type ListOption interface {
+	apply(*listOptions)
+}
+
+type listOptionFunc func(*listOptions)
+
+func (f listOptionFunc) apply(options *listOptions) { f(options) }
+
+// Capacity specifies the maximum number of routing keys to remember.
+// Once full, the sticky list forgets the least recently used routing key to
+// remember a new one.
+//
+// Defaults to 1000.
+func Capacity(capacity int) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.capacity = capacity
+	})
+}
+
+// TTL specifies how long the sticky list remembers the peer for a routing
+// key after the last request with that routing key.
+//
+// Defaults to 10 minutes.
+func TTL(ttl time.Duration) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.ttl = ttl
+	})
+}
+
+// withClock specifies the clock that expires routing keys, for tests.
+func withClock(clk clock.Clock) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.clock = clk
+	})
+}
+
+// New creates a sticky list that chooses peers from the given peer list and
+// remembers the peer it chose for each routing key.
+func New(list peer.ChooserList, opts ...ListOption) *List {
+	options := defaultListOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.capacity < 1 {
+		options.capacity = 1
+	}
+	if options.clock == nil {
+		options.clock = clock.NewReal()
+	}
+
+	return &List{
+		list:       list,
+		ttl:        options.ttl,
+		clock:      options.clock,
+		affinities: newAffinities(options.capacity),
+	}
+}
+
+// List is a peer list that sends requests with the same routing key to the
+// same peer while that peer remains available, choosing peers from an
+// underlying peer list.
+type List struct {
+	lock sync.Mutex
+
+	list       peer.ChooserList
+	ttl        time.Duration
+	clock      clock.Clock
+	affinities *affinities
+}
+
+var (
+	_ peer.ChooserList                    = (*List)(nil)
+	_ introspection.IntrospectableChooser = (*List)(nil)
+)
+
+// Choose returns the peer remembered for the routing key of the request, if
+// that peer is still available.
+// Otherwise, Choose chooses a peer from the underlying peer list and
+// remembers it for the routing key.
+//
+// If the underlying peer list can choose a specific peer, like the peer lists
+// of peer/peerlist/v2, requests to a remembered peer go through it, so that
+// it observes their results, and the sticky list forgets the peer once the
+// underlying peer list no longer considers it available, for example
+// because outlier detection ejected it.
+// Otherwise, requests to a remembered peer bypass the underlying peer list.
+func (l *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
+	key := req.RoutingKey
+	if key == "" {
+		return l.list.Choose(ctx, req)
+	}
+
+	now := l.clock.Now()
+	if p, onFinish, ok := l.sticky(key, now); ok {
+		return p, onFinish, nil
+	}
+
+	p, onFinish, err := l.list.Choose(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l.lock.Lock()
+	l.affinities.Put(key, p, now.Add(l.ttl))
+	l.lock.Unlock()
+	return p, onFinish, nil
+}
+
+// peerChooser is implemented by peer lists that can choose a specific peer,
+// like those of peer/peerlist/v2.
+type peerChooser interface {
+	ChoosePeer(peer.Identifier) (peer.Peer, func(error), bool)
+}
+
+// sticky chooses the peer remembered for the routing key if it is still
+// available, and forgets it otherwise.
+func (l *List) sticky(key string, now time.Time) (peer.Peer, func(error), bool) {
+	if !l.list.IsRunning() {
+		// Let the underlying peer list wait for it to start, or report that
+		// it has stopped.
+		return nil, nil, false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	p, ok := l.affinities.Get(key, now)
+	if !ok {
+		return nil, nil, false
+	}
+	p, onFinish, ok := l.choosePeer(p)
+	if !ok {
+		l.affinities.Remove(key)
+		return nil, nil, false
+	}
+	l.affinities.Touch(key, now.Add(l.ttl))
+	return p, onFinish, true
+}
+
+// choosePeer starts a request to the given peer if it is still available.
+func (l *List) choosePeer(p peer.Peer) (peer.Peer, func(error), bool) {
+	if pc, ok := l.list.(peerChooser); ok {
+		return pc.ChoosePeer(p)
+	}
+
+	if p.Status().ConnectionStatus != peer.Available {
+		return nil, nil, false
+	}
+	p.StartRequest()
+	return p, func(error) { p.EndRequest() }, true
+}
+
+// Update forwards the additions and removals of peer identifiers to the
+// underlying peer list and forgets routing keys remembered for removed
+// peers.
+func (l *List) Update(updates peer.ListUpdates) error {
+	err := l.list.Update(updates)
+
+	if len(updates.Removals) > 0 {
+		removed := make(map[string]struct{}, len(updates.Removals))
+		for _, pid := range updates.Removals {
+			removed[pid.Identifier()] = struct{}{}
+		}
+
+		l.lock.Lock()
+		l.affinities.RemovePeers(removed)
+		l.lock.Unlock()
+	}
+	return err
+}
+
+// Len returns the number of remembered routing keys.
+func (l *List) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.affinities.Len()
+}
+
+// Start starts the underlying peer list.
+func (l *List) Start() error {
+	return l.list.Start()
+}
+
+// Stop stops the underlying peer list and forgets all routing keys.
+func (l *List) Stop() error {
+	err := l.list.Stop()
+
+	l.lock.Lock()
+	l.affinities = newAffinities(l.affinities.capacity)
+	l.lock.Unlock()
+	return err
+}
+
+// IsRunning returns whether the underlying peer list is running.
+func (l *List) IsRunning() bool {
+	return l.list.IsRunning()
+}
+
+// Introspect returns the status of the underlying peer list.
+func (l *List) Introspect() introspection.ChooserStatus {
+	var status introspection.ChooserStatus
+	if ic, ok := l.list.(introspection.IntrospectableChooser); ok {
+		status = ic.Introspect()
+	}
+	status.State = fmt.Sprintf("%s (%d sticky routing keys)", status.State, l.Len())
+	return status
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package sticky
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/peer/peerlist/v2"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// fakePeer is a peer with a configurable connection status that counts its
+// pending requests.
+type fakePeer struct {
+	lock    sync.Mutex
+	id      string
+	status  peer.ConnectionStatus
+	pending int
+}
+
+func (p *fakePeer) Identifier() string { return p.id }
+
+func (p *fakePeer) Status() peer.Status {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return peer.Status{ConnectionStatus: p.status, PendingRequestCount: p.pending}
+}
+
+func (p *fakePeer) SetStatus(status peer.ConnectionStatus) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.status = status
+}
+
+func (p *fakePeer) StartRequest() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pending++
+}
+
+func (p *fakePeer) EndRequest() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pending--
+}
+
+// fakeList chooses its available peers in round-robin order.
+type fakeList struct {
+	lock    sync.Mutex
+	running bool
+	peers   []*fakePeer
+	next    int
+}
+
+func (l *fakeList) Update(updates peer.ListUpdates) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, pid := range updates.Removals {
+		for i, p := range l.peers {
+			if p.id == pid.Identifier() {
+				l.peers = append(l.peers[:i], l.peers[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, pid := range updates.Additions {
+		l.peers = append(l.peers, &fakePeer{id: pid.Identifier(), status: peer.Available})
+	}
+	return nil
+}
+
+func (l *fakeList) Choose(context.Context, *transport.Request) (peer.Peer, func(error), error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.running {
+		return nil, nil, errors.New("not running")
+	}
+	for range l.peers {
+		p := l.peers[l.next%len(l.peers)]
+		l.next++
+		if p.Status().ConnectionStatus == peer.Available {
+			p.StartRequest()
+			return p, func(error) { p.EndRequest() }, nil
+		}
+	}
+	return nil, nil, errors.New("no available peers")
+}
+
+func (l *fakeList) Peer(id string) *fakePeer {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, p := range l.peers {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (l *fakeList) Start() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.running = true
+	return nil
+}
+
+func (l *fakeList) Stop() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.running = false
+	return nil
+}
+
+func (l *fakeList) IsRunning() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.running
+}
+
+// fakeTransport retains available fake peers.
+type fakeTransport struct{}
+
+func (fakeTransport) RetainPeer(pid peer.Identifier, _ peer.Subscriber) (peer.Peer, error) {
+	return &fakePeer{id: pid.Identifier(), status: peer.Available}, nil
+}
+
+func (fakeTransport) ReleasePeer(peer.Identifier, peer.Subscriber) error {
+	return nil
+}
+
+// firstList is a peer list implementation that always chooses the earliest
+// added available peer.
+type firstList struct {
+	peers []peer.StatusPeer
+}
+
+func (l *firstList) Add(p peer.StatusPeer, pid peer.Identifier) peer.Subscriber {
+	l.peers = append(l.peers, p)
+	return nil
+}
+
+func (l *firstList) Remove(p peer.StatusPeer, pid peer.Identifier, ps peer.Subscriber) {
+	for i, q := range l.peers {
+		if q == p {
+			l.peers = append(l.peers[:i], l.peers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *firstList) Choose(context.Context, *transport.Request) peer.StatusPeer {
+	if len(l.peers) == 0 {
+		return nil
+	}
+	return l.peers[0]
+}
+
+func (l *firstList) Start() error    { return nil }
+func (l *firstList) Stop() error     { return nil }
+func (l *firstList) IsRunning() bool { return true }
+
+func identifiers(n int) []peer.Identifier {
+	pids := make([]peer.Identifier, n)
+	for i := range pids {
+		pids[i] = hostport.PeerIdentifier(fmt.Sprintf("10.0.0.%d:4040", i))
+	}
+	return pids
+}
+
+func newTestList(t *testing.T, n int, opts ...ListOption) (*List, *fakeList) {
+	inner := &fakeList{}
+	list := New(inner, opts...)
+	require.NoError(t, list.Start())
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: identifiers(n)}))
+	return list, inner
+}
+
+func choose(t *testing.T, list *List, key string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p, onFinish, err := list.Choose(ctx, &transport.Request{RoutingKey: key})
+	require.NoError(t, err)
+	onFinish(nil)
+	return p.Identifier()
+}
+
+func TestSameRoutingKeySamePeer(t *testing.T) {
+	list, inner := newTestList(t, 3)
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "bob"))
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+		assert.Equal(t, "10.0.0.1:4040", choose(t, list, "bob"))
+	}
+	assert.Equal(t, 2, list.Len())
+
+	for _, pid := range identifiers(3) {
+		assert.Equal(t, 0, inner.Peer(pid.Identifier()).Status().PendingRequestCount,
+			"must end every request")
+	}
+}
+
+func TestNoRoutingKey(t *testing.T) {
+	list, _ := newTestList(t, 3)
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, ""))
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, ""))
+	assert.Equal(t, "10.0.0.2:4040", choose(t, list, ""))
+	assert.Equal(t, 0, list.Len(), "must not remember requests without a routing key")
+}
+
+func TestRepinWhenUnavailable(t *testing.T) {
+	list, inner := newTestList(t, 3)
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+
+	inner.Peer("10.0.0.0:4040").SetStatus(peer.Unavailable)
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "alice"), "must fall back to the peer list")
+
+	inner.Peer("10.0.0.0:4040").SetStatus(peer.Available)
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "alice"), "must remain pinned to the new peer")
+}
+
+func TestForgetRemovedPeers(t *testing.T) {
+	list, _ := newTestList(t, 3)
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "bob"))
+
+	require.NoError(t, list.Update(peer.ListUpdates{Removals: identifiers(1)}))
+	assert.Equal(t, 1, list.Len())
+	assert.NotEqual(t, "10.0.0.0:4040", choose(t, list, "alice"), "must choose a new peer")
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "bob"))
+}
+
+func TestTTL(t *testing.T) {
+	clk := clock.NewFake()
+	list, _ := newTestList(t, 3, TTL(time.Minute), withClock(clk))
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+
+	clk.Add(50 * time.Second)
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+
+	clk.Add(50 * time.Second)
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"), "each request must extend the affinity")
+
+	clk.Add(time.Minute)
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "alice"), "must forget expired routing keys")
+}
+
+func TestCapacity(t *testing.T) {
+	list, _ := newTestList(t, 3, Capacity(2))
+	defer list.Stop()
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	assert.Equal(t, "10.0.0.1:4040", choose(t, list, "bob"))
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	assert.Equal(t, "10.0.0.2:4040", choose(t, list, "carol"))
+	assert.Equal(t, 2, list.Len())
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "bob"), "must forget the least recently used routing key")
+}
+
+func TestStopForgetsRoutingKeys(t *testing.T) {
+	list, _ := newTestList(t, 3)
+
+	assert.Equal(t, "10.0.0.0:4040", choose(t, list, "alice"))
+	require.NoError(t, list.Stop())
+	assert.False(t, list.IsRunning())
+	assert.Equal(t, 0, list.Len())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _, err := list.Choose(ctx, &transport.Request{RoutingKey: "alice"})
+	assert.Error(t, err)
+}
+
+func TestRepinWhenEjected(t *testing.T) {
+	inner := peerlist.New("first", fakeTransport{}, &firstList{}, peerlist.OutlierDetection(peerlist.OutlierDetectionConfig{
+		ConsecutiveFailures: 2,
+		MaxEjectionPercent:  50,
+	}))
+	list := New(inner)
+	require.NoError(t, list.Start())
+	defer list.Stop()
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: identifiers(3)}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req := &transport.Request{RoutingKey: "alice"}
+
+	pinned := choose(t, list, "alice")
+	for i := 0; i < 2; i++ {
+		p, onFinish, err := list.Choose(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, pinned, p.Identifier())
+		onFinish(yarpcerrors.UnavailableErrorf("unavailable"))
+	}
+
+	assert.NotEqual(t, pinned, choose(t, list, "alice"),
+		"must forget peers that outlier detection ejected")
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package subset provides a peer list wrapper that retains only a
+// deterministic subset of the peers that its peer list updater provides.
+//
+// With very large fleets of backend instances, connecting every client to
+// every backend exhausts file descriptors and memory on both ends.
+// The subset list receives the full set of peers from its updater, but only
+// forwards a fixed number of them to the peer list it wraps, so the
+// underlying peer list only retains those peers with the transport.
+//
+// The subset is chosen with rendezvous (highest random weight) hashing of the
+// client identifier and each peer identifier.
+// Each client therefore always selects the same subset for the same set of
+// peers, every peer is equally likely to be selected by any given client, and
+// adding or removing a peer changes at most one member of each client's
+// subset.
+//
+//   list := subset.New(
+//   	roundrobin.New(transport),
+//   	subset.Size(20),
+//   	subset.ClientID(hostname),
+//   )
+//   chooser := peer.Bind(list, dns.Updater(...))
+package subset
@@ -0,0 +1,299 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package subset
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/introspection"
+)
+
+type listOptions struct {
+	size     int
+	clientID string
+}
+
+var defaultListOptions = listOptions{
+	size: 10,
+}
+
+// ListOption customizes the behavior of a subset list.
+type ListOption interface {
+	apply(*listOptions)
+}
+
+type listOptionFunc func(*listOptions)
+
+func (f listOptionFunc) apply(options *listOptions) { f(options) }
+
+// Size specifies the maximum number of peers to retain.
+// A size of zero or less retains all peers.
+//
+// Defaults to 10.
+func Size(size int) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.size = size
+	})
+}
+
+// ClientID specifies the identity of this client, which determines the
+// subset of peers it retains.
+// Clients that share an identifier retain the same subset, so each instance
+// of a service should use a distinct identifier, like its host name or its
+// ordinal within the service.
+//
+// Defaults to the host name.
+func ClientID(id string) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.clientID = id
+	})
+}
+
+// New creates a subset list that forwards a deterministic subset of the peers
+// it receives to the given peer list.
+func New(list peer.ChooserList, opts ...ListOption) *List {
+	options := defaultListOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+
+	if options.clientID == "" {
+		// An empty client identifier still produces a valid, if shared,
+		// subset.
+		options.clientID, _ = os.Hostname()
+	}
+
+	if options.size < 0 {
+		options.size = 0
+	}
+
+	return &List{
+		list:     list,
+		size:     options.size,
+		clientID: options.clientID,
+		peers:    make(map[string]peer.Identifier),
+		subset:   make(map[string]peer.Identifier, options.size),
+	}
+}
+
+// List is a peer list that forwards a deterministic subset of the peers it
+// receives to an underlying peer list, and chooses peers from that list.
+type List struct {
+	lock sync.Mutex
+
+	list     peer.ChooserList
+	size     int
+	clientID string
+
+	// peers are all peers received from the peer list updater.
+	peers map[string]peer.Identifier
+	// subset are the peers forwarded to the underlying peer list.
+	subset map[string]peer.Identifier
+}
+
+var (
+	_ peer.ChooserList                    = (*List)(nil)
+	_ introspection.IntrospectableChooser = (*List)(nil)
+)
+
+// Update applies the additions and removals of peer identifiers to the full
+// set of peers, and forwards the resulting changes to the subset to the
+// underlying peer list.
+func (l *List) Update(updates peer.ListUpdates) error {
+	if len(updates.Additions) == 0 && len(updates.Removals) == 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var errs error
+	for _, pid := range updates.Removals {
+		if _, ok := l.peers[pid.Identifier()]; !ok {
+			errs = multierr.Append(errs, peer.ErrPeerRemoveNotInList(pid.Identifier()))
+			continue
+		}
+		delete(l.peers, pid.Identifier())
+	}
+	for _, pid := range updates.Additions {
+		if _, ok := l.peers[pid.Identifier()]; ok {
+			errs = multierr.Append(errs, peer.ErrPeerAddAlreadyInList(pid.Identifier()))
+			continue
+		}
+		l.peers[pid.Identifier()] = pid
+	}
+
+	subset := l.choose()
+
+	var changes peer.ListUpdates
+	for id, pid := range l.subset {
+		if _, ok := subset[id]; !ok {
+			changes.Removals = append(changes.Removals, pid)
+		}
+	}
+	for id, pid := range subset {
+		if _, ok := l.subset[id]; !ok {
+			changes.Additions = append(changes.Additions, pid)
+		}
+	}
+	l.subset = subset
+
+	if len(changes.Additions) == 0 && len(changes.Removals) == 0 {
+		return errs
+	}
+	sortIdentifiers(changes.Removals)
+	sortIdentifiers(changes.Additions)
+	return multierr.Append(errs, l.list.Update(changes))
+}
+
+// choose returns the peers with the highest rendezvous hash scores for this
+// client.
+// Must be run in a mutex.Lock()
+func (l *List) choose() map[string]peer.Identifier {
+	if l.size == 0 || len(l.peers) <= l.size {
+		subset := make(map[string]peer.Identifier, len(l.peers))
+		for id, pid := range l.peers {
+			subset[id] = pid
+		}
+		return subset
+	}
+
+	scored := make([]scoredPeer, 0, len(l.peers))
+	for id, pid := range l.peers {
+		scored = append(scored, scoredPeer{
+			id:    id,
+			pid:   pid,
+			score: score(l.clientID, id),
+		})
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].id < scored[j].id
+	})
+
+	subset := make(map[string]peer.Identifier, l.size)
+	for _, sp := range scored[:l.size] {
+		subset[sp.id] = sp.pid
+	}
+	return subset
+}
+
+// Subset returns a snapshot of the peers forwarded to the underlying peer
+// list, ordered by identifier.
+func (l *List) Subset() []peer.Identifier {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	pids := make([]peer.Identifier, 0, len(l.subset))
+	for _, pid := range l.subset {
+		pids = append(pids, pid)
+	}
+	sortIdentifiers(pids)
+	return pids
+}
+
+// NumPeers returns how many peers the list has received from its updater,
+// including those outside the subset.
+func (l *List) NumPeers() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.peers)
+}
+
+// Choose chooses a peer from the underlying peer list.
+func (l *List) Choose(ctx context.Context, req *transport.Request) (peer.Peer, func(error), error) {
+	return l.list.Choose(ctx, req)
+}
+
+// Start starts the underlying peer list.
+func (l *List) Start() error {
+	return l.list.Start()
+}
+
+// Stop stops the underlying peer list.
+func (l *List) Stop() error {
+	return l.list.Stop()
+}
+
+// IsRunning returns whether the underlying peer list is running.
+func (l *List) IsRunning() bool {
+	return l.list.IsRunning()
+}
+
+// Introspect returns a ChooserStatus for the underlying peer list, annotated
+// with the size of the subset.
+func (l *List) Introspect() introspection.ChooserStatus {
+	var status introspection.ChooserStatus
+	if ic, ok := l.list.(introspection.IntrospectableChooser); ok {
+		status = ic.Introspect()
+	} else {
+		status = introspection.ChooserStatus{
+			Name: "Introspection not available",
+		}
+	}
+
+	l.lock.Lock()
+	status.State = fmt.Sprintf("%s (subset of %d/%d peers)", status.State, len(l.subset), len(l.peers))
+	l.lock.Unlock()
+	return status
+}
+
+type scoredPeer struct {
+	id    string
+	pid   peer.Identifier
+	score uint64
+}
+
+// score returns the rendezvous hash weight of a peer for a client.
+func score(clientID, peerID string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(clientID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(peerID))
+	return mix(h.Sum64())
+}
+
+// mix is the 64-bit finalizer of MurmurHash3, which spreads the bits of FNV
+// hashes of similar inputs, like host:port pairs that differ by one digit.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func sortIdentifiers(pids []peer.Identifier) {
+	sort.Slice(pids, func(i, j int) bool {
+		return pids[i].Identifier() < pids[j].Identifier()
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package subset_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/peer"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/peer/roundrobin"
+	"go.uber.org/yarpc/peer/subset"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+func identifiers(n int) []peer.Identifier {
+	pids := make([]peer.Identifier, n)
+	for i := range pids {
+		pids[i] = hostport.PeerIdentifier(fmt.Sprintf("10.0.0.%d:4040", i))
+	}
+	return pids
+}
+
+func identifierSet(pids []peer.Identifier) map[string]struct{} {
+	set := make(map[string]struct{}, len(pids))
+	for _, pid := range pids {
+		set[pid.Identifier()] = struct{}{}
+	}
+	return set
+}
+
+func TestSubsetRetainsOnlySubset(t *testing.T) {
+	fake := yarpctest.NewFakeTransport()
+	list := subset.New(roundrobin.New(fake), subset.Size(3), subset.ClientID("client"))
+	require.NoError(t, list.Start())
+	defer list.Stop()
+
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: identifiers(10)}))
+	assert.Len(t, list.Subset(), 3)
+	assert.Equal(t, 10, list.NumPeers())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	subset := identifierSet(list.Subset())
+	for i := 0; i < 10; i++ {
+		p, onFinish, err := list.Choose(ctx, &transport.Request{})
+		require.NoError(t, err)
+		onFinish(nil)
+		assert.Contains(t, subset, p.Identifier(), "must only choose peers in the subset")
+	}
+
+	status := list.Introspect()
+	assert.Len(t, status.Peers, 3)
+	assert.Contains(t, status.State, "subset of 3/10 peers")
+}
+
+func TestSubsetSmallerThanSize(t *testing.T) {
+	inner := roundrobin.New(yarpctest.NewFakeTransport())
+	list := subset.New(inner, subset.Size(5), subset.ClientID("client"))
+
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: identifiers(3)}))
+	assert.Equal(t, identifiers(3), list.Subset())
+}
+
+func TestUnlimitedSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		inner := roundrobin.New(yarpctest.NewFakeTransport())
+		list := subset.New(inner, subset.Size(size), subset.ClientID("client"))
+
+		require.NoError(t, list.Update(peer.ListUpdates{Additions: identifiers(20)}))
+		assert.Equal(t, identifierSet(identifiers(20)), identifierSet(list.Subset()), "size %d must retain all peers", size)
+	}
+}
+
+func TestSubsetIsDeterministic(t *testing.T) {
+	pids := identifiers(50)
+
+	a := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(5), subset.ClientID("client"))
+	require.NoError(t, a.Update(peer.ListUpdates{Additions: pids}))
+
+	// The order of additions does not matter.
+	reversed := make([]peer.Identifier, len(pids))
+	for i, pid := range pids {
+		reversed[len(pids)-1-i] = pid
+	}
+	b := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(5), subset.ClientID("client"))
+	require.NoError(t, b.Update(peer.ListUpdates{Additions: reversed}))
+	assert.Equal(t, a.Subset(), b.Subset())
+
+	c := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(5), subset.ClientID("other-client"))
+	require.NoError(t, c.Update(peer.ListUpdates{Additions: pids}))
+	assert.NotEqual(t, a.Subset(), c.Subset(), "different clients should select different subsets")
+}
+
+func TestSubsetChangesMinimally(t *testing.T) {
+	pids := identifiers(50)
+	list := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(5), subset.ClientID("client"))
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: pids}))
+	before := list.Subset()
+
+	// Removing a peer outside the subset changes nothing.
+	beforeSet := identifierSet(before)
+	for _, pid := range pids {
+		if _, ok := beforeSet[pid.Identifier()]; !ok {
+			require.NoError(t, list.Update(peer.ListUpdates{Removals: []peer.Identifier{pid}}))
+			break
+		}
+	}
+	assert.Equal(t, before, list.Subset())
+
+	// Removing a peer in the subset replaces only that peer.
+	require.NoError(t, list.Update(peer.ListUpdates{Removals: []peer.Identifier{before[0]}}))
+	after := identifierSet(list.Subset())
+	assert.Len(t, after, 5)
+	assert.NotContains(t, after, before[0].Identifier())
+	for _, pid := range before[1:] {
+		assert.Contains(t, after, pid.Identifier())
+	}
+
+	// Adding the peer back restores the original subset.
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: []peer.Identifier{before[0]}}))
+	assert.Equal(t, before, list.Subset())
+}
+
+func TestSubsetIsEvenlyDistributed(t *testing.T) {
+	const (
+		numPeers   = 20
+		numClients = 1000
+		size       = 4
+	)
+	pids := identifiers(numPeers)
+	counts := make(map[string]int, numPeers)
+	for i := 0; i < numClients; i++ {
+		list := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(size), subset.ClientID(fmt.Sprintf("client-%d", i)))
+		require.NoError(t, list.Update(peer.ListUpdates{Additions: pids}))
+		for _, pid := range list.Subset() {
+			counts[pid.Identifier()]++
+		}
+	}
+
+	want := numClients * size / numPeers
+	for id, n := range counts {
+		assert.InDelta(t, want, n, float64(want)/3, "peer %q selected by too few or too many clients", id)
+	}
+}
+
+func TestSubsetUpdateErrors(t *testing.T) {
+	list := subset.New(roundrobin.New(yarpctest.NewFakeTransport()), subset.Size(2), subset.ClientID("client"))
+	pids := identifiers(2)
+
+	require.NoError(t, list.Update(peer.ListUpdates{Additions: pids}))
+	assert.Equal(t, peer.ErrPeerAddAlreadyInList(pids[0].Identifier()),
+		list.Update(peer.ListUpdates{Additions: pids[:1]}))
+	assert.Equal(t, peer.ErrPeerRemoveNotInList("10.0.0.9:4040"),
+		list.Update(peer.ListUpdates{Removals: []peer.Identifier{hostport.PeerIdentifier("10.0.0.9:4040")}}))
+	assert.NoError(t, list.Update(peer.ListUpdates{}))
+}
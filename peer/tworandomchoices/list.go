@@ -24,13 +24,19 @@ import (
 	"math/rand"
 	"time"
 
+	"go.uber.org/net/metrics"
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/peer/peerlist/v2"
+	"go.uber.org/zap"
 )
 
 type listOptions struct {
-	capacity int
-	source   rand.Source
+	capacity         int
+	source           rand.Source
+	outlierDetection *peerlist.OutlierDetectionConfig
+	slowStart        time.Duration
+	logger           *zap.Logger
+	meter            *metrics.Scope
 }
 
 var defaultListOptions = listOptions{
@@ -71,6 +77,50 @@ func Source(source rand.Source) ListOption {
 	})
 }
 
+// OutlierDetection enables temporarily ejecting peers that fail too often
+// from selection.
+// See peerlist.OutlierDetectionConfig for details.
+//
+// Outlier detection is disabled by default.
+func OutlierDetection(cfg peerlist.OutlierDetectionConfig) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.outlierDetection = &cfg
+	})
+}
+
+// SlowStart specifies a window over which a newly available peer ramps up to
+// its full share of requests.
+// See peerlist.SlowStart for details.
+//
+// Slow start is disabled by default.
+func SlowStart(window time.Duration) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.slowStart = window
+	})
+}
+
+// Logger specifies a logger for the peer list, which reports peer ejections
+// and restorations.
+// See peerlist.Logger for details.
+//
+// Defaults to a no-op logger.
+func Logger(logger *zap.Logger) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.logger = logger
+	})
+}
+
+// Meter specifies a metrics scope for the peer list, which reports peer
+// ejections.
+// See peerlist.Meter for details.
+//
+// Metrics are not reported by default.
+func Meter(meter *metrics.Scope) ListOption {
+	return listOptionFunc(func(options *listOptions) {
+		options.meter = meter
+	})
+}
+
 // New creates a new fewest pending requests of two random peers peer list.
 func New(transport peer.Transport, opts ...ListOption) *List {
 	options := defaultListOptions
@@ -86,6 +136,18 @@ func New(transport peer.Transport, opts ...ListOption) *List {
 		peerlist.Capacity(options.capacity),
 		peerlist.NoShuffle(),
 	}
+	if options.outlierDetection != nil {
+		plOpts = append(plOpts, peerlist.OutlierDetection(*options.outlierDetection))
+	}
+	if options.slowStart > 0 {
+		plOpts = append(plOpts, peerlist.SlowStart(options.slowStart))
+	}
+	if options.logger != nil {
+		plOpts = append(plOpts, peerlist.Logger(options.logger))
+	}
+	if options.meter != nil {
+		plOpts = append(plOpts, peerlist.Meter(options.meter))
+	}
 
 	return &List{
 		List: peerlist.New(
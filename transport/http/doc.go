@@ -48,6 +48,20 @@
 // 		},
 // 	})
 //
+// Procedures may also be served on RESTful URLs with the Routes option. A
+// request without a Rpc-Procedure header that matches the method and path
+// template of a Route is dispatched to the procedure of that route.
+//
+// 	myInbound := httpTransport.NewInbound(":8080", http.Routes(http.Route{
+// 		Method:    "GET",
+// 		Pattern:   "/v1/users/{id}",
+// 		Procedure: "getUser",
+// 		Encoding:  "json",
+// 		RequestBody: func(req *http.Request, variables map[string]string) ([]byte, error) {
+// 			return json.Marshal(map[string]string{"id": variables["id"]})
+// 		},
+// 	}))
+//
 // Note that stopping an HTTP transport does NOT immediately terminate ongoing
 // requests. Connections will remain open until all clients have disconnected.
 //
@@ -110,6 +110,7 @@ type Inbound struct {
 	transport   *Transport
 	grabHeaders map[string]struct{}
 	interceptor func(http.Handler) http.Handler
+	routes      []Route
 
 	once *lifecycle.Once
 
@@ -158,6 +159,12 @@ func (i *Inbound) start() error {
 		bothResponseError: i.bothResponseError,
 		logger:            i.logger,
 	}
+	if len(i.routes) > 0 {
+		var err error
+		if httpHandler, err = newRouteHandler(i.routes, i.router.Procedures(), httpHandler); err != nil {
+			return err
+		}
+	}
 	if i.interceptor != nil {
 		httpHandler = i.interceptor(httpHandler)
 	}
@@ -0,0 +1,454 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	// defaultRouteCaller is the caller of requests served by a Route that do
+	// not have an Rpc-Caller header.
+	defaultRouteCaller = "unknown"
+
+	// defaultRouteTTL is the TTL of requests served by a Route that do not
+	// have a Context-TTL-MS header, if their procedure does not declare a
+	// timeout.
+	defaultRouteTTL = 10 * time.Second
+)
+
+// Route serves a procedure on RESTful URLs, for clients that do not use the
+// YARPC HTTP protocol, such as browsers.
+//
+// Requests whose method and path match the route, and that do not have an
+// Rpc-Procedure header, are turned into requests to the procedure. Their
+// caller is given by the Rpc-Caller header, or is "unknown", and their TTL
+// is given by the Context-TTL-MS header, or is the timeout declared in the
+// options of the procedure. Application headers are given as with the YARPC
+// HTTP protocol, with the Rpc-Header- prefix.
+//
+// Routes are usually built from IDL annotations by generated code, such as
+// the google.api.http annotations of Protobuf methods.
+type Route struct {
+	// Method is the HTTP method of the requests served by the route, such as
+	// GET or POST.
+	Method string
+
+	// Pattern is the template of the paths served by the route, in the
+	// syntax of google.api.http annotations. Segments are literals, *
+	// wildcards matching one segment, or a final ** wildcard matching any
+	// number of segments. Variables such as {id} match one segment, and
+	// variables such as {name=shelves/*/books/*} match the given segments.
+	// A template may end with a custom verb, such as /v1/users/{id}:undelete.
+	Pattern string
+
+	// Service is the name of the service of the procedure. Defaults to the
+	// service of the procedure named Procedure, if only one service has a
+	// procedure with that name.
+	Service string
+
+	// Procedure is the name of the procedure that serves the route.
+	Procedure string
+
+	// Encoding is the encoding of the requests sent to the procedure.
+	Encoding transport.Encoding
+
+	// RequestBody builds the body of the request sent to the procedure from
+	// the HTTP request and the values of the variables of the pattern, keyed
+	// by variable name. By default, the body of the HTTP request is sent
+	// as-is.
+	RequestBody func(req *http.Request, variables map[string]string) ([]byte, error)
+
+	// ResponseBody builds the body of the HTTP response from the body of a
+	// successful response of the procedure. By default, the body of the
+	// response is returned as-is.
+	ResponseBody func(body []byte) ([]byte, error)
+}
+
+// Routes specifies routes that serve procedures on RESTful URLs, in
+// addition to the YARPC HTTP protocol. Requests are matched against routes
+// in order.
+//
+// The inbound fails to start if a route has an invalid pattern or refers to
+// a procedure that is not registered.
+//
+// 	inbound := httpTransport.NewInbound(":8080",
+// 		http.Routes(foo.BuildBarYARPCHTTPRoutes()...),
+// 	)
+func Routes(routes ...Route) InboundOption {
+	return func(i *Inbound) {
+		i.routes = append(i.routes, routes...)
+	}
+}
+
+// routeHandler serves requests that match a route with the procedure of the
+// route, and other requests with the YARPC handler.
+type routeHandler struct {
+	routes []*compiledRoute
+	next   http.Handler
+}
+
+func newRouteHandler(routes []Route, procedures []transport.Procedure, next http.Handler) (http.Handler, error) {
+	compiled := make([]*compiledRoute, 0, len(routes))
+	for _, route := range routes {
+		r, err := compileRoute(route, procedures)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, r)
+	}
+	return routeHandler{routes: compiled, next: next}, nil
+}
+
+func (h routeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get(ProcedureHeader) == "" {
+		for _, r := range h.routes {
+			if variables, ok := r.match(req); ok {
+				r.serve(w, req, variables, h.next)
+				return
+			}
+		}
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+type compiledRoute struct {
+	Route
+
+	template *routeTemplate
+	ttl      time.Duration
+}
+
+func compileRoute(route Route, procedures []transport.Procedure) (*compiledRoute, error) {
+	if route.Method == "" {
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "route %q has no method", route.Pattern)
+	}
+	template, err := parseRouteTemplate(route.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	r := &compiledRoute{Route: route, template: template, ttl: defaultRouteTTL}
+
+	var matching []transport.Procedure
+	for _, p := range procedures {
+		if p.Name != route.Procedure || (route.Service != "" && p.Service != route.Service) {
+			continue
+		}
+		if route.Encoding != "" && p.Encoding != "" && p.Encoding != route.Encoding {
+			continue
+		}
+		matching = append(matching, p)
+	}
+	if len(matching) == 0 {
+		return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "route %s %s refers to procedure %q, which is not registered", route.Method, route.Pattern, route.Procedure)
+	}
+	for _, p := range matching[1:] {
+		if p.Service != matching[0].Service {
+			return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "route %s %s refers to procedure %q, which is registered by services %q and %q, a service must be specified", route.Method, route.Pattern, route.Procedure, matching[0].Service, p.Service)
+		}
+	}
+	r.Service = matching[0].Service
+	if options := matching[0].Options; options != nil && options.Timeout > 0 {
+		r.ttl = options.Timeout
+	}
+	return r, nil
+}
+
+func (r *compiledRoute) match(req *http.Request) (map[string]string, bool) {
+	if req.Method != r.Method {
+		return nil, false
+	}
+	return r.template.match(req.URL.EscapedPath())
+}
+
+// serve serves an HTTP request matching the route by rewriting it into a
+// request that follows the YARPC HTTP protocol.
+func (r *compiledRoute) serve(w http.ResponseWriter, req *http.Request, variables map[string]string, next http.Handler) {
+	yarpcReq := new(http.Request)
+	*yarpcReq = *req
+	yarpcReq.Method = http.MethodPost
+	yarpcReq.Header = make(http.Header, len(req.Header)+5)
+	for name, values := range req.Header {
+		yarpcReq.Header[name] = values
+	}
+	if r.RequestBody != nil {
+		body, err := r.RequestBody(req, variables)
+		if err != nil {
+			writeRouteError(w, err)
+			return
+		}
+		yarpcReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		yarpcReq.ContentLength = int64(len(body))
+	}
+
+	yarpcReq.Header.Set(ServiceHeader, r.Service)
+	yarpcReq.Header.Set(ProcedureHeader, r.Procedure)
+	yarpcReq.Header.Set(EncodingHeader, string(r.Encoding))
+	if yarpcReq.Header.Get(CallerHeader) == "" {
+		yarpcReq.Header.Set(CallerHeader, defaultRouteCaller)
+	}
+	if yarpcReq.Header.Get(TTLMSHeader) == "" {
+		yarpcReq.Header.Set(TTLMSHeader, strconv.FormatInt(int64(r.ttl/time.Millisecond), 10))
+	}
+
+	if r.ResponseBody == nil {
+		next.ServeHTTP(w, yarpcReq)
+		return
+	}
+	recorder := &routeResponseRecorder{w: w, status: http.StatusOK}
+	next.ServeHTTP(recorder, yarpcReq)
+	body := recorder.body.Bytes()
+	if recorder.status == http.StatusOK && len(body) > 0 {
+		var err error
+		if body, err = r.ResponseBody(body); err != nil {
+			writeRouteError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(recorder.status)
+	_, _ = w.Write(body)
+}
+
+// routeResponseRecorder buffers a response so that a route can build the
+// body of the HTTP response from it.
+type routeResponseRecorder struct {
+	w      http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *routeResponseRecorder) Header() http.Header {
+	return r.w.Header()
+}
+
+func (r *routeResponseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *routeResponseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func writeRouteError(w http.ResponseWriter, err error) {
+	status := yarpcerrors.FromError(err)
+	if code, err := status.Code().MarshalText(); err == nil {
+		w.Header().Set(ErrorCodeHeader, string(code))
+	}
+	httpStatusCode, ok := _codeToStatusCode[status.Code()]
+	if !ok {
+		httpStatusCode = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf8")
+	w.WriteHeader(httpStatusCode)
+	_, _ = fmt.Fprintln(w, status.Message())
+}
+
+// routeTemplate is a parsed path template of a Route.
+type routeTemplate struct {
+	// segments are literal segments, "*" or "**".
+	segments  []string
+	variables []routeVariable
+	verb      string
+}
+
+// routeVariable is a variable of a path template, which matches the
+// segments from start to end, or to the end of the path if end is -1.
+type routeVariable struct {
+	name       string
+	start, end int
+}
+
+func parseRouteTemplate(pattern string) (*routeTemplate, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "invalid route pattern %q: %s", pattern, fmt.Sprintf(format, args...))
+	}
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, invalid("must start with /")
+	}
+
+	// Split the pattern into segments and the verb, ignoring the slashes
+	// and colons inside of variables.
+	var (
+		tokens []string
+		verb   string
+		depth  int
+		start  = 1
+	)
+	for i := 1; i < len(pattern) && verb == ""; i++ {
+		switch c := pattern[i]; {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == '/' && depth == 0:
+			tokens = append(tokens, pattern[start:i])
+			start = i + 1
+		case c == ':' && depth == 0:
+			verb = pattern[i+1:]
+			if verb == "" || strings.ContainsAny(verb, "/{}:") {
+				return nil, invalid("invalid verb %q", verb)
+			}
+			tokens = append(tokens, pattern[start:i])
+			start = -1
+		}
+		if depth < 0 || depth > 1 {
+			return nil, invalid("unbalanced braces")
+		}
+	}
+	if depth != 0 {
+		return nil, invalid("unbalanced braces")
+	}
+	if start >= 0 {
+		tokens = append(tokens, pattern[start:])
+	}
+
+	t := &routeTemplate{verb: verb}
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if !strings.HasPrefix(token, "{") {
+			if err := t.addSegments(token); err != nil {
+				return nil, invalid("%v", err)
+			}
+			continue
+		}
+		if !strings.HasSuffix(token, "}") {
+			return nil, invalid("variable %q must be a whole segment", token)
+		}
+		name, segments := token[1:len(token)-1], "*"
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name, segments = name[:i], name[i+1:]
+		}
+		if !isFieldPath(name) {
+			return nil, invalid("invalid variable name %q", name)
+		}
+		if seen[name] {
+			return nil, invalid("variable %q is declared more than once", name)
+		}
+		seen[name] = true
+		variable := routeVariable{name: name, start: len(t.segments)}
+		if err := t.addSegments(segments); err != nil {
+			return nil, invalid("%v", err)
+		}
+		variable.end = len(t.segments)
+		if t.segments[len(t.segments)-1] == "**" {
+			variable.end = -1
+		}
+		t.variables = append(t.variables, variable)
+	}
+	for i, segment := range t.segments {
+		if segment == "**" && i != len(t.segments)-1 {
+			return nil, invalid("** must be the last segment")
+		}
+	}
+	return t, nil
+}
+
+// addSegments adds the slash-separated segments of a literal or of the
+// pattern of a variable.
+func (t *routeTemplate) addSegments(segments string) error {
+	for _, segment := range strings.Split(segments, "/") {
+		if segment == "" {
+			return fmt.Errorf("empty segment")
+		}
+		if strings.ContainsAny(segment, "{}=") || (strings.Contains(segment, "*") && segment != "*" && segment != "**") {
+			return fmt.Errorf("invalid segment %q", segment)
+		}
+		t.segments = append(t.segments, segment)
+	}
+	return nil
+}
+
+// match returns the values of the variables of the template if the given
+// escaped path matches it.
+func (t *routeTemplate) match(path string) (map[string]string, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	path = path[1:]
+	if t.verb != "" {
+		if !strings.HasSuffix(path, ":"+t.verb) {
+			return nil, false
+		}
+		path = strings.TrimSuffix(path, ":"+t.verb)
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = unescaped
+	}
+
+	for i, segment := range t.segments {
+		switch {
+		case segment == "**":
+			// Matches the remaining parts.
+		case i >= len(parts):
+			return nil, false
+		case segment == "*":
+			if parts[i] == "" {
+				return nil, false
+			}
+		case segment != parts[i]:
+			return nil, false
+		}
+	}
+	last := len(t.segments) - 1
+	if len(parts) != len(t.segments) && (last < 0 || t.segments[last] != "**") {
+		return nil, false
+	}
+
+	variables := make(map[string]string, len(t.variables))
+	for _, v := range t.variables {
+		end := v.end
+		if end < 0 {
+			end = len(parts)
+		}
+		variables[v.name] = strings.Join(parts[v.start:end], "/")
+	}
+	return variables, true
+}
+
+// isFieldPath returns whether s is a dot-separated path of identifiers.
+func isFieldPath(s string) bool {
+	for _, ident := range strings.Split(s, ".") {
+		if ident == "" {
+			return false
+		}
+		for _, c := range ident {
+			if !(c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')) {
+				return false
+			}
+		}
+	}
+	return true
+}
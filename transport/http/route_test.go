@@ -0,0 +1,397 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/yarpctest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestParseRouteTemplate(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		want      *routeTemplate
+		wantError string
+	}{
+		{
+			pattern: "/v1/users",
+			want:    &routeTemplate{segments: []string{"v1", "users"}},
+		},
+		{
+			pattern: "/v1/users/{id}",
+			want: &routeTemplate{
+				segments:  []string{"v1", "users", "*"},
+				variables: []routeVariable{{name: "id", start: 2, end: 3}},
+			},
+		},
+		{
+			pattern: "/v1/{name=shelves/*/books/*}:publish",
+			want: &routeTemplate{
+				segments:  []string{"v1", "shelves", "*", "books", "*"},
+				variables: []routeVariable{{name: "name", start: 1, end: 5}},
+				verb:      "publish",
+			},
+		},
+		{
+			pattern: "/v1/{user.id}/files/{path=**}",
+			want: &routeTemplate{
+				segments: []string{"v1", "*", "files", "**"},
+				variables: []routeVariable{
+					{name: "user.id", start: 1, end: 2},
+					{name: "path", start: 3, end: -1},
+				},
+			},
+		},
+		{
+			pattern:   "v1/users",
+			wantError: "must start with /",
+		},
+		{
+			pattern:   "/v1/users/{id",
+			wantError: "unbalanced braces",
+		},
+		{
+			pattern:   "/v1/{a={b}}",
+			wantError: "unbalanced braces",
+		},
+		{
+			pattern:   "/v1//users",
+			wantError: "empty segment",
+		},
+		{
+			pattern:   "/v1/users:",
+			wantError: "invalid verb",
+		},
+		{
+			pattern:   "/v1/user-{id}",
+			wantError: "invalid segment",
+		},
+		{
+			pattern:   "/v1/{id}x",
+			wantError: "must be a whole segment",
+		},
+		{
+			pattern:   "/v1/{user id}",
+			wantError: "invalid variable name",
+		},
+		{
+			pattern:   "/v1/{id}/{id}",
+			wantError: "declared more than once",
+		},
+		{
+			pattern:   "/v1/**/users",
+			wantError: "** must be the last segment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := parseRouteTemplate(tt.pattern)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+				assert.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRouteTemplateMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    map[string]string
+	}{
+		{
+			pattern: "/v1/users",
+			path:    "/v1/users",
+			want:    map[string]string{},
+		},
+		{
+			pattern: "/v1/users",
+			path:    "/v1/users/foo",
+		},
+		{
+			pattern: "/v1/users/{id}",
+			path:    "/v1/users/foo",
+			want:    map[string]string{"id": "foo"},
+		},
+		{
+			pattern: "/v1/users/{id}",
+			path:    "/v1/users/foo%2Fbar%20baz",
+			want:    map[string]string{"id": "foo/bar baz"},
+		},
+		{
+			pattern: "/v1/users/{id}",
+			path:    "/v1/users/",
+		},
+		{
+			pattern: "/v1/users/{id}",
+			path:    "/v1/groups/foo",
+		},
+		{
+			pattern: "/v1/{name=shelves/*/books/*}:publish",
+			path:    "/v1/shelves/1/books/2:publish",
+			want:    map[string]string{"name": "shelves/1/books/2"},
+		},
+		{
+			pattern: "/v1/{name=shelves/*/books/*}:publish",
+			path:    "/v1/shelves/1/books/2",
+		},
+		{
+			pattern: "/v1/{name=shelves/*/books/*}",
+			path:    "/v1/shelves/1/books/2:publish",
+			want:    map[string]string{"name": "shelves/1/books/2:publish"},
+		},
+		{
+			pattern: "/v1/files/{path=**}",
+			path:    "/v1/files/a/b/c",
+			want:    map[string]string{"path": "a/b/c"},
+		},
+		{
+			pattern: "/v1/files/{path=**}",
+			path:    "/v1/files",
+			want:    map[string]string{"path": ""},
+		},
+		{
+			pattern: "/v1/*/files",
+			path:    "/v1/foo/files",
+			want:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			template, err := parseRouteTemplate(tt.pattern)
+			require.NoError(t, err)
+			got, ok := template.match(tt.path)
+			if tt.want == nil {
+				assert.False(t, ok, "path must not match")
+				return
+			}
+			require.True(t, ok, "path must match")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type routeTestHandler func(context.Context, *transport.Request, transport.ResponseWriter) error
+
+func (f routeTestHandler) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	return f(ctx, req, resw)
+}
+
+func TestRoutes(t *testing.T) {
+	echo := routeTestHandler(func(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		deadline, _ := ctx.Deadline()
+		ttl := time.Until(deadline).Round(time.Second)
+		_, err = fmt.Fprintf(resw, "%s %s %s %s %v %s", req.Caller, req.Service, req.Procedure, req.Encoding, ttl, body)
+		return err
+	})
+	fail := routeTestHandler(func(context.Context, *transport.Request, transport.ResponseWriter) error {
+		return yarpcerrors.Newf(yarpcerrors.CodeNotFound, "user not found")
+	})
+	router := newTestRouter([]transport.Procedure{
+		{
+			Name:        "getUser",
+			Service:     "users",
+			Encoding:    "json",
+			HandlerSpec: transport.NewUnaryHandlerSpec(echo),
+			Options:     &transport.ProcedureOptions{Timeout: time.Minute},
+		},
+		{
+			Name:        "deleteUser",
+			Service:     "users",
+			HandlerSpec: transport.NewUnaryHandlerSpec(fail),
+		},
+		{
+			Name:        "listUsers",
+			Service:     "users",
+			HandlerSpec: transport.NewUnaryHandlerSpec(echo),
+		},
+	})
+
+	inbound := NewTransport().NewInbound(":0", Routes(
+		Route{
+			Method:    http.MethodGet,
+			Pattern:   "/v1/users/{id}",
+			Procedure: "getUser",
+			Encoding:  "json",
+			RequestBody: func(req *http.Request, variables map[string]string) ([]byte, error) {
+				if variables["id"] == "invalid" {
+					return nil, yarpcerrors.Newf(yarpcerrors.CodeInvalidArgument, "invalid id")
+				}
+				return []byte(fmt.Sprintf(`{"id":%q,"verbose":%q}`, variables["id"], req.URL.Query().Get("verbose"))), nil
+			},
+		},
+		Route{
+			Method:    http.MethodDelete,
+			Pattern:   "/v1/users/{id}",
+			Procedure: "deleteUser",
+			Encoding:  "json",
+		},
+		Route{
+			Method:    http.MethodPost,
+			Pattern:   "/v1/users:search",
+			Service:   "users",
+			Procedure: "listUsers",
+			Encoding:  "raw",
+			ResponseBody: func(body []byte) ([]byte, error) {
+				return []byte(strings.ToUpper(string(body))), nil
+			},
+		},
+	))
+	inbound.SetRouter(router)
+	require.NoError(t, inbound.Start())
+	defer inbound.Stop()
+	url := fmt.Sprintf("http://%v", yarpctest.ZeroAddrToHostPort(inbound.Addr()))
+
+	do := func(method string, path string, body string, headers map[string]string) (int, http.Header, string) {
+		req, err := http.NewRequest(method, url+path, strings.NewReader(body))
+		require.NoError(t, err)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		resBody, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, res.Header, string(resBody)
+	}
+
+	t.Run("path and query", func(t *testing.T) {
+		status, header, body := do(http.MethodGet, "/v1/users/foo?verbose=true", "", nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.Equal(t, `unknown users getUser json 1m0s {"id":"foo","verbose":"true"}`, body)
+	})
+
+	t.Run("caller and TTL headers", func(t *testing.T) {
+		status, _, body := do(http.MethodGet, "/v1/users/foo", "", map[string]string{
+			CallerHeader: "browser",
+			TTLMSHeader:  "5000",
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `browser users getUser json 5s {"id":"foo","verbose":""}`, body)
+	})
+
+	t.Run("request body error", func(t *testing.T) {
+		status, header, body := do(http.MethodGet, "/v1/users/invalid", "", nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid-argument", header.Get(ErrorCodeHeader))
+		assert.Equal(t, "invalid id\n", body)
+	})
+
+	t.Run("procedure error", func(t *testing.T) {
+		status, header, body := do(http.MethodDelete, "/v1/users/foo", "", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "not-found", header.Get(ErrorCodeHeader))
+		assert.Equal(t, "user not found\n", body)
+	})
+
+	t.Run("verb and response body", func(t *testing.T) {
+		status, _, body := do(http.MethodPost, "/v1/users:search", "query", nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "UNKNOWN USERS LISTUSERS RAW 10S QUERY", body)
+	})
+
+	t.Run("unmatched method", func(t *testing.T) {
+		status, _, _ := do(http.MethodPut, "/v1/users/foo", "", nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("YARPC protocol", func(t *testing.T) {
+		status, _, body := do(http.MethodPost, "/v1/users/foo", "hello", map[string]string{
+			CallerHeader:    "caller",
+			ServiceHeader:   "users",
+			ProcedureHeader: "listUsers",
+			EncodingHeader:  "raw",
+			TTLMSHeader:     "2000",
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "caller users listUsers raw 2s hello", body)
+	})
+}
+
+func TestRoutesStartErrors(t *testing.T) {
+	procedures := []transport.Procedure{
+		{Name: "get", Service: "foo"},
+		{Name: "get", Service: "bar"},
+		{Name: "list", Service: "foo"},
+	}
+	tests := []struct {
+		msg       string
+		route     Route
+		wantError string
+	}{
+		{
+			msg:       "no method",
+			route:     Route{Pattern: "/v1/foo", Procedure: "list"},
+			wantError: "has no method",
+		},
+		{
+			msg:       "invalid pattern",
+			route:     Route{Method: "GET", Pattern: "v1/foo", Procedure: "list"},
+			wantError: "must start with /",
+		},
+		{
+			msg:       "unknown procedure",
+			route:     Route{Method: "GET", Pattern: "/v1/foo", Procedure: "create"},
+			wantError: `procedure "create", which is not registered`,
+		},
+		{
+			msg:       "unknown service",
+			route:     Route{Method: "GET", Pattern: "/v1/foo", Service: "baz", Procedure: "get"},
+			wantError: `procedure "get", which is not registered`,
+		},
+		{
+			msg:       "ambiguous procedure",
+			route:     Route{Method: "GET", Pattern: "/v1/foo", Procedure: "get"},
+			wantError: "a service must be specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			inbound := NewTransport().NewInbound(":0", Routes(tt.route))
+			inbound.SetRouter(newTestRouter(procedures))
+			err := inbound.Start()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/api/middleware"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/internal/digester"
+)
+
+// Option customizes the behavior of a Cache.
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) apply(opts *options) { f(opts) }
+
+type options struct {
+	ttl           time.Duration
+	filter        func(*transport.Request) bool
+	keyHeaders    []string
+	controlHeader string
+	maxEntries    int
+	maxBytes      int
+	clock         clock.Clock
+}
+
+var defaultOptions = options{
+	filter:        func(*transport.Request) bool { return true },
+	controlHeader: "cache-control",
+	maxEntries:    1000,
+	maxBytes:      64 * 1024 * 1024,
+}
+
+// TTL specifies how long responses are cached when the server does not give
+// a max-age in the cache-control response header.
+//
+// Defaults to zero: responses without a max-age are not cached.
+func TTL(ttl time.Duration) Option {
+	return optionFunc(func(opts *options) {
+		opts.ttl = ttl
+	})
+}
+
+// Filter specifies a function that decides which requests may be answered
+// from the cache. Requests for which the function returns false go straight
+// to the outbound, and their responses are not cached.
+//
+// All requests may be cached by default.
+func Filter(f func(*transport.Request) bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.filter = f
+	})
+}
+
+// KeyHeaders specifies request headers whose values are part of the cache
+// key, for servers whose responses depend on them. Other request headers are
+// ignored: requests that differ only by them share cached responses.
+func KeyHeaders(keys ...string) Option {
+	return optionFunc(func(opts *options) {
+		opts.keyHeaders = append(opts.keyHeaders, keys...)
+	})
+}
+
+// ControlHeader specifies the name of the response header from which the
+// cache reads the caching directives of the server.
+//
+// Defaults to "cache-control".
+func ControlHeader(key string) Option {
+	return optionFunc(func(opts *options) {
+		opts.controlHeader = key
+	})
+}
+
+// MaxEntries specifies the maximum number of responses to cache.
+// Once full, the cache forgets the least recently used response to cache a
+// new one.
+//
+// Defaults to 1000.
+func MaxEntries(n int) Option {
+	return optionFunc(func(opts *options) {
+		opts.maxEntries = n
+	})
+}
+
+// MaxBytes specifies the maximum total size of the cached responses, counting
+// their bodies and headers. Responses larger than this are never cached.
+//
+// Defaults to 64 MiB.
+func MaxBytes(n int) Option {
+	return optionFunc(func(opts *options) {
+		opts.maxBytes = n
+	})
+}
+
+// withClock specifies the clock that expires responses, for tests.
+func withClock(clk clock.Clock) Option {
+	return optionFunc(func(opts *options) {
+		opts.clock = clk
+	})
+}
+
+// Cache is a unary outbound middleware that caches responses and coalesces
+// concurrent identical calls.
+type Cache struct {
+	opts options
+
+	lock    sync.Mutex
+	entries *entries
+	flights map[string]*flight
+}
+
+var _ middleware.UnaryOutbound = (*Cache)(nil)
+
+// flight is a call in progress, awaited by identical calls.
+type flight struct {
+	done  chan struct{}
+	entry *entry
+	err   error
+
+	// abandoned is set if the call failed because its caller's context
+	// ended, in which case the calls awaiting it make the call again.
+	abandoned bool
+}
+
+// result returns the response and error of the call.
+func (f *flight) result() (*transport.Response, error) {
+	if f.entry == nil {
+		return nil, f.err
+	}
+	return f.entry.response(), f.err
+}
+
+// New builds a new response cache.
+func New(opts ...Option) *Cache {
+	options := defaultOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.maxEntries < 1 {
+		options.maxEntries = 1
+	}
+	if options.clock == nil {
+		options.clock = clock.NewReal()
+	}
+
+	return &Cache{
+		opts:    options,
+		entries: newEntries(options.maxEntries, options.maxBytes),
+		flights: make(map[string]*flight),
+	}
+}
+
+// Call answers the request from the cache if it holds a fresh response for
+// it, waits for an identical call in flight if there is one, and calls the
+// outbound otherwise.
+//
+// Calls waiting for an identical call share its response and error, unless
+// that call failed because its own context ended, in which case they make
+// the call again.
+func (c *Cache) Call(ctx context.Context, req *transport.Request, out transport.UnaryOutbound) (*transport.Response, error) {
+	if !c.opts.filter(req) {
+		return out.Call(ctx, req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+	key := c.key(req, body)
+
+	for {
+		c.lock.Lock()
+		if e, ok := c.entries.Get(key, c.opts.clock.Now()); ok {
+			c.lock.Unlock()
+			return e.response(), nil
+		}
+		f, inFlight := c.flights[key]
+		if !inFlight {
+			f = &flight{done: make(chan struct{})}
+			c.flights[key] = f
+		}
+		c.lock.Unlock()
+
+		if !inFlight {
+			r := *req
+			r.Body = bytes.NewReader(body)
+			c.lead(ctx, key, &r, out, f)
+			return f.result()
+		}
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !f.abandoned {
+			return f.result()
+		}
+	}
+}
+
+// lead makes the call for the flight, caches its response if allowed, and
+// releases the calls awaiting it.
+func (c *Cache) lead(ctx context.Context, key string, req *transport.Request, out transport.UnaryOutbound, f *flight) {
+	f.entry, f.err = call(ctx, req, out)
+	f.abandoned = f.err != nil && ctx.Err() != nil
+
+	c.lock.Lock()
+	delete(c.flights, key)
+	if f.err == nil {
+		c.store(key, f.entry)
+	}
+	c.lock.Unlock()
+	close(f.done)
+}
+
+// key builds the cache key for the given request.
+func (c *Cache) key(req *transport.Request, body []byte) string {
+	d := digester.New()
+	defer d.Free()
+
+	d.Add(req.Service)
+	d.Add(req.Procedure)
+	d.Add(string(req.Encoding))
+	d.Add(req.ShardKey)
+	for _, k := range c.opts.keyHeaders {
+		if v, ok := req.Headers.Get(k); ok {
+			d.Add("1" + v)
+		} else {
+			d.Add("0")
+		}
+	}
+	sum := sha256.Sum256(body)
+	d.Add(string(sum[:]))
+	return string(d.Digest())
+}
+
+// store caches the entry for the TTL allowed by its response headers, if
+// any. store must be called with the lock held.
+func (c *Cache) store(key string, e *entry) {
+	if e.applicationError || e.size > c.opts.maxBytes {
+		return
+	}
+
+	ttl := c.opts.ttl
+	if v, ok := e.headers.Get(c.opts.controlHeader); ok {
+		ttl = parseControl(v, ttl)
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	e.expires = c.opts.clock.Now().Add(ttl)
+	c.entries.Put(key, e)
+}
+
+// call makes the call and reads its response into an entry.
+// Outbounds may return a response with an error, for example the details of
+// an application error, so call returns both.
+func call(ctx context.Context, req *transport.Request, out transport.UnaryOutbound) (*entry, error) {
+	res, err := out.Call(ctx, req)
+	if res == nil {
+		return nil, err
+	}
+
+	var body []byte
+	if res.Body != nil {
+		var rerr error
+		body, rerr = ioutil.ReadAll(res.Body)
+		if cerr := res.Body.Close(); rerr == nil {
+			rerr = cerr
+		}
+		if rerr != nil {
+			if err == nil {
+				err = rerr
+			}
+			return nil, err
+		}
+	}
+	return newEntry(res, body), err
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/clock"
+	"go.uber.org/yarpc/internal/testtime"
+)
+
+// fakeOutbound answers calls with the given function and counts them.
+type fakeOutbound struct {
+	transport.UnaryOutbound
+
+	calls  int32
+	handle func(req *transport.Request, body string) (*transport.Response, error)
+}
+
+func (o *fakeOutbound) Call(ctx context.Context, req *transport.Request) (*transport.Response, error) {
+	atomic.AddInt32(&o.calls, 1)
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return o.handle(req, string(body))
+}
+
+func (o *fakeOutbound) Calls() int {
+	return int(atomic.LoadInt32(&o.calls))
+}
+
+// cancelledOutbound blocks calls until their context ends.
+type cancelledOutbound struct {
+	transport.UnaryOutbound
+
+	started chan<- struct{}
+}
+
+func (o cancelledOutbound) Call(ctx context.Context, _ *transport.Request) (*transport.Response, error) {
+	o.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// echoOutbound responds with the request body and the given headers.
+func echoOutbound(headers map[string]string) *fakeOutbound {
+	return &fakeOutbound{handle: func(_ *transport.Request, body string) (*transport.Response, error) {
+		return &transport.Response{
+			Headers: transport.HeadersFromMap(headers),
+			Body:    ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil
+	}}
+}
+
+func newRequest(body string, headers map[string]string) *transport.Request {
+	return &transport.Request{
+		Caller:    "caller",
+		Service:   "service",
+		Encoding:  "raw",
+		Procedure: "get",
+		Headers:   transport.HeadersFromMap(headers),
+		Body:      bytes.NewReader([]byte(body)),
+	}
+}
+
+func doCall(t *testing.T, c *Cache, out transport.UnaryOutbound, req *transport.Request) *transport.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	res, err := c.Call(ctx, req, out)
+	require.NoError(t, err)
+	return res
+}
+
+func readBody(t *testing.T, res *transport.Response) string {
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return string(body)
+}
+
+func TestCacheKey(t *testing.T) {
+	out := echoOutbound(map[string]string{"Foo": "bar"})
+	c := New(TTL(time.Minute), KeyHeaders("tenant"))
+
+	res := doCall(t, c, out, newRequest("hello", nil))
+	assert.Equal(t, "hello", readBody(t, res))
+	assert.Equal(t, 1, out.Calls())
+
+	res = doCall(t, c, out, newRequest("hello", map[string]string{"trace": "1"}))
+	assert.Equal(t, "hello", readBody(t, res), "body of cached response")
+	assert.Equal(t, map[string]string{"Foo": "bar"}, res.Headers.OriginalItems(), "headers of cached response")
+	assert.Equal(t, 1, out.Calls(), "headers not in the key must not change the key")
+
+	// Changing the cached response must not change the cache.
+	res.Headers.With("Foo", "baz")
+	res = doCall(t, c, out, newRequest("hello", nil))
+	assert.Equal(t, map[string]string{"Foo": "bar"}, res.Headers.OriginalItems())
+
+	doCall(t, c, out, newRequest("world", nil))
+	assert.Equal(t, 2, out.Calls(), "a different body must miss the cache")
+
+	doCall(t, c, out, newRequest("hello", map[string]string{"tenant": "a"}))
+	doCall(t, c, out, newRequest("hello", map[string]string{"tenant": ""}))
+	doCall(t, c, out, newRequest("hello", map[string]string{"Tenant": "a"}))
+	assert.Equal(t, 4, out.Calls(), "key headers must be part of the key")
+
+	req := newRequest("hello", nil)
+	req.Procedure = "other"
+	doCall(t, c, out, req)
+
+	req = newRequest("hello", nil)
+	req.ShardKey = "shard"
+	doCall(t, c, out, req)
+	assert.Equal(t, 6, out.Calls())
+}
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		desc     string
+		ttl      time.Duration
+		control  string
+		wantTTL  time.Duration
+		wantMiss bool
+	}{
+		{desc: "no ttl", wantMiss: true},
+		{desc: "default ttl", ttl: time.Minute, wantTTL: time.Minute},
+		{desc: "max-age", control: "max-age=10", wantTTL: 10 * time.Second},
+		{desc: "max-age overrides ttl", ttl: time.Minute, control: "public, max-age=10", wantTTL: 10 * time.Second},
+		{desc: "max-age zero", ttl: time.Minute, control: "max-age=0", wantMiss: true},
+		{desc: "no-store", ttl: time.Minute, control: "max-age=10, no-store", wantMiss: true},
+		{desc: "no-cache", ttl: time.Minute, control: "No-Cache", wantMiss: true},
+		{desc: "invalid max-age", ttl: time.Minute, control: "max-age=soon", wantTTL: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var headers map[string]string
+			if tt.control != "" {
+				headers = map[string]string{"Cache-Control": tt.control}
+			}
+			out := echoOutbound(headers)
+			clk := clock.NewFake()
+			c := New(TTL(tt.ttl), withClock(clk))
+
+			doCall(t, c, out, newRequest("hello", nil))
+			doCall(t, c, out, newRequest("hello", nil))
+			if tt.wantMiss {
+				assert.Equal(t, 2, out.Calls(), "response must not be cached")
+				return
+			}
+			assert.Equal(t, 1, out.Calls(), "response must be cached")
+
+			clk.Add(tt.wantTTL - time.Millisecond)
+			doCall(t, c, out, newRequest("hello", nil))
+			assert.Equal(t, 1, out.Calls(), "response must be cached until it expires")
+
+			clk.Add(time.Millisecond)
+			doCall(t, c, out, newRequest("hello", nil))
+			assert.Equal(t, 2, out.Calls(), "response must expire")
+		})
+	}
+}
+
+func TestCacheControlHeader(t *testing.T) {
+	out := echoOutbound(map[string]string{"x-ttl": "max-age=10"})
+	c := New(ControlHeader("x-ttl"))
+
+	doCall(t, c, out, newRequest("hello", nil))
+	doCall(t, c, out, newRequest("hello", nil))
+	assert.Equal(t, 1, out.Calls())
+}
+
+func TestCacheErrors(t *testing.T) {
+	c := New(TTL(time.Minute))
+
+	out := &fakeOutbound{handle: func(*transport.Request, string) (*transport.Response, error) {
+		return nil, errors.New("great sadness")
+	}}
+	for i := 0; i < 2; i++ {
+		_, err := c.Call(context.Background(), newRequest("hello", nil), out)
+		assert.EqualError(t, err, "great sadness")
+	}
+	assert.Equal(t, 2, out.Calls(), "errors must not be cached")
+
+	out = &fakeOutbound{handle: func(*transport.Request, string) (*transport.Response, error) {
+		return &transport.Response{
+			Body:             ioutil.NopCloser(bytes.NewReader([]byte("oops"))),
+			ApplicationError: true,
+		}, nil
+	}}
+	for i := 0; i < 2; i++ {
+		res := doCall(t, c, out, newRequest("hello", nil))
+		assert.True(t, res.ApplicationError)
+		assert.Equal(t, "oops", readBody(t, res))
+	}
+	assert.Equal(t, 2, out.Calls(), "application errors must not be cached")
+
+	out = &fakeOutbound{handle: func(*transport.Request, string) (*transport.Response, error) {
+		return &transport.Response{
+			Headers:          transport.NewHeaders().With("detail", "1"),
+			Body:             ioutil.NopCloser(bytes.NewReader([]byte("details"))),
+			ApplicationError: true,
+		}, errors.New("great sadness")
+	}}
+	for i := 0; i < 2; i++ {
+		res, err := c.Call(context.Background(), newRequest("hello", nil), out)
+		assert.EqualError(t, err, "great sadness")
+		require.NotNil(t, res, "must return the response with the error")
+		assert.True(t, res.ApplicationError)
+		assert.Equal(t, map[string]string{"detail": "1"}, res.Headers.OriginalItems())
+		assert.Equal(t, "details", readBody(t, res))
+	}
+	assert.Equal(t, 2, out.Calls(), "responses with errors must not be cached")
+}
+
+func TestCacheFilter(t *testing.T) {
+	out := echoOutbound(nil)
+	c := New(TTL(time.Minute), Filter(func(req *transport.Request) bool {
+		return req.Procedure == "get"
+	}))
+
+	req := newRequest("hello", nil)
+	req.Procedure = "set"
+	doCall(t, c, out, req)
+
+	req = newRequest("hello", nil)
+	req.Procedure = "set"
+	assert.Equal(t, "hello", readBody(t, doCall(t, c, out, req)))
+	assert.Equal(t, 2, out.Calls(), "filtered requests must not be cached")
+
+	doCall(t, c, out, newRequest("hello", nil))
+	doCall(t, c, out, newRequest("hello", nil))
+	assert.Equal(t, 3, out.Calls())
+}
+
+func TestCacheLimits(t *testing.T) {
+	t.Run("max entries", func(t *testing.T) {
+		out := echoOutbound(nil)
+		c := New(TTL(time.Minute), MaxEntries(2))
+
+		doCall(t, c, out, newRequest("a", nil))
+		doCall(t, c, out, newRequest("b", nil))
+		doCall(t, c, out, newRequest("a", nil)) // a is now more recent than b
+		doCall(t, c, out, newRequest("c", nil)) // evicts b
+		assert.Equal(t, 3, out.Calls())
+		assert.Equal(t, 2, c.entries.Len())
+
+		doCall(t, c, out, newRequest("a", nil))
+		assert.Equal(t, 3, out.Calls(), "a must still be cached")
+		doCall(t, c, out, newRequest("b", nil))
+		assert.Equal(t, 4, out.Calls(), "b must have been evicted")
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		out := echoOutbound(nil)
+		c := New(TTL(time.Minute), MaxBytes(10))
+
+		doCall(t, c, out, newRequest("0123456789a", nil))
+		doCall(t, c, out, newRequest("0123456789a", nil))
+		assert.Equal(t, 2, out.Calls(), "responses larger than the limit must not be cached")
+
+		doCall(t, c, out, newRequest("01234", nil))
+		doCall(t, c, out, newRequest("56789", nil))
+		assert.Equal(t, 2, c.entries.Len())
+		doCall(t, c, out, newRequest("abc", nil)) // evicts 01234
+		assert.Equal(t, 2, c.entries.Len())
+
+		doCall(t, c, out, newRequest("56789", nil))
+		assert.Equal(t, 5, out.Calls())
+		doCall(t, c, out, newRequest("01234", nil))
+		assert.Equal(t, 6, out.Calls())
+	})
+}
+
+func TestCacheCoalesce(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	out := &fakeOutbound{handle: func(_ *transport.Request, body string) (*transport.Response, error) {
+		started <- struct{}{}
+		<-release
+		return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	}}
+	c := New(TTL(time.Minute))
+
+	const callers = 10
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "hello", readBody(t, doCall(t, c, out, newRequest("hello", nil))))
+		}()
+		if i == 0 {
+			<-started
+		}
+	}
+
+	// A call whose context ends while waiting for the call in flight gives
+	// up without calling the outbound.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.Call(ctx, newRequest("hello", nil), out)
+	assert.Equal(t, context.Canceled, err)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, out.Calls())
+}
+
+func TestCacheCoalesceAbandoned(t *testing.T) {
+	started := make(chan struct{}, 2)
+	out := &fakeOutbound{handle: func(_ *transport.Request, body string) (*transport.Response, error) {
+		started <- struct{}{}
+		return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	}}
+	// The first call gives up when its context ends.
+	leader := cancelledOutbound{started: started}
+	c := New(TTL(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Call(ctx, newRequest("hello", nil), leader)
+		leaderErr <- err
+	}()
+	<-started
+
+	followerRes := make(chan *transport.Response, 1)
+	go func() {
+		followerRes <- doCall(t, c, out, newRequest("hello", nil))
+	}()
+	// Give the second call time to start waiting for the first.
+	time.Sleep(10 * testtime.Millisecond)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-leaderErr)
+	assert.Equal(t, "hello", readBody(t, <-followerRes),
+		"calls awaiting a call whose context ended must make the call again")
+	assert.Equal(t, 1, out.Calls())
+}
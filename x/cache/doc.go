@@ -0,0 +1,54 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package cache provides an outbound middleware that caches the responses of
+// unary calls, for procedures whose results are safe to reuse for a while.
+//
+// Responses are cached by service, procedure, encoding, shard key, a digest
+// of the request body, and the values of the request headers given with the
+// KeyHeaders option. Calls answered from the cache do not reach the outbound.
+// Concurrent identical calls are coalesced: while a call is in flight, other
+// calls with the same key wait for its response instead of making their own.
+//
+// 	responseCache := cache.New(
+// 		cache.TTL(5*time.Second),
+// 		cache.Filter(func(req *transport.Request) bool {
+// 			return strings.HasPrefix(req.Procedure, "Users::get")
+// 		}),
+// 	)
+// 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+// 		Name:      "myservice",
+// 		Outbounds: outbounds,
+// 		OutboundMiddleware: yarpc.OutboundMiddleware{
+// 			Unary: responseCache,
+// 		},
+// 	})
+//
+// Servers control how long their responses are cached with the cache-control
+// response header, which holds comma-separated directives like those of HTTP.
+// "max-age=30" caches the response for 30 seconds, and "no-store" or
+// "no-cache" prevents it from being cached. Responses without this header are
+// cached for the duration given by the TTL option, which is zero by default:
+// without that option, only responses carrying a max-age are cached.
+//
+// Errors and responses with application errors are never cached, although
+// coalesced calls share them. If a call fails because its own context ended,
+// the calls waiting for it make the call again instead.
+package cache
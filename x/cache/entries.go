@@ -0,0 +1,127 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+)
+
+// entry is a response read from the outbound.
+type entry struct {
+	key              string
+	headers          transport.Headers
+	body             []byte
+	applicationError bool
+	size             int
+	expires          time.Time
+}
+
+func newEntry(res *transport.Response, body []byte) *entry {
+	// Copy the headers so that the caller may not change the cached ones.
+	headers := transport.HeadersFromMap(res.Headers.OriginalItems())
+	size := len(body)
+	for k, v := range headers.OriginalItems() {
+		size += len(k) + len(v)
+	}
+	return &entry{
+		headers:          headers,
+		body:             body,
+		applicationError: res.ApplicationError,
+		size:             size,
+	}
+}
+
+// response builds a new response from the entry.
+func (e *entry) response() *transport.Response {
+	return &transport.Response{
+		Headers:          transport.HeadersFromMap(e.headers.OriginalItems()),
+		Body:             ioutil.NopCloser(bytes.NewReader(e.body)),
+		ApplicationError: e.applicationError,
+	}
+}
+
+// entries is a least recently used cache of entries, bounded in number and
+// total size. entries is not safe for concurrent use.
+type entries struct {
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	order      *list.List // of *entry, most recently used first
+	keys       map[string]*list.Element
+}
+
+func newEntries(maxEntries, maxBytes int) *entries {
+	return &entries{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		keys:       make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for the given key, if it has not expired, and marks
+// it as recently used.
+func (es *entries) Get(key string, now time.Time) (*entry, bool) {
+	el, ok := es.keys[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expires) {
+		es.remove(el)
+		return nil, false
+	}
+	es.order.MoveToFront(el)
+	return e, true
+}
+
+// Put caches the entry under the given key, evicting the least recently used
+// entries until the cache fits its bounds.
+func (es *entries) Put(key string, e *entry) {
+	if el, ok := es.keys[key]; ok {
+		es.remove(el)
+	}
+
+	e.key = key
+	es.keys[key] = es.order.PushFront(e)
+	es.bytes += e.size
+	for es.order.Len() > es.maxEntries || es.bytes > es.maxBytes {
+		es.remove(es.order.Back())
+	}
+}
+
+// Len returns the number of entries, including expired ones that have not
+// been evicted yet.
+func (es *entries) Len() int {
+	return es.order.Len()
+}
+
+func (es *entries) remove(el *list.Element) {
+	e := el.Value.(*entry)
+	delete(es.keys, e.key)
+	es.bytes -= e.size
+	es.order.Remove(el)
+}
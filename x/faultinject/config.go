@@ -0,0 +1,208 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+	"gopkg.in/yaml.v2"
+)
+
+// Direction is the direction of the requests matched by a rule.
+type Direction string
+
+const (
+	// Inbound matches requests received by the service.
+	Inbound Direction = "inbound"
+
+	// Outbound matches requests sent by the service.
+	Outbound Direction = "outbound"
+)
+
+// Config configures the faults injected by an Injector.
+type Config struct {
+	// Enabled must be true for any fault to be injected.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Rules are matched in order against each request. The first rule that
+	// matches a request decides whether to inject a fault into it.
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule matches requests and specifies the faults to inject into them.
+//
+// Empty matching fields match all requests. A rule injects its faults into
+// the given fraction of the requests it matches: the request is first
+// delayed, then failed with the error code or dropped.
+type Rule struct {
+	// Name identifies the rule in the errors it injects.
+	Name string `json:"name,omitempty" yaml:"name"`
+
+	// Direction, Service, Procedure, Caller and Headers match requests.
+	Direction Direction         `json:"direction,omitempty" yaml:"direction"`
+	Service   string            `json:"service,omitempty" yaml:"service"`
+	Procedure string            `json:"procedure,omitempty" yaml:"procedure"`
+	Caller    string            `json:"caller,omitempty" yaml:"caller"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers"`
+
+	// Fraction is the fraction of matched requests to inject faults into,
+	// between 0 and 1.
+	Fraction float64 `json:"fraction" yaml:"fraction"`
+
+	// Delay delays requests by the given duration. Delays are written like
+	// "500ms" in JSON and YAML.
+	Delay time.Duration `json:"delay,omitempty" yaml:"delay"`
+
+	// Code fails requests with an error with the given code, and the given
+	// message if any.
+	Code    yarpcerrors.Code `json:"code,omitempty" yaml:"code"`
+	Message string           `json:"message,omitempty" yaml:"message"`
+
+	// Drop drops requests. Unary requests are handled, but their responses
+	// are discarded, and the call fails when its context ends, as if the
+	// response had been lost. Oneway requests are acknowledged but neither
+	// sent nor handled.
+	Drop bool `json:"drop,omitempty" yaml:"drop"`
+}
+
+// jsonRule is a Rule without JSON methods.
+type jsonRule Rule
+
+// MarshalJSON implements json.Marshaler, writing the delay as a string.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	var delay string
+	if r.Delay != 0 {
+		delay = r.Delay.String()
+	}
+	return json.Marshal(struct {
+		jsonRule
+		Delay string `json:"delay,omitempty"`
+	}{jsonRule(r), delay})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading the delay from a string.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	var v struct {
+		jsonRule
+		Delay string `json:"delay"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*r = Rule(v.jsonRule)
+	r.Delay = 0
+	if v.Delay != "" {
+		delay, err := time.ParseDuration(v.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid delay %q: %v", v.Delay, err)
+		}
+		r.Delay = delay
+	}
+	return nil
+}
+
+// LoadConfigFromYAML reads a configuration from YAML and validates it.
+func LoadConfigFromYAML(r io.Reader) (Config, error) {
+	var cfg Config
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks that the configuration is valid.
+func (c Config) Validate() error {
+	for i, r := range c.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("invalid rule %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch r.Direction {
+	case "", Inbound, Outbound:
+	default:
+		return fmt.Errorf("unknown direction %q", r.Direction)
+	}
+	if r.Fraction < 0 || r.Fraction > 1 {
+		return fmt.Errorf("fraction %v is not between 0 and 1", r.Fraction)
+	}
+	if r.Delay < 0 {
+		return fmt.Errorf("delay %v is negative", r.Delay)
+	}
+	if r.Code != yarpcerrors.CodeOK && r.Drop {
+		return fmt.Errorf("cannot both fail and drop requests")
+	}
+	if r.Delay == 0 && r.Code == yarpcerrors.CodeOK && !r.Drop {
+		return fmt.Errorf("no fault to inject")
+	}
+	return nil
+}
+
+// matches returns whether the rule matches the request.
+func (r *Rule) matches(dir Direction, req *transport.Request) bool {
+	if r.Direction != "" && r.Direction != dir {
+		return false
+	}
+	if r.Service != "" && r.Service != req.Service {
+		return false
+	}
+	if r.Procedure != "" && r.Procedure != req.Procedure {
+		return false
+	}
+	if r.Caller != "" && r.Caller != req.Caller {
+		return false
+	}
+	for k, v := range r.Headers {
+		if got, ok := req.Headers.Get(k); !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// err returns the error to inject, if any.
+func (r *Rule) err() error {
+	if r.Code == yarpcerrors.CodeOK {
+		return nil
+	}
+	msg := r.Message
+	if msg == "" {
+		msg = "injected fault"
+		if r.Name != "" {
+			msg = fmt.Sprintf("fault injected by rule %q", r.Name)
+		}
+	}
+	return yarpcerrors.Newf(r.Code, "%s", msg)
+}
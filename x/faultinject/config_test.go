@@ -0,0 +1,173 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func TestLoadConfigFromYAML(t *testing.T) {
+	cfg, err := LoadConfigFromYAML(strings.NewReader(`
+enabled: true
+rules:
+  - name: slow-users
+    direction: outbound
+    service: users
+    procedure: Users::get
+    fraction: 0.1
+    delay: 500ms
+  - name: flaky-payments
+    direction: inbound
+    caller: checkout
+    headers:
+      x-chaos: "true"
+    fraction: 0.5
+    code: unavailable
+    message: try again
+  - fraction: 1
+    drop: true
+`))
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		Enabled: true,
+		Rules: []Rule{
+			{
+				Name:      "slow-users",
+				Direction: Outbound,
+				Service:   "users",
+				Procedure: "Users::get",
+				Fraction:  0.1,
+				Delay:     500 * time.Millisecond,
+			},
+			{
+				Name:      "flaky-payments",
+				Direction: Inbound,
+				Caller:    "checkout",
+				Headers:   map[string]string{"x-chaos": "true"},
+				Fraction:  0.5,
+				Code:      yarpcerrors.CodeUnavailable,
+				Message:   "try again",
+			},
+			{
+				Fraction: 1,
+				Drop:     true,
+			},
+		},
+	}, cfg)
+}
+
+func TestLoadConfigFromYAMLErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		give    string
+		wantErr string
+	}{
+		{
+			desc:    "invalid yaml",
+			give:    "rules: {",
+			wantErr: "yaml",
+		},
+		{
+			desc:    "unknown code",
+			give:    "rules: [{fraction: 1, code: sadness}]",
+			wantErr: "unknown code string: sadness",
+		},
+		{
+			desc:    "unknown direction",
+			give:    "rules: [{direction: sideways, fraction: 1, drop: true}]",
+			wantErr: `invalid rule 0: unknown direction "sideways"`,
+		},
+		{
+			desc:    "fraction too large",
+			give:    "rules: [{fraction: 1.5, drop: true}]",
+			wantErr: "invalid rule 0: fraction 1.5 is not between 0 and 1",
+		},
+		{
+			desc:    "negative fraction",
+			give:    "rules: [{fraction: 1, drop: true}, {fraction: -1, drop: true}]",
+			wantErr: "invalid rule 1: fraction -1 is not between 0 and 1",
+		},
+		{
+			desc:    "negative delay",
+			give:    "rules: [{fraction: 1, delay: -1s}]",
+			wantErr: "invalid rule 0: delay -1s is negative",
+		},
+		{
+			desc:    "fail and drop",
+			give:    "rules: [{fraction: 1, code: internal, drop: true}]",
+			wantErr: "invalid rule 0: cannot both fail and drop requests",
+		},
+		{
+			desc:    "no fault",
+			give:    "rules: [{fraction: 1, service: users}]",
+			wantErr: "invalid rule 0: no fault to inject",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := LoadConfigFromYAML(strings.NewReader(tt.give))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRuleJSON(t *testing.T) {
+	rule := Rule{
+		Name:     "slow",
+		Service:  "users",
+		Fraction: 0.25,
+		Delay:    1500 * time.Millisecond,
+		Code:     yarpcerrors.CodeDeadlineExceeded,
+	}
+
+	data, err := json.Marshal(rule)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "slow",
+		"service": "users",
+		"fraction": 0.25,
+		"delay": "1.5s",
+		"code": "deadline-exceeded"
+	}`, string(data))
+
+	var got Rule
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, rule, got)
+
+	data, err = json.Marshal(Rule{Fraction: 1, Drop: true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"fraction": 1, "drop": true}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"delay": "soon"}`), &got)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid delay "soon"`)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"fraction": "all"}`), &got))
+}
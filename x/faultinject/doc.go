@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package faultinject provides middleware that injects faults into requests,
+// to exercise the resilience of services and their callers in staging
+// environments.
+//
+// An Injector is an inbound and outbound middleware for unary and oneway
+// requests. It injects faults into a fraction of the requests matched by the
+// rules of its configuration: it delays them, fails them with a YARPC error
+// code, or drops their responses. The Injector is inert until it is given a
+// configuration that enables it.
+//
+// 	injector := faultinject.New()
+// 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+// 		Name:     "myservice",
+// 		Inbounds: inbounds,
+// 		InboundMiddleware: yarpc.InboundMiddleware{
+// 			Unary:  injector,
+// 			Oneway: injector,
+// 		},
+// 		OutboundMiddleware: yarpc.OutboundMiddleware{
+// 			Unary:  injector,
+// 			Oneway: injector,
+// 		},
+// 	})
+//
+// The configuration may be loaded from YAML with LoadConfigFromYAML.
+//
+// 	enabled: true
+// 	rules:
+// 	  - name: slow-users
+// 	    direction: outbound
+// 	    service: users
+// 	    procedure: Users::get
+// 	    fraction: 0.1
+// 	    delay: 500ms
+// 	  - name: flaky-payments
+// 	    direction: inbound
+// 	    headers:
+// 	      x-chaos: "true"
+// 	    fraction: 0.5
+// 	    code: unavailable
+//
+// The configuration may also be read at runtime with the yarpc::faults
+// procedure, encoded in JSON, once registered on a dispatcher with Register.
+// The yarpc::faults::set procedure, which replaces the configuration, is only
+// registered with the EnableSet option, which may restrict it to a list of
+// callers.
+//
+// 	faultinject.Register(dispatcher, injector, faultinject.EnableSet("chaos-controller"))
+//
+// WARNING: anyone able to call yarpc::faults::set can make the service fail.
+// YARPC does not authenticate callers, and caller names are declared by the
+// callers themselves, so the list of callers does not stop a malicious
+// caller. Only enable the procedure on inbounds that trusted networks alone
+// can reach, and never in production without such a restriction.
+package faultinject
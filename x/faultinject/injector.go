@@ -0,0 +1,207 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/api/middleware"
+	"go.uber.org/yarpc/api/transport"
+)
+
+// Injector is a middleware that injects faults into the requests matched by
+// the rules of its configuration.
+type Injector struct {
+	lock   sync.RWMutex
+	config Config
+
+	// rand returns a random number in [0, 1).
+	rand func() float64
+}
+
+var (
+	_ middleware.UnaryInbound   = (*Injector)(nil)
+	_ middleware.OnewayInbound  = (*Injector)(nil)
+	_ middleware.UnaryOutbound  = (*Injector)(nil)
+	_ middleware.OnewayOutbound = (*Injector)(nil)
+)
+
+// New builds an Injector without rules, which injects no faults until it is
+// given a configuration that enables it.
+func New() *Injector {
+	return &Injector{rand: rand.Float64}
+}
+
+// Config returns the configuration of the Injector.
+func (i *Injector) Config() Config {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	cfg := i.config
+	cfg.Rules = append([]Rule(nil), cfg.Rules...)
+	return cfg
+}
+
+// SetConfig validates the given configuration and replaces the
+// configuration of the Injector with it.
+func (i *Injector) SetConfig(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	cfg.Rules = append([]Rule(nil), cfg.Rules...)
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.config = cfg
+	return nil
+}
+
+// fault returns the rule whose faults to inject into the request, if any.
+func (i *Injector) fault(dir Direction, req *transport.Request) (Rule, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if !i.config.Enabled {
+		return Rule{}, false
+	}
+	for _, r := range i.config.Rules {
+		if r.matches(dir, req) {
+			return r, i.rand() < r.Fraction
+		}
+	}
+	return Rule{}, false
+}
+
+// Handle injects faults into an inbound unary request.
+func (i *Injector) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	r, ok := i.fault(Inbound, req)
+	if !ok {
+		return h.Handle(ctx, req, resw)
+	}
+
+	if err := sleep(ctx, r.Delay); err != nil {
+		return err
+	}
+	if err := r.err(); err != nil {
+		return err
+	}
+	if r.Drop {
+		// The handler's response and error are lost.
+		_ = h.Handle(ctx, req, discardWriter{})
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return h.Handle(ctx, req, resw)
+}
+
+// HandleOneway injects faults into an inbound oneway request.
+func (i *Injector) HandleOneway(ctx context.Context, req *transport.Request, h transport.OnewayHandler) error {
+	r, ok := i.fault(Inbound, req)
+	if !ok {
+		return h.HandleOneway(ctx, req)
+	}
+
+	if err := sleep(ctx, r.Delay); err != nil {
+		return err
+	}
+	if err := r.err(); err != nil {
+		return err
+	}
+	if r.Drop {
+		return nil
+	}
+	return h.HandleOneway(ctx, req)
+}
+
+// Call injects faults into an outbound unary request.
+func (i *Injector) Call(ctx context.Context, req *transport.Request, out transport.UnaryOutbound) (*transport.Response, error) {
+	r, ok := i.fault(Outbound, req)
+	if !ok {
+		return out.Call(ctx, req)
+	}
+
+	if err := sleep(ctx, r.Delay); err != nil {
+		return nil, err
+	}
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if r.Drop {
+		if res, err := out.Call(ctx, req); err == nil && res.Body != nil {
+			_ = res.Body.Close()
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return out.Call(ctx, req)
+}
+
+// CallOneway injects faults into an outbound oneway request.
+func (i *Injector) CallOneway(ctx context.Context, req *transport.Request, out transport.OnewayOutbound) (transport.Ack, error) {
+	r, ok := i.fault(Outbound, req)
+	if !ok {
+		return out.CallOneway(ctx, req)
+	}
+
+	if err := sleep(ctx, r.Delay); err != nil {
+		return nil, err
+	}
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if r.Drop {
+		return droppedAck{}, nil
+	}
+	return out.CallOneway(ctx, req)
+}
+
+// sleep waits for the given duration, or until the context ends.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discardWriter is a response writer that discards the response.
+type discardWriter struct{}
+
+func (discardWriter) AddHeaders(transport.Headers) {}
+
+func (discardWriter) SetApplicationError() {}
+
+func (discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+// droppedAck acknowledges a dropped oneway request.
+type droppedAck struct{}
+
+func (droppedAck) String() string { return "dropped" }
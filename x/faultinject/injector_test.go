@@ -0,0 +1,325 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/testtime"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+type unaryHandlerFunc func(context.Context, *transport.Request, transport.ResponseWriter) error
+
+func (f unaryHandlerFunc) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	return f(ctx, req, resw)
+}
+
+type onewayHandlerFunc func(context.Context, *transport.Request) error
+
+func (f onewayHandlerFunc) HandleOneway(ctx context.Context, req *transport.Request) error {
+	return f(ctx, req)
+}
+
+// fakeOutbound is a unary and oneway outbound that counts its calls.
+type fakeOutbound struct {
+	transport.Outbound
+
+	calls int
+}
+
+func (o *fakeOutbound) Call(context.Context, *transport.Request) (*transport.Response, error) {
+	o.calls++
+	return &transport.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte("hello")))}, nil
+}
+
+func (o *fakeOutbound) CallOneway(context.Context, *transport.Request) (transport.Ack, error) {
+	o.calls++
+	return nil, nil
+}
+
+func newRequest() *transport.Request {
+	return &transport.Request{
+		Caller:    "caller",
+		Service:   "service",
+		Encoding:  "raw",
+		Procedure: "procedure",
+		Headers:   transport.NewHeaders().With("x-chaos", "true"),
+		Body:      bytes.NewReader(nil),
+	}
+}
+
+func newInjector(t *testing.T, rules ...Rule) *Injector {
+	i := New()
+	require.NoError(t, i.SetConfig(Config{Enabled: true, Rules: rules}))
+	return i
+}
+
+// injects returns whether the injector fails an outbound unary request.
+func injects(i *Injector, req *transport.Request) bool {
+	_, err := i.Call(context.Background(), req, new(fakeOutbound))
+	return err != nil
+}
+
+func TestInert(t *testing.T) {
+	rule := Rule{Fraction: 1, Code: yarpcerrors.CodeInternal}
+
+	assert.False(t, injects(New(), newRequest()), "injector without configuration must be inert")
+
+	i := New()
+	require.NoError(t, i.SetConfig(Config{Rules: []Rule{rule}}))
+	assert.False(t, injects(i, newRequest()), "disabled injector must be inert")
+
+	i = newInjector(t, rule)
+	assert.True(t, injects(i, newRequest()))
+
+	cfg := i.Config()
+	cfg.Enabled = false
+	require.NoError(t, i.SetConfig(cfg))
+	assert.False(t, injects(i, newRequest()), "injector must be disabled at runtime")
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		desc string
+		rule Rule
+		want bool
+	}{
+		{desc: "all", want: true},
+		{desc: "direction", rule: Rule{Direction: Outbound}, want: true},
+		{desc: "other direction", rule: Rule{Direction: Inbound}},
+		{desc: "service", rule: Rule{Service: "service"}, want: true},
+		{desc: "other service", rule: Rule{Service: "other"}},
+		{desc: "procedure", rule: Rule{Procedure: "procedure"}, want: true},
+		{desc: "other procedure", rule: Rule{Procedure: "other"}},
+		{desc: "caller", rule: Rule{Caller: "caller"}, want: true},
+		{desc: "other caller", rule: Rule{Caller: "other"}},
+		{desc: "header", rule: Rule{Headers: map[string]string{"X-Chaos": "true"}}, want: true},
+		{desc: "header value", rule: Rule{Headers: map[string]string{"x-chaos": "false"}}},
+		{desc: "missing header", rule: Rule{Headers: map[string]string{"x-chaos": "true", "x-other": ""}}},
+		{
+			desc: "all fields",
+			rule: Rule{
+				Direction: Outbound,
+				Service:   "service",
+				Procedure: "procedure",
+				Caller:    "caller",
+				Headers:   map[string]string{"x-chaos": "true"},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.rule.Fraction = 1
+			tt.rule.Code = yarpcerrors.CodeInternal
+			assert.Equal(t, tt.want, injects(newInjector(t, tt.rule), newRequest()))
+		})
+	}
+}
+
+func TestFirstMatchingRule(t *testing.T) {
+	i := newInjector(t,
+		Rule{Procedure: "other", Fraction: 1, Code: yarpcerrors.CodeInternal},
+		Rule{Service: "service", Fraction: 1, Code: yarpcerrors.CodeUnavailable},
+		Rule{Fraction: 1, Code: yarpcerrors.CodeAborted},
+	)
+
+	_, err := i.Call(context.Background(), newRequest(), new(fakeOutbound))
+	assert.Equal(t, yarpcerrors.CodeUnavailable, yarpcerrors.FromError(err).Code())
+}
+
+func TestFraction(t *testing.T) {
+	i := newInjector(t, Rule{Fraction: 0.25, Code: yarpcerrors.CodeInternal})
+
+	i.rand = func() float64 { return 0.24 }
+	assert.True(t, injects(i, newRequest()))
+
+	i.rand = func() float64 { return 0.25 }
+	assert.False(t, injects(i, newRequest()))
+
+	i = newInjector(t, Rule{Fraction: 0, Code: yarpcerrors.CodeInternal})
+	i.rand = func() float64 { return 0 }
+	assert.False(t, injects(i, newRequest()), "zero fraction must never inject")
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		rule Rule
+		want error
+	}{
+		{
+			desc: "default message",
+			rule: Rule{Code: yarpcerrors.CodeUnavailable},
+			want: yarpcerrors.Newf(yarpcerrors.CodeUnavailable, "injected fault"),
+		},
+		{
+			desc: "rule name",
+			rule: Rule{Name: "flaky", Code: yarpcerrors.CodeInternal},
+			want: yarpcerrors.Newf(yarpcerrors.CodeInternal, `fault injected by rule "flaky"`),
+		},
+		{
+			desc: "message",
+			rule: Rule{Name: "flaky", Code: yarpcerrors.CodeInternal, Message: "great sadness"},
+			want: yarpcerrors.Newf(yarpcerrors.CodeInternal, "great sadness"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.rule.Fraction = 1
+			i := newInjector(t, tt.rule)
+			ctx := context.Background()
+
+			out := new(fakeOutbound)
+			_, err := i.Call(ctx, newRequest(), out)
+			assert.Equal(t, tt.want, err, "unary outbound")
+			_, err = i.CallOneway(ctx, newRequest(), out)
+			assert.Equal(t, tt.want, err, "oneway outbound")
+			assert.Equal(t, 0, out.calls, "failed requests must not be sent")
+
+			handled := false
+			err = i.Handle(ctx, newRequest(), new(transporttest.FakeResponseWriter),
+				unaryHandlerFunc(func(context.Context, *transport.Request, transport.ResponseWriter) error {
+					handled = true
+					return nil
+				}))
+			assert.Equal(t, tt.want, err, "unary inbound")
+			err = i.HandleOneway(ctx, newRequest(), onewayHandlerFunc(func(context.Context, *transport.Request) error {
+				handled = true
+				return nil
+			}))
+			assert.Equal(t, tt.want, err, "oneway inbound")
+			assert.False(t, handled, "failed requests must not be handled")
+		})
+	}
+}
+
+func TestDelay(t *testing.T) {
+	i := newInjector(t, Rule{Fraction: 1, Delay: 20 * time.Millisecond})
+	out := new(fakeOutbound)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+	start := time.Now()
+	res, err := i.Call(ctx, newRequest(), out)
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond, "request must be delayed")
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = i.CallOneway(ctx, newRequest(), out)
+	assert.Equal(t, context.DeadlineExceeded, err, "delay must end with the context")
+	err = i.HandleOneway(ctx, newRequest(), onewayHandlerFunc(func(context.Context, *transport.Request) error {
+		t.Error("request must not be handled")
+		return nil
+	}))
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, out.calls)
+}
+
+func TestDrop(t *testing.T) {
+	i := newInjector(t, Rule{Fraction: 1, Drop: true})
+
+	t.Run("unary inbound", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		handled := false
+		resw := new(transporttest.FakeResponseWriter)
+		err := i.Handle(ctx, newRequest(), resw,
+			unaryHandlerFunc(func(_ context.Context, _ *transport.Request, resw transport.ResponseWriter) error {
+				handled = true
+				_, err := resw.Write([]byte("hello"))
+				return err
+			}))
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.True(t, handled, "request must be handled")
+		assert.Equal(t, 0, resw.Body.Len(), "response must be dropped")
+	})
+
+	t.Run("oneway inbound", func(t *testing.T) {
+		err := i.HandleOneway(context.Background(), newRequest(),
+			onewayHandlerFunc(func(context.Context, *transport.Request) error {
+				t.Error("request must not be handled")
+				return nil
+			}))
+		assert.NoError(t, err)
+	})
+
+	t.Run("unary outbound", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		out := new(fakeOutbound)
+		_, err := i.Call(ctx, newRequest(), out)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, 1, out.calls, "request must be sent")
+	})
+
+	t.Run("oneway outbound", func(t *testing.T) {
+		out := new(fakeOutbound)
+		ack, err := i.CallOneway(context.Background(), newRequest(), out)
+		require.NoError(t, err)
+		assert.Equal(t, "dropped", ack.String())
+		assert.Equal(t, 0, out.calls, "request must not be sent")
+	})
+}
+
+func TestNoFault(t *testing.T) {
+	i := newInjector(t, Rule{Service: "other", Fraction: 1, Drop: true})
+	ctx := context.Background()
+
+	out := new(fakeOutbound)
+	_, err := i.Call(ctx, newRequest(), out)
+	require.NoError(t, err)
+	_, err = i.CallOneway(ctx, newRequest(), out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, out.calls)
+
+	handled := 0
+	resw := new(transporttest.FakeResponseWriter)
+	require.NoError(t, i.Handle(ctx, newRequest(), resw,
+		unaryHandlerFunc(func(_ context.Context, _ *transport.Request, resw transport.ResponseWriter) error {
+			handled++
+			_, err := resw.Write([]byte("hello"))
+			return err
+		})))
+	assert.Equal(t, "hello", resw.Body.String())
+	require.NoError(t, i.HandleOneway(ctx, newRequest(), onewayHandlerFunc(func(context.Context, *transport.Request) error {
+		handled++
+		return nil
+	})))
+	assert.Equal(t, 2, handled)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"context"
+
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/json"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	// GetProcedure is the name of the procedure answering with the
+	// configuration of an Injector, encoded in JSON.
+	GetProcedure = "yarpc::faults"
+
+	// SetProcedure is the name of the procedure replacing the configuration
+	// of an Injector with the configuration in its request, encoded in JSON.
+	// It answers with the new configuration.
+	//
+	// It is only registered with the EnableSet option.
+	SetProcedure = "yarpc::faults::set"
+)
+
+// ProcedureOption customizes the procedures controlling an Injector.
+type ProcedureOption interface {
+	apply(*procedureOptions)
+}
+
+type procedureOptionFunc func(*procedureOptions)
+
+func (f procedureOptionFunc) apply(opts *procedureOptions) { f(opts) }
+
+type procedureOptions struct {
+	enableSet      bool
+	allowedCallers map[string]struct{}
+}
+
+// EnableSet registers the SetProcedure procedure, which replaces the
+// configuration of the Injector. If callers are given, only requests from
+// those callers may replace the configuration; other callers receive a
+// permission denied error.
+//
+// Caller names are declared by the callers themselves, so the allow-list
+// only guards against mistakes. Anyone able to reach the service can still
+// make it fail, unless the inbounds serving the procedure are restricted to
+// trusted networks.
+//
+// Only GetProcedure, which reads the configuration, is registered by
+// default.
+func EnableSet(callers ...string) ProcedureOption {
+	return procedureOptionFunc(func(opts *procedureOptions) {
+		opts.enableSet = true
+		if len(callers) == 0 {
+			return
+		}
+		if opts.allowedCallers == nil {
+			opts.allowedCallers = make(map[string]struct{}, len(callers))
+		}
+		for _, caller := range callers {
+			opts.allowedCallers[caller] = struct{}{}
+		}
+	})
+}
+
+// Register registers the procedures controlling the given Injector on a
+// dispatcher.
+func Register(d *yarpc.Dispatcher, i *Injector, opts ...ProcedureOption) {
+	d.Register(Procedures(i, opts...))
+}
+
+// Procedures returns the procedures controlling the given Injector.
+func Procedures(i *Injector, opts ...ProcedureOption) []transport.Procedure {
+	var options procedureOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+
+	get := func(context.Context, interface{}) (*Config, error) {
+		cfg := i.Config()
+		return &cfg, nil
+	}
+	set := func(ctx context.Context, cfg *Config) (*Config, error) {
+		if options.allowedCallers != nil {
+			caller := yarpc.CallFromContext(ctx).Caller()
+			if _, ok := options.allowedCallers[caller]; !ok {
+				return nil, yarpcerrors.PermissionDeniedErrorf("caller %q may not replace the fault injection configuration", caller)
+			}
+		}
+		if err := i.SetConfig(*cfg); err != nil {
+			return nil, yarpcerrors.InvalidArgumentErrorf("%v", err)
+		}
+		return get(ctx, nil)
+	}
+
+	methods := []struct {
+		Name      string
+		Handler   interface{}
+		Signature string
+	}{
+		{GetProcedure, get,
+			`faults() {"enabled": true, "rules": [{"fraction": 0.1, "delay": "100ms"}]}`},
+		{SetProcedure, set,
+			`setFaults({"enabled": true, "rules": [...]}) {"enabled": true, "rules": [...]}`},
+	}
+	var r []transport.Procedure
+	for _, m := range methods {
+		if m.Name == SetProcedure && !options.enableSet {
+			continue
+		}
+		p := json.Procedure(m.Name, m.Handler)[0]
+		p.Signature = m.Signature
+		r = append(r, p)
+	}
+	return r
+}
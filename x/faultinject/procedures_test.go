@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package faultinject
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/encoding/json"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+func callProcedure(t *testing.T, procedures []transport.Procedure, name, body string) (string, error) {
+	return callProcedureAs(t, procedures, "caller", name, body)
+}
+
+func callProcedureAs(t *testing.T, procedures []transport.Procedure, caller, name, body string) (string, error) {
+	for _, p := range procedures {
+		if p.Name != name {
+			continue
+		}
+		resw := new(transporttest.FakeResponseWriter)
+		err := p.HandlerSpec.Unary().Handle(context.Background(), &transport.Request{
+			Caller:    caller,
+			Service:   "service",
+			Encoding:  json.Encoding,
+			Procedure: name,
+			Body:      bytes.NewReader([]byte(body)),
+		}, resw)
+		return resw.Body.String(), err
+	}
+	t.Fatalf("procedure %q not found", name)
+	return "", nil
+}
+
+func TestProcedures(t *testing.T) {
+	i := New()
+	procedures := Procedures(i, EnableSet())
+	require.Len(t, procedures, 2)
+
+	res, err := callProcedure(t, procedures, GetProcedure, "{}")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"enabled": false, "rules": null}`, res)
+
+	res, err = callProcedure(t, procedures, SetProcedure,
+		`{"enabled": true, "rules": [{"service": "users", "fraction": 0.5, "delay": "100ms", "code": "unavailable"}]}`)
+	require.NoError(t, err)
+	want := `{"enabled": true, "rules": [{"service": "users", "fraction": 0.5, "delay": "100ms", "code": "unavailable"}]}`
+	assert.JSONEq(t, want, res)
+	assert.Equal(t, Config{
+		Enabled: true,
+		Rules: []Rule{{
+			Service:  "users",
+			Fraction: 0.5,
+			Delay:    100 * time.Millisecond,
+			Code:     yarpcerrors.CodeUnavailable,
+		}},
+	}, i.Config())
+
+	res, err = callProcedure(t, procedures, GetProcedure, "{}")
+	require.NoError(t, err)
+	assert.JSONEq(t, want, res)
+
+	_, err = callProcedure(t, procedures, SetProcedure, `{"enabled": true, "rules": [{"fraction": 2, "drop": true}]}`)
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+	assert.Contains(t, yarpcerrors.FromError(err).Message(), "fraction 2 is not between 0 and 1")
+
+	assert.True(t, i.Config().Enabled, "invalid configurations must not be applied")
+}
+
+func TestSetProcedureDisabledByDefault(t *testing.T) {
+	procedures := Procedures(New())
+	require.Len(t, procedures, 1)
+	assert.Equal(t, GetProcedure, procedures[0].Name)
+}
+
+func TestSetProcedureAllowedCallers(t *testing.T) {
+	i := New()
+	procedures := Procedures(i, EnableSet("chaos", "oncall"))
+
+	_, err := callProcedureAs(t, procedures, "intruder", SetProcedure, `{"enabled": true}`)
+	assert.Equal(t, yarpcerrors.CodePermissionDenied, yarpcerrors.FromError(err).Code())
+	assert.False(t, i.Config().Enabled, "configurations from other callers must not be applied")
+
+	_, err = callProcedureAs(t, procedures, "oncall", SetProcedure, `{"enabled": true}`)
+	require.NoError(t, err)
+	assert.True(t, i.Config().Enabled)
+}
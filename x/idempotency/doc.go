@@ -0,0 +1,52 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package idempotency provides an inbound middleware that deduplicates
+// retried requests by their idempotency key, for procedures that are not
+// safe to run twice.
+//
+// Callers give each logical request a unique key in the idempotency-key
+// header, and reuse it when they retry the request. The first request with a
+// key runs the handler, and its response is stored; requests with the same
+// key replay that response, including its headers and application error
+// flag, without running the handler again. Requests with a key that is still
+// being handled wait until the first one finishes.
+//
+// 	dedup := idempotency.New(idempotency.NewMemoryStore(), idempotency.TTL(time.Hour))
+// 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+// 		Name:     "payments",
+// 		Inbounds: inbounds,
+// 		InboundMiddleware: yarpc.InboundMiddleware{
+// 			Unary: dedup,
+// 		},
+// 	})
+//
+// Keys are scoped to the caller, service and procedure of the request.
+// Requests without an idempotency key are handled as usual.
+//
+// Responses are kept in a Store. The MemoryStore keeps them in the memory of
+// the process, so it only deduplicates requests that reach the same instance
+// of a service. Services with many instances may implement Store on top of a
+// shared database instead.
+//
+// Only successful responses and responses with application errors written by
+// the handler are stored. When the handler returns an error, the key is
+// released so that the request may be retried.
+package idempotency
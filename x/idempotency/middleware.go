@@ -0,0 +1,271 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.uber.org/yarpc/api/middleware"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/internal/digester"
+	"go.uber.org/zap"
+)
+
+// _releaseTimeout bounds how long releasing a claimed key may take.
+const _releaseTimeout = 5 * time.Second
+
+// Option customizes the behavior of a Deduplicator.
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) apply(opts *options) { f(opts) }
+
+type options struct {
+	header       string
+	ttl          time.Duration
+	claimTTL     time.Duration
+	pollInterval time.Duration
+	filter       func(*transport.Request) bool
+	logger       *zap.Logger
+}
+
+var defaultOptions = options{
+	header:       "idempotency-key",
+	ttl:          24 * time.Hour,
+	claimTTL:     time.Minute,
+	pollInterval: 100 * time.Millisecond,
+	filter:       func(*transport.Request) bool { return true },
+}
+
+// Header specifies the name of the request header holding the idempotency
+// key.
+//
+// Defaults to "idempotency-key".
+func Header(key string) Option {
+	return optionFunc(func(opts *options) {
+		opts.header = key
+	})
+}
+
+// TTL specifies how long responses are stored, and so how long callers may
+// retry a request.
+//
+// Defaults to 24 hours.
+func TTL(ttl time.Duration) Option {
+	return optionFunc(func(opts *options) {
+		opts.ttl = ttl
+	})
+}
+
+// ClaimTTL specifies how long a key stays claimed by a request being handled.
+// If the request does not complete in that time, for example because the
+// process handling it died, another request with the key may be handled.
+// It should be longer than the timeout of the procedures.
+//
+// Defaults to one minute.
+func ClaimTTL(ttl time.Duration) Option {
+	return optionFunc(func(opts *options) {
+		opts.claimTTL = ttl
+	})
+}
+
+// PollInterval specifies how often a request waiting for another request
+// with the same key checks whether that request has completed.
+//
+// Defaults to 100 milliseconds.
+func PollInterval(d time.Duration) Option {
+	return optionFunc(func(opts *options) {
+		opts.pollInterval = d
+	})
+}
+
+// Filter specifies a function that decides which requests are deduplicated.
+// Requests for which the function returns false are handled as usual, even
+// if they have an idempotency key.
+//
+// All requests with an idempotency key are deduplicated by default.
+func Filter(f func(*transport.Request) bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.filter = f
+	})
+}
+
+// Logger specifies a logger for failures to store responses.
+func Logger(logger *zap.Logger) Option {
+	return optionFunc(func(opts *options) {
+		opts.logger = logger
+	})
+}
+
+// Deduplicator is a unary inbound middleware that replays the stored
+// response of requests whose idempotency key it has seen before.
+type Deduplicator struct {
+	store Store
+	opts  options
+}
+
+var _ middleware.UnaryInbound = (*Deduplicator)(nil)
+
+// New builds a Deduplicator that stores responses in the given store.
+func New(store Store, opts ...Option) *Deduplicator {
+	options := defaultOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+	if options.logger == nil {
+		options.logger = zap.NewNop()
+	}
+
+	return &Deduplicator{store: store, opts: options}
+}
+
+// Handle handles the request, unless a request with the same idempotency key
+// was already handled, in which case it replays its response. If a request
+// with the same key is being handled, Handle waits for it to complete.
+func (d *Deduplicator) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter, h transport.UnaryHandler) error {
+	idempotencyKey, _ := req.Headers.Get(d.opts.header)
+	if idempotencyKey == "" || !d.opts.filter(req) {
+		return h.Handle(ctx, req, resw)
+	}
+	key := storeKey(req, idempotencyKey)
+
+	for {
+		res, claimed, err := d.store.Claim(ctx, key, d.opts.claimTTL)
+		if err != nil {
+			return err
+		}
+		if res != nil {
+			return replay(res, resw)
+		}
+		if claimed {
+			break
+		}
+
+		select {
+		case <-time.After(d.opts.pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	completed := false
+	defer func() {
+		// Release the key if the handler fails or panics, or if its response
+		// could not be stored. The request context has often ended by then,
+		// so the key is released with a context of its own.
+		if completed {
+			return
+		}
+		releaseCtx, cancel := context.WithTimeout(context.Background(), _releaseTimeout)
+		defer cancel()
+		if err := d.store.Release(releaseCtx, key); err != nil {
+			d.opts.logger.Warn("failed to release idempotency key",
+				zap.String("procedure", req.Procedure), zap.Error(err))
+		}
+	}()
+
+	rec := newRecorder()
+	if err := h.Handle(ctx, req, rec); err != nil {
+		// Pass on what the handler wrote, like the application error flag,
+		// with its error, which takes precedence over failures to write.
+		_ = replay(rec.Response(), resw)
+		return err
+	}
+
+	res := rec.Response()
+	if err := d.store.Complete(ctx, key, res, d.opts.ttl); err != nil {
+		d.opts.logger.Warn("failed to store response for idempotency key",
+			zap.String("procedure", req.Procedure), zap.Error(err))
+	} else {
+		completed = true
+	}
+	return replay(res, resw)
+}
+
+// storeKey scopes the idempotency key of the request to its caller, service
+// and procedure.
+func storeKey(req *transport.Request, idempotencyKey string) string {
+	d := digester.New()
+	defer d.Free()
+
+	d.Add(req.Caller)
+	d.Add(req.Service)
+	d.Add(req.Procedure)
+	d.Add(idempotencyKey)
+	return string(d.Digest())
+}
+
+// replay writes the response to the response writer.
+func replay(res *Response, resw transport.ResponseWriter) error {
+	if len(res.Headers) > 0 {
+		resw.AddHeaders(transport.HeadersFromMap(res.Headers))
+	}
+	if res.ApplicationError {
+		resw.SetApplicationError()
+	}
+	if len(res.Body) == 0 {
+		return nil
+	}
+	_, err := resw.Write(res.Body)
+	return err
+}
+
+// recorder is a response writer that records the response written by a
+// handler.
+type recorder struct {
+	headers          map[string]string
+	body             bytes.Buffer
+	applicationError bool
+}
+
+var _ transport.ResponseWriter = (*recorder)(nil)
+
+func newRecorder() *recorder {
+	return &recorder{headers: make(map[string]string)}
+}
+
+func (r *recorder) AddHeaders(h transport.Headers) {
+	for k, v := range h.OriginalItems() {
+		r.headers[k] = v
+	}
+}
+
+func (r *recorder) SetApplicationError() {
+	r.applicationError = true
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	return r.body.Write(p)
+}
+
+// Response returns the recorded response.
+func (r *recorder) Response() *Response {
+	return &Response{
+		Headers:          r.headers,
+		Body:             r.body.Bytes(),
+		ApplicationError: r.applicationError,
+	}
+}
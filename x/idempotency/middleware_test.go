@@ -0,0 +1,311 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/internal/testtime"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type handlerFunc func(context.Context, *transport.Request, transport.ResponseWriter) error
+
+func (f handlerFunc) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	return f(ctx, req, resw)
+}
+
+// countingHandler counts the requests it handles, and responds with their
+// body, a header and an application error.
+type countingHandler struct {
+	calls int32
+}
+
+func (h *countingHandler) Handle(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+	atomic.AddInt32(&h.calls, 1)
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(req.Body); err != nil {
+		return err
+	}
+	resw.AddHeaders(transport.NewHeaders().With("Foo", "bar"))
+	resw.SetApplicationError()
+	_, err := resw.Write(body.Bytes())
+	return err
+}
+
+func (h *countingHandler) Calls() int {
+	return int(atomic.LoadInt32(&h.calls))
+}
+
+func newRequest(procedure, key, body string) *transport.Request {
+	headers := transport.NewHeaders()
+	if key != "" {
+		headers = headers.With("Idempotency-Key", key)
+	}
+	return &transport.Request{
+		Caller:    "caller",
+		Service:   "service",
+		Encoding:  "raw",
+		Procedure: procedure,
+		Headers:   headers,
+		Body:      bytes.NewReader([]byte(body)),
+	}
+}
+
+func handle(t *testing.T, d *Deduplicator, h transport.UnaryHandler, req *transport.Request) *transporttest.FakeResponseWriter {
+	ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+	defer cancel()
+
+	resw := new(transporttest.FakeResponseWriter)
+	require.NoError(t, d.Handle(ctx, req, resw, h))
+	return resw
+}
+
+func TestReplay(t *testing.T) {
+	h := new(countingHandler)
+	d := New(NewMemoryStore())
+
+	first := handle(t, d, h, newRequest("charge", "1", "hello"))
+	replayed := handle(t, d, h, newRequest("charge", "1", "world"))
+	assert.Equal(t, 1, h.Calls(), "requests with the same key must be handled once")
+	assert.Equal(t, first, replayed, "replayed response must match the first")
+	assert.Equal(t, "hello", replayed.Body.String())
+	assert.True(t, replayed.IsApplicationError)
+	assert.Equal(t, map[string]string{"Foo": "bar"}, replayed.Headers.OriginalItems())
+
+	handle(t, d, h, newRequest("charge", "2", "hello"))
+	assert.Equal(t, 2, h.Calls(), "requests with other keys must be handled")
+
+	handle(t, d, h, newRequest("refund", "1", "hello"))
+	assert.Equal(t, 3, h.Calls(), "keys must be scoped to the procedure")
+
+	req := newRequest("charge", "1", "hello")
+	req.Caller = "other"
+	handle(t, d, h, req)
+	assert.Equal(t, 4, h.Calls(), "keys must be scoped to the caller")
+
+	handle(t, d, h, newRequest("charge", "", "hello"))
+	handle(t, d, h, newRequest("charge", "", "hello"))
+	assert.Equal(t, 6, h.Calls(), "requests without a key must always be handled")
+}
+
+func TestHeaderOption(t *testing.T) {
+	h := new(countingHandler)
+	d := New(NewMemoryStore(), Header("x-request-id"))
+
+	for i := 0; i < 2; i++ {
+		req := newRequest("charge", "1", "hello")
+		req.Headers = req.Headers.With("x-request-id", "a")
+		handle(t, d, h, req)
+	}
+	assert.Equal(t, 1, h.Calls())
+}
+
+func TestFilter(t *testing.T) {
+	h := new(countingHandler)
+	d := New(NewMemoryStore(), Filter(func(req *transport.Request) bool {
+		return req.Procedure == "charge"
+	}))
+
+	handle(t, d, h, newRequest("get", "1", "hello"))
+	handle(t, d, h, newRequest("get", "1", "hello"))
+	assert.Equal(t, 2, h.Calls(), "filtered requests must always be handled")
+
+	handle(t, d, h, newRequest("charge", "1", "hello"))
+	handle(t, d, h, newRequest("charge", "1", "hello"))
+	assert.Equal(t, 3, h.Calls())
+}
+
+func TestHandlerError(t *testing.T) {
+	calls := 0
+	h := handlerFunc(func(_ context.Context, _ *transport.Request, resw transport.ResponseWriter) error {
+		calls++
+		if calls == 1 {
+			resw.SetApplicationError()
+			return errors.New("great sadness")
+		}
+		_, err := resw.Write([]byte("ok"))
+		return err
+	})
+	d := New(NewMemoryStore())
+
+	resw := new(transporttest.FakeResponseWriter)
+	err := d.Handle(context.Background(), newRequest("charge", "1", ""), resw, h)
+	assert.EqualError(t, err, "great sadness")
+	assert.True(t, resw.IsApplicationError, "application error flag must be passed on")
+
+	resw = handle(t, d, h, newRequest("charge", "1", ""))
+	assert.Equal(t, "ok", resw.Body.String(), "request must be retried after an error")
+	handle(t, d, h, newRequest("charge", "1", ""))
+	assert.Equal(t, 2, calls)
+}
+
+func TestHandlerPanic(t *testing.T) {
+	store := NewMemoryStore()
+	d := New(store)
+
+	assert.Panics(t, func() {
+		d.Handle(context.Background(), newRequest("charge", "1", ""), new(transporttest.FakeResponseWriter),
+			handlerFunc(func(context.Context, *transport.Request, transport.ResponseWriter) error {
+				panic("great sadness")
+			}))
+	})
+	assert.Equal(t, 0, store.Len(), "key must be released")
+}
+
+func TestConcurrentDuplicates(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := new(countingHandler)
+	blocking := handlerFunc(func(ctx context.Context, req *transport.Request, resw transport.ResponseWriter) error {
+		close(started)
+		<-release
+		return h.Handle(ctx, req, resw)
+	})
+	d := New(NewMemoryStore(), PollInterval(time.Millisecond))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handle(t, d, blocking, newRequest("charge", "1", "hello"))
+	}()
+	<-started
+
+	// A duplicate gives up when its context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := d.Handle(ctx, newRequest("charge", "1", "hello"), new(transporttest.FakeResponseWriter), h)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	const duplicates = 5
+	wg.Add(duplicates)
+	for i := 0; i < duplicates; i++ {
+		go func() {
+			defer wg.Done()
+			resw := handle(t, d, h, newRequest("charge", "1", "hello"))
+			assert.Equal(t, "hello", resw.Body.String())
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, h.Calls(), "duplicates must wait for the first request")
+}
+
+// fakeStore is a Store whose methods fail with the given errors.
+type fakeStore struct {
+	Store
+
+	claimErr, completeErr, releaseErr error
+}
+
+func (s *fakeStore) Claim(ctx context.Context, key string, ttl time.Duration) (*Response, bool, error) {
+	if s.claimErr != nil {
+		return nil, false, s.claimErr
+	}
+	return s.Store.Claim(ctx, key, ttl)
+}
+
+func (s *fakeStore) Complete(ctx context.Context, key string, res *Response, ttl time.Duration) error {
+	if s.completeErr != nil {
+		return s.completeErr
+	}
+	return s.Store.Complete(ctx, key, res, ttl)
+}
+
+func (s *fakeStore) Release(ctx context.Context, key string) error {
+	if s.releaseErr != nil {
+		return s.releaseErr
+	}
+	if err := ctx.Err(); err != nil {
+		// Like stores backed by a database, refuse to work with an ended
+		// context.
+		return err
+	}
+	return s.Store.Release(ctx, key)
+}
+
+func TestStoreErrors(t *testing.T) {
+	t.Run("claim", func(t *testing.T) {
+		h := new(countingHandler)
+		d := New(&fakeStore{Store: NewMemoryStore(), claimErr: errors.New("great sadness")})
+
+		err := d.Handle(context.Background(), newRequest("charge", "1", ""), new(transporttest.FakeResponseWriter), h)
+		assert.EqualError(t, err, "great sadness")
+		assert.Equal(t, 0, h.Calls())
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		h := new(countingHandler)
+		d := New(&fakeStore{Store: NewMemoryStore(), completeErr: errors.New("great sadness")},
+			Logger(zap.New(core)))
+
+		resw := handle(t, d, h, newRequest("charge", "1", "hello"))
+		assert.Equal(t, "hello", resw.Body.String(), "response must be written anyway")
+		assert.Equal(t, 1, logs.FilterMessage("failed to store response for idempotency key").Len())
+
+		// The key is released, so a retry is handled right away instead of
+		// waiting for the claim to expire.
+		handle(t, d, h, newRequest("charge", "1", "hello"))
+		assert.Equal(t, 2, h.Calls())
+	})
+
+	t.Run("release", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		d := New(&fakeStore{Store: NewMemoryStore(), releaseErr: errors.New("great sadness")},
+			Logger(zap.New(core)))
+
+		err := d.Handle(context.Background(), newRequest("charge", "1", ""), new(transporttest.FakeResponseWriter),
+			handlerFunc(func(context.Context, *transport.Request, transport.ResponseWriter) error {
+				return errors.New("handler failed")
+			}))
+		assert.EqualError(t, err, "handler failed")
+		assert.Equal(t, 1, logs.FilterMessage("failed to release idempotency key").Len())
+	})
+
+	t.Run("release after the request context ended", func(t *testing.T) {
+		store := NewMemoryStore()
+		d := New(&fakeStore{Store: store})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := d.Handle(ctx, newRequest("charge", "1", ""), new(transporttest.FakeResponseWriter),
+			handlerFunc(func(context.Context, *transport.Request, transport.ResponseWriter) error {
+				cancel()
+				return context.Canceled
+			}))
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, store.Len(), "key must be released")
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/internal/clock"
+)
+
+// Response is a response stored for an idempotency key.
+type Response struct {
+	Headers          map[string]string
+	Body             []byte
+	ApplicationError bool
+}
+
+// Store stores the responses of requests by idempotency key.
+//
+// A key is either unknown, claimed by a request being handled, or completed
+// with a response. Implementations must claim keys atomically, so that only
+// one request at a time can claim a key, even across processes.
+type Store interface {
+	// Claim claims the given key for a request about to be handled, unless
+	// the key is already claimed or completed. The claim expires after the
+	// given TTL, in case the request never completes.
+	//
+	// Claim returns the stored response if the key is completed, and whether
+	// the key was claimed by this call. Claim returns neither if another
+	// request holds the claim.
+	Claim(ctx context.Context, key string, ttl time.Duration) (res *Response, claimed bool, err error)
+
+	// Complete stores the response for a claimed key, replacing the claim,
+	// and keeps it for the given TTL.
+	Complete(ctx context.Context, key string, res *Response, ttl time.Duration) error
+
+	// Release forgets the claim on a key without storing a response, so that
+	// another request with that key may be handled.
+	Release(ctx context.Context, key string) error
+}
+
+// _sweepInterval is how often a MemoryStore forgets expired keys.
+const _sweepInterval = time.Minute
+
+// MemoryStore is a Store that keeps responses in memory.
+type MemoryStore struct {
+	lock      sync.Mutex
+	clock     clock.Clock
+	records   map[string]record
+	lastSweep time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// record is a claimed key if res is nil, and a completed key otherwise.
+type record struct {
+	res     *Response
+	expires time.Time
+}
+
+// NewMemoryStore builds a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return newMemoryStore(clock.NewReal())
+}
+
+func newMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:     clk,
+		records:   make(map[string]record),
+		lastSweep: clk.Now(),
+	}
+}
+
+// Claim claims the given key, unless it is claimed or completed.
+func (s *MemoryStore) Claim(_ context.Context, key string, ttl time.Duration) (*Response, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Now()
+	s.sweep(now)
+	if r, ok := s.records[key]; ok && now.Before(r.expires) {
+		return r.res, false, nil
+	}
+	s.records[key] = record{expires: now.Add(ttl)}
+	return nil, true, nil
+}
+
+// Complete stores the response for the given key.
+func (s *MemoryStore) Complete(_ context.Context, key string, res *Response, ttl time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.records[key] = record{res: res, expires: s.clock.Now().Add(ttl)}
+	return nil
+}
+
+// Release forgets the claim on the given key. Release does nothing if the key
+// is completed.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r, ok := s.records[key]; ok && r.res == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// Len returns the number of keys in the store, including expired ones that
+// have not been forgotten yet.
+func (s *MemoryStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.records)
+}
+
+// sweep forgets expired keys, at most once per sweep interval. sweep must be
+// called with the lock held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < _sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, r := range s.records {
+		if !now.Before(r.expires) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc/internal/clock"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake()
+	s := newMemoryStore(clk)
+
+	res, claimed, err := s.Claim(ctx, "a", time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+	assert.True(t, claimed, "unknown key must be claimed")
+
+	res, claimed, err = s.Claim(ctx, "a", time.Second)
+	require.NoError(t, err)
+	assert.Nil(t, res)
+	assert.False(t, claimed, "claimed key must not be claimed again")
+
+	clk.Add(time.Second)
+	_, claimed, err = s.Claim(ctx, "a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, claimed, "expired claim must be claimed again")
+
+	want := &Response{Body: []byte("hello")}
+	require.NoError(t, s.Complete(ctx, "a", want, time.Minute))
+	require.NoError(t, s.Release(ctx, "a"), "release of a completed key must do nothing")
+
+	res, claimed, err = s.Claim(ctx, "a", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, want, res)
+	assert.False(t, claimed, "completed key must not be claimed")
+
+	_, claimed, err = s.Claim(ctx, "b", time.Second)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, s.Release(ctx, "b"))
+	_, claimed, err = s.Claim(ctx, "b", time.Second)
+	require.NoError(t, err)
+	assert.True(t, claimed, "released key must be claimed again")
+
+	clk.Add(time.Minute)
+	_, claimed, err = s.Claim(ctx, "a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, claimed, "expired response must be forgotten")
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake()
+	s := newMemoryStore(clk)
+
+	require.NoError(t, s.Complete(ctx, "a", &Response{}, time.Second))
+	require.NoError(t, s.Complete(ctx, "b", &Response{}, time.Hour))
+	assert.Equal(t, 2, s.Len())
+
+	clk.Add(_sweepInterval)
+	_, _, err := s.Claim(ctx, "c", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.Len(), "expired keys must be forgotten")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package proxy provides a router middleware that transparently forwards
+// requests to outbounds, for services that relay calls from one transport to
+// another.
+//
+// Requests for a service that has outbounds are forwarded as-is, preserving
+// the caller, encoding, headers, shard key, routing key, routing delegate and
+// deadline of the request. Errors returned by the outbound are returned to
+// the inbound unchanged, so callers see the same error codes and messages as
+// they would calling the service directly. All other requests are routed to
+// the procedures registered on the dispatcher.
+//
+// 	outbounds := yarpc.Outbounds{
+// 		"users": {
+// 			Unary:  tchannelTransport.NewSingleOutbound("127.0.0.1:4040"),
+// 			Stream: grpcTransport.NewSingleOutbound("127.0.0.1:5050"),
+// 		},
+// 	}
+// 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+// 		Name:             "edge",
+// 		Inbounds:         yarpc.Inbounds{httpTransport.NewInbound(":8080")},
+// 		Outbounds:        outbounds,
+// 		RouterMiddleware: proxy.New(outbounds),
+// 	})
+//
+// The outbounds should also be given to the dispatcher, as above, so that
+// they are started and stopped with it.
+//
+// The service name of each forwarded request may be rewritten with the
+// RewriteService option, and headers may be dropped with the FilterHeaders
+// option. Proxies that forward streaming calls from gRPC inbounds should
+// specify the RPC type of their procedures with the RPCType option.
+package proxy
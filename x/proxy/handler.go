@@ -0,0 +1,170 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+)
+
+// forwarder rewrites requests before they are forwarded.
+type forwarder struct {
+	service        string
+	filterHeader   func(string) bool
+	defaultTimeout time.Duration
+}
+
+// withDeadline applies the default timeout to the context if it has no
+// deadline.
+func (f forwarder) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, f.defaultTimeout)
+}
+
+func (f forwarder) request(req *transport.Request) *transport.Request {
+	forwarded := *req
+	forwarded.Service = f.service
+	forwarded.Headers = f.headers(req.Headers)
+	return &forwarded
+}
+
+func (f forwarder) requestMeta(meta *transport.RequestMeta) *transport.RequestMeta {
+	forwarded := *meta
+	forwarded.Service = f.service
+	forwarded.Headers = f.headers(meta.Headers)
+	return &forwarded
+}
+
+func (f forwarder) headers(headers transport.Headers) transport.Headers {
+	items := headers.OriginalItems()
+	filtered := transport.NewHeadersWithCapacity(len(items))
+	for k, v := range items {
+		if f.filterHeader(transport.CanonicalizeHeaderKey(k)) {
+			filtered = filtered.With(k, v)
+		}
+	}
+	return filtered
+}
+
+type unaryHandler struct {
+	forwarder
+
+	outbound transport.UnaryOutbound
+}
+
+func (h unaryHandler) Handle(ctx context.Context, req *transport.Request, rw transport.ResponseWriter) error {
+	ctx, cancel := h.withDeadline(ctx)
+	defer cancel()
+
+	res, err := h.outbound.Call(ctx, h.request(req))
+	if res == nil {
+		return err
+	}
+	if res.Body != nil {
+		defer res.Body.Close()
+	}
+
+	rw.AddHeaders(res.Headers)
+	if res.ApplicationError {
+		rw.SetApplicationError()
+	}
+	if res.Body != nil {
+		if _, copyErr := io.Copy(rw, res.Body); copyErr != nil && err == nil {
+			err = copyErr
+		}
+	}
+	return err
+}
+
+type onewayHandler struct {
+	forwarder
+
+	outbound transport.OnewayOutbound
+}
+
+func (h onewayHandler) HandleOneway(ctx context.Context, req *transport.Request) error {
+	ctx, cancel := h.withDeadline(ctx)
+	defer cancel()
+
+	_, err := h.outbound.CallOneway(ctx, h.request(req))
+	return err
+}
+
+type streamHandler struct {
+	forwarder
+
+	outbound transport.StreamOutbound
+}
+
+func (h streamHandler) HandleStream(serverStream *transport.ServerStream) error {
+	ctx := serverStream.Context()
+	clientStream, err := h.outbound.CallStream(ctx, &transport.StreamRequest{
+		Meta: h.requestMeta(serverStream.Request().Meta),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Messages from the caller are relayed in the background. Once the caller
+	// is done sending, the outbound stream is closed for sending as well.
+	sendErr := make(chan error, 1)
+	go func() {
+		err := relay(ctx, serverStream, clientStream)
+		if closeErr := clientStream.Close(ctx); err == nil {
+			err = closeErr
+		}
+		sendErr <- err
+	}()
+
+	// The stream ends when the service is done sending, including when it
+	// fails with an error, which is returned to the caller.
+	if err := relay(ctx, clientStream, serverStream); err != nil {
+		return err
+	}
+	select {
+	case err := <-sendErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// relay sends every message received from src to dst until src is
+// exhausted.
+func relay(ctx context.Context, src, dst transport.Stream) error {
+	for {
+		msg, err := src.ReceiveMessage(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := dst.SendMessage(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
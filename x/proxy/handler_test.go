@@ -0,0 +1,188 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/raw"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// pipe connects the two ends of an in-memory stream. The server end closes
+// the pipe with the error that its handler returned.
+type pipe struct {
+	ch   chan *transport.StreamMessage
+	once sync.Once
+	err  error
+}
+
+func newPipe() *pipe {
+	return &pipe{ch: make(chan *transport.StreamMessage, 16)}
+}
+
+func (p *pipe) close(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.ch)
+	})
+}
+
+// pipeStream is one end of an in-memory stream.
+type pipeStream struct {
+	ctx  context.Context
+	req  *transport.StreamRequest
+	recv *pipe
+	send *pipe
+}
+
+func (s *pipeStream) Context() context.Context          { return s.ctx }
+func (s *pipeStream) Request() *transport.StreamRequest { return s.req }
+
+func (s *pipeStream) SendMessage(_ context.Context, msg *transport.StreamMessage) error {
+	s.send.ch <- msg
+	return nil
+}
+
+func (s *pipeStream) ReceiveMessage(context.Context) (*transport.StreamMessage, error) {
+	msg, ok := <-s.recv.ch
+	if !ok {
+		if s.recv.err != nil {
+			return nil, s.recv.err
+		}
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (s *pipeStream) Close(context.Context) error {
+	s.send.close(nil)
+	return nil
+}
+
+// pipeOutbound is a StreamOutbound that runs a stream handler in-process.
+type pipeOutbound struct {
+	transport.Outbound
+
+	handler  transport.StreamHandler
+	requests chan *transport.StreamRequest
+}
+
+func (o *pipeOutbound) CallStream(ctx context.Context, req *transport.StreamRequest) (*transport.ClientStream, error) {
+	o.requests <- req
+	toServer, toClient := newPipe(), newPipe()
+	serverStream, err := transport.NewServerStream(&pipeStream{ctx: ctx, req: req, recv: toServer, send: toClient})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		toClient.close(o.handler.HandleStream(serverStream))
+	}()
+
+	return transport.NewClientStream(&pipeStream{ctx: ctx, req: req, recv: toClient, send: toServer})
+}
+
+func TestProxyStream(t *testing.T) {
+	backend := &pipeOutbound{
+		requests: make(chan *transport.StreamRequest, 1),
+		handler: raw.StreamProcedure("echo", func(stream *raw.ServerStream) error {
+			for {
+				frame, err := stream.Receive()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if string(frame) == "fail" {
+					return yarpcerrors.AbortedErrorf("failed on request")
+				}
+				if err := stream.Send(frame); err != nil {
+					return err
+				}
+			}
+		})[0].HandlerSpec.Stream(),
+	}
+
+	p := New(map[string]transport.Outbounds{
+		"backend": {Stream: backend},
+	}, FilterHeaders(func(key string) bool { return key != "secret" }))
+
+	edge := &pipeOutbound{requests: make(chan *transport.StreamRequest, 1)}
+	client := raw.NewStreamClient(&transport.OutboundConfig{
+		CallerName: "caller",
+		Outbounds:  transport.Outbounds{ServiceName: "backend", Stream: edge},
+	})
+
+	t.Run("echo", func(t *testing.T) {
+		spec, err := p.Choose(context.Background(), &transport.Request{Service: "backend"}, nil)
+		require.NoError(t, err)
+		require.Equal(t, transport.Streaming, spec.Type())
+		edge.handler = spec.Stream()
+
+		stream, err := client.CallStream(context.Background(), "echo",
+			yarpc.WithHeader("foo", "bar"), yarpc.WithHeader("secret", "hunter2"))
+		require.NoError(t, err)
+		<-edge.requests
+
+		for _, frame := range []string{"a", "b", "c"} {
+			require.NoError(t, stream.Send([]byte(frame)))
+			got, err := stream.Receive()
+			require.NoError(t, err)
+			assert.Equal(t, frame, string(got))
+		}
+		require.NoError(t, stream.Close())
+		_, err = stream.Receive()
+		assert.Equal(t, io.EOF, err)
+
+		req := <-backend.requests
+		assert.Equal(t, "caller", req.Meta.Caller)
+		assert.Equal(t, "backend", req.Meta.Service)
+		assert.Equal(t, "echo", req.Meta.Procedure)
+		assert.Equal(t, raw.Encoding, req.Meta.Encoding)
+		assert.Equal(t, map[string]string{"foo": "bar"}, req.Meta.Headers.Items())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		spec, err := p.Choose(context.Background(), &transport.Request{Service: "backend"}, nil)
+		require.NoError(t, err)
+		edge.handler = spec.Stream()
+
+		stream, err := client.CallStream(context.Background(), "echo")
+		require.NoError(t, err)
+		<-edge.requests
+		<-backend.requests
+
+		require.NoError(t, stream.Send([]byte("fail")))
+		_, err = stream.Receive()
+		require.Error(t, err)
+		assert.True(t, yarpcerrors.IsAborted(err))
+		assert.Equal(t, "failed on request", yarpcerrors.FromError(err).Message())
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/yarpc/api/middleware"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// Option customizes the behavior of a Proxy.
+type Option interface {
+	apply(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) apply(opts *options) { f(opts) }
+
+type options struct {
+	rewriteService func(string) string
+	filterHeader   func(string) bool
+	rpcType        func(*transport.Request, transport.Outbounds) transport.Type
+	defaultTimeout time.Duration
+}
+
+var defaultOptions = options{
+	rewriteService: func(service string) string { return service },
+	filterHeader:   func(string) bool { return true },
+	rpcType:        defaultRPCType,
+	defaultTimeout: time.Second,
+}
+
+// RewriteService specifies a function that maps the service name of incoming
+// requests to the service name that they are forwarded to. The rewritten
+// name selects the outbounds to forward the request to.
+//
+// Service names are not rewritten by default.
+func RewriteService(f func(service string) string) Option {
+	return optionFunc(func(opts *options) {
+		opts.rewriteService = f
+	})
+}
+
+// FilterHeaders specifies a function that decides which request headers are
+// forwarded. Headers for which the function returns false are dropped. The
+// function receives canonicalized (lower-case) header keys.
+//
+// All headers are forwarded by default.
+func FilterHeaders(f func(key string) bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.filterHeader = f
+	})
+}
+
+// DefaultTimeout specifies the timeout for forwarding unary and oneway
+// requests that do not have a deadline. Some inbounds, like HTTP, do not give
+// oneway requests a deadline, while most outbounds require one.
+//
+// Defaults to one second.
+func DefaultTimeout(d time.Duration) Option {
+	return optionFunc(func(opts *options) {
+		opts.defaultTimeout = d
+	})
+}
+
+// RPCType specifies a function that decides whether a request is forwarded
+// as a unary, oneway or streaming call.
+//
+// Transports decide how to handle a request based on the handler that the
+// router chooses, so the proxy must know the RPC type of a request before it
+// is forwarded. By default, requests are forwarded to the unary outbound of
+// the service if it has one, otherwise to its oneway outbound, otherwise to
+// its stream outbound, skipping the RPC types that the inbound transport of
+// the request does not handle: HTTP inbounds handle unary and oneway calls,
+// TChannel inbounds handle unary calls, and gRPC inbounds handle unary and
+// streaming calls.
+//
+// gRPC requests do not indicate whether the call is streaming, so proxies
+// that forward streaming calls from gRPC inbounds to services that also have
+// a unary outbound must specify the RPC type of their procedures with this
+// option.
+func RPCType(f func(*transport.Request) transport.Type) Option {
+	return optionFunc(func(opts *options) {
+		opts.rpcType = func(req *transport.Request, _ transport.Outbounds) transport.Type {
+			return f(req)
+		}
+	})
+}
+
+// _inboundRPCTypes are the RPC types handled by the inbounds of each
+// transport, by transport name. Requests from other transports may be
+// forwarded as any RPC type.
+var _inboundRPCTypes = map[string][]transport.Type{
+	"grpc":     {transport.Unary, transport.Streaming},
+	"http":     {transport.Unary, transport.Oneway},
+	"tchannel": {transport.Unary},
+}
+
+func defaultRPCType(req *transport.Request, outbounds transport.Outbounds) transport.Type {
+	types, ok := _inboundRPCTypes[req.Transport]
+	if !ok {
+		types = []transport.Type{transport.Unary, transport.Oneway, transport.Streaming}
+	}
+	for _, t := range types {
+		switch {
+		case t == transport.Unary && outbounds.Unary != nil,
+			t == transport.Oneway && outbounds.Oneway != nil,
+			t == transport.Streaming && outbounds.Stream != nil:
+			return t
+		}
+	}
+	// No outbound handles a type the inbound handles; Choose reports the
+	// preferred type as unimplemented.
+	return types[0]
+}
+
+// Proxy is a router middleware that forwards requests for services with
+// outbounds to those outbounds.
+type Proxy struct {
+	outbounds map[string]transport.Outbounds
+	opts      options
+}
+
+var _ middleware.Router = (*Proxy)(nil)
+
+// New builds a Proxy that forwards requests to the given outbounds, keyed by
+// outbound name. Requests are matched to outbounds by the ServiceName of the
+// outbounds, or by the outbound name if it has no ServiceName.
+func New(outbounds map[string]transport.Outbounds, opts ...Option) *Proxy {
+	options := defaultOptions
+	for _, o := range opts {
+		o.apply(&options)
+	}
+
+	byService := make(map[string]transport.Outbounds, len(outbounds))
+	for name, o := range outbounds {
+		if o.ServiceName == "" {
+			o.ServiceName = name
+		}
+		byService[o.ServiceName] = o
+	}
+
+	return &Proxy{outbounds: byService, opts: options}
+}
+
+// Procedures returns the procedures registered on the given router. Forwarded
+// services do not have procedures of their own.
+func (p *Proxy) Procedures(router transport.Router) []transport.Procedure {
+	return router.Procedures()
+}
+
+// Choose returns a handler that forwards the request if its service has
+// outbounds, and otherwise chooses a handler from the given router.
+func (p *Proxy) Choose(ctx context.Context, req *transport.Request, router transport.Router) (transport.HandlerSpec, error) {
+	service := p.opts.rewriteService(req.Service)
+	outbounds, ok := p.outbounds[service]
+	if !ok {
+		return router.Choose(ctx, req)
+	}
+
+	f := forwarder{
+		service:        service,
+		filterHeader:   p.opts.filterHeader,
+		defaultTimeout: p.opts.defaultTimeout,
+	}
+	t := p.opts.rpcType(req, outbounds)
+	switch t {
+	case transport.Unary:
+		if outbounds.Unary != nil {
+			return transport.NewUnaryHandlerSpec(unaryHandler{f, outbounds.Unary}), nil
+		}
+	case transport.Oneway:
+		if outbounds.Oneway != nil {
+			return transport.NewOnewayHandlerSpec(onewayHandler{f, outbounds.Oneway}), nil
+		}
+	case transport.Streaming:
+		if outbounds.Stream != nil {
+			return transport.NewStreamHandlerSpec(streamHandler{f, outbounds.Stream}), nil
+		}
+	}
+	return transport.HandlerSpec{}, yarpcerrors.UnimplementedErrorf(
+		"proxy has no outbound to forward %v requests for procedure %q of service %q",
+		t, req.Procedure, service)
+}
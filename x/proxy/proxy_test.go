@@ -0,0 +1,257 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package proxy_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/encoding/raw"
+	"go.uber.org/yarpc/transport/http"
+	"go.uber.org/yarpc/x/proxy"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// startDispatcher starts a dispatcher with an HTTP inbound and returns the
+// address of the inbound.
+func startDispatcher(t *testing.T, cfg yarpc.Config, procedures []transport.Procedure) (string, func()) {
+	inbound := http.NewTransport().NewInbound("127.0.0.1:0")
+	cfg.Inbounds = yarpc.Inbounds{inbound}
+	d := yarpc.NewDispatcher(cfg)
+	d.Register(procedures)
+	require.NoError(t, d.Start())
+	return fmt.Sprintf("http://%v", inbound.Addr()), func() { assert.NoError(t, d.Stop()) }
+}
+
+func newClient(t *testing.T, service, addr string) (raw.Client, func()) {
+	d := yarpc.NewDispatcher(yarpc.Config{
+		Name: "client",
+		Outbounds: yarpc.Outbounds{
+			service: {Unary: http.NewTransport().NewSingleOutbound(addr), Oneway: http.NewTransport().NewSingleOutbound(addr)},
+		},
+	})
+	require.NoError(t, d.Start())
+	return raw.New(d.ClientConfig(service)), func() { assert.NoError(t, d.Stop()) }
+}
+
+func TestProxy(t *testing.T) {
+	oneway := make(chan []byte, 1)
+
+	var procedures []transport.Procedure
+	procedures = append(procedures, raw.Procedure("echo", func(ctx context.Context, body []byte) ([]byte, error) {
+		call := yarpc.CallFromContext(ctx)
+		for _, k := range call.HeaderNames() {
+			if err := call.WriteResponseHeader(k, call.Header(k)); err != nil {
+				return nil, err
+			}
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, yarpcerrors.InternalErrorf("request has no deadline")
+		}
+		return []byte(fmt.Sprintf("%s %s %s %s %s", call.Caller(), call.Service(), call.Encoding(), call.ShardKey(), body)), nil
+	})...)
+	procedures = append(procedures, raw.Procedure("fail", func(ctx context.Context, body []byte) ([]byte, error) {
+		return nil, yarpcerrors.NotFoundErrorf("no user %q", body)
+	})...)
+	procedures = append(procedures, raw.OnewayProcedure("notify", func(ctx context.Context, body []byte) error {
+		oneway <- body
+		return nil
+	})...)
+	backendAddr, stopBackend := startDispatcher(t, yarpc.Config{Name: "users-backend"}, procedures)
+	defer stopBackend()
+
+	httpTransport := http.NewTransport()
+	outbounds := yarpc.Outbounds{
+		"users-backend": {
+			Unary:  httpTransport.NewSingleOutbound(backendAddr),
+			Oneway: httpTransport.NewSingleOutbound(backendAddr),
+		},
+	}
+	edgeAddr, stopEdge := startDispatcher(t, yarpc.Config{
+		Name:      "edge",
+		Outbounds: outbounds,
+		RouterMiddleware: proxy.New(outbounds,
+			proxy.RewriteService(func(service string) string {
+				if service == "users" {
+					return "users-backend"
+				}
+				return service
+			}),
+			proxy.FilterHeaders(func(key string) bool { return key != "secret" }),
+			proxy.RPCType(func(req *transport.Request) transport.Type {
+				if req.Procedure == "notify" {
+					return transport.Oneway
+				}
+				return transport.Unary
+			}),
+		),
+	}, raw.Procedure("local", func(ctx context.Context, body []byte) ([]byte, error) {
+		return []byte("local"), nil
+	}))
+	defer stopEdge()
+
+	t.Run("unary", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		client, stop := newClient(t, "users", edgeAddr)
+		defer stop()
+
+		var headers map[string]string
+		res, err := client.Call(ctx, "echo", []byte("hello"),
+			yarpc.WithHeader("foo", "bar"),
+			yarpc.WithHeader("secret", "hunter2"),
+			yarpc.WithShardKey("shard"),
+			yarpc.ResponseHeaders(&headers),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "client users-backend raw shard hello", string(res))
+		assert.Equal(t, map[string]string{"foo": "bar"}, headers)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		client, stop := newClient(t, "users", edgeAddr)
+		defer stop()
+
+		_, err := client.Call(ctx, "fail", []byte("jane"))
+		require.Error(t, err)
+		assert.Equal(t, yarpcerrors.CodeNotFound, yarpcerrors.FromError(err).Code())
+		assert.Equal(t, `no user "jane"`, yarpcerrors.FromError(err).Message())
+	})
+
+	t.Run("oneway", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		client, stop := newClient(t, "users", edgeAddr)
+		defer stop()
+
+		_, err := client.CallOneway(ctx, "notify", []byte("ping"))
+		require.NoError(t, err)
+		select {
+		case body := <-oneway:
+			assert.Equal(t, "ping", string(body))
+		case <-ctx.Done():
+			t.Fatal("oneway request was not forwarded")
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		client, stop := newClient(t, "edge", edgeAddr)
+		defer stop()
+
+		res, err := client.Call(ctx, "local", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "local", string(res))
+	})
+}
+
+func TestProxyNoOutboundForType(t *testing.T) {
+	p := proxy.New(map[string]transport.Outbounds{
+		"users": {Unary: http.NewTransport().NewSingleOutbound("http://127.0.0.1:1")},
+	}, proxy.RPCType(func(*transport.Request) transport.Type { return transport.Streaming }))
+
+	_, err := p.Choose(context.Background(), &transport.Request{Service: "users", Procedure: "watch"}, yarpc.NewMapRouter("edge"))
+	require.Error(t, err)
+	assert.True(t, yarpcerrors.IsUnimplemented(err))
+}
+
+func TestProxyDefaultRPCType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	unary := transporttest.NewMockUnaryOutbound(mockCtrl)
+	oneway := transporttest.NewMockOnewayOutbound(mockCtrl)
+	stream := transporttest.NewMockStreamOutbound(mockCtrl)
+
+	tests := []struct {
+		msg       string
+		transport string
+		outbounds transport.Outbounds
+		want      transport.Type
+		wantErr   bool
+	}{
+		{
+			msg:       "unary preferred",
+			transport: "grpc",
+			outbounds: transport.Outbounds{Unary: unary, Stream: stream},
+			want:      transport.Unary,
+		},
+		{
+			msg:       "grpc skips oneway",
+			transport: "grpc",
+			outbounds: transport.Outbounds{Oneway: oneway, Stream: stream},
+			want:      transport.Streaming,
+		},
+		{
+			msg:       "http oneway",
+			transport: "http",
+			outbounds: transport.Outbounds{Oneway: oneway, Stream: stream},
+			want:      transport.Oneway,
+		},
+		{
+			msg:       "http does not stream",
+			transport: "http",
+			outbounds: transport.Outbounds{Stream: stream},
+			wantErr:   true,
+		},
+		{
+			msg:       "tchannel does not stream",
+			transport: "tchannel",
+			outbounds: transport.Outbounds{Oneway: oneway, Stream: stream},
+			wantErr:   true,
+		},
+		{
+			msg:       "unknown transport",
+			transport: "custom",
+			outbounds: transport.Outbounds{Stream: stream},
+			want:      transport.Streaming,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			p := proxy.New(map[string]transport.Outbounds{"users": tt.outbounds})
+			spec, err := p.Choose(context.Background(), &transport.Request{
+				Service:   "users",
+				Procedure: "get",
+				Transport: tt.transport,
+			}, yarpc.NewMapRouter("edge"))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, yarpcerrors.IsUnimplemented(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, spec.Type())
+		})
+	}
+}
@@ -75,6 +75,8 @@ func (b *builder) Build() (yarpc.Config, error) {
 		cfg        = yarpc.Config{Name: b.Name}
 		errs       error
 	)
+	cfg.Logging.Zap = b.kit.c.logger
+	cfg.Metrics.Metrics = b.kit.c.meter
 
 	for name, spec := range b.needTransports {
 		cv, ok := b.transports[name]
@@ -113,21 +115,21 @@ func (b *builder) Build() (yarpc.Config, error) {
 		}
 
 		if o := c.Unary; o != nil {
-			ob.Unary, err = buildUnaryOutbound(o, transports[o.TransportSpec.Name], b.kit)
+			ob.Unary, err = buildUnaryOutbound(o, transports[o.TransportSpec.Name], b.kit.withOutbound(ccname, transport.Unary))
 			if err != nil {
 				errs = multierr.Append(errs, fmt.Errorf(`failed to configure unary outbound for %q: %v`, ccname, err))
 				continue
 			}
 		}
 		if o := c.Oneway; o != nil {
-			ob.Oneway, err = buildOnewayOutbound(o, transports[o.TransportSpec.Name], b.kit)
+			ob.Oneway, err = buildOnewayOutbound(o, transports[o.TransportSpec.Name], b.kit.withOutbound(ccname, transport.Oneway))
 			if err != nil {
 				errs = multierr.Append(errs, fmt.Errorf(`failed to configure oneway outbound for %q: %v`, ccname, err))
 				continue
 			}
 		}
 		if o := c.Stream; o != nil {
-			ob.Stream, err = buildStreamOutbound(o, transports[o.TransportSpec.Name], b.kit)
+			ob.Stream, err = buildStreamOutbound(o, transports[o.TransportSpec.Name], b.kit.withOutbound(ccname, transport.Streaming))
 			if err != nil {
 				errs = multierr.Append(errs, fmt.Errorf(`failed to configure stream outbound for %q: %v`, ccname, err))
 				continue
@@ -24,10 +24,14 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"go.uber.org/yarpc/api/peer"
 	"go.uber.org/yarpc/internal/config"
 	peerbind "go.uber.org/yarpc/peer"
+	"go.uber.org/yarpc/peer/locality"
+	"go.uber.org/yarpc/peer/sticky"
+	"go.uber.org/yarpc/peer/subset"
 )
 
 // PeerChooser facilitates decoding and building peer choosers. A peer chooser
@@ -69,6 +73,49 @@ import (
 // robin peer list. The only remaining key is the name of the peer list
 // updater: `peers` which is just a static list of peers.
 //
+// Any peer list may also specify a `subset` to retain only a deterministic
+// subset of the peers provided by its peer list updater.
+// See the go.uber.org/yarpc/peer/subset package for details.
+//
+// 	round-robin:
+// 	  subset:
+// 	    size: 20
+// 	    clientID: ${HOSTNAME}
+// 	  dns:
+// 	    name: myservice.example.com
+//
+// Any peer list may also specify a `locality` to prefer peers in the zone of
+// the caller, with a separate instance of the peer list for each zone.
+// Requests spill over to other zones, in order of priority, when the local
+// zone has too few available peers or too many errors.
+// The zone of each peer may be given with a static list of peers for each
+// zone.
+// See the go.uber.org/yarpc/peer/locality package for details.
+//
+// 	round-robin:
+// 	  locality:
+// 	    zone: ${ZONE}
+// 	    priorities: [us-east1-b, us-east1-c]
+// 	    minAvailable: 3
+// 	    maxErrorRate: 0.5
+// 	    peers:
+// 	      us-east1-a:
+// 	        - 127.0.0.1:8080
+// 	      us-east1-b:
+// 	        - 127.0.0.1:8081
+//
+// Any peer list may also specify `sticky` to send requests with the same
+// routing key to the same peer while that peer remains available.
+// See the go.uber.org/yarpc/peer/sticky package for details.
+//
+// 	round-robin:
+// 	  sticky:
+// 	    capacity: 10000
+// 	    ttl: 1h
+// 	  peers:
+// 	    - 127.0.0.1:8080
+// 	    - 127.0.0.1:8081
+//
 // Integration
 //
 // To integrate peer choosers with your transport, embed this struct into your
@@ -198,7 +245,30 @@ func (pc PeerChooser) buildPeerChooser(transport peer.Transport, identify func(s
 	//
 	// We will be left with only failurePenalty in the map so that we can simply
 	// decode it into the peer list configuration type.
-	peerListUpdater, err := buildPeerListUpdater(peerChooserConfig, identify, kit)
+	var localityConfig peerLocality
+	hasLocality, err := peerChooserConfig.Pop("locality", &localityConfig, config.InterpolateWith(kit.resolver))
+	if err != nil {
+		return nil, err
+	}
+
+	var peerListUpdater peer.Binder
+	if hasLocality && len(localityConfig.Peers) > 0 {
+		peerListUpdater, err = localityConfig.bindPeers(peerChooserConfig, identify, kit)
+	} else {
+		peerListUpdater, err = buildPeerListUpdater(peerChooserConfig, identify, kit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var subsetConfig peerSubset
+	hasSubset, err := peerChooserConfig.Pop("subset", &subsetConfig, config.InterpolateWith(kit.resolver))
+	if err != nil {
+		return nil, err
+	}
+
+	var stickyConfig peerSticky
+	hasSticky, err := peerChooserConfig.Pop("sticky", &stickyConfig, config.InterpolateWith(kit.resolver))
 	if err != nil {
 		return nil, err
 	}
@@ -212,10 +282,172 @@ func (pc PeerChooser) buildPeerChooser(transport peer.Transport, identify func(s
 		return nil, err
 	}
 	peerChooser := result.(peer.ChooserList)
+	if hasLocality {
+		peerChooser, err = localityConfig.wrap(peerChooser, func() (peer.ChooserList, error) {
+			result, err := listBuilder.Build(transport, kit)
+			if err != nil {
+				return nil, err
+			}
+			return result.(peer.ChooserList), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hasSubset {
+		peerChooser, err = subsetConfig.wrap(peerChooser)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hasSticky {
+		peerChooser, err = stickyConfig.wrap(peerChooser)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return peerbind.Bind(peerChooser, peerListUpdater), nil
 }
 
+// peerSubset is the configuration for retaining only a subset of the peers
+// provided by a peer list updater.
+//
+//   subset:
+//     size: 20
+//     clientID: ${HOSTNAME}
+type peerSubset struct {
+	Size     int    `config:"size"`
+	ClientID string `config:"clientID,interpolate"`
+}
+
+// wrap returns a peer list that forwards a subset of its peers to the given
+// peer list.
+func (c peerSubset) wrap(list peer.ChooserList) (peer.ChooserList, error) {
+	if c.Size < 0 {
+		return nil, fmt.Errorf("subset size must not be negative, got %d", c.Size)
+	}
+
+	var opts []subset.ListOption
+	if c.Size > 0 {
+		opts = append(opts, subset.Size(c.Size))
+	}
+	if c.ClientID != "" {
+		opts = append(opts, subset.ClientID(c.ClientID))
+	}
+	return subset.New(list, opts...), nil
+}
+
+// peerSticky is the configuration for sending requests with the same routing
+// key to the same peer.
+//
+//   sticky:
+//     capacity: 10000
+//     ttl: 1h
+type peerSticky struct {
+	Capacity int           `config:"capacity"`
+	TTL      time.Duration `config:"ttl"`
+}
+
+// wrap returns a peer list that remembers the peer that the given peer list
+// chose for each routing key.
+func (c peerSticky) wrap(list peer.ChooserList) (peer.ChooserList, error) {
+	if c.Capacity < 0 {
+		return nil, fmt.Errorf("sticky capacity must not be negative, got %d", c.Capacity)
+	}
+	if c.TTL < 0 {
+		return nil, fmt.Errorf("sticky ttl must not be negative, got %v", c.TTL)
+	}
+
+	var opts []sticky.ListOption
+	if c.Capacity > 0 {
+		opts = append(opts, sticky.Capacity(c.Capacity))
+	}
+	if c.TTL > 0 {
+		opts = append(opts, sticky.TTL(c.TTL))
+	}
+	return sticky.New(list, opts...), nil
+}
+
+// peerLocality is the configuration for preferring peers in the zone of the
+// caller.
+//
+//   locality:
+//     zone: ${ZONE}
+//     priorities: [us-east1-b, us-east1-c]
+//     minAvailable: 3
+//     maxErrorRate: 0.5
+//     minimumRequests: 20
+//     errorRateWindow: 10s
+//     peers:
+//       us-east1-a:
+//         - 127.0.0.1:8080
+type peerLocality struct {
+	Zone            string              `config:"zone,interpolate"`
+	Priorities      []string            `config:"priorities"`
+	MinAvailable    int                 `config:"minAvailable"`
+	MaxErrorRate    float64             `config:"maxErrorRate"`
+	MinimumRequests int                 `config:"minimumRequests"`
+	ErrorRateWindow time.Duration       `config:"errorRateWindow"`
+	Peers           map[string][]string `config:"peers"`
+}
+
+// bindPeers returns a peer list updater for the static list of peers in each
+// zone, ensuring that the peer list configuration has no other peer list
+// updater.
+func (c peerLocality) bindPeers(etc config.AttributeMap, identify func(string) peer.Identifier, kit *Kit) (peer.Binder, error) {
+	for _, name := range configNames(etc) {
+		if name == "peers" || kit.peerListUpdaterSpec(name) != nil {
+			return nil, fmt.Errorf("locality peers cannot be combined with peer list updater %q", name)
+		}
+	}
+
+	zones := make(map[string][]peer.Identifier, len(c.Peers))
+	for zone, peers := range c.Peers {
+		zones[zone] = identifyAll(identify, peers)
+	}
+	return locality.BindPeers(zones), nil
+}
+
+// wrap returns a locality-aware peer list that uses the given peer list for
+// the first zone that receives peers and builds a new peer list for each
+// other zone.
+func (c peerLocality) wrap(list peer.ChooserList, build func() (peer.ChooserList, error)) (peer.ChooserList, error) {
+	if c.MinAvailable < 0 {
+		return nil, fmt.Errorf("locality minAvailable must not be negative, got %d", c.MinAvailable)
+	}
+	if c.MaxErrorRate < 0 {
+		return nil, fmt.Errorf("locality maxErrorRate must not be negative, got %v", c.MaxErrorRate)
+	}
+
+	opts := []locality.ListOption{locality.Priorities(c.Priorities...)}
+	if c.MinAvailable > 0 {
+		opts = append(opts, locality.MinAvailable(c.MinAvailable))
+	}
+	if c.MaxErrorRate > 0 {
+		opts = append(opts, locality.MaxErrorRate(c.MaxErrorRate))
+	}
+	if c.MinimumRequests > 0 {
+		opts = append(opts, locality.MinimumRequests(c.MinimumRequests))
+	}
+	if c.ErrorRateWindow > 0 {
+		opts = append(opts, locality.ErrorRateWindow(c.ErrorRateWindow))
+	}
+
+	// The peer list was already built to validate its configuration, so
+	// reuse it rather than discarding it.
+	first := list
+	newList := func(string) (peer.ChooserList, error) {
+		if first != nil {
+			list := first
+			first = nil
+			return list, nil
+		}
+		return build()
+	}
+	return locality.New(c.Zone, newList, opts...), nil
+}
+
 // getPeerListInfo extracts the peer list entry from the given attribute map. It
 // must be the only remaining entry.
 //
@@ -40,8 +40,11 @@ import (
 	"go.uber.org/yarpc/internal/whitespace"
 	"go.uber.org/yarpc/peer"
 	"go.uber.org/yarpc/peer/hostport"
+	"go.uber.org/yarpc/peer/locality"
 	"go.uber.org/yarpc/peer/pendingheap"
 	"go.uber.org/yarpc/peer/roundrobin"
+	"go.uber.org/yarpc/peer/sticky"
+	"go.uber.org/yarpc/peer/subset"
 	"go.uber.org/yarpc/peer/x/peerheap"
 	"go.uber.org/yarpc/transport/http"
 	"go.uber.org/yarpc/transport/tchannel"
@@ -361,6 +364,138 @@ func TestChooserConfigurator(t *testing.T) {
 				require.True(t, ok, "use round robin")
 			},
 		},
+		{
+			desc: "use round-robin chooser with subset",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								round-robin:
+									subset:
+										size: 2
+										clientID: client-1
+									peers:
+										- 127.0.0.1:8080
+										- 127.0.0.1:8081
+										- 127.0.0.1:8082
+										- 127.0.0.1:8083
+			`),
+			test: func(t *testing.T, c yarpc.Config) {
+				outbound := c.Outbounds["their-service"]
+				unary := outbound.Unary.(*yarpctest.FakeOutbound)
+				chooser := unary.Chooser().(*peer.BoundChooser)
+				list, ok := chooser.ChooserList().(*subset.List)
+				require.True(t, ok, "use subset")
+
+				require.NoError(t, chooser.Start(), "error starting chooser")
+				defer chooser.Stop()
+				assert.Len(t, list.Subset(), 2, "retains a subset of peers")
+				assert.Equal(t, 4, list.NumPeers(), "receives all peers")
+			},
+		},
+		{
+			desc: "invalid subset size",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								round-robin:
+									subset:
+										size: -1
+									fake-updater: {}
+			`),
+			wantErr: []string{"subset size must not be negative, got -1"},
+		},
+		{
+			desc: "use round-robin chooser with locality",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								round-robin:
+									locality:
+										zone: us-east1-a
+										priorities: [us-east1-c]
+										minAvailable: 1
+										maxErrorRate: 0.2
+										peers:
+											us-east1-a:
+												- 127.0.0.1:8080
+											us-east1-b:
+												- 127.0.0.1:8081
+											us-east1-c:
+												- 127.0.0.1:8082
+			`),
+			test: func(t *testing.T, c yarpc.Config) {
+				outbound := c.Outbounds["their-service"]
+				unary := outbound.Unary.(*yarpctest.FakeOutbound)
+				chooser := unary.Chooser().(*peer.BoundChooser)
+				list, ok := chooser.ChooserList().(*locality.List)
+				require.True(t, ok, "use locality")
+
+				require.NoError(t, chooser.Start(), "error starting chooser")
+				defer chooser.Stop()
+				assert.Equal(t, []string{"us-east1-a", "us-east1-c", "us-east1-b"}, list.Zones())
+			},
+		},
+		{
+			desc: "locality peers with another peer list updater",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								round-robin:
+									locality:
+										zone: us-east1-a
+										peers:
+											us-east1-a:
+												- 127.0.0.1:8080
+									fake-updater: {}
+			`),
+			wantErr: []string{`locality peers cannot be combined with peer list updater "fake-updater"`},
+		},
+		{
+			desc: "use round-robin chooser with sticky routing keys",
+			given: whitespace.Expand(`
+				outbounds:
+					their-service:
+						unary:
+							fake-transport:
+								round-robin:
+									sticky:
+										capacity: 100
+										ttl: 1m
+									peers:
+										- 127.0.0.1:8080
+										- 127.0.0.1:8081
+			`),
+			test: func(t *testing.T, c yarpc.Config) {
+				outbound := c.Outbounds["their-service"]
+				unary := outbound.Unary.(*yarpctest.FakeOutbound)
+				chooser := unary.Chooser().(*peer.BoundChooser)
+				_, ok := chooser.ChooserList().(*sticky.List)
+				require.True(t, ok, "use sticky")
+
+				require.NoError(t, chooser.Start(), "error starting chooser")
+				defer chooser.Stop()
+
+				ctx, cancel := context.WithTimeout(context.Background(), testtime.Second)
+				defer cancel()
+				first, onFinish, err := chooser.Choose(ctx, &transport.Request{RoutingKey: "alice"})
+				require.NoError(t, err)
+				onFinish(nil)
+				for i := 0; i < 5; i++ {
+					p, onFinish, err := chooser.Choose(ctx, &transport.Request{RoutingKey: "alice"})
+					require.NoError(t, err)
+					onFinish(nil)
+					assert.Equal(t, first.Identifier(), p.Identifier(), "must choose the same peer")
+				}
+			},
+		},
 		{
 			desc: "use least-pending chooser",
 			given: whitespace.Expand(`
@@ -28,9 +28,11 @@ import (
 	"os"
 
 	"go.uber.org/multierr"
+	"go.uber.org/net/metrics"
 	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/internal/config"
 	"go.uber.org/yarpc/internal/interpolate"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 )
 
@@ -46,6 +48,8 @@ type Configurator struct {
 	knownPeerLists        map[string]*compiledPeerListSpec
 	knownPeerListUpdaters map[string]*compiledPeerListUpdaterSpec
 	resolver              interpolate.VariableResolver
+	logger                *zap.Logger
+	meter                 *metrics.Scope
 }
 
 // New sets up a new empty Configurator. The returned Configurator does not
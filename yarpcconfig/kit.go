@@ -27,7 +27,10 @@ import (
 	"sort"
 	"strings"
 
+	"go.uber.org/net/metrics"
+	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/internal/interpolate"
+	"go.uber.org/zap"
 )
 
 // Kit is an opaque object that carries context for the Configurator. Build
@@ -42,6 +45,11 @@ type Kit struct {
 
 	// TransportSpec currently being used. This may or may not be set.
 	transportSpec *compiledTransportSpec
+
+	// Outbound currently being built and its RPC type. These may or may not
+	// be set.
+	outbound string
+	rpcType  transport.Type
 }
 
 // Returns a shallow copy of this Kit with spec set to the given value.
@@ -51,10 +59,49 @@ func (k *Kit) withTransportSpec(spec *compiledTransportSpec) *Kit {
 	return &newK
 }
 
+// Returns a shallow copy of this Kit for building the given outbound.
+func (k *Kit) withOutbound(name string, rpcType transport.Type) *Kit {
+	newK := *k
+	newK.outbound = name
+	newK.rpcType = rpcType
+	return &newK
+}
+
 // ServiceName returns the name of the service for which components are being
 // built.
 func (k *Kit) ServiceName() string { return k.name }
 
+// Logger returns the logger given to the Configurator with the Logger
+// option, annotated with the outbound being built, or a no-op logger.
+// It may be called on a nil Kit, as build functions are in tests.
+func (k *Kit) Logger() *zap.Logger {
+	if k == nil || k.c == nil || k.c.logger == nil {
+		return zap.NewNop()
+	}
+	logger := k.c.logger.With(zap.String("dispatcher", k.name))
+	if k.outbound != "" {
+		logger = logger.With(zap.String("outbound", k.outbound), zap.Stringer("rpcType", k.rpcType))
+	}
+	return logger
+}
+
+// Meter returns the metrics scope given to the Configurator with the Metrics
+// option, tagged with the outbound being built, or nil if metrics are not
+// recorded. Components built for different outbounds may therefore register
+// metrics with the same names.
+// It may be called on a nil Kit, as build functions are in tests.
+func (k *Kit) Meter() *metrics.Scope {
+	if k == nil || k.c == nil || k.c.meter == nil {
+		return nil
+	}
+	tags := metrics.Tags{"component": "yarpc", "dispatcher": k.name}
+	if k.outbound != "" {
+		tags["outbound"] = k.outbound
+		tags["rpc_type"] = strings.ToLower(k.rpcType.String())
+	}
+	return k.c.meter.Tagged(tags)
+}
+
 var _typeOfKit = reflect.TypeOf((*Kit)(nil))
 
 func (k *Kit) maybePeerChooserSpec(name string) *compiledPeerChooserSpec {
@@ -24,6 +24,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/net/metrics"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/zap"
 )
 
 func TestKitWithTransportSpec(t *testing.T) {
@@ -40,3 +43,17 @@ func TestKitWithTransportSpec(t *testing.T) {
 	assert.Equal(t, "foo", root.ServiceName())
 	assert.Equal(t, "bar", child.ServiceName())
 }
+
+func TestKitLoggerAndMeter(t *testing.T) {
+	var nilKit *Kit
+	assert.NotNil(t, nilKit.Logger(), "logger must default to a no-op logger")
+	assert.Nil(t, nilKit.Meter(), "meter must default to nil")
+
+	root := &Kit{name: "foo", c: New()}
+	assert.NotNil(t, root.Logger(), "logger must default to a no-op logger")
+	assert.Nil(t, root.Meter(), "meter must default to nil")
+
+	root = &Kit{name: "foo", c: New(Logger(zap.NewNop()), Metrics(metrics.New().Scope()))}
+	assert.NotNil(t, root.withOutbound("bar", transport.Unary).Logger())
+	assert.NotNil(t, root.withOutbound("bar", transport.Unary).Meter())
+}
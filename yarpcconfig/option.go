@@ -20,6 +20,11 @@
 
 package yarpcconfig
 
+import (
+	"go.uber.org/net/metrics"
+	"go.uber.org/zap"
+)
+
 // Option customizes a Configurator.
 type Option func(*Configurator)
 
@@ -44,3 +49,25 @@ func InterpolationResolver(f func(k string) (v string, ok bool)) Option {
 		c.resolver = f
 	}
 }
+
+// Logger specifies a logger for the dispatchers built by the Configurator and
+// for the components built for them, like peer lists, which receive it
+// through their Kit.
+//
+// By default, no logs are emitted.
+func Logger(logger *zap.Logger) Option {
+	return func(c *Configurator) {
+		c.logger = logger
+	}
+}
+
+// Metrics specifies a metrics scope for the dispatchers built by the
+// Configurator and for the components built for them, like peer lists, which
+// receive it through their Kit.
+//
+// By default, metrics are not recorded.
+func Metrics(meter *metrics.Scope) Option {
+	return func(c *Configurator) {
+		c.meter = meter
+	}
+}
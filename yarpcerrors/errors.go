@@ -39,7 +39,8 @@ func Newf(code Code, format string, args ...interface{}) *Status {
 }
 
 // FromError returns the Status for the provided error. If the provided error
-// is not a Status, a new error with code CodeUnknown is returned.
+// is not a Status, and does not carry one by implementing
+// YARPCError() *Status, a new error with code CodeUnknown is returned.
 //
 // Returns nil if the provided error is nil.
 func FromError(err error) *Status {
@@ -49,18 +50,35 @@ func FromError(err error) *Status {
 	if status, ok := err.(*Status); ok {
 		return status
 	}
+	if yerr, ok := err.(yarpcError); ok {
+		return yerr.YARPCError()
+	}
 	return &Status{
 		code:    CodeUnknown,
 		message: err.Error(),
 	}
 }
 
-// IsStatus returns whether the provided error is a YARPC error.
+// IsStatus returns whether the provided error is a YARPC error, that is, a
+// Status or an error that carries one by implementing YARPCError() *Status.
 //
 // This is always false if the error is nil.
 func IsStatus(err error) bool {
-	_, ok := err.(*Status)
-	return ok
+	switch err.(type) {
+	case *Status, yarpcError:
+		return true
+	default:
+		return false
+	}
+}
+
+// yarpcError is implemented by errors that carry a Status along with other
+// information that callers may want to recover, like the field violations
+// of protobuf validation errors.
+type yarpcError interface {
+	error
+
+	YARPCError() *Status
 }
 
 // Status represents a YARPC error.
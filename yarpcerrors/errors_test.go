@@ -152,6 +152,26 @@ func TestNonYARPCErrors(t *testing.T) {
 	assert.Nil(t, FromHeaders(CodeOK, "", ""))
 }
 
+// detailedError is an error that carries a Status.
+type detailedError struct {
+	detail string
+}
+
+func (e *detailedError) Error() string { return "detailed: " + e.detail }
+
+func (e *detailedError) YARPCError() *Status {
+	return Newf(CodeInvalidArgument, "detailed: %s", e.detail)
+}
+
+func TestErrorsCarryingStatus(t *testing.T) {
+	err := error(&detailedError{detail: "foo"})
+	assert.True(t, IsStatus(err))
+	assert.True(t, IsInvalidArgument(err))
+	assert.Equal(t, "detailed: foo", FromError(err).Message())
+	assert.False(t, IsStatus(nil))
+	assert.False(t, IsStatus(errors.New("")))
+}
+
 func TestFromHeadersBadName(t *testing.T) {
 	assert.Equal(t, validateName("123"), FromHeaders(CodeUnknown, "123", ""))
 }
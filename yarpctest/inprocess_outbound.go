@@ -0,0 +1,371 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package yarpctest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/pkg/lifecycle"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const _inProcessTransportName = "inprocess"
+
+var (
+	_ transport.UnaryOutbound  = (*InProcessOutbound)(nil)
+	_ transport.OnewayOutbound = (*InProcessOutbound)(nil)
+	_ transport.StreamOutbound = (*InProcessOutbound)(nil)
+)
+
+// InProcessOutbound is an outbound that calls the handlers registered on a
+// transport.Router directly, in the same process, without a network. Use the
+// Router of a Dispatcher to call the procedures registered on it, including
+// its inbound middleware.
+//
+//   outbound := yarpctest.NewInProcessOutbound(dispatcher.Router())
+//
+// Unlike most outbounds, it does not need to be started. Oneway requests are
+// handled before CallOneway returns, so that tests may check their effects
+// right away.
+type InProcessOutbound struct {
+	once   *lifecycle.Once
+	router transport.Router
+}
+
+// NewInProcessOutbound returns an InProcessOutbound that calls the handlers
+// of the given router.
+func NewInProcessOutbound(router transport.Router) *InProcessOutbound {
+	return &InProcessOutbound{
+		once:   lifecycle.NewOnce(),
+		router: router,
+	}
+}
+
+// Start starts the outbound.
+func (o *InProcessOutbound) Start() error {
+	return o.once.Start(nil)
+}
+
+// Stop stops the outbound.
+func (o *InProcessOutbound) Stop() error {
+	return o.once.Stop(nil)
+}
+
+// IsRunning returns whether the outbound is running.
+func (o *InProcessOutbound) IsRunning() bool {
+	return o.once.IsRunning()
+}
+
+// Transports returns no transports, as the outbound does not use one.
+func (o *InProcessOutbound) Transports() []transport.Transport {
+	return nil
+}
+
+// Call calls the unary handler for the request.
+func (o *InProcessOutbound) Call(ctx context.Context, req *transport.Request) (*transport.Response, error) {
+	req, spec, err := o.choose(ctx, req, transport.Unary)
+	if err != nil {
+		return nil, err
+	}
+	resw := &inProcessResponseWriter{}
+	if err := transport.InvokeUnaryHandler(transport.UnaryInvokeRequest{
+		Context:        ctx,
+		StartTime:      time.Now(),
+		Request:        req,
+		ResponseWriter: resw,
+		Handler:        spec.Unary(),
+	}); err != nil {
+		return nil, yarpcerrors.FromError(err)
+	}
+	return &transport.Response{
+		Headers:          resw.headers,
+		Body:             ioutil.NopCloser(bytes.NewReader(resw.body.Bytes())),
+		ApplicationError: resw.applicationError,
+	}, nil
+}
+
+// CallOneway calls the oneway handler for the request, and returns once it
+// has handled the request.
+func (o *InProcessOutbound) CallOneway(ctx context.Context, req *transport.Request) (transport.Ack, error) {
+	req, spec, err := o.choose(ctx, req, transport.Oneway)
+	if err != nil {
+		return nil, err
+	}
+	if err := transport.InvokeOnewayHandler(transport.OnewayInvokeRequest{
+		Context: ctx,
+		Request: req,
+		Handler: spec.Oneway(),
+	}); err != nil {
+		return nil, yarpcerrors.FromError(err)
+	}
+	return inProcessAck{}, nil
+}
+
+// choose copies the request, so that the handler may not observe later
+// changes to it, and finds its handler.
+func (o *InProcessOutbound) choose(ctx context.Context, req *transport.Request, rpcType transport.Type) (*transport.Request, transport.HandlerSpec, error) {
+	if err := transport.ValidateRequest(req); err != nil {
+		return nil, transport.HandlerSpec{}, err
+	}
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, transport.HandlerSpec{}, err
+	}
+	copied := *req
+	copied.Transport = _inProcessTransportName
+	copied.Headers = copyHeaders(req.Headers)
+	copied.Body = bytes.NewReader(body)
+	spec, err := o.router.Choose(ctx, &copied)
+	if err != nil {
+		return nil, transport.HandlerSpec{}, err
+	}
+	if spec.Type() != rpcType {
+		return nil, transport.HandlerSpec{}, yarpcerrors.InvalidArgumentErrorf(
+			"procedure %q of service %q is a %v procedure, not a %v procedure",
+			req.Procedure, req.Service, spec.Type(), rpcType)
+	}
+	return &copied, spec, nil
+}
+
+// CallStream starts a stream with the stream handler for the request. The
+// handler runs in its own goroutine until it returns.
+func (o *InProcessOutbound) CallStream(ctx context.Context, req *transport.StreamRequest) (*transport.ClientStream, error) {
+	meta := *req.Meta
+	meta.Transport = _inProcessTransportName
+	meta.Headers = copyHeaders(req.Meta.Headers)
+	request := &transport.StreamRequest{Meta: &meta}
+	spec, err := o.router.Choose(ctx, meta.ToRequest())
+	if err != nil {
+		return nil, err
+	}
+	if spec.Type() != transport.Streaming {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"procedure %q of service %q is a %v procedure, not a %v procedure",
+			meta.Procedure, meta.Service, spec.Type(), transport.Streaming)
+	}
+
+	serverCtx, cancel := context.WithCancel(ctx)
+	pipe := &inProcessPipe{
+		clientToServer: make(chan *transport.StreamMessage),
+		serverToClient: make(chan *transport.StreamMessage),
+		done:           make(chan struct{}),
+	}
+	serverStream, err := transport.NewServerStream(&inProcessServerStream{
+		ctx:     serverCtx,
+		request: request,
+		pipe:    pipe,
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		defer cancel()
+		pipe.err = transport.InvokeStreamHandler(transport.StreamInvokeRequest{
+			Stream:  serverStream,
+			Handler: spec.Stream(),
+		})
+		close(pipe.done)
+	}()
+	return transport.NewClientStream(&inProcessClientStream{
+		ctx:     ctx,
+		request: request,
+		pipe:    pipe,
+	})
+}
+
+// inProcessPipe carries the messages of a stream between the client and the
+// handler.
+type inProcessPipe struct {
+	clientToServer chan *transport.StreamMessage
+	serverToClient chan *transport.StreamMessage
+
+	closeOnce sync.Once
+
+	// done is closed once the handler returns, after err is set.
+	done chan struct{}
+	err  error
+}
+
+type inProcessClientStream struct {
+	ctx     context.Context
+	request *transport.StreamRequest
+	pipe    *inProcessPipe
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *inProcessClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *inProcessClientStream) Request() *transport.StreamRequest {
+	return s.request
+}
+
+func (s *inProcessClientStream) SendMessage(ctx context.Context, msg *transport.StreamMessage) error {
+	msg, err := copyStreamMessage(msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return yarpcerrors.FailedPreconditionErrorf("cannot send a message on a closed stream")
+	}
+	select {
+	case s.pipe.clientToServer <- msg:
+		return nil
+	case <-s.pipe.done:
+		return io.EOF
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *inProcessClientStream) ReceiveMessage(ctx context.Context) (*transport.StreamMessage, error) {
+	select {
+	case msg := <-s.pipe.serverToClient:
+		return msg, nil
+	case <-s.pipe.done:
+		if s.pipe.err != nil {
+			return nil, yarpcerrors.FromError(s.pipe.err)
+		}
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close signals the end of the messages sent by the client. The handler
+// receives io.EOF once it has received all prior messages.
+func (s *inProcessClientStream) Close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.pipe.clientToServer)
+	}
+	return nil
+}
+
+type inProcessServerStream struct {
+	ctx     context.Context
+	request *transport.StreamRequest
+	pipe    *inProcessPipe
+}
+
+func (s *inProcessServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *inProcessServerStream) Request() *transport.StreamRequest {
+	return s.request
+}
+
+func (s *inProcessServerStream) SendMessage(ctx context.Context, msg *transport.StreamMessage) error {
+	msg, err := copyStreamMessage(msg)
+	if err != nil {
+		return err
+	}
+	select {
+	case s.pipe.serverToClient <- msg:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *inProcessServerStream) ReceiveMessage(ctx context.Context) (*transport.StreamMessage, error) {
+	select {
+	case msg, ok := <-s.pipe.clientToServer:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type inProcessResponseWriter struct {
+	headers          transport.Headers
+	body             bytes.Buffer
+	applicationError bool
+}
+
+func (w *inProcessResponseWriter) Write(p []byte) (int, error) {
+	return w.body.Write(p)
+}
+
+func (w *inProcessResponseWriter) AddHeaders(headers transport.Headers) {
+	for k, v := range headers.OriginalItems() {
+		w.headers = w.headers.With(k, v)
+	}
+}
+
+func (w *inProcessResponseWriter) SetApplicationError() {
+	w.applicationError = true
+}
+
+type inProcessAck struct{}
+
+func (inProcessAck) String() string {
+	return ""
+}
+
+func readBody(body io.Reader) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return ioutil.ReadAll(body)
+}
+
+func copyHeaders(headers transport.Headers) transport.Headers {
+	var copied transport.Headers
+	for k, v := range headers.OriginalItems() {
+		copied = copied.With(k, v)
+	}
+	return copied
+}
+
+func copyStreamMessage(msg *transport.StreamMessage) (*transport.StreamMessage, error) {
+	if msg == nil || msg.Body == nil {
+		return &transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+	}
+	defer msg.Body.Close()
+	body, err := ioutil.ReadAll(msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &transport.StreamMessage{Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+}
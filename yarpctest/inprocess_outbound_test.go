@@ -0,0 +1,162 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package yarpctest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/raw"
+	"go.uber.org/yarpc/yarpcerrors"
+	"go.uber.org/yarpc/yarpctest"
+)
+
+func newInProcessClientConfig(procedures ...[]transport.Procedure) transport.ClientConfig {
+	router := yarpc.NewMapRouter("server")
+	for _, p := range procedures {
+		router.Register(p)
+	}
+	outbound := yarpctest.NewInProcessOutbound(router)
+	return &transport.OutboundConfig{
+		CallerName: "client",
+		Outbounds: transport.Outbounds{
+			ServiceName: "server",
+			Unary:       outbound,
+			Oneway:      outbound,
+			Stream:      outbound,
+		},
+	}
+}
+
+func TestInProcessOutboundCall(t *testing.T) {
+	client := raw.New(newInProcessClientConfig(
+		raw.Procedure("echo", func(ctx context.Context, body []byte) ([]byte, error) {
+			call := yarpc.CallFromContext(ctx)
+			assert.Equal(t, "client", call.Caller())
+			assert.Equal(t, "bar", call.Header("foo"))
+			require.NoError(t, call.WriteResponseHeader("baz", "qux"))
+			return body, nil
+		}),
+		raw.Procedure("fail", func(context.Context, []byte) ([]byte, error) {
+			return nil, yarpcerrors.NotFoundErrorf("not found")
+		}),
+		raw.Procedure("panic", func(context.Context, []byte) ([]byte, error) {
+			panic("great sadness")
+		}),
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var headers map[string]string
+	response, err := client.Call(ctx, "echo", []byte("hello"), yarpc.WithHeader("foo", "bar"), yarpc.ResponseHeaders(&headers))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(response))
+	assert.Equal(t, map[string]string{"baz": "qux"}, headers)
+
+	_, err = client.Call(ctx, "fail", nil)
+	assert.Equal(t, yarpcerrors.NotFoundErrorf("not found"), err)
+
+	_, err = client.Call(ctx, "panic", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "great sadness")
+
+	_, err = client.Call(ctx, "unknown", nil)
+	assert.Equal(t, yarpcerrors.CodeUnimplemented, yarpcerrors.FromError(err).Code())
+}
+
+func TestInProcessOutboundCallOneway(t *testing.T) {
+	var received string
+	client := raw.New(newInProcessClientConfig(
+		raw.OnewayProcedure("fire", func(_ context.Context, body []byte) error {
+			received = string(body)
+			return nil
+		}),
+		raw.Procedure("echo", func(_ context.Context, body []byte) ([]byte, error) {
+			return body, nil
+		}),
+	))
+
+	ack, err := client.CallOneway(context.Background(), "fire", []byte("hello"))
+	require.NoError(t, err)
+	assert.NotNil(t, ack)
+	assert.Equal(t, "hello", received, "oneway requests must be handled before CallOneway returns")
+
+	_, err = client.CallOneway(context.Background(), "echo", nil)
+	assert.Equal(t, yarpcerrors.CodeInvalidArgument, yarpcerrors.FromError(err).Code())
+}
+
+func TestInProcessOutboundCallStream(t *testing.T) {
+	client := raw.NewStreamClient(newInProcessClientConfig(
+		raw.StreamProcedure("echo", func(stream *raw.ServerStream) error {
+			for {
+				body, err := stream.Receive()
+				if err == io.EOF {
+					return stream.Send([]byte("done"))
+				}
+				if err != nil {
+					return err
+				}
+				if string(body) == "fail" {
+					return errors.New("great sadness")
+				}
+				if err := stream.Send(body); err != nil {
+					return err
+				}
+			}
+		}),
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stream, err := client.CallStream(ctx, "echo")
+	require.NoError(t, err)
+	for _, message := range []string{"foo", "bar"} {
+		require.NoError(t, stream.Send([]byte(message)))
+		body, err := stream.Receive()
+		require.NoError(t, err)
+		assert.Equal(t, message, string(body))
+	}
+	require.NoError(t, stream.Close())
+	body, err := stream.Receive()
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+	_, err = stream.Receive()
+	assert.Equal(t, io.EOF, err)
+
+	stream, err = client.CallStream(ctx, "echo")
+	require.NoError(t, err)
+	require.NoError(t, stream.Send([]byte("fail")))
+	_, err = stream.Receive()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "great sadness")
+
+	_, err = client.CallStream(ctx, "unknown")
+	assert.Equal(t, yarpcerrors.CodeUnimplemented, yarpcerrors.FromError(err).Code())
+}